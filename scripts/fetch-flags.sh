@@ -0,0 +1,21 @@
+#!/bin/sh
+# SPDX-FileCopyrightText: 2025 Humaid Alqasimi
+# SPDX-License-Identifier: Apache-2.0
+#
+# Vendors the 4:3 flags from lipis/flag-icons (MIT) into src/static/flags,
+# one for each country code in countryFlagCodes in src/utils/adif.go.
+# Run from the repository root and commit the result.
+set -eu
+
+version="${FLAG_ICONS_VERSION:-v7.2.3}"
+base="https://raw.githubusercontent.com/lipis/flag-icons/$version/flags/4x3"
+dest="src/static/flags"
+
+codes=$(awk '/^var countryFlagCodes/,/^}/' src/utils/adif.go |
+	grep -o '": *"[a-z][a-z]"' | grep -o '[a-z][a-z]' | sort -u)
+
+mkdir -p "$dest"
+for code in $codes; do
+	curl -fsSL -o "$dest/$code.svg" "$base/$code.svg"
+done
+echo "Fetched $(echo "$codes" | wc -l) flags from flag-icons $version"
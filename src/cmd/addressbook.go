@@ -0,0 +1,147 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/flamego/csrf"
+	"github.com/flamego/flamego"
+	"github.com/flamego/template"
+	"github.com/urfave/cli/v3"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// addressBookPath is where the encrypted address book is kept
+const addressBookPath = "qsl-addressbook.json"
+
+// addressBookKeyFile holds the generated address book key when none is
+// configured. It is left out of backups, so keep a copy elsewhere.
+const addressBookKeyFile = "qsl-addressbook.key"
+
+// Limits on address book fields
+const (
+	maxAddressBookName    = 100
+	maxAddressBookAddress = 500
+	maxAddressBookNote    = 500
+)
+
+// newAddressBook opens the address book with the configured key, or one
+// generated on first start and kept in addressBookKeyFile
+func newAddressBook(cmd *cli.Command) (*utils.AddressBook, error) {
+	key, err := addressBookKey(cmd.String("address-book-key"))
+	if err != nil {
+		return nil, err
+	}
+	return utils.NewAddressBook(addressBookPath, key)
+}
+
+// addressBookKey decodes the configured hex key, or reads or generates the
+// key file when none is configured
+func addressBookKey(configured string) ([]byte, error) {
+	if configured == "" {
+		data, err := os.ReadFile(addressBookKeyFile)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read address book key: %w", err)
+		}
+		configured = strings.TrimSpace(string(data))
+	}
+	if configured != "" {
+		key, err := hex.DecodeString(configured)
+		if err != nil || len(key) != 32 {
+			return nil, errors.New("address book key must be 64 hex digits")
+		}
+		return key, nil
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate address book key: %w", err)
+	}
+	if err := os.WriteFile(addressBookKeyFile, []byte(hex.EncodeToString(key)+"\n"), 0600); err != nil {
+		return nil, fmt.Errorf("failed to save address book key: %w", err)
+	}
+	return key, nil
+}
+
+// newAdminAddressBookHandler returns a handler listing the address book with
+// a form to add or edit an entry. Given ?call= the form is filled in from
+// the station's entry, or the address from its latest direct card request.
+func newAdminAddressBookHandler(book *utils.AddressBook) flamego.Handler {
+	return func(c flamego.Context, t template.Template, data template.Data, x csrf.CSRF, qslRequests *utils.QSLRequestStore) {
+		call := strings.ToUpper(strings.TrimSpace(c.Query("call")))
+		entry, ok := book.Get(call)
+		if !ok {
+			entry = utils.AddressBookEntry{Call: call}
+			for _, req := range qslRequests.List() {
+				if strings.EqualFold(req.Call, call) && req.Address != "" && req.Created.After(entry.Updated) {
+					entry.Name, entry.Address, entry.Updated = req.Name, req.Address, req.Created
+				}
+			}
+		}
+
+		c.ResponseWriter().Header().Set("Cache-Control", "no-store")
+		data["Title"] = "Admin: Address Book"
+		data["CSRFToken"] = x.Token()
+		data["Entries"] = book.List()
+		data["Entry"] = entry
+		data["Editing"] = ok
+		data["Failed"] = c.Query("failed") != ""
+		t.HTML(http.StatusOK, "admin-addresses")
+	}
+}
+
+// newAdminAddressBookSaveHandler returns a handler that saves an address
+// book entry, removing it when the address is left empty
+func newAdminAddressBookSaveHandler(book *utils.AddressBook) flamego.Handler {
+	return func(c flamego.Context) {
+		r := c.Request().Request
+		entry := utils.AddressBookEntry{
+			Call:    strings.ToUpper(strings.TrimSpace(r.FormValue("call"))),
+			Name:    strings.TrimSpace(r.FormValue("name")),
+			Address: strings.TrimSpace(r.FormValue("address")),
+			Note:    strings.TrimSpace(r.FormValue("note")),
+		}
+		if entry.Call == "" || len(entry.Name) > maxAddressBookName ||
+			len(entry.Address) > maxAddressBookAddress || len(entry.Note) > maxAddressBookNote {
+			c.Redirect("/admin/addresses?failed=1", http.StatusFound)
+			return
+		}
+
+		if err := book.Set(entry); err != nil {
+			// The address itself is never logged
+			log.Printf("Failed to save address book entry for %s: %v", entry.Call, err)
+			c.Redirect("/admin/addresses?failed=1", http.StatusFound)
+			return
+		}
+		c.Redirect("/admin/addresses", http.StatusFound)
+	}
+}
+
+// newAdminLabelsCSVHandler returns a handler downloading mailing labels for
+// the direct cards to send as CSV for a mail merge
+func newAdminLabelsCSVHandler(book *utils.AddressBook) flamego.Handler {
+	return func(c flamego.Context, store utils.QSOStore, qslRequests *utils.QSLRequestStore) {
+		labels := utils.MailingLabels(utils.OutgoingQueue(store.GetQSOs(), qslRequests.List()), book)
+
+		w := c.ResponseWriter()
+		fileName := "labels-" + time.Now().UTC().Format("2006-01-02") + ".csv"
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+fileName+`"`)
+		if err := utils.WriteLabelsCSV(w, labels); err != nil {
+			log.Printf("Failed to write mailing labels: %v", err)
+		}
+	}
+}
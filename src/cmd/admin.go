@@ -0,0 +1,341 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/flamego/csrf"
+	"github.com/flamego/flamego"
+	"github.com/flamego/session"
+	"github.com/flamego/template"
+	"github.com/urfave/cli/v3"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// adminSessionKey is the session key holding the signed in admin username
+const adminSessionKey = "admin"
+
+// adminLogTail is how many lines of a log file the log viewer shows
+const adminLogTail = 200
+
+// adminLogs are the log files viewable in the admin area, by name
+var adminLogs = map[string]string{
+	"access":  "qsl-access.log",
+	"lookups": "qsl-lookups.log",
+	"abuse":   abuseLogPath,
+}
+
+// adminAuth checks admin credentials and guards the admin area
+type adminAuth struct {
+	username     string
+	passwordHash string
+	logins       *utils.RateLimiter
+}
+
+// newAdminAuth creates the admin authenticator, or returns nil when no
+// password hash is configured and the admin area is disabled
+func newAdminAuth(cmd *cli.Command) *adminAuth {
+	hash := cmd.String("admin-password-hash")
+	if hash == "" {
+		return nil
+	}
+
+	return &adminAuth{
+		username:     cmd.String("admin-user"),
+		passwordHash: hash,
+		logins:       utils.NewRateLimiter(5, 15*time.Minute),
+	}
+}
+
+// require redirects to the login page unless an admin is signed in
+func (a *adminAuth) require(c flamego.Context, s session.Session, data template.Data) {
+	user, _ := s.Get(adminSessionKey).(string)
+	if user == "" {
+		c.Redirect("/admin/login?next="+url.QueryEscape(c.Request().URL.Path), http.StatusFound)
+		return
+	}
+	data["AdminPage"] = true
+	data["AdminUser"] = user
+}
+
+// handleLoginForm shows the admin login form
+func (a *adminAuth) handleLoginForm(c flamego.Context, t template.Template, data template.Data, x csrf.CSRF) {
+	data["Title"] = "Admin Login"
+	data["AdminPage"] = true
+	data["CSRFToken"] = x.Token()
+	data["Next"] = c.Query("next")
+	data["LoginError"] = c.Query("error")
+	t.HTML(http.StatusOK, "admin-login")
+}
+
+// handleLogin signs the admin in. Attempts are rate limited per client, and
+// the session ID is regenerated on success to prevent session fixation.
+func (a *adminAuth) handleLogin(c flamego.Context, s session.Session) {
+	r := c.Request().Request
+	ip := clientIP(r)
+
+	next := r.FormValue("next")
+	if !strings.HasPrefix(next, "/admin") {
+		next = "/admin"
+	}
+
+	if !a.logins.Allow(ip) {
+		log.Printf("Admin login rate limited for %s", ip)
+		c.Redirect("/admin/login?error=limited", http.StatusFound)
+		return
+	}
+
+	userOK := subtle.ConstantTimeCompare([]byte(r.FormValue("username")), []byte(a.username)) == 1
+	passOK := utils.CheckPassword(a.passwordHash, r.FormValue("password"))
+	if !userOK || !passOK {
+		log.Printf("Failed admin login from %s", ip)
+		c.Redirect("/admin/login?error=invalid&next="+url.QueryEscape(next), http.StatusFound)
+		return
+	}
+
+	if err := s.RegenerateID(c.ResponseWriter(), r); err != nil {
+		log.Printf("Failed to regenerate session: %v", err)
+		c.Redirect("/admin/login?error=failed", http.StatusFound)
+		return
+	}
+	s.Set(adminSessionKey, a.username)
+
+	log.Printf("Admin %s signed in from %s", a.username, ip)
+	c.Redirect(next, http.StatusFound)
+}
+
+// handleLogout signs the admin out
+func (a *adminAuth) handleLogout(c flamego.Context, s session.Session) {
+	s.Delete(adminSessionKey)
+	c.Redirect("/", http.StatusFound)
+}
+
+// adminSetting is a configuration value shown on the admin dashboard
+type adminSetting struct {
+	Name  string
+	Value string
+}
+
+// adminSettings lists the effective command line configuration, hiding the
+// values of secrets
+func adminSettings(cmd *cli.Command) []adminSetting {
+	var settings []adminSetting
+	for _, flag := range cmd.Flags {
+		name := flag.Names()[0]
+
+		value := fmt.Sprint(cmd.Value(name))
+		if isSecretSetting(name) {
+			if value != "" {
+				value = "(set)"
+			} else {
+				value = "(not set)"
+			}
+		}
+		settings = append(settings, adminSetting{Name: name, Value: value})
+	}
+	return settings
+}
+
+// isSecretSetting reports whether a flag holds a credential
+func isSecretSetting(name string) bool {
+	for _, s := range []string{"password", "token", "secret", "key"} {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// adminWarningLimit is how many parse warnings and sanity issues the
+// dashboard lists
+const adminWarningLimit = 50
+
+// newAdminDashboardHandler returns the admin dashboard handler, showing log
+// status and the running configuration
+func newAdminDashboardHandler(rp *ReloadableParser, settings []adminSetting) flamego.Handler {
+	return func(c flamego.Context, t template.Template, data template.Data, x csrf.CSRF, store utils.QSOStore, qslRequests *utils.QSLRequestStore, maintenance *utils.MaintenanceMode) {
+		status := rp.status()
+
+		requests := qslRequests.List()
+		pending := 0
+		for _, r := range requests {
+			if !r.IsSent() {
+				pending++
+			}
+		}
+
+		warnings := status.Warnings
+		if len(warnings) > adminWarningLimit {
+			warnings = warnings[:adminWarningLimit]
+		}
+
+		data["Title"] = "Admin"
+		data["CSRFToken"] = x.Token()
+		data["ADIFPath"] = rp.filePath
+		data["FileQSOs"] = status.FileCount
+		data["LiveQSOs"] = status.LiveCount
+		data["LoadedAt"] = status.LoadedAt.UTC().Format("2006-01-02 15:04:05")
+		data["LoadedAgo"] = humanize.Time(status.LoadedAt)
+		issues := status.Issues
+		if len(issues) > adminWarningLimit {
+			issues = issues[:adminWarningLimit]
+		}
+
+		data["WarningCount"] = len(status.Warnings)
+		data["Warnings"] = warnings
+		data["IssueCount"] = len(status.Issues)
+		data["Issues"] = issues
+		if status.Err != nil {
+			data["LoadError"] = status.Err.Error()
+		}
+		data["Reloaded"] = c.Query("reloaded") != ""
+		data["PendingQSLRequests"] = pending
+		data["OutgoingCards"] = len(utils.OutgoingQueue(store.GetQSOs(), requests))
+		data["Settings"] = settings
+		data["Maintenance"] = maintenance.State()
+		data["MaintenanceFailed"] = c.Query("maintenance") == "failed"
+		t.HTML(http.StatusOK, "admin")
+	}
+}
+
+// newAdminReloadHandler returns a handler that reloads the ADIF file right
+// away and shows the outcome on the dashboard
+func newAdminReloadHandler(rp *ReloadableParser) flamego.Handler {
+	return func(c flamego.Context, s session.Session) {
+		if err := rp.reload(); err != nil {
+			log.Printf("Manual reload failed: %v", err)
+		} else {
+			log.Printf("Manual reload by %s", s.Get(adminSessionKey))
+		}
+		c.Redirect("/admin?reloaded=1", http.StatusFound)
+	}
+}
+
+// handleAdminLogs shows the tail of a log file
+func handleAdminLogs(c flamego.Context, t template.Template, data template.Data, x csrf.CSRF) {
+	name := c.Param("name")
+	path, ok := adminLogs[name]
+	if !ok {
+		c.Redirect("/admin", http.StatusFound)
+		return
+	}
+
+	lines, err := tailFile(path, adminLogTail)
+	if err != nil && !os.IsNotExist(err) {
+		data["LogError"] = err.Error()
+	}
+
+	data["Title"] = "Admin: " + path
+	data["CSRFToken"] = x.Token()
+	data["LogName"] = name
+	data["LogPath"] = path
+	data["LogLines"] = lines
+	data["LogTail"] = adminLogTail
+	t.HTML(http.StatusOK, "admin-logs")
+}
+
+// handleAdminQSLRequests shows the outgoing paper QSL card queue
+func handleAdminQSLRequests(t template.Template, data template.Data, x csrf.CSRF, qslRequests *utils.QSLRequestStore) {
+	data["Title"] = "Admin: QSL Requests"
+	data["CSRFToken"] = x.Token()
+	data["QSLRequests"] = qslRequests.List()
+	t.HTML(http.StatusOK, "admin-qsl-requests")
+}
+
+// newAdminQSLRequestSentHandler returns a handler that marks a paper QSL card
+// request as sent, recording the card for its QSO as sent too
+func newAdminQSLRequestSentHandler(rp *ReloadableParser) flamego.Handler {
+	return func(c flamego.Context, qslRequests *utils.QSLRequestStore, outgoing *utils.OutgoingQSLStore) {
+		req, err := qslRequests.MarkSent(c.Param("id"))
+		if err != nil {
+			log.Printf("Failed to mark QSL request %s as sent: %v", c.Param("id"), err)
+			c.Redirect("/admin/qsl-requests", http.StatusFound)
+			return
+		}
+		if err := outgoing.MarkSent(req.Call, req.QSOTime, req.Sent); err != nil {
+			log.Printf("Failed to mark QSL card for %s as sent: %v", req.Call, err)
+		}
+		rp.refresh()
+		c.Redirect("/admin/qsl-requests", http.StatusFound)
+	}
+}
+
+// handleAdminLookups shows aggregated statistics from the lookup log
+func handleAdminLookups(t template.Template, data template.Data, x csrf.CSRF) {
+	data["Title"] = "Admin: Lookups"
+	data["CSRFToken"] = x.Token()
+
+	file, err := os.Open(adminLogs["lookups"])
+	if err != nil && !os.IsNotExist(err) {
+		data["LookupError"] = err.Error()
+	}
+	if file != nil {
+		defer file.Close()
+
+		stats, err := utils.ComputeLookupStats(file, time.Now(), 30, 20, 50)
+		if err != nil {
+			data["LookupError"] = err.Error()
+		}
+		data["Stats"] = stats
+	}
+
+	t.HTML(http.StatusOK, "admin-lookups")
+}
+
+// tailFile returns up to the last n lines of a file, newest last
+func tailFile(path string, n int) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	// Read back from the end in chunks until there are enough lines
+	const chunkSize = 64 * 1024
+	var data []byte
+	offset := info.Size()
+	for offset > 0 && bytes.Count(data, []byte("\n")) <= n {
+		size := int64(chunkSize)
+		if offset < size {
+			size = offset
+		}
+		offset -= size
+
+		chunk := make([]byte, size)
+		if _, err := file.ReadAt(chunk, offset); err != nil && err != io.EOF {
+			return nil, err
+		}
+		data = append(chunk, data...)
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if offset > 0 && len(lines) > 0 {
+		lines = lines[1:] // First line is likely partial
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	if len(lines) == 1 && lines[0] == "" {
+		return nil, nil
+	}
+	return lines, nil
+}
@@ -0,0 +1,280 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/flamego/flamego"
+	"github.com/urfave/cli/v3"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// apiMaxFailures is how many failed attempts a client may make in the
+// failure window before API requests from it are refused
+const apiMaxFailures = 10
+
+// apiAuth guards the write API with a bearer token. Programmatic clients
+// send the token in the Authorization header instead of a session cookie,
+// so API routes don't use CSRF tokens.
+type apiAuth struct {
+	token    string
+	failures *utils.RateLimiter
+}
+
+// apiError is the body of an API error response
+type apiError struct {
+	Error string `json:"error"`
+}
+
+// newAPIAuth creates the API authenticator, or returns nil when no token is
+// configured and the API is disabled
+func newAPIAuth(cmd *cli.Command) *apiAuth {
+	token := cmd.String("api-token")
+	if token == "" {
+		return nil
+	}
+
+	return &apiAuth{
+		token:    token,
+		failures: utils.NewRateLimiter(apiMaxFailures, 15*time.Minute),
+	}
+}
+
+// bearerToken returns the token from an "Authorization: Bearer" header
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// require rejects requests without a valid bearer token
+func (a *apiAuth) require(c flamego.Context) {
+	w := c.ResponseWriter()
+	switch status := a.authorize(c.Request().Request); status {
+	case http.StatusOK:
+	case http.StatusUnauthorized:
+		w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+		writeAPIError(w, status, "invalid or missing bearer token")
+	default:
+		writeAPIError(w, status, "too many failed attempts")
+	}
+}
+
+// authorize checks the request's bearer token, returning the HTTP status to
+// respond with. Clients failing too often are rate limited, so the token
+// can't be guessed.
+func (a *apiAuth) authorize(r *http.Request) int {
+	token, _ := bearerToken(r)
+	return a.check(clientIP(r), token)
+}
+
+// check validates a token sent by the client at ip, returning the HTTP
+// status to respond with
+func (a *apiAuth) check(ip, token string) int {
+	if a.failures.Count(ip) >= apiMaxFailures {
+		return http.StatusTooManyRequests
+	}
+
+	if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(a.token)) != 1 {
+		a.failures.Allow(ip)
+		log.Printf("Rejected API request from %s", ip)
+		return http.StatusUnauthorized
+	}
+	return http.StatusOK
+}
+
+// writeAPIJSON writes v as a JSON API response
+func writeAPIJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Failed to write API response: %v", err)
+	}
+}
+
+// writeAPIError writes a JSON API error response
+func writeAPIError(w http.ResponseWriter, status int, message string) {
+	writeAPIJSON(w, status, apiError{Error: message})
+}
+
+// apiReloadResponse is the result of reloading the log through the API
+type apiReloadResponse struct {
+	LoadedAt  time.Time `json:"loaded_at"`
+	FileCount int       `json:"file_qsos"`
+	LiveCount int       `json:"live_qsos"`
+}
+
+// newAPIReloadHandler returns a handler reloading the ADIF file, for example
+// from a hook after the logging software saves it
+func newAPIReloadHandler(rp *ReloadableParser) flamego.Handler {
+	return func(c flamego.Context) {
+		w := c.ResponseWriter()
+		if err := rp.reload(); err != nil {
+			log.Printf("API reload failed: %v", err)
+			writeAPIError(w, http.StatusInternalServerError, "failed to reload log")
+			return
+		}
+
+		log.Printf("API reload by %s", clientIP(c.Request().Request))
+		status := rp.status()
+		writeAPIJSON(w, http.StatusOK, apiReloadResponse{
+			LoadedAt:  status.LoadedAt,
+			FileCount: status.FileCount,
+			LiveCount: status.LiveCount,
+		})
+	}
+}
+
+// apiMaxQSOSize is the largest QSO submission accepted, in bytes
+const apiMaxQSOSize = 64 << 10
+
+// apiQSO is a QSO submitted as JSON. Time is the start of the QSO.
+type apiQSO struct {
+	Call            string    `json:"call"`
+	Time            time.Time `json:"time"`
+	Band            string    `json:"band"`
+	Mode            string    `json:"mode"`
+	Submode         string    `json:"submode"`
+	Freq            string    `json:"freq"`
+	RSTSent         string    `json:"rst_sent"`
+	RSTRcvd         string    `json:"rst_rcvd"`
+	Name            string    `json:"name"`
+	QTH             string    `json:"qth"`
+	GridSquare      string    `json:"gridsquare"`
+	Country         string    `json:"country"`
+	DXCC            string    `json:"dxcc"`
+	State           string    `json:"state"`
+	CQZone          string    `json:"cqz"`
+	Comment         string    `json:"comment"`
+	StationCallsign string    `json:"station_callsign"`
+	MyGridSquare    string    `json:"my_gridsquare"`
+	TxPwr           string    `json:"tx_pwr"`
+	ContestID       string    `json:"contest_id"`
+	SRX             string    `json:"srx"`
+	STX             string    `json:"stx"`
+	SRXString       string    `json:"srx_string"`
+	STXString       string    `json:"stx_string"`
+}
+
+// record returns the submitted QSO as an ADIF record
+func (q apiQSO) record() string {
+	at := q.Time.UTC()
+	qso := utils.QSO{
+		Call:         strings.ToUpper(strings.TrimSpace(q.Call)),
+		QSODate:      at.Format("20060102"),
+		TimeOn:       at.Format("150405"),
+		Band:         q.Band,
+		Mode:         q.Mode,
+		Submode:      q.Submode,
+		Freq:         q.Freq,
+		RSTSent:      q.RSTSent,
+		RSTRcvd:      q.RSTRcvd,
+		Name:         q.Name,
+		QTH:          q.QTH,
+		GridSquare:   q.GridSquare,
+		Country:      q.Country,
+		DXCC:         q.DXCC,
+		State:        q.State,
+		CQZone:       q.CQZone,
+		Comment:      q.Comment,
+		StationCall:  q.StationCallsign,
+		MyGridSquare: q.MyGridSquare,
+		TxPwr:        q.TxPwr,
+		ContestID:    q.ContestID,
+		SRX:          q.SRX,
+		STX:          q.STX,
+		SRXString:    q.SRXString,
+		STXString:    q.STXString,
+	}
+	if qso.Band == "" {
+		if mhz, ok := utils.ParseFrequency(qso.Freq); ok {
+			qso.Band = utils.BandFromFrequency(mhz)
+		}
+	}
+	return utils.FormatADIFRecord(qso)
+}
+
+// parseAPIQSO parses a submitted QSO, given as JSON or as a raw ADIF record
+// depending on the content type. JSON QSOs go through ADIF too, so both are
+// read exactly as they will be when the file is next loaded.
+func parseAPIQSO(contentType string, body []byte) (utils.QSO, error) {
+	record := string(body)
+	if mediaType, _, _ := strings.Cut(contentType, ";"); strings.TrimSpace(mediaType) == "application/json" {
+		var q apiQSO
+		if err := json.Unmarshal(body, &q); err != nil {
+			return utils.QSO{}, fmt.Errorf("invalid JSON: %w", err)
+		}
+		if strings.TrimSpace(q.Call) == "" || q.Time.IsZero() {
+			return utils.QSO{}, errors.New("call and time are required")
+		}
+		record = q.record()
+	}
+
+	qso, err := utils.ParseADIFRecord(record)
+	if err != nil {
+		return utils.QSO{}, err
+	}
+	if qso.Timestamp.IsZero() {
+		return utils.QSO{}, errors.New("QSO has no valid date and time")
+	}
+	return qso, nil
+}
+
+// apiQSOResponse is the result of submitting a QSO through the API
+type apiQSOResponse struct {
+	Call      string    `json:"call"`
+	Time      time.Time `json:"time"`
+	FileCount int       `json:"file_qsos"`
+}
+
+// newAPIQSOHandler returns a handler appending a submitted QSO to the ADIF
+// file and the served log, so loggers can publish contacts as they are made
+func newAPIQSOHandler(rp *ReloadableParser) flamego.Handler {
+	return func(c flamego.Context) {
+		w := c.ResponseWriter()
+		r := c.Request().Request
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, apiMaxQSOSize))
+		if err != nil {
+			writeAPIError(w, http.StatusRequestEntityTooLarge, "QSO too large")
+			return
+		}
+		qso, err := parseAPIQSO(r.Header.Get("Content-Type"), body)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if err := rp.appendQSO(qso); errors.Is(err, errQSOExists) {
+			writeAPIError(w, http.StatusConflict, "QSO already logged")
+			return
+		} else if err != nil {
+			log.Printf("Failed to append API QSO with %s: %v", qso.Call, err)
+			writeAPIError(w, http.StatusInternalServerError, "failed to save QSO")
+			return
+		}
+
+		log.Printf("API QSO with %s added by %s", qso.Call, clientIP(r))
+		writeAPIJSON(w, http.StatusCreated, apiQSOResponse{
+			Call:      qso.Call,
+			Time:      qso.Timestamp,
+			FileCount: rp.status().FileCount,
+		})
+	}
+}
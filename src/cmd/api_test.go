@@ -0,0 +1,102 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+func TestBearerToken(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+		ok     bool
+	}{
+		{"Bearer secret", "secret", true},
+		{"bearer  secret ", "secret", true},
+		{"Basic c2VjcmV0", "", false},
+		{"Bearer", "", false},
+		{"Bearer ", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("POST", "/api/v1/reload", nil)
+		if tt.header != "" {
+			r.Header.Set("Authorization", tt.header)
+		}
+		got, ok := bearerToken(r)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("bearerToken(%q) = %q, %v, want %q, %v", tt.header, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestAPIAuthAuthorize(t *testing.T) {
+	api := &apiAuth{token: "secret", failures: utils.NewRateLimiter(apiMaxFailures, time.Minute)}
+
+	authorize := func(header string) int {
+		r := httptest.NewRequest("POST", "/api/v1/reload", nil)
+		if header != "" {
+			r.Header.Set("Authorization", header)
+		}
+		return api.authorize(r)
+	}
+
+	if status := authorize("Bearer secret"); status != http.StatusOK {
+		t.Errorf("valid token: got %d, want %d", status, http.StatusOK)
+	}
+	if status := authorize(""); status != http.StatusUnauthorized {
+		t.Errorf("missing token: got %d, want %d", status, http.StatusUnauthorized)
+	}
+	for i := 1; i < apiMaxFailures; i++ {
+		if status := authorize("Bearer wrong"); status != http.StatusUnauthorized {
+			t.Fatalf("invalid token %d: got %d, want %d", i, status, http.StatusUnauthorized)
+		}
+	}
+
+	// The client is rate limited even with the right token
+	if status := authorize("Bearer secret"); status != http.StatusTooManyRequests {
+		t.Errorf("after failures: got %d, want %d", status, http.StatusTooManyRequests)
+	}
+}
+
+func TestParseAPIQSO(t *testing.T) {
+	qso, err := parseAPIQSO("application/json; charset=utf-8",
+		[]byte(`{"call": "ea4abc", "time": "2025-03-01T12:05:00Z", "freq": "14.074", "mode": "FT8", "name": "José"}`))
+	if err != nil {
+		t.Fatalf("JSON QSO: %v", err)
+	}
+	if qso.Call != "EA4ABC" || qso.Band != "20m" || qso.Name != "José" || qso.Timestamp.Format(time.RFC3339) != "2025-03-01T12:05:00Z" {
+		t.Errorf("unexpected JSON QSO %+v", qso)
+	}
+
+	qso, err = parseAPIQSO("text/plain", []byte("<CALL:4>W1AW<QSO_DATE:8>20250301<TIME_ON:4>1200<BAND:3>40m<EOR>"))
+	if err != nil {
+		t.Fatalf("ADIF QSO: %v", err)
+	}
+	if qso.Call != "W1AW" || qso.Band != "40m" {
+		t.Errorf("unexpected ADIF QSO %+v", qso)
+	}
+
+	invalid := []struct {
+		contentType, body string
+	}{
+		{"application/json", `{"call": "W1AW"}`},
+		{"application/json", `{"call":`},
+		{"text/plain", ""},
+		{"text/plain", "<CALL:4>W1AW<EOR>"},
+	}
+	for _, tt := range invalid {
+		if _, err := parseAPIQSO(tt.contentType, []byte(tt.body)); err == nil {
+			t.Errorf("parseAPIQSO(%q, %q) accepted", tt.contentType, tt.body)
+		}
+	}
+}
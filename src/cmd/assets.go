@@ -0,0 +1,118 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/flamego/flamego"
+)
+
+// assetCacheControl is sent with fingerprinted assets. Their URLs change
+// whenever the content does, so they can be cached indefinitely.
+const assetCacheControl = "public, max-age=31536000, immutable"
+
+// assetManifest maps static asset paths to fingerprinted paths that include
+// a hash of the file content, e.g. /main.css to /main.1a2b3c4d5e.css
+type assetManifest struct {
+	fsys        fs.FS
+	hashed      map[string]string // Asset path to fingerprinted path
+	files       map[string]string // Fingerprinted path to asset path
+	fingerprint bool              // Whether path returns fingerprinted URLs
+}
+
+// newAssetManifest hashes every file in fsys, which is done once at startup
+// since the static files are embedded
+func newAssetManifest(fsys fs.FS) (*assetManifest, error) {
+	m := &assetManifest{
+		fsys:        fsys,
+		hashed:      make(map[string]string),
+		files:       make(map[string]string),
+		fingerprint: true,
+	}
+
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || strings.HasSuffix(name, ".go") {
+			return err
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+
+		ext := path.Ext(name)
+		fingerprinted := fmt.Sprintf("/%s.%s%s", strings.TrimSuffix(name, ext), hex.EncodeToString(sum[:5]), ext)
+		m.hashed["/"+name] = fingerprinted
+		m.files[fingerprinted] = name
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fingerprint static assets: %w", err)
+	}
+
+	return m, nil
+}
+
+// path returns the fingerprinted URL for an asset path, or the path itself
+// if it isn't a known asset or fingerprinting is off
+func (m *assetManifest) path(name string) string {
+	if p, ok := m.hashed[name]; ok && m.fingerprint {
+		return p
+	}
+	return name
+}
+
+// flag returns the URL of the flag image for a country code, or an empty
+// string if no flag is embedded for it
+func (m *assetManifest) flag(code string) string {
+	name := "/flags/" + code + ".svg"
+	if code == "" || !m.isAsset(name) {
+		return ""
+	}
+	return m.path(name)
+}
+
+// isAsset reports whether a URL path is a static asset, fingerprinted or not
+func (m *assetManifest) isAsset(p string) bool {
+	_, hashed := m.hashed[p]
+	_, fingerprinted := m.files[p]
+	return hashed || fingerprinted
+}
+
+// handler serves fingerprinted asset URLs with long-lived cache headers and
+// passes everything else on
+func (m *assetManifest) handler(c flamego.Context) {
+	if !m.serve(c.ResponseWriter(), c.Request().Request) {
+		c.Next()
+	}
+}
+
+// serve writes the asset for a fingerprinted URL, reporting whether r was
+// for one
+func (m *assetManifest) serve(w http.ResponseWriter, r *http.Request) bool {
+	name, ok := m.files[r.URL.Path]
+	if !ok || (r.Method != http.MethodGet && r.Method != http.MethodHead) {
+		return false
+	}
+
+	data, err := fs.ReadFile(m.fsys, name)
+	if err != nil {
+		return false
+	}
+
+	w.Header().Set("Cache-Control", assetCacheControl)
+	http.ServeContent(w, r, path.Base(name), time.Time{}, bytes.NewReader(data))
+	return true
+}
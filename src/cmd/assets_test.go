@@ -0,0 +1,86 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"testing/fstest"
+)
+
+func TestAssetManifest(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.css":     {Data: []byte("body { color: red; }")},
+		"flags/ae.svg": {Data: []byte("<svg/>")},
+		"embed.go":     {Data: []byte("package static")},
+	}
+
+	m, err := newAssetManifest(fsys)
+	if err != nil {
+		t.Fatalf("Failed to build manifest: %v", err)
+	}
+
+	css := m.path("/main.css")
+	if !regexp.MustCompile(`^/main\.[0-9a-f]{10}\.css$`).MatchString(css) {
+		t.Errorf("Unexpected fingerprinted path %q", css)
+	}
+	if flag := m.path("/flags/ae.svg"); !regexp.MustCompile(`^/flags/ae\.[0-9a-f]{10}\.svg$`).MatchString(flag) {
+		t.Errorf("Unexpected fingerprinted path %q", flag)
+	}
+	if got := m.flag("ae"); got != m.path("/flags/ae.svg") {
+		t.Errorf("Expected the embedded flag, got %q", got)
+	}
+	if got := m.flag("zz"); got != "" {
+		t.Errorf("Expected no URL for a flag that isn't embedded, got %q", got)
+	}
+	if got := m.flag(""); got != "" {
+		t.Errorf("Expected no URL without a country code, got %q", got)
+	}
+	if got := m.path("/embed.go"); got != "/embed.go" {
+		t.Errorf("Expected Go sources to be skipped, got %q", got)
+	}
+	if got := m.path("/missing.js"); got != "/missing.js" {
+		t.Errorf("Expected unknown assets to be unchanged, got %q", got)
+	}
+
+	fsys["main.css"] = &fstest.MapFile{Data: []byte("body { color: blue; }")}
+	changed, err := newAssetManifest(fsys)
+	if err != nil {
+		t.Fatalf("Failed to build manifest: %v", err)
+	}
+	if changed.path("/main.css") == css {
+		t.Error("Expected the fingerprint to change with the content")
+	}
+
+	// Development mode serves the files as they are edited
+	changed.fingerprint = false
+	if got := changed.path("/main.css"); got != "/main.css" {
+		t.Errorf("Expected the plain path without fingerprinting, got %q", got)
+	}
+}
+
+func TestAssetHandler(t *testing.T) {
+	m, err := newAssetManifest(fstest.MapFS{"main.css": {Data: []byte("body {}")}})
+	if err != nil {
+		t.Fatalf("Failed to build manifest: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	if !m.serve(rec, httptest.NewRequest(http.MethodGet, m.path("/main.css"), nil)) {
+		t.Fatal("Expected the fingerprinted URL to be served")
+	}
+	if rec.Code != http.StatusOK || rec.Body.String() != "body {}" {
+		t.Fatalf("Expected the asset, got %d %q", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Cache-Control"); got != assetCacheControl {
+		t.Errorf("Cache-Control = %q, want %q", got, assetCacheControl)
+	}
+
+	if m.serve(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/main.css", nil)) {
+		t.Error("Expected the plain asset path to be left to the static handler")
+	}
+}
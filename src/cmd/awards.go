@@ -0,0 +1,50 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/flamego/template"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// handleAwards shows progress towards DXCC, WAS, WAZ and VUCC
+func handleAwards(t template.Template, data template.Data, store utils.QSOStore, l *localizer) {
+	data["Title"] = l.T("nav.awards")
+	data["AwardsPage"] = true
+	data["Awards"] = utils.ComputeAwards(store.GetQSOs())
+	data["Certificates"] = utils.EarnedCertificates(store.GetQSOs())
+	t.HTML(http.StatusOK, "awards")
+}
+
+// handleDXCCMatrix shows the worked and confirmed status of every DXCC entity
+// on every band
+func handleDXCCMatrix(t template.Template, data template.Data, store utils.QSOStore, l *localizer) {
+	data["Title"] = l.T("dxcc.matrix.title")
+	data["AwardsPage"] = true
+	data["Matrix"] = utils.ComputeDXCCMatrix(store.GetQSOs())
+	t.HTML(http.StatusOK, "dxcc-matrix")
+}
+
+// handleDXCCChallenge shows DXCC Challenge band-slot progress and the slots
+// still needing a confirmation
+func handleDXCCChallenge(t template.Template, data template.Data, store utils.QSOStore, l *localizer) {
+	data["Title"] = l.T("challenge.title")
+	data["AwardsPage"] = true
+	data["Challenge"] = utils.ComputeDXCCChallenge(store.GetQSOs())
+	t.HTML(http.StatusOK, "dxcc-challenge")
+}
+
+// handleGridChase shows the grids worked on 6m, 2m and 70cm, highlighting new
+// ones this year
+func handleGridChase(t template.Template, data template.Data, store utils.QSOStore, l *localizer) {
+	data["Title"] = l.T("grids.title")
+	data["AwardsPage"] = true
+	data["GridChase"] = utils.ComputeGridChase(store.GetQSOs(), time.Now())
+	t.HTML(http.StatusOK, "grid-chase")
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/flamego/flamego"
+)
+
+// backupWriteTimeout bounds how long a backup download may take
+const backupWriteTimeout = 10 * time.Minute
+
+// backupFiles are the state files worth backing up, relative to the working
+// directory where the server keeps them. Missing files are skipped.
+var backupFiles = []string{
+	"qsl-corrections.json",
+	"qsl-requests.json",
+	"qsl-blocklist.json",
+	"qsl-maintenance.json",
+	"qsl-callbook.json",
+	outgoingStorePath,
+	addressBookPath,
+	lotwStorePath,
+	adminLogs["lookups"],
+}
+
+// writeBackup writes a gzipped tarball of the ADIF file, the state files and
+// the generated maps and cards in store
+func writeBackup(w io.Writer, adifPath string, files []string, store imageStore) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	for _, path := range append([]string{adifPath}, files...) {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) && path != adifPath {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if err := addBackupFile(tw, filepath.Base(path), data, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	images, err := store.List()
+	if err != nil {
+		return fmt.Errorf("failed to list maps: %w", err)
+	}
+	for _, img := range images {
+		data, info, err := store.Get(img.Name)
+		if err != nil {
+			// Purged since it was listed
+			continue
+		}
+		if err := addBackupFile(tw, "maps/"+img.Name, data, info.Modified); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finish backup: %w", err)
+	}
+	return gz.Close()
+}
+
+// addBackupFile writes one file to the tarball
+func addBackupFile(tw *tar.Writer, name string, data []byte, modified time.Time) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: modified,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("failed to add %s to backup: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to add %s to backup: %w", name, err)
+	}
+	return nil
+}
+
+// newBackupHandler streams a backup tarball, e.g. for
+// curl -H "Authorization: Bearer TOKEN" -o backup.tar.gz https://qsl.example/api/v1/backup
+func newBackupHandler(rp *ReloadableParser) flamego.Handler {
+	return func(c flamego.Context, store imageStore) {
+		w := c.ResponseWriter()
+
+		// A large map cache takes longer than the server write timeout, so
+		// refuse rather than send a tarball that may be cut short
+		if err := extendWriteDeadline(c.Request().Request, backupWriteTimeout); err != nil {
+			log.Printf("Refusing backup, can't extend the write timeout: %v", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+
+		fileName := "qsl-backup-" + time.Now().UTC().Format("20060102-150405") + ".tar.gz"
+		w.Header().Set("Content-Type", "application/gzip")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+fileName+`"`)
+		w.Header().Set("Cache-Control", "no-store")
+
+		// The status is already sent, so a failure leaves a truncated
+		// tarball that won't extract
+		if err := writeBackup(w, rp.filePath, backupFiles, store); err != nil {
+			log.Printf("Failed to write backup: %v", err)
+			return
+		}
+		log.Printf("Backup downloaded by %s", clientIP(c.Request().Request))
+	}
+}
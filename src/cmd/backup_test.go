@@ -0,0 +1,116 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flamego/flamego"
+)
+
+func TestWriteBackup(t *testing.T) {
+	dir := t.TempDir()
+	adifPath := filepath.Join(dir, "log.adi")
+	if err := os.WriteFile(adifPath, []byte("<EOH>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	corrections := filepath.Join(dir, "qsl-corrections.json")
+	if err := os.WriteFile(corrections, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	store := diskImageStore{dir: t.TempDir()}
+	if err := store.Put("a.png", []byte("png")); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	missing := filepath.Join(dir, "qsl-requests.json")
+	if err := writeBackup(&buf, adifPath, []string{corrections, missing}, store); err != nil {
+		t.Fatalf("writeBackup failed: %v", err)
+	}
+
+	gz, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatalf("Backup is not gzipped: %v", err)
+	}
+	tr := tar.NewReader(gz)
+	got := make(map[string]string)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Backup is not a tarball: %v", err)
+		}
+		data, _ := io.ReadAll(tr)
+		got[hdr.Name] = string(data)
+	}
+
+	want := map[string]string{"log.adi": "<EOH>", "qsl-corrections.json": "{}", "maps/a.png": "png"}
+	if len(got) != len(want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	for name, data := range want {
+		if got[name] != data {
+			t.Errorf("Expected %s to hold %q, got %q", name, data, got[name])
+		}
+	}
+
+	if err := writeBackup(io.Discard, missing, nil, store); err == nil {
+		t.Error("Expected a missing ADIF file to fail the backup")
+	}
+}
+
+func TestBackupHandlerWriteDeadline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.adi")
+	if err := os.WriteFile(path, []byte(followHeader), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rp, err := NewReloadableParser(path)
+	if err != nil {
+		t.Fatalf("NewReloadableParser: %v", err)
+	}
+
+	f := flamego.New()
+	f.MapTo(diskImageStore{dir: t.TempDir()}, (*imageStore)(nil))
+	f.Get("/backup", newBackupHandler(rp))
+
+	// Without the server's deadline middleware the backup is refused
+	rec := httptest.NewRecorder()
+	f.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/backup", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Expected the backup to be refused, got %d", rec.Code)
+	}
+
+	srv := httptest.NewUnstartedServer(nil)
+	srv.Config = listener{}.newServer(f, false)
+	srv.Start()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected the backup to be served, got %d", resp.StatusCode)
+	}
+	gz, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		t.Fatalf("Backup is not gzipped: %v", err)
+	}
+	if _, err := tar.NewReader(gz).Next(); err != nil {
+		t.Errorf("Backup is not a tarball: %v", err)
+	}
+}
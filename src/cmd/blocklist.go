@@ -0,0 +1,94 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/flamego/csrf"
+	"github.com/flamego/flamego"
+	"github.com/flamego/template"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// blockDurations are the expiry options offered when blocking, in order
+var blockDurations = []struct {
+	Value    string
+	Label    string
+	Duration time.Duration
+}{
+	{"1h", "1 hour", time.Hour},
+	{"24h", "1 day", 24 * time.Hour},
+	{"168h", "1 week", 7 * 24 * time.Hour},
+	{"720h", "30 days", 30 * 24 * time.Hour},
+	{"never", "Never", 0},
+}
+
+// newBlockListMiddleware returns a middleware rejecting requests from blocked
+// addresses. The admin area stays reachable so a mistaken block can be undone.
+func newBlockListMiddleware(blocks *utils.BlockList) flamego.Handler {
+	return func(c flamego.Context) {
+		if strings.HasPrefix(c.Request().URL.Path, "/admin") {
+			return
+		}
+		if blocks.Blocked(clientIP(c.Request().Request)) {
+			http.Error(c.ResponseWriter(), "Forbidden", http.StatusForbidden)
+		}
+	}
+}
+
+// handleAdminBlockList lists blocked addresses
+func handleAdminBlockList(c flamego.Context, t template.Template, data template.Data, x csrf.CSRF, blocks *utils.BlockList) {
+	data["Title"] = "Admin: Block List"
+	data["CSRFToken"] = x.Token()
+	data["Blocks"] = blocks.List()
+	data["BlockDurations"] = blockDurations
+	data["BlockError"] = c.Query("error")
+	data["Prefill"] = c.Query("ip")
+	t.HTML(http.StatusOK, "admin-blocklist")
+}
+
+// handleAdminBlockAdd blocks an IP address or network
+func handleAdminBlockAdd(c flamego.Context, blocks *utils.BlockList) {
+	r := c.Request().Request
+
+	var duration time.Duration
+	valid := false
+	for _, d := range blockDurations {
+		if d.Value == r.FormValue("expires") {
+			duration, valid = d.Duration, true
+		}
+	}
+	if !valid {
+		c.Redirect("/admin/blocklist?error="+url.QueryEscape("invalid expiry"), http.StatusFound)
+		return
+	}
+
+	block, err := blocks.Add(r.FormValue("prefix"), strings.TrimSpace(r.FormValue("reason")), duration)
+	if err != nil {
+		c.Redirect("/admin/blocklist?error="+url.QueryEscape(err.Error()), http.StatusFound)
+		return
+	}
+
+	log.Printf("Blocked %s", block.Prefix)
+	c.Redirect("/admin/blocklist", http.StatusFound)
+}
+
+// handleAdminBlockRemove unblocks an IP address or network
+func handleAdminBlockRemove(c flamego.Context, blocks *utils.BlockList) {
+	prefix := c.Request().FormValue("prefix")
+	if err := blocks.Remove(prefix); err != nil {
+		c.Redirect("/admin/blocklist?error="+url.QueryEscape(err.Error()), http.StatusFound)
+		return
+	}
+
+	log.Printf("Unblocked %s", prefix)
+	c.Redirect("/admin/blocklist", http.StatusFound)
+}
@@ -0,0 +1,43 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/flamego/flamego"
+	"github.com/flamego/template"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// callsignPath returns the history page path for a callsign
+func callsignPath(callsign string) string {
+	return "/call/" + url.PathEscape(strings.ToUpper(callsign))
+}
+
+// handleCallsignHistory shows a timeline of every QSO with a station
+func handleCallsignHistory(c flamego.Context, t template.Template, data template.Data, store utils.QSOStore, cfg *siteConfig, l *localizer) {
+	callsign, err := url.PathUnescape(c.Param("call"))
+	if err != nil {
+		renderError(t, data, l, http.StatusNotFound, "", false)
+		return
+	}
+	callsign = strings.ToUpper(strings.TrimSpace(callsign))
+
+	timeline := store.GetCallsignTimeline(callsign)
+	if len(timeline) == 0 {
+		renderError(t, data, l, http.StatusNotFound, callsign, false)
+		return
+	}
+
+	data["Title"] = l.T("callsign.title", callsign)
+	data["Callsign"] = callsign
+	data["Timeline"] = timeline
+	data["Canonical"] = cfg.baseURL(c.Request().Request) + callsignPath(callsign)
+	t.HTML(http.StatusOK, "callsign")
+}
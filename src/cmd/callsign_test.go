@@ -0,0 +1,98 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	gotemplate "html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flamego/flamego"
+	"github.com/flamego/template"
+
+	"github.com/humaidq/humaid-qsl/locales"
+	"github.com/humaidq/humaid-qsl/static"
+	"github.com/humaidq/humaid-qsl/templates"
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// newPageRouter returns a router rendering the embedded page templates in
+// English, with store and cfg mapped like the server does
+func newPageRouter(t *testing.T, store utils.QSOStore, cfg *siteConfig) *flamego.Flame {
+	t.Helper()
+	catalog, err := utils.LoadCatalog(locales.Locales)
+	if err != nil {
+		t.Fatalf("LoadCatalog failed: %v", err)
+	}
+	assets, err := newAssetManifest(static.Static)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fsys, err := template.EmbedFS(templates.Templates, ".", []string{".html"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := flamego.New()
+	f.Use(template.Templater(template.Options{FileSystem: fsys, FuncMaps: []gotemplate.FuncMap{templateFuncs(assets, catalog, cfg)}}))
+	f.Map(cfg)
+	f.MapTo(store, (*utils.QSOStore)(nil))
+	f.Use(func(c flamego.Context, data template.Data) {
+		c.Map(&localizer{catalog: catalog, locale: "en", units: unitsMetric})
+		data["Locale"] = "en"
+		data["Dir"] = utils.Direction("en")
+		data["Locales"] = catalog.Locales()
+	})
+	return f
+}
+
+func TestCallsignHistory(t *testing.T) {
+	parser := &utils.ADIFParser{QSOs: []utils.QSO{
+		{Call: "A66H/P", Band: "20m", Mode: "FT8", Timestamp: time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC)},
+		{Call: "A66H/P", Band: "40m", Mode: "CW", Timestamp: time.Date(2025, 3, 2, 18, 0, 0, 0, time.UTC)},
+		{Call: "A66H", Band: "20m", Mode: "SSB", Timestamp: time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)},
+	}}
+	f := newPageRouter(t, parser, &siteConfig{BaseURL: "https://qsl.example"})
+	f.Get("/call/{call: **}", handleCallsignHistory)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		f.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	// Portable calls work whether or not the slash is escaped
+	for _, path := range []string{"/call/A66H/P", "/call/a66h%2Fp"} {
+		rec := get(path)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s = %d", path, rec.Code)
+		}
+		body := rec.Body.String()
+		if got := strings.Count(body, `<li class="entry">`); got != 2 {
+			t.Errorf("GET %s: expected 2 QSOs with A66H/P, got %d", path, got)
+		}
+		if !strings.Contains(body, `href="/qso/A66H/P/1740832200"`) {
+			t.Errorf("GET %s: expected a link to the first QSO", path)
+		}
+		if !strings.Contains(body, "https://qsl.example/call/A66H%2FP") {
+			t.Errorf("GET %s: expected the canonical URL with the slash escaped", path)
+		}
+	}
+
+	if rec := get("/call/A66H"); rec.Code != http.StatusOK || strings.Count(rec.Body.String(), `<li class="entry">`) != 1 {
+		t.Errorf("Expected only the QSO with A66H itself, got %d", rec.Code)
+	}
+
+	rec := get("/call/W1AW")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a station never worked, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), `value="W1AW"`) {
+		t.Error("Expected the search form to be filled with the callsign")
+	}
+}
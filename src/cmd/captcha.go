@@ -0,0 +1,86 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// searchCaptcha asks clients to solve a CAPTCHA before searching, either
+// always or once they have searched more than a threshold within an hour. A
+// nil searchCaptcha never asks.
+type searchCaptcha struct {
+	verifier  *utils.CaptchaVerifier
+	threshold int
+	searches  *utils.RateLimiter // Recent searches per client, when thresholded
+}
+
+// captchaWidget is what the search form needs to render the CAPTCHA
+type captchaWidget struct {
+	ScriptURL   string
+	WidgetClass string
+	SiteKey     string
+}
+
+// newSearchCaptcha builds the search CAPTCHA from command line flags,
+// returning nil when no provider is configured
+func newSearchCaptcha(cmd *cli.Command) (*searchCaptcha, error) {
+	provider := cmd.String("captcha-provider")
+	if provider == "" {
+		return nil, nil
+	}
+
+	verifier, err := utils.NewCaptchaVerifier(provider, cmd.String("captcha-site-key"), cmd.String("captcha-secret"))
+	if err != nil {
+		return nil, err
+	}
+
+	sc := &searchCaptcha{verifier: verifier, threshold: cmd.Int("captcha-threshold")}
+	if sc.threshold > 0 {
+		sc.searches = utils.NewRateLimiter(sc.threshold, time.Hour)
+	}
+	return sc, nil
+}
+
+// required reports whether the client must solve the CAPTCHA to search
+func (sc *searchCaptcha) required(ip string) bool {
+	if sc == nil {
+		return false
+	}
+	return sc.searches == nil || sc.searches.Count(ip) >= sc.threshold
+}
+
+// widget returns the CAPTCHA widget for the client, or nil if it doesn't
+// need to solve one
+func (sc *searchCaptcha) widget(ip string) *captchaWidget {
+	if !sc.required(ip) {
+		return nil
+	}
+	return &captchaWidget{
+		ScriptURL:   sc.verifier.ScriptURL(),
+		WidgetClass: sc.verifier.WidgetClass(),
+		SiteKey:     sc.verifier.SiteKey(),
+	}
+}
+
+// check verifies the CAPTCHA on a search request if the client needs one,
+// and counts the search towards the threshold
+func (sc *searchCaptcha) check(r *http.Request) error {
+	if sc == nil {
+		return nil
+	}
+
+	ip := clientIP(r)
+	if sc.required(ip) {
+		return sc.verifier.Verify(r.Context(), r.FormValue(sc.verifier.ResponseField()), ip)
+	}
+	sc.searches.Allow(ip)
+	return nil
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+func TestSearchCaptchaThreshold(t *testing.T) {
+	verifier, err := utils.NewCaptchaVerifier("turnstile", "site", "secret")
+	if err != nil {
+		t.Fatalf("NewCaptchaVerifier: %v", err)
+	}
+	sc := &searchCaptcha{verifier: verifier, threshold: 2, searches: utils.NewRateLimiter(2, time.Hour)}
+
+	r := httptest.NewRequest("POST", "/", nil)
+	ip := clientIP(r)
+	for i := 0; i < 2; i++ {
+		if sc.widget(ip) != nil {
+			t.Fatalf("CAPTCHA shown before the threshold, search %d", i+1)
+		}
+		if err := sc.check(r); err != nil {
+			t.Fatalf("search %d under the threshold failed: %v", i+1, err)
+		}
+	}
+
+	if w := sc.widget(ip); w == nil || w.SiteKey != "site" {
+		t.Errorf("widget above the threshold = %+v", w)
+	}
+	if err := sc.check(r); !errors.Is(err, utils.ErrCaptchaMissing) {
+		t.Errorf("check above the threshold = %v, want ErrCaptchaMissing", err)
+	}
+
+	// Other clients are unaffected
+	if sc.required("192.0.2.99") {
+		t.Errorf("CAPTCHA required for a new client")
+	}
+}
+
+func TestSearchCaptchaDisabled(t *testing.T) {
+	var sc *searchCaptcha
+	if sc.widget("192.0.2.1") != nil {
+		t.Errorf("disabled CAPTCHA shows a widget")
+	}
+	if err := sc.check(httptest.NewRequest("POST", "/", nil)); err != nil {
+		t.Errorf("disabled CAPTCHA check = %v", err)
+	}
+}
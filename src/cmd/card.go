@@ -0,0 +1,153 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/flamego/flamego"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// cardFileNameFor returns the cached share card file name for a QSO, kept
+// beside its map
+func cardFileNameFor(qso utils.QSO) string {
+	return strings.TrimSuffix(mapFileNameFor(qso), ".png") + ".card.png"
+}
+
+// cardRenderer composes share cards from QSO details and the rendered map.
+// Cards are drawn one at a time; the maps they need go through the map
+// render queue.
+type cardRenderer struct {
+	store imageStore
+	maps  *mapRenderer
+	mutex sync.Mutex
+}
+
+func newCardRenderer(store imageStore, maps *mapRenderer) *cardRenderer {
+	return &cardRenderer{store: store, maps: maps}
+}
+
+// Render draws the card for a QSO unless it is already cached, returning its
+// file name in the image store
+func (r *cardRenderer) Render(qso utils.QSO) (string, error) {
+	cardFileName := cardFileNameFor(qso)
+	if r.store.Exists(cardFileName) {
+		return cardFileName, nil
+	}
+
+	card := newConfirmationCard(qso)
+	if canMapQSO(qso) {
+		mapFileName := mapFileNameFor(qso)
+		if err := r.maps.Render(mapFileName, qso); err == nil {
+			if img, err := loadPNG(r.store, mapFileName); err == nil {
+				card.Map = img
+			} else {
+				log.Printf("Failed to load map for card %s: %v", cardFileName, err)
+			}
+		}
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	// Another request may have drawn it while this one waited
+	if r.store.Exists(cardFileName) {
+		return cardFileName, nil
+	}
+	img, err := utils.DrawConfirmationCard(card)
+	if err != nil {
+		return "", err
+	}
+	data, err := utils.EncodePNG(img)
+	if err != nil {
+		return "", err
+	}
+	if err := r.store.Put(cardFileName, data); err != nil {
+		return "", err
+	}
+	return cardFileName, nil
+}
+
+// newConfirmationCard returns the card content for a QSO
+func newConfirmationCard(qso utils.QSO) utils.ConfirmationCard {
+	myCall := qso.StationCall
+	if myCall == "" {
+		myCall = "A66H"
+	}
+
+	var details []string
+	for _, d := range []string{qso.Band, qso.Mode} {
+		if d != "" {
+			details = append(details, d)
+		}
+	}
+	if qso.Freq != "" {
+		details = append(details, qso.Freq+" MHz")
+	}
+
+	card := utils.ConfirmationCard{
+		MyCall:      strings.ToUpper(myCall),
+		Call:        qso.Call,
+		Country:     qso.Country,
+		CountryCode: strings.ToUpper(qso.GetFlagCode()),
+		Details:     strings.Join(details, " · "),
+		When:        fmt.Sprintf("%s %s UTC", qso.FormatDate(), qso.FormatTime()),
+	}
+	if canMapQSO(qso) {
+		if km, err := utils.Distance(qso.MyGridSquare, qso.GridSquare); err == nil {
+			card.Footer = fmt.Sprintf("%s → %s · %.0f km", qso.MyGridSquare, qso.GridSquare, km)
+		}
+	}
+	return card
+}
+
+// loadPNG decodes a PNG from the image store
+func loadPNG(store imageStore, name string) (image.Image, error) {
+	data, _, err := store.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return png.Decode(bytes.NewReader(data))
+}
+
+// loadPNGFile decodes a PNG file from disk
+func loadPNGFile(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
+
+// handleConfirmationCard serves the share card for a QSO, drawing it on the
+// first request
+func handleConfirmationCard(c flamego.Context, w http.ResponseWriter, store utils.QSOStore, cfg *siteConfig, cards *cardRenderer) (int, error) {
+	qso, ok := cfg.findQSO(c, store)
+	if !ok {
+		return http.StatusNotFound, nil
+	}
+
+	cardFileName, err := cards.Render(qso)
+	if err != nil {
+		log.Printf("Failed to generate card for %s: %v", confirmationPath(qso), err)
+		return http.StatusInternalServerError, nil
+	}
+	if err := serveMap(w, c.Request().Request, cards.store, cardFileName); err != nil {
+		log.Printf("Failed to serve card %s: %v", cardFileName, err)
+		return http.StatusInternalServerError, nil
+	}
+	return http.StatusOK, nil
+}
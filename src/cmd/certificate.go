@@ -0,0 +1,158 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/flamego/flamego"
+	"github.com/urfave/cli/v3"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// CmdCertificate generates award certificate PDFs from an ADIF file
+var CmdCertificate = &cli.Command{
+	Name:  "certificate",
+	Usage: "List earned award certificates or write one as a PDF",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "adif",
+			Usage:    "path to ADIF file containing QSO logs",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  "id",
+			Usage: "certificate to write, e.g. dxcc-100 (lists earned certificates if empty)",
+		},
+		&cli.StringFlag{
+			Name:  "output",
+			Usage: "path to write the PDF to (defaults to <id>.pdf)",
+		},
+		&cli.StringFlag{
+			Name:  "callsign",
+			Value: "A66H",
+			Usage: "station callsign printed on the certificate",
+		},
+		&cli.StringFlag{
+			Name:  "base-url",
+			Usage: "public base URL printed in the certificate footer",
+		},
+		&cli.StringFlag{
+			Name:  "certificate-operator",
+			Usage: "operator name printed on certificates under the callsign",
+		},
+		&cli.StringFlag{
+			Name:  "certificate-logo",
+			Usage: "path to a PNG logo printed at the top of certificates",
+		},
+	},
+	Action: writeCertificate,
+}
+
+func writeCertificate(ctx context.Context, cmd *cli.Command) error {
+	file, err := os.Open(cmd.String("adif"))
+	if err != nil {
+		return fmt.Errorf("failed to open ADIF file: %w", err)
+	}
+	defer file.Close()
+
+	parser := utils.NewADIFParser()
+	if err := parser.ParseFile(file); err != nil {
+		return fmt.Errorf("failed to parse ADIF file: %w", err)
+	}
+
+	id := cmd.String("id")
+	if id == "" {
+		listCertificates(os.Stdout, utils.EarnedCertificates(parser.GetQSOs()))
+		return nil
+	}
+	cert, ok := utils.FindCertificate(parser.GetQSOs(), id)
+	if !ok {
+		return fmt.Errorf("certificate %s has not been earned", id)
+	}
+
+	branding, err := newCertificateBranding(cmd)
+	if err != nil {
+		return err
+	}
+
+	output := cmd.String("output")
+	if output == "" {
+		output = id + ".pdf"
+	}
+	out, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", output, err)
+	}
+	if err := utils.WriteCertificatePDF(out, cert, branding); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to write certificate: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to write certificate: %w", err)
+	}
+	fmt.Printf("Wrote %s (%s)\n", output, cert.Title)
+	return nil
+}
+
+// listCertificates writes the earned certificates, one per line
+func listCertificates(w io.Writer, certs []utils.Certificate) {
+	if len(certs) == 0 {
+		fmt.Fprintln(w, "No certificates earned yet")
+		return
+	}
+	for _, cert := range certs {
+		fmt.Fprintf(w, "%-16s %s  %s\n", cert.ID, cert.Earned.UTC().Format("2006-01-02"), cert.Title)
+	}
+}
+
+// newCertificateBranding builds the certificate branding from command line
+// flags, loading the logo if one is given
+func newCertificateBranding(cmd *cli.Command) (utils.CertificateBranding, error) {
+	branding := utils.CertificateBranding{
+		Callsign: strings.ToUpper(cmd.String("callsign")),
+		Operator: cmd.String("certificate-operator"),
+	}
+	if baseURL := cmd.String("base-url"); baseURL != "" {
+		site := strings.TrimPrefix(strings.TrimPrefix(baseURL, "https://"), "http://")
+		branding.Site = strings.TrimSuffix(site, "/")
+	}
+	if path := cmd.String("certificate-logo"); path != "" {
+		logo, err := loadPNGFile(path)
+		if err != nil {
+			return branding, fmt.Errorf("failed to load certificate logo: %w", err)
+		}
+		branding.Logo = logo
+	}
+	return branding, nil
+}
+
+// newCertificateHandler returns a handler serving an earned certificate as a
+// PDF download
+func newCertificateHandler(branding utils.CertificateBranding) flamego.Handler {
+	return func(c flamego.Context, store utils.QSOStore) {
+		id := strings.TrimSuffix(c.Param("id"), ".pdf")
+		cert, ok := utils.FindCertificate(store.GetQSOs(), id)
+		if !ok {
+			c.Redirect("/awards", http.StatusFound)
+			return
+		}
+
+		w := c.ResponseWriter()
+		fileName := strings.ReplaceAll(branding.Callsign+"-"+cert.ID, "/", "_") + ".pdf"
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+fileName+`"`)
+		if err := utils.WriteCertificatePDF(w, cert, branding); err != nil {
+			log.Printf("Failed to write certificate %s: %v", cert.ID, err)
+		}
+	}
+}
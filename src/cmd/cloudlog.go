@@ -0,0 +1,152 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/flamego/flamego"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// cloudlogQSORequest is the body of a Cloudlog api/qso request. The key is
+// checked against the API token, and the station profile is ignored since
+// this site has a single log.
+type cloudlogQSORequest struct {
+	Key              string `json:"key"`
+	StationProfileID string `json:"station_profile_id"`
+	Type             string `json:"type"`
+	String           string `json:"string"`
+}
+
+// cloudlogResponse is a Cloudlog API response
+type cloudlogResponse struct {
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+	Type   string `json:"type,omitempty"`
+	String string `json:"string,omitempty"`
+}
+
+// cloudlogFailed writes a Cloudlog API error response
+func cloudlogFailed(w http.ResponseWriter, status int, reason string) {
+	writeAPIJSON(w, status, cloudlogResponse{Status: "failed", Reason: reason})
+}
+
+// parseCloudlogADIF parses the QSOs in a Cloudlog request's ADIF string,
+// which some loggers send without a header
+func parseCloudlogADIF(adif string) ([]utils.QSO, error) {
+	parser := utils.NewADIFParser()
+	if err := parser.ParseFile(strings.NewReader(adif)); err != nil {
+		return nil, err
+	}
+	if len(parser.Warnings) > 0 {
+		return nil, errors.New(parser.Warnings[0])
+	}
+	if len(parser.QSOs) == 0 {
+		return nil, utils.ErrNoADIFRecord
+	}
+	for _, qso := range parser.QSOs {
+		if qso.Timestamp.IsZero() {
+			return nil, errors.New("QSO has no valid date and time")
+		}
+	}
+	return parser.QSOs, nil
+}
+
+// newCloudlogQSOHandler returns a handler implementing Cloudlog's api/qso, so
+// WSJT-X bridges and other integrations written for Cloudlog can log QSOs
+// here unchanged. QSOs already in the log are skipped rather than refused,
+// as loggers may resend them.
+func newCloudlogQSOHandler(api *apiAuth, rp *ReloadableParser) flamego.Handler {
+	return func(c flamego.Context) {
+		w := c.ResponseWriter()
+		r := c.Request().Request
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, apiMaxQSOSize))
+		if err != nil {
+			cloudlogFailed(w, http.StatusRequestEntityTooLarge, "request too large")
+			return
+		}
+		var req cloudlogQSORequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			cloudlogFailed(w, http.StatusBadRequest, "wrong JSON")
+			return
+		}
+
+		switch api.check(clientIP(r), req.Key) {
+		case http.StatusOK:
+		case http.StatusTooManyRequests:
+			cloudlogFailed(w, http.StatusTooManyRequests, "too many failed attempts")
+			return
+		default:
+			cloudlogFailed(w, http.StatusUnauthorized, "missing api key")
+			return
+		}
+
+		if req.Type != "adif" {
+			cloudlogFailed(w, http.StatusBadRequest, "unsupported type, expected adif")
+			return
+		}
+		qsos, err := parseCloudlogADIF(req.String)
+		if err != nil {
+			cloudlogFailed(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		for _, qso := range qsos {
+			if err := rp.appendQSO(qso); errors.Is(err, errQSOExists) {
+				continue
+			} else if err != nil {
+				log.Printf("Failed to append Cloudlog API QSO with %s: %v", qso.Call, err)
+				cloudlogFailed(w, http.StatusInternalServerError, "failed to save QSO")
+				return
+			}
+			log.Printf("Cloudlog API QSO with %s added by %s", qso.Call, clientIP(r))
+		}
+
+		writeAPIJSON(w, http.StatusCreated, cloudlogResponse{Status: "created", Type: req.Type, String: req.String})
+	}
+}
+
+// cloudlogAuth is Cloudlog's api/auth response, used by loggers to test a key
+type cloudlogAuth struct {
+	XMLName xml.Name `xml:"auth"`
+	Status  string   `xml:"status"`
+	Rights  string   `xml:"rights,omitempty"`
+}
+
+// maskAPIKeyPath hides the key in Cloudlog api/auth paths so it isn't
+// written to the access log
+func maskAPIKeyPath(path string) string {
+	if i := strings.Index(path, "/api/auth/"); i != -1 {
+		return path[:i] + "/api/auth/***"
+	}
+	return path
+}
+
+// newCloudlogAuthHandler returns a handler implementing Cloudlog's api/auth
+// key check
+func newCloudlogAuthHandler(api *apiAuth) flamego.Handler {
+	return func(c flamego.Context) {
+		w := c.ResponseWriter()
+		auth := cloudlogAuth{Status: "Valid", Rights: "rw"}
+		if api.check(clientIP(c.Request().Request), c.Param("key")) != http.StatusOK {
+			auth = cloudlogAuth{Status: "Key Invalid"}
+		}
+
+		w.Header().Set("Content-Type", "application/xml")
+		w.Header().Set("Cache-Control", "no-store")
+		if err := xml.NewEncoder(w).Encode(auth); err != nil {
+			log.Printf("Failed to write API response: %v", err)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import "testing"
+
+func TestParseCloudlogADIF(t *testing.T) {
+	qsos, err := parseCloudlogADIF("<call:4>W1AW <gridsquare:4>FN31 <mode:3>FT8 <rst_sent:3>-10 <rst_rcvd:3>-12 <qso_date:8>20250301 <time_on:6>120500 <band:3>20m <eor>")
+	if err != nil {
+		t.Fatalf("parseCloudlogADIF: %v", err)
+	}
+	if len(qsos) != 1 || qsos[0].Call != "W1AW" || qsos[0].Timestamp.IsZero() {
+		t.Errorf("unexpected QSOs %+v", qsos)
+	}
+
+	for _, adif := range []string{"", "<call:4>W1AW <eor>", "<call:4>W1AW <qso_date:8>20250301 <eor>"} {
+		if _, err := parseCloudlogADIF(adif); err == nil {
+			t.Errorf("parseCloudlogADIF(%q) accepted", adif)
+		}
+	}
+}
+
+func TestMaskAPIKeyPath(t *testing.T) {
+	tests := map[string]string{
+		"/index.php/api/auth/secret": "/index.php/api/auth/***",
+		"/api/auth/secret":           "/api/auth/***",
+		"/api/qso":                   "/api/qso",
+	}
+	for path, want := range tests {
+		if got := maskAPIKeyPath(path); got != want {
+			t.Errorf("maskAPIKeyPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
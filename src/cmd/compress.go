@@ -0,0 +1,175 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// defaultCompressTypes are the content types compressed unless configured
+// otherwise. Images other than SVG are already compressed.
+var defaultCompressTypes = []string{
+	"text/html",
+	"text/css",
+	"text/plain",
+	"application/json",
+	"application/xml",
+	"image/svg+xml",
+}
+
+// newCompressHandler wraps h to gzip or deflate responses whose content type
+// is one of types, depending on what the client accepts
+func newCompressHandler(h http.Handler, types []string) http.Handler {
+	if len(types) == 0 {
+		return h
+	}
+
+	allowed := make(map[string]bool, len(types))
+	for _, t := range types {
+		allowed[strings.ToLower(strings.TrimSpace(t))] = true
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Leave byte ranges alone, as they refer to the uncompressed body
+		if r.Method == http.MethodHead || r.Header.Get("Range") != "" {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressResponseWriter{
+			ResponseWriter: w,
+			encoding:       negotiateEncoding(r.Header.Get("Accept-Encoding")),
+			types:          allowed,
+		}
+		defer cw.Close()
+		h.ServeHTTP(cw, r)
+	})
+}
+
+// negotiateEncoding picks gzip or deflate from an Accept-Encoding header,
+// preferring gzip, or returns an empty string if neither is acceptable
+func negotiateEncoding(header string) string {
+	accepted := make(map[string]bool)
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		accepted[name] = q > 0
+	}
+
+	for _, enc := range []string{"gzip", "deflate"} {
+		if accepted[enc] {
+			return enc
+		}
+	}
+	return ""
+}
+
+// compressResponseWriter decides on the first write whether to compress,
+// based on the response's content type
+type compressResponseWriter struct {
+	http.ResponseWriter
+	encoding string
+	types    map[string]bool
+
+	decided    bool
+	compressor io.WriteCloser
+	flusher    interface{ Flush() error }
+}
+
+func (cw *compressResponseWriter) decide(status int) {
+	if cw.decided {
+		return
+	}
+	cw.decided = true
+
+	h := cw.Header()
+	if h.Get("Content-Encoding") != "" || status == http.StatusNoContent || status == http.StatusNotModified {
+		return
+	}
+	mediaType, _, err := mime.ParseMediaType(h.Get("Content-Type"))
+	if err != nil || !cw.types[mediaType] {
+		return
+	}
+
+	h.Add("Vary", "Accept-Encoding")
+	if cw.encoding == "" {
+		return
+	}
+
+	h.Del("Content-Length")
+	h.Set("Content-Encoding", cw.encoding)
+	switch cw.encoding {
+	case "gzip":
+		gz := gzip.NewWriter(cw.ResponseWriter)
+		cw.compressor, cw.flusher = gz, gz
+	case "deflate":
+		fw, _ := flate.NewWriter(cw.ResponseWriter, flate.DefaultCompression)
+		cw.compressor, cw.flusher = fw, fw
+	}
+}
+
+func (cw *compressResponseWriter) WriteHeader(status int) {
+	cw.decide(status)
+	cw.ResponseWriter.WriteHeader(status)
+}
+
+func (cw *compressResponseWriter) Write(b []byte) (int, error) {
+	if !cw.decided {
+		if cw.Header().Get("Content-Type") == "" {
+			cw.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.compressor != nil {
+		return cw.compressor.Write(b)
+	}
+	return cw.ResponseWriter.Write(b)
+}
+
+// Flush sends any buffered compressed data, so streamed responses still
+// arrive promptly
+func (cw *compressResponseWriter) Flush() {
+	if cw.flusher != nil {
+		cw.flusher.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack hands the connection over for WebSocket upgrades. flamego's
+// writer only looks for http.Hijacker on the writer it wraps, so this can't
+// be left to Unwrap.
+func (cw *compressResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(cw.ResponseWriter).Hijack()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (cw *compressResponseWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// Close finishes the compressed stream
+func (cw *compressResponseWriter) Close() error {
+	if cw.compressor != nil {
+		return cw.compressor.Close()
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := map[string]string{
+		"":                        "",
+		"gzip":                    "gzip",
+		"deflate, gzip":           "gzip",
+		"deflate":                 "deflate",
+		"gzip;q=0, deflate":       "deflate",
+		"br, gzip;q=0.5":          "gzip",
+		"identity":                "",
+		"GZIP;q=0.1, deflate;q=0": "gzip",
+	}
+
+	for header, want := range tests {
+		if got := negotiateEncoding(header); got != want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestCompressHandler(t *testing.T) {
+	body := strings.Repeat("<p>QSO</p>", 200)
+	h := newCompressHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/map.png":
+			w.Header().Set("Content-Type", "image/png")
+		case "/page":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		}
+		io.WriteString(w, body)
+	}), defaultCompressTypes)
+
+	tests := []struct {
+		path, accept, wantEncoding string
+		decode                     func(io.Reader) (io.Reader, error)
+	}{
+		{"/page", "gzip", "gzip", func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }},
+		{"/page", "deflate", "deflate", func(r io.Reader) (io.Reader, error) { return flate.NewReader(r), nil }},
+		{"/page", "", "", nil},
+		{"/map.png", "gzip", "", nil},
+		{"/sniffed", "gzip", "gzip", func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		if tt.accept != "" {
+			req.Header.Set("Accept-Encoding", tt.accept)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		if got := rec.Header().Get("Content-Encoding"); got != tt.wantEncoding {
+			t.Errorf("%s with %q: Content-Encoding = %q, want %q", tt.path, tt.accept, got, tt.wantEncoding)
+			continue
+		}
+
+		var r io.Reader = rec.Body
+		if tt.decode != nil {
+			var err error
+			if r, err = tt.decode(rec.Body); err != nil {
+				t.Fatalf("%s: failed to open compressed body: %v", tt.path, err)
+			}
+		}
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("%s: failed to read body: %v", tt.path, err)
+		}
+		if string(got) != body {
+			t.Errorf("%s with %q: body did not round trip", tt.path, tt.accept)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// siteConfig holds site-wide settings needed by handlers, injected into the
+// request context
+type siteConfig struct {
+	BaseURL  string // Public base URL, e.g. https://qsl.huma.id (optional)
+	Callsign string // Station callsign shown on share previews
+
+	IndexConfirmations bool // Allow search engines to index QSO confirmation pages
+	Awards             bool // Show the public awards progress page
+	Contests           bool // Show the public contest pages
+	Stats              bool // Show the public statistics page
+
+	OnAirWindow time.Duration // How recently a QSO must be logged to be on air
+
+	StrictMatch bool // Searches must give the exact logged minute
+	StrictBand  bool // Strict searches must also give the band
+	StrictMode  bool // Strict searches must also give the mode
+
+	Private         bool   // Require signed tokens in confirmation links
+	confirmationKey []byte // Key signing confirmation links in private mode
+
+	redactor *utils.Redactor // Hides personal QSO fields on public pages
+}
+
+// newSiteConfig builds the site configuration from command line flags
+func newSiteConfig(cmd *cli.Command) *siteConfig {
+	return &siteConfig{
+		BaseURL:            strings.TrimSuffix(cmd.String("base-url"), "/"),
+		Callsign:           strings.ToUpper(strings.TrimSpace(cmd.String("callsign"))),
+		IndexConfirmations: cmd.Bool("index-confirmations"),
+		Awards:             cmd.Bool("awards"),
+		Contests:           cmd.Bool("contests"),
+		Stats:              cmd.Bool("stats"),
+		OnAirWindow:        cmd.Duration("on-air-window"),
+		StrictMatch:        cmd.Bool("strict-match"),
+		StrictBand:         cmd.Bool("strict-match") && cmd.Bool("strict-match-band"),
+		StrictMode:         cmd.Bool("strict-match") && cmd.Bool("strict-match-mode"),
+		Private:            cmd.Bool("private"),
+	}
+}
+
+// newStationDefaults builds the station details used for QSOs logged
+// without them from command line flags, or returns nil if none are set
+func newStationDefaults(cmd *cli.Command) (*utils.StationDefaults, error) {
+	d := &utils.StationDefaults{
+		MyGridSquare: strings.TrimSpace(cmd.String("station-grid")),
+		MyRig:        strings.TrimSpace(cmd.String("station-rig")),
+		MyAntenna:    strings.TrimSpace(cmd.String("station-antenna")),
+		TxPwr:        strings.TrimSpace(cmd.String("station-power")),
+	}
+	if *d == (utils.StationDefaults{}) {
+		return nil, nil
+	}
+
+	if d.MyGridSquare != "" {
+		if err := utils.ValidateGridSquare(d.MyGridSquare); err != nil {
+			return nil, fmt.Errorf("invalid --station-grid: %w", err)
+		}
+	}
+	if d.TxPwr != "" {
+		if _, err := strconv.ParseFloat(d.TxPwr, 64); err != nil {
+			return nil, fmt.Errorf("invalid --station-power %q: must be a number of watts", d.TxPwr)
+		}
+	}
+	return d, nil
+}
+
+// baseURL returns the configured public base URL, falling back to the scheme
+// and host the request was made to
+func (cfg *siteConfig) baseURL(r *http.Request) string {
+	if cfg.BaseURL != "" {
+		return cfg.BaseURL
+	}
+
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return (&url.URL{Scheme: scheme, Host: r.Host}).String()
+}
+
+// redact returns a QSO field's value as it may be shown on public pages
+func (cfg *siteConfig) redact(field, value string) string {
+	return cfg.redactor.Redact(field, value)
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/flamego/csrf"
+	"github.com/flamego/flamego"
+	"github.com/flamego/session"
+	"github.com/flamego/template"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// contactStatusMessages maps the contact form result to the key of the message
+// shown to the visitor
+var contactStatusMessages = map[string]string{
+	"sent":    "contact.status.sent",
+	"invalid": "contact.status.invalid",
+	"limited": "contact.status.limited",
+	"failed":  "contact.status.failed",
+}
+
+// Limits on contact form fields
+const (
+	maxContactSubject  = 150
+	maxContactMessage  = 5000
+	maxContactCallsign = 20
+)
+
+// handleContact shows the contact form
+func handleContact(c flamego.Context, t template.Template, data template.Data, x csrf.CSRF, l *localizer) {
+	data["Title"] = l.T("contact.title")
+	data["ContactPage"] = true
+	data["CSRFToken"] = x.Token()
+	data["ContactStatus"] = c.Query("status")
+	data["ContactMessage"] = l.T(contactStatusMessages[c.Query("status")])
+	t.HTML(http.StatusOK, "contact")
+}
+
+// newContactSubmitHandler returns a handler that emails contact form messages
+// to the station. The form guard catches bots filling in every input or
+// submitting instantly, and sends are rate limited per client.
+func newContactSubmitHandler(mailer *utils.Mailer, to string) flamego.Handler {
+	clients := utils.NewRateLimiter(3, time.Hour)
+
+	return func(c flamego.Context, forms *utils.FormGuard, s session.Session) {
+		redirect := func(status string) {
+			c.Redirect("/contact?status="+status, http.StatusFound)
+		}
+
+		r := c.Request().Request
+		ip := clientIP(r)
+
+		// Pretend bots succeeded so they don't try again. A bad stamp may
+		// also be a person who left the form open for hours.
+		if err := checkFormGuard(forms, r, s); err != nil {
+			log.Printf("Dropped contact form spam from %s: %v", ip, err)
+			if errors.Is(err, utils.ErrFormStamp) {
+				redirect("invalid")
+				return
+			}
+			redirect("sent")
+			return
+		}
+
+		subject := strings.Join(strings.Fields(r.FormValue("subject")), " ")
+		message := strings.TrimSpace(r.FormValue("message"))
+		callsign := strings.ToUpper(strings.TrimSpace(r.FormValue("callsign")))
+		if subject == "" || message == "" ||
+			len(subject) > maxContactSubject ||
+			len(message) > maxContactMessage ||
+			len(callsign) > maxContactCallsign || strings.ContainsAny(callsign, " \r\n") {
+			redirect("invalid")
+			return
+		}
+
+		var replyTo string
+		if email := strings.TrimSpace(r.FormValue("email")); email != "" {
+			valid, err := utils.ValidateEmailAddress(email)
+			if err != nil {
+				redirect("invalid")
+				return
+			}
+			replyTo = valid
+		}
+
+		if !clients.Allow(ip) {
+			redirect("limited")
+			return
+		}
+
+		var b strings.Builder
+		if callsign != "" {
+			fmt.Fprintf(&b, "Callsign: %s\n", callsign)
+		}
+		if replyTo != "" {
+			fmt.Fprintf(&b, "Email:    %s\n", replyTo)
+		}
+		fmt.Fprintf(&b, "Sent:     %s UTC\n\n", time.Now().UTC().Format("2006-01-02 15:04"))
+		b.WriteString(message)
+		b.WriteString("\n")
+
+		if callsign != "" {
+			subject = callsign + ": " + subject
+		}
+		if err := mailer.SendWithReplyTo(to, replyTo, "[QSL contact] "+subject, b.String()); err != nil {
+			log.Printf("Failed to send contact message: %v", err)
+			redirect("failed")
+			return
+		}
+
+		log.Printf("Sent contact message from %s", ip)
+		redirect("sent")
+	}
+}
@@ -0,0 +1,175 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/flamego/flamego"
+	"github.com/flamego/session"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// fakeSMTP accepts mail on a local port and records the message bodies
+type fakeSMTP struct {
+	listener net.Listener
+	mutex    sync.Mutex
+	messages []string
+}
+
+func newFakeSMTP(t *testing.T) *fakeSMTP {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeSMTP{listener: l}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+		case strings.HasPrefix(cmd, "DATA"):
+			reply("354 go ahead")
+			var msg strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				msg.WriteString(line)
+			}
+			s.mutex.Lock()
+			s.messages = append(s.messages, msg.String())
+			s.mutex.Unlock()
+			reply("250 queued")
+		case strings.HasPrefix(cmd, "QUIT"):
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func (s *fakeSMTP) count() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.messages)
+}
+
+// idSession is a memory session with a fixed ID, which form stamps are
+// signed with
+type idSession struct {
+	*memorySession
+	id string
+}
+
+func (s idSession) ID() string { return s.id }
+
+func TestContactSubmitHandler(t *testing.T) {
+	smtpServer := newFakeSMTP(t)
+	host, port, _ := net.SplitHostPort(smtpServer.listener.Addr().String())
+	mailer := utils.NewMailer(host, port, "", "", "qsl@huma.id")
+
+	forms := utils.NewFormGuard("secret", time.Second)
+	sess := idSession{memorySession: newMemorySession(), id: "visitor"}
+
+	f := flamego.New()
+	f.Map(forms)
+	f.MapTo(sess, (*session.Session)(nil))
+	f.Post("/contact", newContactSubmitHandler(mailer, "qsl@huma.id"))
+
+	post := func(form url.Values) string {
+		t.Helper()
+		r := httptest.NewRequest(http.MethodPost, "/contact", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		f.ServeHTTP(rec, r)
+		if rec.Code != http.StatusFound {
+			t.Fatalf("Expected a redirect, got %d", rec.Code)
+		}
+		return rec.Header().Get("Location")
+	}
+	form := func(stamp string) url.Values {
+		return url.Values{
+			formStampField: {stamp},
+			"subject":      {"Card received"},
+			"message":      {"Thanks for the card, 73"},
+			"callsign":     {"dl1abc"},
+		}
+	}
+	validStamp := forms.Stamp(time.Now().Add(-time.Minute), sess.id)
+
+	// A filled honeypot looks like success to the bot but sends nothing
+	spam := form(validStamp)
+	spam.Set(honeypotField, "https://spam.example")
+	if got := post(spam); got != "/contact?status=sent" {
+		t.Errorf("Expected the bot to be told it succeeded, got %s", got)
+	}
+
+	// A forged stamp, or one from another session, is refused
+	if got := post(form("1.forged")); got != "/contact?status=invalid" {
+		t.Errorf("Expected a forged stamp to be refused, got %s", got)
+	}
+	if got := post(form(forms.Stamp(time.Now().Add(-time.Minute), "other"))); got != "/contact?status=invalid" {
+		t.Errorf("Expected a stamp from another session to be refused, got %s", got)
+	}
+
+	// Submitting faster than a person could is also treated as a bot
+	if got := post(form(forms.Stamp(time.Now(), sess.id))); got != "/contact?status=sent" {
+		t.Errorf("Expected the bot to be told it succeeded, got %s", got)
+	}
+
+	if n := smtpServer.count(); n != 0 {
+		t.Fatalf("Expected no mail for rejected submissions, got %d", n)
+	}
+
+	// A real message is sent until the client runs into the rate limit
+	for i := 0; i < 3; i++ {
+		if got := post(form(validStamp)); got != "/contact?status=sent" {
+			t.Fatalf("Expected message %d to be sent, got %s", i+1, got)
+		}
+	}
+	if got := post(form(validStamp)); got != "/contact?status=limited" {
+		t.Errorf("Expected the fourth message to be rate limited, got %s", got)
+	}
+	if n := smtpServer.count(); n != 3 {
+		t.Errorf("Expected 3 messages, got %d", n)
+	}
+}
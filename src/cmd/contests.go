@@ -0,0 +1,91 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/flamego/flamego"
+	"github.com/flamego/template"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// handleContests lists the contests in the log with their totals
+func handleContests(t template.Template, data template.Data, store utils.QSOStore, l *localizer) {
+	data["Title"] = l.T("nav.contests")
+	data["ContestsPage"] = true
+	data["Contests"] = utils.ComputeContests(store.GetQSOs())
+	t.HTML(http.StatusOK, "contests")
+}
+
+// handleContest shows the scoring summary, band breakdown and rates of one
+// contest
+func handleContest(c flamego.Context, t template.Template, data template.Data, store utils.QSOStore, l *localizer) {
+	contest, ok := utils.ComputeContest(store.GetQSOs(), c.Param("id"))
+	if !ok {
+		c.Redirect("/contests", http.StatusFound)
+		return
+	}
+
+	data["Title"] = contest.ID
+	data["ContestsPage"] = true
+	data["Contest"] = contest
+	data["Rate"] = l.Decimal(contest.Rate(), 1)
+	t.HTML(http.StatusOK, "contest")
+}
+
+// handleAdminContests lists the contests in the log with their exports
+func handleAdminContests(t template.Template, data template.Data, store utils.QSOStore) {
+	data["Title"] = "Admin: Contests"
+	data["Contests"] = utils.ComputeContests(store.GetQSOs())
+	t.HTML(http.StatusOK, "admin-contests")
+}
+
+// handleAdminContestDupes lists the dupes in a contest, so they can be fixed
+// or marked before the log is submitted
+func handleAdminContestDupes(c flamego.Context, t template.Template, data template.Data, store utils.QSOStore) {
+	qsos := utils.ContestQSOs(store.GetQSOs(), c.Param("id"))
+	if len(qsos) == 0 {
+		c.Redirect("/admin/contests", http.StatusFound)
+		return
+	}
+
+	data["Title"] = "Admin: " + qsos[0].ContestID + " Dupes"
+	data["ContestID"] = qsos[0].ContestID
+	data["Dupes"] = utils.FindDupes(qsos)
+	t.HTML(http.StatusOK, "admin-contest-dupes")
+}
+
+// newAdminCabrilloHandler returns a handler downloading a contest as a
+// Cabrillo log, using callsign when QSOs don't record the station callsign
+func newAdminCabrilloHandler(callsign string) flamego.Handler {
+	return func(c flamego.Context, store utils.QSOStore) {
+		qsos := utils.ContestQSOs(store.GetQSOs(), c.Param("id"))
+		if len(qsos) == 0 {
+			c.Redirect("/admin/contests", http.StatusFound)
+			return
+		}
+
+		header := utils.CabrilloHeader{
+			Contest:   qsos[0].ContestID,
+			Callsign:  strings.ToUpper(callsign),
+			CreatedBy: "humaid-qsl",
+		}
+		if qsos[0].StationCall != "" {
+			header.Callsign = strings.ToUpper(qsos[0].StationCall)
+		}
+
+		w := c.ResponseWriter()
+		fileName := strings.ReplaceAll(header.Contest+"-"+header.Callsign, "/", "_") + ".log"
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+fileName+`"`)
+		if err := utils.WriteCabrillo(w, header, qsos); err != nil {
+			log.Printf("Failed to write Cabrillo log: %v", err)
+		}
+	}
+}
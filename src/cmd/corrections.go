@@ -0,0 +1,96 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/flamego/csrf"
+	"github.com/flamego/flamego"
+	"github.com/flamego/template"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// maxCorrectionNote limits the length of a correction note
+const maxCorrectionNote = 500
+
+// handleAdminCorrections lists the QSO corrections
+func handleAdminCorrections(t template.Template, data template.Data, x csrf.CSRF, corrections *utils.CorrectionStore) {
+	type row struct {
+		utils.Correction
+		PagePath string
+	}
+
+	var rows []row
+	for _, c := range corrections.List() {
+		rows = append(rows, row{
+			Correction: c,
+			PagePath:   confirmationPath(utils.QSO{Call: c.Call, Timestamp: c.QSOTime}),
+		})
+	}
+
+	data["Title"] = "Admin: Corrections"
+	data["CSRFToken"] = x.Token()
+	data["Corrections"] = rows
+	t.HTML(http.StatusOK, "admin-corrections")
+}
+
+// handleAdminCorrectionForm shows the correction form for a QSO
+func handleAdminCorrectionForm(c flamego.Context, t template.Template, data template.Data, x csrf.CSRF, store utils.QSOStore, cfg *siteConfig, corrections *utils.CorrectionStore) {
+	qso, ok := findQSO(c, store)
+	if !ok {
+		c.Redirect("/admin/corrections", http.StatusFound)
+		return
+	}
+
+	correction, _ := corrections.Get(qso.Call, qso.Timestamp)
+
+	data["Title"] = "Admin: Correct " + qso.Call
+	data["CSRFToken"] = x.Token()
+	data["QSO"] = qso
+	data["Correction"] = correction
+	data["PagePath"] = confirmationPath(qso)
+	data["ConfirmationPath"] = cfg.confirmationPath(qso)
+	t.HTML(http.StatusOK, "admin-correction")
+}
+
+// newAdminCorrectionSaveHandler returns a handler that saves a correction and
+// republishes the log so it takes effect immediately
+func newAdminCorrectionSaveHandler(rp *ReloadableParser) flamego.Handler {
+	return func(c flamego.Context, store utils.QSOStore, cfg *siteConfig, corrections *utils.CorrectionStore) {
+		qso, ok := findQSO(c, store)
+		if !ok {
+			c.Redirect("/admin/corrections", http.StatusFound)
+			return
+		}
+
+		pagePath := confirmationPath(qso)
+
+		r := c.Request().Request
+		correction := utils.Correction{
+			Call:       qso.Call,
+			QSOTime:    qso.Timestamp,
+			GridSquare: strings.ToUpper(strings.TrimSpace(r.FormValue("gridsquare"))),
+			Name:       strings.TrimSpace(r.FormValue("name")),
+			Note:       strings.TrimSpace(r.FormValue("note")),
+		}
+		if len(correction.Note) > maxCorrectionNote {
+			correction.Note = correction.Note[:maxCorrectionNote]
+		}
+
+		if err := corrections.Set(correction); err != nil {
+			log.Printf("Failed to save correction for %s: %v", qso.Call, err)
+			c.Redirect("/admin/corrections"+pagePath, http.StatusFound)
+			return
+		}
+		rp.refresh()
+
+		log.Printf("Saved correction for %s at %s", qso.Call, qso.FormatQSOTime())
+		c.Redirect(cfg.confirmationPath(qso), http.StatusFound)
+	}
+}
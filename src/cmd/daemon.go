@@ -0,0 +1,142 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/quic-go/quic-go/http3"
+)
+
+// restartFDsEnv tells a restarted process how many listening sockets it was
+// handed, from file descriptor 3 onwards
+const restartFDsEnv = "QSL_LISTEN_FDS"
+
+// shutdownTimeout is how long in-flight requests get to finish when
+// stopping
+const shutdownTimeout = 30 * time.Second
+
+// writePidFile writes the process ID to path
+func writePidFile(path string) error {
+	if err := os.WriteFile(path, []byte(strconv.Itoa(os.Getpid())+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write pidfile: %w", err)
+	}
+	return nil
+}
+
+// removePidFile removes the pidfile unless a restarted process has already
+// replaced it with its own
+func removePidFile(path string) {
+	data, err := os.ReadFile(path)
+	if err != nil || string(bytes.TrimSpace(data)) != strconv.Itoa(os.Getpid()) {
+		return
+	}
+	if err := os.Remove(path); err != nil {
+		log.Printf("Failed to remove pidfile: %v", err)
+	}
+}
+
+// inheritedListeners returns the sockets handed over by the process that
+// restarted into this one, or nil if it wasn't restarted
+func inheritedListeners() ([]net.Listener, error) {
+	n, err := strconv.Atoi(os.Getenv(restartFDsEnv))
+	if err != nil || n <= 0 {
+		return nil, nil
+	}
+	os.Unsetenv(restartFDsEnv)
+	return fileListeners(n, nil)
+}
+
+// restart starts a new copy of the running binary with the same arguments,
+// handing it the listening sockets so no connection is refused meanwhile
+func restart(sockets []net.Listener) (*os.Process, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find executable: %w", err)
+	}
+
+	files := []*os.File{os.Stdin, os.Stdout, os.Stderr}
+	for _, ln := range sockets {
+		fl, ok := ln.(interface{ File() (*os.File, error) })
+		if !ok {
+			return nil, fmt.Errorf("listener on %s can't be handed over", ln.Addr())
+		}
+		f, err := fl.File()
+		if err != nil {
+			return nil, fmt.Errorf("failed to hand over listener on %s: %w", ln.Addr(), err)
+		}
+		defer f.Close()
+		files = append(files, f)
+	}
+
+	return os.StartProcess(exe, os.Args, &os.ProcAttr{
+		Env:   append(os.Environ(), restartFDsEnv+"="+strconv.Itoa(len(sockets))),
+		Files: files,
+	})
+}
+
+// takeOver tells the process that restarted into this one to stop, now that
+// this one is serving, and tells systemd which process to follow
+func takeOver() {
+	if err := sdNotify("MAINPID=" + strconv.Itoa(os.Getpid())); err != nil {
+		log.Printf("Failed to tell systemd about the restart: %v", err)
+	}
+	if err := syscall.Kill(os.Getppid(), syscall.SIGTERM); err != nil {
+		log.Printf("Failed to stop the previous process: %v", err)
+	}
+}
+
+// serve waits until a server fails or the process is told to stop, then
+// lets in-flight requests finish. SIGUSR2 restarts into a new copy of the
+// binary, which stops this process once it is serving.
+func serve(servers []*http.Server, quicServers []*http3.Server, sockets []net.Listener, errs <-chan error) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM, syscall.SIGUSR2)
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case err := <-errs:
+			return err
+		case sig := <-signals:
+			if sig == syscall.SIGUSR2 {
+				proc, err := restart(sockets)
+				if err != nil {
+					log.Printf("Failed to restart: %v", err)
+					continue
+				}
+				log.Printf("Restarting as process %d", proc.Pid)
+				continue
+			}
+
+			log.Printf("Shutting down on %s", sig)
+			ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+			defer cancel()
+			// HTTP/3 goes first, freeing its UDP ports for a restarted
+			// process as soon as possible
+			for _, srv := range quicServers {
+				if err := srv.Shutdown(ctx); err != nil {
+					log.Printf("Failed to shut down HTTP/3 on %s cleanly: %v", srv.Addr, err)
+				}
+			}
+			for _, srv := range servers {
+				if err := srv.Shutdown(ctx); err != nil {
+					log.Printf("Failed to shut down %s cleanly: %v", srv.Addr, err)
+				}
+			}
+			return nil
+		}
+	}
+}
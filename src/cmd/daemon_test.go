@@ -0,0 +1,39 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "qsl.pid")
+	if err := writePidFile(path); err != nil {
+		t.Fatalf("writePidFile failed: %v", err)
+	}
+	removePidFile(path)
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected the pidfile to be removed, got %v", err)
+	}
+
+	// A restarted process has written its own
+	if err := os.WriteFile(path, []byte("1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	removePidFile(path)
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Expected another process's pidfile to be kept, got %v", err)
+	}
+}
+
+func TestInheritedListenersWithoutRestart(t *testing.T) {
+	t.Setenv(restartFDsEnv, "")
+	listeners, err := inheritedListeners()
+	if err != nil || listeners != nil {
+		t.Errorf("Expected no sockets, got %v (%v)", listeners, err)
+	}
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// CmdDupes lists contest dupes in an ADIF file
+var CmdDupes = &cli.Command{
+	Name:  "dupes",
+	Usage: "List stations worked more than once on the same band and mode in each contest",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "adif",
+			Usage:    "path to ADIF file containing QSO logs",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  "contest",
+			Usage: "only check this CONTEST_ID (all contests if empty)",
+		},
+	},
+	Action: listDupes,
+}
+
+func listDupes(ctx context.Context, cmd *cli.Command) error {
+	file, err := os.Open(cmd.String("adif"))
+	if err != nil {
+		return fmt.Errorf("failed to open ADIF file: %w", err)
+	}
+	defer file.Close()
+
+	parser := utils.NewADIFParser()
+	if err := parser.ParseFile(file); err != nil {
+		return fmt.Errorf("failed to parse ADIF file: %w", err)
+	}
+
+	contests := utils.ComputeContests(parser.GetQSOs())
+	if id := cmd.String("contest"); id != "" {
+		contest, ok := utils.ComputeContest(parser.GetQSOs(), id)
+		if !ok {
+			return fmt.Errorf("no QSOs logged for contest %s", id)
+		}
+		contests = []utils.ContestSummary{contest}
+	}
+
+	for _, contest := range contests {
+		writeDupes(os.Stdout, contest.ID, utils.FindDupes(utils.ContestQSOs(parser.GetQSOs(), contest.ID)))
+	}
+	return nil
+}
+
+// writeDupes writes a contest's dupes, one station per line with the later,
+// duplicate contact times marked
+func writeDupes(w io.Writer, contest string, dupes []utils.ContestDupe) {
+	fmt.Fprintf(w, "%s: %d dupe station(s)\n", contest, len(dupes))
+	for _, dupe := range dupes {
+		times := make([]string, len(dupe.QSOs))
+		for i, qso := range dupe.QSOs {
+			times[i] = qso.Timestamp.UTC().Format("2006-01-02 15:04")
+			if i > 0 {
+				times[i] += " (dupe)"
+			}
+		}
+		fmt.Fprintf(w, "  %-13s %-6s %-2s %s\n", dupe.Call, dupe.Band, dupe.Mode, strings.Join(times, ", "))
+	}
+}
@@ -0,0 +1,32 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+func TestWriteDupes(t *testing.T) {
+	start := time.Date(2024, 10, 26, 0, 5, 0, 0, time.UTC)
+	dupes := []utils.ContestDupe{{
+		Call: "DL1ABC",
+		Band: "20m",
+		Mode: "PH",
+		QSOs: []utils.QSO{{Timestamp: start}, {Timestamp: start.Add(2 * time.Hour)}},
+	}}
+
+	var b strings.Builder
+	writeDupes(&b, "CQ-WW-SSB", dupes)
+
+	want := "CQ-WW-SSB: 1 dupe station(s)\n" +
+		"  DL1ABC        20m    PH 2024-10-26 00:05, 2024-10-26 02:05 (dupe)\n"
+	if b.String() != want {
+		t.Errorf("got:\n%q\nwant:\n%q", b.String(), want)
+	}
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/flamego/flamego"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// emailStatusMessages maps the email result passed back to the confirmation
+// page to the key of the message shown to the visitor
+var emailStatusMessages = map[string]string{
+	"sent":    "email.status.sent",
+	"invalid": "email.status.invalid",
+	"limited": "email.status.limited",
+	"failed":  "email.status.failed",
+}
+
+// newEmailConfirmationHandler returns a handler that emails a QSO confirmation
+// with its map to the visitor. Sends are rate limited both per client and per
+// recipient so the form can't be used to spam a mailbox.
+func newEmailConfirmationHandler(mailer *utils.Mailer) flamego.Handler {
+	clients := utils.NewRateLimiter(5, time.Hour)
+	recipients := utils.NewRateLimiter(3, 24*time.Hour)
+
+	return func(c flamego.Context, store utils.QSOStore, cfg *siteConfig, maps *mapRenderer, l *localizer) {
+		qso, ok := cfg.findQSO(c, store)
+		if !ok {
+			c.Redirect("/", http.StatusFound)
+			return
+		}
+		pagePath := cfg.confirmationPath(qso)
+
+		redirect := func(status string) {
+			c.Redirect(pagePath+"?email="+status, http.StatusFound)
+		}
+
+		to, err := utils.ValidateEmailAddress(c.Request().FormValue("email"))
+		if err != nil {
+			redirect("invalid")
+			return
+		}
+
+		if !clients.Allow(clientIP(c.Request().Request)) || !recipients.Allow(strings.ToLower(to)) {
+			redirect("limited")
+			return
+		}
+
+		// The email goes to whoever asked for it, so it is redacted like the page.
+		// The link uses --base-url, which start requires alongside --smtp-host.
+		public := qso
+		public.Name = cfg.redact("name", qso.Name)
+		body := formatConfirmationEmail(l, public, cfg.BaseURL+pagePath)
+
+		var attachments []utils.Attachment
+		if canMapQSO(qso) {
+			mapFileName := mapFileNameFor(qso)
+			if err := maps.Render(mapFileName, qso); err != nil {
+				log.Printf("Sending confirmation for %s without a map: %v", qso.Call, err)
+			}
+			if png, _, err := maps.store.Get(mapFileName); err == nil {
+				attachments = append(attachments, utils.Attachment{
+					Name:        "map.png",
+					ContentType: "image/png",
+					Data:        png,
+				})
+			}
+		}
+
+		subject := l.T("email.subject", qso.Call, l.Date(qso.Timestamp))
+		if err := mailer.Send(to, subject, body, attachments...); err != nil {
+			log.Printf("Failed to email confirmation for %s: %v", qso.Call, err)
+			redirect("failed")
+			return
+		}
+
+		log.Printf("Emailed confirmation for %s", qso.Call)
+		redirect("sent")
+	}
+}
+
+// formatConfirmationEmail renders the plain text confirmation email in the
+// visitor's language
+func formatConfirmationEmail(l *localizer, qso utils.QSO, link string) string {
+	var b strings.Builder
+	field := func(key, value string) {
+		fmt.Fprintf(&b, "%s: %s\n", l.T(key), value)
+	}
+
+	if qso.Name != "" {
+		b.WriteString(l.T("email.hello", qso.Name) + "\n\n")
+	} else {
+		b.WriteString(l.T("email.hello", qso.Call) + "\n\n")
+	}
+	b.WriteString(l.T("email.confirming") + "\n\n")
+	field("email.toradio", qso.Call)
+	field("email.datetime", l.DateTime(qso.Timestamp))
+	if qso.Freq != "" {
+		field("email.frequency", qso.Freq+" MHz")
+	}
+	if qso.Band != "" {
+		field("email.band", qso.Band)
+	}
+	field("email.mode", qso.Mode)
+	if qso.RSTRcvd != "" {
+		field("email.report", qso.FormatRSTRcvd())
+	}
+	if km, err := utils.Distance(qso.MyGridSquare, qso.GridSquare); err == nil {
+		field("email.distance", l.Distance(km))
+	}
+	b.WriteString("\n" + l.T("email.view", link) + "\n")
+	b.WriteString("\n73,\nHumaid Alqasimi, A66H\n")
+
+	return b.String()
+}
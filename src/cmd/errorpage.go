@@ -0,0 +1,67 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"runtime/debug"
+	"strings"
+
+	"github.com/flamego/flamego"
+	"github.com/flamego/template"
+)
+
+// renderError renders the error page for status with a search form, filled
+// in with callsign when the visitor was looking for a station. qsoHint
+// explains the QSO link format for links that didn't parse.
+func renderError(t template.Template, data template.Data, l *localizer, status int, callsign string, qsoHint bool) {
+	data["Title"] = l.T(fmt.Sprintf("error.%d.title", status))
+	data["ErrorTitle"] = data["Title"]
+	data["ErrorMessage"] = l.T(fmt.Sprintf("error.%d.message", status))
+	data["SearchCallsign"] = strings.ToUpper(strings.TrimSpace(callsign))
+	data["QSOHint"] = qsoHint
+	t.HTML(status, "error")
+}
+
+// handleNotFound renders the 404 page for paths no route matches. Paths
+// under /qso/ are broken confirmation links, so the callsign in them is
+// offered for a search.
+func handleNotFound(c flamego.Context, t template.Template, data template.Data, l *localizer) {
+	rest, isQSO := strings.CutPrefix(c.Request().URL.Path, "/qso/")
+	callsign := ""
+	if isQSO {
+		callsign, _, _ = strings.Cut(rest, "/")
+		callsign, _ = url.PathUnescape(callsign)
+	}
+	renderError(t, data, l, http.StatusNotFound, callsign, isQSO)
+}
+
+// renderQSONotFound renders the 404 page for a confirmation route, with the
+// link format explained if the link didn't parse
+func renderQSONotFound(c flamego.Context, t template.Template, data template.Data, l *localizer) {
+	unix, _, _ := strings.Cut(c.Param("unix"), "-")
+	call, _, ok := parseQSORef(c.Param("call"), unix)
+	if !ok {
+		call = c.Param("call")
+	}
+	renderError(t, data, l, http.StatusNotFound, call, !ok)
+}
+
+// recoverWithErrorPage renders the 500 page when a handler panics, logging
+// the stack so the cause can be found
+func recoverWithErrorPage(c flamego.Context, t template.Template, data template.Data, l *localizer) {
+	defer func() {
+		if err := recover(); err != nil {
+			log.Printf("Panic serving %s: %v\n%s", c.Request().URL.Path, err, debug.Stack())
+			if !c.ResponseWriter().Written() {
+				renderError(t, data, l, http.StatusInternalServerError, "", false)
+			}
+		}
+	}()
+	c.Next()
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/flamego/template"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+func TestErrorPages(t *testing.T) {
+	f := newPageRouter(t, &utils.ADIFParser{}, &siteConfig{})
+	f.Use(recoverWithErrorPage)
+	f.Get("/qso/{call: **}/{unix}", renderQSONotFound)
+	f.Get("/panic", func() { panic("boom") })
+	f.NotFound(handleNotFound)
+
+	const hint = "QSO links look like /qso/CALLSIGN/UNIXTIME"
+	tests := []struct {
+		path     string
+		status   int
+		callsign string
+		hint     bool
+	}{
+		{"/no/such/page", http.StatusNotFound, "", false},
+		{"/qso/dl1abc", http.StatusNotFound, "DL1ABC", true},
+		{"/qso/DL1ABC/yesterday", http.StatusNotFound, "DL1ABC", true},
+		{"/qso/A66H/P/1714568700-5", http.StatusNotFound, "A66H/P", false},
+		{"/panic", http.StatusInternalServerError, "", false},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		f.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rec.Code != tt.status {
+			t.Errorf("GET %s: expected %d, got %d (Location %q)", tt.path, tt.status, rec.Code, rec.Header().Get("Location"))
+			continue
+		}
+		body := rec.Body.String()
+		if !strings.Contains(body, `value="`+tt.callsign+`"`) {
+			t.Errorf("GET %s: expected the search form filled with %q", tt.path, tt.callsign)
+		}
+		if got := strings.Contains(body, hint); got != tt.hint {
+			t.Errorf("GET %s: expected the QSO link hint %v, got %v", tt.path, tt.hint, got)
+		}
+	}
+}
+
+func TestRenderErrorTitle(t *testing.T) {
+	f := newPageRouter(t, &utils.ADIFParser{}, &siteConfig{})
+	f.Get("/", func(t template.Template, data template.Data, l *localizer) {
+		renderError(t, data, l, http.StatusNotFound, " w1aw ", false)
+	})
+
+	rec := httptest.NewRecorder()
+	f.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	body := rec.Body.String()
+	if !strings.Contains(body, "<h2>Page Not Found</h2>") {
+		t.Errorf("Expected the localized 404 title, got %s", body)
+	}
+	if !strings.Contains(body, `value="W1AW"`) {
+		t.Error("Expected the callsign to be trimmed and upper cased")
+	}
+}
@@ -0,0 +1,156 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// startFollowing starts the goroutine tailing the ADIF file, used instead of
+// periodic reloads
+func (rp *ReloadableParser) startFollowing(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := rp.follow(); err != nil {
+				log.Printf("Failed to follow ADIF file: %v", err)
+			}
+		}
+	}()
+}
+
+// follow adds the records appended to the ADIF file since it was last read,
+// like tail -f. The file is fully reloaded instead if it was replaced or
+// shrank, as loggers do when a QSO is edited or deleted.
+func (rp *ReloadableParser) follow() error {
+	info, err := os.Stat(rp.filePath)
+	if err != nil {
+		return fmt.Errorf("failed to stat ADIF file: %w", err)
+	}
+
+	rp.mutex.RLock()
+	previous, offset := rp.fileInfo, rp.offset
+	rp.mutex.RUnlock()
+
+	if previous == nil || !os.SameFile(previous, info) || info.Size() < offset {
+		return rp.reload()
+	}
+	if info.Size() == offset {
+		return nil
+	}
+
+	file, err := os.Open(rp.filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open ADIF file: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek ADIF file: %w", err)
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return fmt.Errorf("failed to read ADIF file: %w", err)
+	}
+
+	parser := utils.NewADIFParser()
+	n, err := parser.ParseComplete(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse appended ADIF records: %w", err)
+	}
+	if n == 0 {
+		// The last record is still being written
+		return nil
+	}
+
+	rp.mutex.Lock()
+	if rp.offset != offset {
+		// Reloaded in the meantime, e.g. from the admin area
+		rp.mutex.Unlock()
+		return nil
+	}
+	newQSOs := rp.newQSOs(parser.QSOs)
+	// Clipped so appending never writes into the slice being served
+	rp.fileQSOs = append(slices.Clip(rp.fileQSOs), parser.QSOs...)
+	for _, qso := range parser.QSOs {
+		rp.fileKeys[liveQSOKey(qso)] = true
+	}
+	rp.fileInfo = info
+	rp.offset += int64(n)
+	rp.loadedAt = time.Now()
+	rp.warnings = append(slices.Clip(rp.warnings), parser.Warnings...)
+	rp.issues = utils.CheckLog(rp.fileQSOs, time.Now())
+	rp.publish()
+	rp.mutex.Unlock()
+
+	log.Printf("Added %d appended QSOs from %s", len(parser.QSOs), rp.filePath)
+	if len(newQSOs) > 0 {
+		rp.events.Publish(utils.Event{Type: utils.EventNewQSOs, QSOs: newQSOs})
+	}
+	return nil
+}
+
+// errQSOExists is returned when appending a QSO already in the ADIF file
+var errQSOExists = errors.New("QSO already in the ADIF file")
+
+// appendQSO appends a QSO to the ADIF file and adds it to the served log
+// while holding the lock, so the file and log can't disagree. If the file
+// has changes that haven't been read yet, it is reloaded instead so they
+// aren't skipped when following it.
+func (rp *ReloadableParser) appendQSO(qso utils.QSO) error {
+	record := utils.FormatADIFRecord(qso) + "\n"
+	key := liveQSOKey(qso)
+
+	rp.mutex.Lock()
+	if rp.fileKeys[key] {
+		rp.mutex.Unlock()
+		return errQSOExists
+	}
+
+	file, err := os.OpenFile(rp.filePath, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		rp.mutex.Unlock()
+		return fmt.Errorf("failed to open ADIF file: %w", err)
+	}
+	info, err := file.Stat()
+	if err == nil {
+		_, err = file.WriteString(record)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		rp.mutex.Unlock()
+		return fmt.Errorf("failed to append to ADIF file: %w", err)
+	}
+
+	upToDate := rp.fileInfo != nil && os.SameFile(rp.fileInfo, info) && info.Size() == rp.offset
+	var newQSOs []utils.QSO
+	if upToDate {
+		newQSOs = rp.newQSOs([]utils.QSO{qso})
+		rp.fileQSOs = append(slices.Clip(rp.fileQSOs), qso)
+		rp.fileKeys[key] = true
+		rp.offset += int64(len(record))
+		rp.publish()
+	}
+	rp.mutex.Unlock()
+
+	if !upToDate {
+		return rp.reload()
+	}
+	if len(newQSOs) > 0 {
+		rp.events.Publish(utils.Event{Type: utils.EventNewQSOs, QSOs: newQSOs})
+	}
+	return nil
+}
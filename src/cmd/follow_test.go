@@ -0,0 +1,180 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+const followHeader = "Test log\n<ADIF_VER:5>3.1.4<EOH>\n"
+
+func appendADIF(t *testing.T, path, data string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReloadableParserFollow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.adi")
+	first := "<CALL:4>W1AW<QSO_DATE:8>20250301<TIME_ON:4>1200<EOR>\n"
+	if err := os.WriteFile(path, []byte(followHeader+first), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	rp, err := NewReloadableParser(path)
+	if err != nil {
+		t.Fatalf("NewReloadableParser: %v", err)
+	}
+
+	// A record still being written isn't added until it is complete
+	appendADIF(t, path, "<CALL:6>DL1ABC<QSO_DATE:8>20250301")
+	if err := rp.follow(); err != nil {
+		t.Fatalf("follow: %v", err)
+	}
+	if got := rp.getParser().GetTotalQSOCount(); got != 1 {
+		t.Fatalf("QSO count after partial record = %d, want 1", got)
+	}
+
+	appendADIF(t, path, "<TIME_ON:4>1205<EOR>\n")
+	if err := rp.follow(); err != nil {
+		t.Fatalf("follow: %v", err)
+	}
+	qsos := rp.getParser().GetQSOs()
+	if len(qsos) != 2 || qsos[1].Call != "DL1ABC" {
+		t.Fatalf("unexpected QSOs after append: %+v", qsos)
+	}
+
+	// A rewritten, shorter file is reloaded in full
+	if err := os.WriteFile(path, []byte(followHeader), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := rp.follow(); err != nil {
+		t.Fatalf("follow: %v", err)
+	}
+	if got := rp.getParser().GetTotalQSOCount(); got != 0 {
+		t.Errorf("QSO count after rewrite = %d, want 0", got)
+	}
+}
+
+func TestReloadableParserAppendQSO(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.adi")
+	if err := os.WriteFile(path, []byte(followHeader), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rp, err := NewReloadableParser(path)
+	if err != nil {
+		t.Fatalf("NewReloadableParser: %v", err)
+	}
+
+	qso := utils.QSO{Call: "W1AW", QSODate: "20250301", TimeOn: "1200", Timestamp: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)}
+	if err := rp.appendQSO(qso); err != nil {
+		t.Fatalf("appendQSO: %v", err)
+	}
+	if err := rp.appendQSO(qso); !errors.Is(err, errQSOExists) {
+		t.Errorf("second append error = %v, want errQSOExists", err)
+	}
+
+	// Following the file doesn't add the appended QSO a second time
+	if err := rp.follow(); err != nil {
+		t.Fatalf("follow: %v", err)
+	}
+	if got := rp.getParser().GetTotalQSOCount(); got != 1 {
+		t.Errorf("QSO count = %d, want 1", got)
+	}
+
+	if err := rp.reload(); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if qsos := rp.getParser().GetQSOs(); len(qsos) != 1 || qsos[0].Call != "W1AW" {
+		t.Errorf("QSO not written to the file: %+v", qsos)
+	}
+}
+
+func TestReloadableParserSQLiteMirror(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.adi")
+	if err := os.WriteFile(path, []byte(followHeader+"<CALL:4>W1AW<QSO_DATE:8>20250301<TIME_ON:4>1200<EOR>\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rp, err := NewReloadableParser(path)
+	if err != nil {
+		t.Fatalf("NewReloadableParser: %v", err)
+	}
+	if _, ok := rp.getStore().(*utils.ADIFParser); !ok {
+		t.Fatalf("Expected the parser to be served without SQLite, got %T", rp.getStore())
+	}
+
+	store, err := utils.OpenSQLiteStore(filepath.Join(t.TempDir(), "qsos.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	rp.sqlite = newSQLiteMirror(store)
+	rp.refresh()
+	waitForSQLiteMirror(t, rp, store)
+
+	// Reloading republishes the log into the mirror
+	appendADIF(t, path, "<CALL:5>A61XX<QSO_DATE:8>20250301<TIME_ON:4>1300<EOR>\n")
+	if err := rp.reload(); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	waitForSQLiteMirror(t, rp, store)
+	if got := store.GetTotalQSOCount(); got != 2 {
+		t.Errorf("Expected 2 QSOs in the mirror, got %d", got)
+	}
+	if got := store.GetQSOsByCallsign("A61XX"); len(got) != 1 {
+		t.Errorf("Expected the new QSO in the mirror, got %v", got)
+	}
+
+	// The parser is served while the mirror lags behind the log
+	store.Close()
+	appendADIF(t, path, "<CALL:5>A61XY<QSO_DATE:8>20250301<TIME_ON:4>1400<EOR>\n")
+	if err := rp.reload(); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if got := rp.getStore(); got.GetTotalQSOCount() != 3 {
+		t.Errorf("Expected the parser with 3 QSOs while the mirror is behind, got %T with %d", got, got.GetTotalQSOCount())
+	}
+}
+
+// waitForSQLiteMirror waits for the mirror to catch up with the served log
+func waitForSQLiteMirror(t *testing.T, rp *ReloadableParser, store *utils.SQLiteStore) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for rp.getStore() != utils.QSOStore(store) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the SQLite store to be served, got %T", rp.getStore())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestLiveQSOsCapped(t *testing.T) {
+	rp := &ReloadableParser{live: make(map[string]utils.QSO)}
+	for i := 0; i < maxLiveQSOs+5; i++ {
+		rp.setLiveQSO("test:"+strconv.Itoa(i), utils.QSO{Call: "W1AW", Timestamp: time.Unix(int64(1740835800+i), 0)})
+	}
+	if len(rp.live) != maxLiveQSOs {
+		t.Fatalf("Expected %d live QSOs, got %d", maxLiveQSOs, len(rp.live))
+	}
+
+	// Replacing a QSO already held is still allowed when full
+	rp.setLiveQSO("test:0", utils.QSO{Call: "K1ABC", Timestamp: time.Unix(1740835800, 0)})
+	if rp.live["test:0"].Call != "K1ABC" {
+		t.Errorf("Expected the held QSO to be replaced, got %s", rp.live["test:0"].Call)
+	}
+}
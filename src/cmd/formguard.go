@@ -0,0 +1,26 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/flamego/session"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// Names of the fields rendered by the form-guard template
+const (
+	honeypotField  = "website"
+	formStampField = "_stamp"
+)
+
+// checkFormGuard checks the honeypot and render stamp of a form submitted in
+// a session
+func checkFormGuard(forms *utils.FormGuard, r *http.Request, s session.Session) error {
+	return forms.Check(r.FormValue(honeypotField), r.FormValue(formStampField), s.ID(), time.Now())
+}
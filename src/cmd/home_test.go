@@ -0,0 +1,37 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+func TestHomeStatsCachedPerGeneration(t *testing.T) {
+	rp := &ReloadableParser{
+		fileQSOs: []utils.QSO{{Call: "A61XX", Country: "United Arab Emirates", Timestamp: time.Unix(1740832200, 0)}},
+		live:     make(map[string]utils.QSO),
+	}
+	rp.publish()
+
+	first := rp.homeStats()
+	if first.totalQSOs != 1 {
+		t.Fatalf("Expected 1 QSO, got %d", first.totalQSOs)
+	}
+	if rp.homeStats() != first {
+		t.Error("Expected home stats to be cached until the log changes")
+	}
+
+	rp.setLiveQSO("test:1", utils.QSO{Call: "W1AW", Country: "United States", Timestamp: time.Unix(1740835800, 0)})
+	second := rp.homeStats()
+	if second == first || second.totalQSOs != 2 || second.uniqueCountries != 2 {
+		t.Errorf("Expected fresh stats after the log changed, got %+v", second)
+	}
+	if second.latestQSO == nil || second.latestQSO.Call != "W1AW" {
+		t.Errorf("Expected W1AW as the latest QSO, got %+v", second.latestQSO)
+	}
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/quic-go/quic-go/http3"
+)
+
+// loadTLSConfig loads the certificate shared by the HTTPS and HTTP/3
+// servers
+func loadTLSConfig(certFile, keyFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	return &tls.Config{Certificates: []tls.Certificate{cert}}, nil
+}
+
+// newHTTP3Server creates the HTTP/3 server for an HTTPS listener, serving
+// the same handler on the UDP port of its address
+func (l listener) newHTTP3Server(handler http.Handler, tlsConfig *tls.Config) *http3.Server {
+	return &http3.Server{
+		Addr:      l.addr,
+		Handler:   withWriteDeadlines(handler),
+		TLSConfig: http3.ConfigureTLSConfig(tlsConfig),
+	}
+}
+
+// withAltSvc advertises an HTTP/3 server in the responses of h, so browsers
+// move to it for later requests
+func withAltSvc(h http.Handler, h3 *http3.Server) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// This fails until the HTTP/3 server is listening, when there is
+		// nothing to advertise yet
+		_ = h3.SetQUICHeaders(w.Header())
+		h.ServeHTTP(w, r)
+	})
+}
+
+// listenQUIC opens the UDP socket of an HTTP/3 server. Unlike the TCP
+// sockets it isn't handed over on restart, so a restarted process waits up
+// to wait for the previous one to let go of it.
+func listenQUIC(addr string, wait time.Duration) (net.PacketConn, error) {
+	deadline := time.Now().Add(wait)
+	for {
+		conn, err := net.ListenPacket("udp", addr)
+		if err == nil {
+			return conn, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("failed to listen on %s for HTTP/3: %w", addr, err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/quic-go/quic-go/http3"
+)
+
+func TestHTTP3Server(t *testing.T) {
+	tlsConfig, err := loadTLSConfig(writeTestCertificate(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, _ := parseListener("https://127.0.0.1:0")
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "73")
+	})
+
+	h3 := l.newHTTP3Server(handler, tlsConfig)
+	conn, err := listenQUIC(l.addr, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	go func() { _ = h3.Serve(conn) }()
+	defer h3.Close()
+
+	transport := &http3.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	defer transport.Close()
+	resp, err := (&http.Client{Transport: transport}).Get("https://" + conn.LocalAddr().String() + "/")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.ProtoMajor != 3 || string(body) != "73" {
+		t.Errorf("Expected 73 over HTTP/3, got %q over %s", body, resp.Proto)
+	}
+
+	// The HTTPS server points browsers at the HTTP/3 port
+	rec := httptest.NewRecorder()
+	withAltSvc(handler, h3).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	want := fmt.Sprintf(`h3=":%d"; ma=2592000`, conn.LocalAddr().(*net.UDPAddr).Port)
+	if got := rec.Header().Get("Alt-Svc"); got != want {
+		t.Errorf("Expected Alt-Svc %q, got %q", want, got)
+	}
+}
+
+func TestListenQUICWaitsForPort(t *testing.T) {
+	held, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := held.LocalAddr().String()
+
+	if _, err := listenQUIC(addr, 0); err == nil {
+		t.Fatal("Expected a port in use to fail without waiting")
+	}
+
+	// The previous process lets go of the port while this one waits
+	go held.Close()
+	conn, err := listenQUIC(addr, shutdownTimeout)
+	if err != nil {
+		t.Fatalf("Expected the port once it was released: %v", err)
+	}
+	conn.Close()
+}
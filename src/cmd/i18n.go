@@ -0,0 +1,99 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/flamego/flamego"
+	"github.com/flamego/session"
+	"github.com/flamego/template"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// localeSessionKey holds the visitor's chosen language in the session
+const localeSessionKey = "locale"
+
+// localizer translates messages into the language of the current request and
+// formats values for the visitor's preferences
+type localizer struct {
+	catalog *utils.Catalog
+	locale  string
+	units   string
+}
+
+// T returns the message for key in the request language
+func (l *localizer) T(key string, args ...interface{}) string {
+	return l.catalog.Translate(l.locale, key, args...)
+}
+
+// Date formats the UTC date of t for the request language
+func (l *localizer) Date(t time.Time) string {
+	return l.catalog.FormatDate(l.locale, t)
+}
+
+// DateTime formats the UTC date and time of t for the request language
+func (l *localizer) DateTime(t time.Time) string {
+	return l.catalog.FormatDateTime(l.locale, t)
+}
+
+// Ago describes how long ago t was in the request language
+func (l *localizer) Ago(t time.Time) string {
+	return l.catalog.RelativeTime(l.locale, t, time.Now())
+}
+
+// Decimal formats v with the given number of decimals for the request
+// language
+func (l *localizer) Decimal(v float64, decimals int) string {
+	return l.catalog.FormatFloat(l.locale, v, decimals)
+}
+
+// Distance formats a distance given in kilometres in the visitor's units
+func (l *localizer) Distance(km float64) string {
+	if l.units == unitsImperial {
+		return l.T("unit.mi", l.Decimal(km/utils.KmPerMile, 0))
+	}
+	return l.T("unit.km", l.Decimal(km, 0))
+}
+
+// requestLocale returns the language chosen in the session, or the one
+// negotiated from the Accept-Language header
+func requestLocale(catalog *utils.Catalog, s session.Session, r *http.Request) string {
+	if locale, _ := s.Get(localeSessionKey).(string); catalog.Supported(locale) {
+		return locale
+	}
+	return catalog.Negotiate(r.Header.Get("Accept-Language"))
+}
+
+// newLocaleHandler returns a middleware that picks the request language,
+// maps a localizer for handlers and exposes the language and units to
+// templates
+func newLocaleHandler(catalog *utils.Catalog) flamego.Handler {
+	return func(c flamego.Context, s session.Session, data template.Data) {
+		locale := requestLocale(catalog, s, c.Request().Request)
+		c.ResponseWriter().Header().Add("Vary", "Accept-Language")
+		units := sessionUnits(s)
+		c.Map(&localizer{catalog: catalog, locale: locale, units: units})
+
+		data["Locale"] = locale
+		data["Dir"] = utils.Direction(locale)
+		data["Locales"] = catalog.Locales()
+		data["Units"] = units
+	}
+}
+
+// newLocaleSetHandler returns a handler that stores the visitor's language
+// and returns them to the page they came from
+func newLocaleSetHandler(catalog *utils.Catalog) flamego.Handler {
+	return func(c flamego.Context, s session.Session) {
+		r := c.Request().Request
+		if locale := r.FormValue("locale"); catalog.Supported(locale) {
+			s.Set(localeSessionKey, locale)
+		}
+		c.Redirect(localRedirect(r.FormValue("next"), "/"), http.StatusFound)
+	}
+}
@@ -0,0 +1,340 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/urfave/cli/v3"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// imageStoreTimeout bounds a single object storage request
+const imageStoreTimeout = 30 * time.Second
+
+// storedImage is a generated map or card image in the image store
+type storedImage struct {
+	Name     string
+	Size     int64
+	Modified time.Time
+}
+
+// imageStore holds generated map and card images by file name. Missing
+// images are reported with errors wrapping fs.ErrNotExist.
+type imageStore interface {
+	Get(name string) ([]byte, storedImage, error)
+	Put(name string, data []byte) error
+	Exists(name string) bool
+	Delete(name string) error
+	List() ([]storedImage, error)
+}
+
+// newImageStore returns the image store configured on the command line:
+// memory if a size is given, spilling to the backing store if asked to, or
+// otherwise the backing store itself
+func newImageStore(cmd *cli.Command) (imageStore, error) {
+	if size := cmd.String("map-memory"); size != "" {
+		limit, err := humanize.ParseBytes(size)
+		if err != nil {
+			return nil, fmt.Errorf("invalid map memory size %q: %w", size, err)
+		}
+		var spill imageStore
+		if cmd.Bool("map-spill") {
+			if spill, err = newBackingImageStore(cmd); err != nil {
+				return nil, err
+			}
+		}
+		return newMemoryImageStore(int64(limit), spill), nil
+	}
+	return newBackingImageStore(cmd)
+}
+
+// newBackingImageStore returns an S3-compatible bucket if one is given,
+// otherwise the maps directory
+func newBackingImageStore(cmd *cli.Command) (imageStore, error) {
+	if bucket := cmd.String("s3-bucket"); bucket != "" {
+		client, err := utils.NewS3Client(cmd.String("s3-endpoint"), cmd.String("s3-region"), bucket,
+			cmd.String("s3-access-key"), cmd.String("s3-secret-key"))
+		if err != nil {
+			return nil, err
+		}
+		return &s3ImageStore{client: client, prefix: cmd.String("s3-prefix")}, nil
+	}
+
+	if err := os.MkdirAll("maps", 0755); err != nil {
+		return nil, fmt.Errorf("failed to create maps directory: %w", err)
+	}
+	return diskImageStore{dir: "maps"}, nil
+}
+
+// diskImageStore keeps images as files in a directory
+type diskImageStore struct {
+	dir string
+}
+
+func (s diskImageStore) Get(name string) ([]byte, storedImage, error) {
+	path := filepath.Join(s.dir, name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, storedImage{}, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, storedImage{}, err
+	}
+	return data, storedImage{Name: name, Size: info.Size(), Modified: info.ModTime()}, nil
+}
+
+// Put writes the image to a temporary file first so a partly written image
+// is never served
+func (s diskImageStore) Put(name string, data []byte) error {
+	path := filepath.Join(s.dir, name)
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to save %s: %w", name, err)
+	}
+	return nil
+}
+
+func (s diskImageStore) Exists(name string) bool {
+	_, err := os.Stat(filepath.Join(s.dir, name))
+	return err == nil
+}
+
+func (s diskImageStore) Delete(name string) error {
+	return os.Remove(filepath.Join(s.dir, name))
+}
+
+func (s diskImageStore) List() ([]storedImage, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var images []storedImage
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".png") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		images = append(images, storedImage{Name: e.Name(), Size: info.Size(), Modified: info.ModTime()})
+	}
+	return images, nil
+}
+
+// s3ImageStore keeps images in an S3-compatible bucket, so several instances
+// share one cache and renders survive container restarts
+type s3ImageStore struct {
+	client *utils.S3Client
+	prefix string // Key prefix, e.g. maps/
+}
+
+func (s *s3ImageStore) Get(name string) ([]byte, storedImage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), imageStoreTimeout)
+	defer cancel()
+
+	data, modified, err := s.client.Get(ctx, s.prefix+name)
+	if err != nil {
+		return nil, storedImage{}, err
+	}
+	return data, storedImage{Name: name, Size: int64(len(data)), Modified: modified}, nil
+}
+
+func (s *s3ImageStore) Put(name string, data []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), imageStoreTimeout)
+	defer cancel()
+	return s.client.Put(ctx, s.prefix+name, data, "image/png")
+}
+
+func (s *s3ImageStore) Exists(name string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), imageStoreTimeout)
+	defer cancel()
+	_, err := s.client.Head(ctx, s.prefix+name)
+	return err == nil
+}
+
+func (s *s3ImageStore) Delete(name string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), imageStoreTimeout)
+	defer cancel()
+	return s.client.Delete(ctx, s.prefix+name)
+}
+
+func (s *s3ImageStore) List() ([]storedImage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), imageStoreTimeout)
+	defer cancel()
+
+	objects, err := s.client.List(ctx, s.prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	images := make([]storedImage, 0, len(objects))
+	for _, o := range objects {
+		name := strings.TrimPrefix(o.Key, s.prefix)
+		if strings.Contains(name, "/") || !strings.HasSuffix(name, ".png") {
+			continue
+		}
+		images = append(images, storedImage{Name: name, Size: o.Size, Modified: o.Modified})
+	}
+	return images, nil
+}
+
+// memoryImage is an image held by memoryImageStore
+type memoryImage struct {
+	data []byte
+	info storedImage
+}
+
+// memoryImageStore keeps images in memory, so serving them needs no file
+// system access and the root file system can be read-only. Once the images
+// outgrow the limit the least recently used are evicted, to the spill store
+// if there is one or otherwise dropped to be rendered again.
+type memoryImageStore struct {
+	limit int64
+	spill imageStore // May be nil
+	now   func() time.Time
+
+	mutex  sync.Mutex
+	size   int64
+	order  *list.List // Least recently used at the back
+	images map[string]*list.Element
+}
+
+func newMemoryImageStore(limit int64, spill imageStore) *memoryImageStore {
+	return &memoryImageStore{
+		limit:  limit,
+		spill:  spill,
+		now:    time.Now,
+		order:  list.New(),
+		images: make(map[string]*list.Element),
+	}
+}
+
+func (s *memoryImageStore) Get(name string) ([]byte, storedImage, error) {
+	s.mutex.Lock()
+	if e, ok := s.images[name]; ok {
+		s.order.MoveToFront(e)
+		img := e.Value.(*memoryImage)
+		s.mutex.Unlock()
+		return img.data, img.info, nil
+	}
+	s.mutex.Unlock()
+
+	if s.spill == nil {
+		return nil, storedImage{}, fmt.Errorf("image %s: %w", name, fs.ErrNotExist)
+	}
+	return s.spill.Get(name)
+}
+
+func (s *memoryImageStore) Put(name string, data []byte) error {
+	s.mutex.Lock()
+	if e, ok := s.images[name]; ok {
+		s.size -= e.Value.(*memoryImage).info.Size
+		s.order.Remove(e)
+	}
+	img := &memoryImage{data: data, info: storedImage{Name: name, Size: int64(len(data)), Modified: s.now()}}
+	s.images[name] = s.order.PushFront(img)
+	s.size += img.info.Size
+	evicted := s.evict()
+	s.mutex.Unlock()
+
+	if s.spill == nil {
+		return nil
+	}
+	for _, img := range evicted {
+		if err := s.spill.Put(img.info.Name, img.data); err != nil {
+			return fmt.Errorf("failed to spill %s: %w", img.info.Name, err)
+		}
+	}
+	return nil
+}
+
+// evict removes the least recently used images until the rest fit in the
+// limit, always keeping the newest. The caller must hold the lock.
+func (s *memoryImageStore) evict() []*memoryImage {
+	var evicted []*memoryImage
+	for s.size > s.limit && s.order.Len() > 1 {
+		img := s.order.Remove(s.order.Back()).(*memoryImage)
+		delete(s.images, img.info.Name)
+		s.size -= img.info.Size
+		evicted = append(evicted, img)
+	}
+	return evicted
+}
+
+func (s *memoryImageStore) Exists(name string) bool {
+	s.mutex.Lock()
+	_, ok := s.images[name]
+	s.mutex.Unlock()
+	return ok || (s.spill != nil && s.spill.Exists(name))
+}
+
+func (s *memoryImageStore) Delete(name string) error {
+	s.mutex.Lock()
+	e, ok := s.images[name]
+	if ok {
+		s.size -= e.Value.(*memoryImage).info.Size
+		s.order.Remove(e)
+		delete(s.images, name)
+	}
+	s.mutex.Unlock()
+
+	if s.spill == nil {
+		if !ok {
+			return fmt.Errorf("image %s: %w", name, fs.ErrNotExist)
+		}
+		return nil
+	}
+	// The image may only ever have been in memory
+	err := s.spill.Delete(name)
+	if ok && errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (s *memoryImageStore) List() ([]storedImage, error) {
+	var images []storedImage
+	seen := make(map[string]bool)
+	s.mutex.Lock()
+	for _, e := range s.images {
+		img := e.Value.(*memoryImage)
+		images = append(images, img.info)
+		seen[img.info.Name] = true
+	}
+	s.mutex.Unlock()
+
+	if s.spill == nil {
+		return images, nil
+	}
+	spilled, err := s.spill.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, img := range spilled {
+		if !seen[img.Name] {
+			images = append(images, img)
+		}
+	}
+	return images, nil
+}
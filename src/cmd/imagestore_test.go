@@ -0,0 +1,113 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiskImageStore(t *testing.T) {
+	store := diskImageStore{dir: t.TempDir()}
+
+	if _, _, err := store.Get("a.png"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected a missing image to be fs.ErrNotExist, got %v", err)
+	}
+	if err := store.Put("a.png", []byte("png")); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if !store.Exists("a.png") {
+		t.Error("Expected the image to exist")
+	}
+	data, info, err := store.Get("a.png")
+	if err != nil || string(data) != "png" || info.Size != 3 {
+		t.Errorf("Expected the image back, got %q %+v (%v)", data, info, err)
+	}
+	if _, err := os.Stat(filepath.Join(store.dir, "a.png.tmp")); !os.IsNotExist(err) {
+		t.Errorf("Temporary file left behind: %v", err)
+	}
+
+	// Only images are listed
+	if err := os.WriteFile(filepath.Join(store.dir, "notes.txt"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	images, err := store.List()
+	if err != nil || len(images) != 1 || images[0].Name != "a.png" {
+		t.Errorf("Expected one listed image, got %+v (%v)", images, err)
+	}
+
+	if err := store.Delete("a.png"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if store.Exists("a.png") {
+		t.Error("Expected the image to be gone")
+	}
+}
+
+func TestMemoryImageStoreEvicts(t *testing.T) {
+	store := newMemoryImageStore(6, nil)
+
+	for _, name := range []string{"a.png", "b.png"} {
+		if err := store.Put(name, []byte("png")); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+	// Using a keeps it, so b is the least recently used
+	if _, _, err := store.Get("a.png"); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if err := store.Put("c.png", []byte("png")); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	if !store.Exists("a.png") || store.Exists("b.png") || !store.Exists("c.png") {
+		t.Error("Expected the least recently used image to be evicted")
+	}
+	if _, _, err := store.Get("b.png"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected an evicted image to be fs.ErrNotExist, got %v", err)
+	}
+	if store.size != 6 {
+		t.Errorf("Expected 6 bytes held, got %d", store.size)
+	}
+}
+
+func TestMemoryImageStoreSpills(t *testing.T) {
+	spill := diskImageStore{dir: t.TempDir()}
+	store := newMemoryImageStore(3, spill)
+
+	if err := store.Put("a.png", []byte("png")); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if spill.Exists("a.png") {
+		t.Error("Expected the image to stay in memory until evicted")
+	}
+	if err := store.Put("b.png", []byte("png")); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if !spill.Exists("a.png") {
+		t.Fatal("Expected the evicted image to be spilled")
+	}
+
+	data, _, err := store.Get("a.png")
+	if err != nil || string(data) != "png" {
+		t.Errorf("Expected the spilled image back, got %q (%v)", data, err)
+	}
+	images, err := store.List()
+	if err != nil || len(images) != 2 {
+		t.Errorf("Expected both images listed, got %+v (%v)", images, err)
+	}
+
+	for _, name := range []string{"a.png", "b.png"} {
+		if err := store.Delete(name); err != nil {
+			t.Errorf("Delete %s failed: %v", name, err)
+		}
+		if store.Exists(name) {
+			t.Errorf("Expected %s to be gone", name)
+		}
+	}
+}
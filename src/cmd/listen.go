@@ -0,0 +1,167 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// routeSet is a group of routes that can be served on a listener
+type routeSet string
+
+const (
+	routesPublic routeSet = "public" // Public pages
+	routesAdmin  routeSet = "admin"  // Admin area under /admin
+	routesAPI    routeSet = "api"    // QSO submission and Cloudlog APIs
+)
+
+// listener is an address to serve on and the route sets served there
+type listener struct {
+	addr   string
+	tls    bool       // Serve HTTPS with the --tls-cert certificate
+	routes []routeSet // Empty serves every route
+}
+
+// parseListener parses a --listen value of the form [https://]ADDR or
+// [https://]ADDR=SET,SET, e.g. https://:443=public or 127.0.0.1:8081=admin,api.
+// A bare port listens on all interfaces.
+func parseListener(s string) (listener, error) {
+	spec := strings.TrimSpace(s)
+	var l listener
+	if rest, ok := strings.CutPrefix(spec, "https://"); ok {
+		spec, l.tls = rest, true
+	} else {
+		spec = strings.TrimPrefix(spec, "http://")
+	}
+
+	addr, sets, hasSets := strings.Cut(spec, "=")
+	if !strings.Contains(addr, ":") {
+		addr = "0.0.0.0:" + addr
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return listener{}, fmt.Errorf("invalid listen address %q: %w", s, err)
+	}
+
+	l.addr = addr
+	if !hasSets {
+		return l, nil
+	}
+	for _, set := range strings.Split(sets, ",") {
+		switch rs := routeSet(strings.ToLower(strings.TrimSpace(set))); rs {
+		case routesPublic, routesAdmin, routesAPI:
+			if !slices.Contains(l.routes, rs) {
+				l.routes = append(l.routes, rs)
+			}
+		default:
+			return listener{}, fmt.Errorf("unknown route set %q in %q (expected public, admin or api)", set, s)
+		}
+	}
+	return l, nil
+}
+
+// String describes the protocol and routes served, for logging
+func (l listener) String() string {
+	scheme := "HTTP"
+	if l.tls {
+		scheme = "HTTPS"
+	}
+	return scheme + " with " + l.describeRoutes()
+}
+
+// describeRoutes lists the route sets served
+func (l listener) describeRoutes() string {
+	if len(l.routes) == 0 {
+		return "all routes"
+	}
+	names := make([]string, len(l.routes))
+	for i, rs := range l.routes {
+		names[i] = string(rs)
+	}
+	return strings.Join(names, ", ") + " routes"
+}
+
+// routeSetOf returns the route set a request path belongs to
+func routeSetOf(p string) routeSet {
+	// The summary is public data for phone widgets, not part of the API
+	// used by loggers
+	if p == "/api/v1/summary" {
+		return routesPublic
+	}
+	for _, prefix := range []string{"/api", "/index.php/api"} {
+		if p == prefix || strings.HasPrefix(p, prefix+"/") {
+			return routesAPI
+		}
+	}
+	if p == "/admin" || strings.HasPrefix(p, "/admin/") {
+		return routesAdmin
+	}
+	return routesPublic
+}
+
+// filter wraps next to serve only the listener's route sets, answering
+// anything else with a 404. Static assets, the theme and language
+// preferences and the health check are served everywhere, so admin pages
+// still work on an admin-only listener.
+func (l listener) filter(next http.Handler, assets *assetManifest) http.Handler {
+	if len(l.routes) == 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := r.URL.Path
+		if slices.Contains(l.routes, routeSetOf(p)) || assets.isAsset(p) || p == "/theme" || p == "/locale" || p == "/healthz" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		http.NotFound(w, r)
+	})
+}
+
+// writeDeadlineKey is the request context key of the response controller
+// for the connection serving the request
+type writeDeadlineKey struct{}
+
+// withWriteDeadlines wraps h so handlers can extend the server write timeout
+// with extendWriteDeadline. flamego's response writer doesn't unwrap to the
+// connection, so the controller is taken before flamego wraps the writer.
+func withWriteDeadlines(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), writeDeadlineKey{}, http.NewResponseController(w))
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// extendWriteDeadline moves the write deadline of the connection serving r
+// to d from now, for responses that outlast the server write timeout
+func extendWriteDeadline(r *http.Request, d time.Duration) error {
+	rc, ok := r.Context().Value(writeDeadlineKey{}).(*http.ResponseController)
+	if !ok {
+		return http.ErrNotSupported
+	}
+	return rc.SetWriteDeadline(time.Now().Add(d))
+}
+
+// newServer creates the server for a listener. HTTPS listeners negotiate
+// HTTP/2 unless it is disabled.
+func (l listener) newServer(handler http.Handler, http2 bool) *http.Server {
+	srv := &http.Server{
+		Addr:         l.addr,
+		Handler:      withWriteDeadlines(handler),
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+	if l.tls && !http2 {
+		// An empty, non-nil map turns off the built-in HTTP/2 support
+		srv.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+	}
+	return srv
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestParseListener(t *testing.T) {
+	l, err := parseListener("8080")
+	if err != nil || l.addr != "0.0.0.0:8080" || len(l.routes) != 0 {
+		t.Errorf("Expected a bare port to serve everything on all interfaces, got %+v (%v)", l, err)
+	}
+
+	l, err = parseListener("127.0.0.1:8081=admin, API,admin")
+	if err != nil {
+		t.Fatalf("parseListener failed: %v", err)
+	}
+	if l.addr != "127.0.0.1:8081" || len(l.routes) != 2 || l.String() != "HTTP with admin, api routes" {
+		t.Errorf("Unexpected listener %+v (%s)", l, l)
+	}
+
+	l, err = parseListener("https://:443=public")
+	if err != nil || !l.tls || l.addr != ":443" || l.String() != "HTTPS with public routes" {
+		t.Errorf("Expected an HTTPS public listener, got %+v (%v)", l, err)
+	}
+
+	for _, bad := range []string{"127.0.0.1:8081=private", "[::1=api"} {
+		if _, err := parseListener(bad); err == nil {
+			t.Errorf("Expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestListenerFilter(t *testing.T) {
+	assets, err := newAssetManifest(fstest.MapFS{"main.css": {Data: []byte("body{}")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	admin, _ := parseListener("127.0.0.1:8081=admin,api")
+	public, _ := parseListener(":443=public")
+	tests := []struct {
+		l    listener
+		path string
+		want int
+	}{
+		{admin, "/admin/login", http.StatusOK},
+		{admin, "/api/v1/qsos", http.StatusOK},
+		{admin, "/index.php/api/qso", http.StatusOK},
+		{admin, "/main.css", http.StatusOK},
+		{admin, assets.path("/main.css"), http.StatusOK},
+		{admin, "/theme", http.StatusOK},
+		{admin, "/", http.StatusNotFound},
+		{admin, "/qso/W1AW/1", http.StatusNotFound},
+		{public, "/", http.StatusOK},
+		{public, "/apiary", http.StatusOK},
+		{public, "/admin", http.StatusNotFound},
+		{public, "/api/auth/key", http.StatusNotFound},
+		{public, "/api/v1/summary", http.StatusOK},
+		{admin, "/api/v1/summary", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		tt.l.filter(next, assets).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rec.Code != tt.want {
+			t.Errorf("%s on %s: expected %d, got %d", tt.path, tt.l, tt.want, rec.Code)
+		}
+	}
+}
+
+// writeTestCertificate writes a self-signed certificate for 127.0.0.1
+func writeTestCertificate(t *testing.T) (certFile, keyFile string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	certFile, keyFile = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestListenerServesHTTP2OverTLS(t *testing.T) {
+	certFile, keyFile := writeTestCertificate(t)
+	l, _ := parseListener("https://127.0.0.1:0")
+
+	for _, http2 := range []bool{true, false} {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		srv := l.newServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}), http2)
+		go func() { _ = srv.ServeTLS(ln, certFile, keyFile) }()
+
+		client := &http.Client{Transport: &http.Transport{
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+			ForceAttemptHTTP2: true,
+		}}
+		resp, err := client.Get("https://" + ln.Addr().String() + "/")
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+		_ = srv.Close()
+
+		want := 1
+		if http2 {
+			want = 2
+		}
+		if resp.ProtoMajor != want {
+			t.Errorf("With HTTP/2 %v, expected HTTP/%d, got %s", http2, want, resp.Proto)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/flamego/flamego"
+	"github.com/flamego/template"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// liveHeartbeat is how often the live status is re-sent to keep the stream
+// open and let the on air indicator expire
+const liveHeartbeat = 30 * time.Second
+
+// liveStatus is the on air state of the station and its last logged contact
+type liveStatus struct {
+	OnAir bool   `json:"on_air"`
+	Call  string `json:"call,omitempty"`
+	Band  string `json:"band,omitempty"`
+	Mode  string `json:"mode,omitempty"`
+	Freq  string `json:"freq,omitempty"`
+	Time  string `json:"time,omitempty"`
+	Ago   string `json:"ago,omitempty"`
+}
+
+// newLiveStatus builds the live status from the latest QSO in the log. The
+// station is on air if it logged a contact within the window.
+func newLiveStatus(store utils.QSOStore, window time.Duration, l *localizer) liveStatus {
+	latest := store.GetLatestQSO()
+	if latest == nil || latest.Timestamp.IsZero() {
+		return liveStatus{}
+	}
+
+	return liveStatus{
+		OnAir: time.Since(latest.Timestamp) <= window,
+		Call:  latest.Call,
+		Band:  latest.Band,
+		Mode:  latest.Mode,
+		Freq:  latest.Freq,
+		Time:  l.DateTime(latest.Timestamp),
+		Ago:   l.Ago(latest.Timestamp),
+	}
+}
+
+// handleLive shows whether the station is on air and the last contact heard
+func handleLive(t template.Template, data template.Data, store utils.QSOStore, cfg *siteConfig, l *localizer) {
+	data["Title"] = l.T("live.title")
+	data["LivePage"] = true
+	data["Live"] = newLiveStatus(store, cfg.OnAirWindow, l)
+	data["OnAirMinutes"] = int(cfg.OnAirWindow.Minutes())
+	t.HTML(http.StatusOK, "live")
+}
+
+// newLiveEventsHandler returns a handler streaming live status updates as
+// Server-Sent Events whenever the log changes
+func newLiveEventsHandler(rp *ReloadableParser, events *utils.EventBus) func(c flamego.Context, cfg *siteConfig, l *localizer) {
+	return func(c flamego.Context, cfg *siteConfig, l *localizer) {
+		w := c.ResponseWriter()
+		ctx := c.Request().Context()
+
+		// Log changes only need a nudge; the status is rebuilt from the
+		// latest parser snapshot
+		changed := make(chan struct{}, 1)
+		unsubscribe := events.Subscribe(func(e utils.Event) {
+			if e.Type != utils.EventReload && e.Type != utils.EventNewQSOs {
+				return
+			}
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		})
+		defer unsubscribe()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+
+		ticker := time.NewTicker(liveHeartbeat)
+		defer ticker.Stop()
+
+		for {
+			// Each update extends the server write timeout past the next
+			// heartbeat. Should that fail, the stream ends at the timeout
+			// and the browser reconnects.
+			if err := extendWriteDeadline(c.Request().Request, liveHeartbeat+10*time.Second); err != nil {
+				log.Printf("Live events stream can't outlast the write timeout: %v", err)
+			}
+
+			payload, err := json.Marshal(newLiveStatus(rp.getParser(), cfg.OnAirWindow, l))
+			if err != nil {
+				return
+			}
+			if _, err := fmt.Fprintf(w, "event: status\ndata: %s\n\n", payload); err != nil {
+				return
+			}
+			w.Flush()
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-changed:
+			case <-ticker.C:
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flamego/flamego"
+
+	"github.com/humaidq/humaid-qsl/locales"
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// TestLiveEventsOutlastWriteTimeout keeps a live events stream open past the
+// server write timeout and expects updates to keep arriving
+func TestLiveEventsOutlastWriteTimeout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.adi")
+	if err := os.WriteFile(path, []byte(followHeader+"<CALL:4>W1AW<QSO_DATE:8>20250301<TIME_ON:4>1200<EOR>\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rp, err := NewReloadableParser(path)
+	if err != nil {
+		t.Fatalf("NewReloadableParser: %v", err)
+	}
+	catalog, err := utils.LoadCatalog(locales.Locales)
+	if err != nil {
+		t.Fatalf("LoadCatalog failed: %v", err)
+	}
+
+	events := utils.NewEventBus()
+	f := flamego.New()
+	f.Map(&siteConfig{})
+	f.Map(&localizer{catalog: catalog, locale: "en", units: unitsMetric})
+	f.Get("/live/events", newLiveEventsHandler(rp, events))
+
+	const writeTimeout = 300 * time.Millisecond
+	srv := httptest.NewUnstartedServer(nil)
+	srv.Config = listener{}.newServer(newCompressHandler(f, defaultCompressTypes), false)
+	srv.Config.WriteTimeout = writeTimeout
+	srv.Start()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/live/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	reader := bufio.NewReader(resp.Body)
+
+	nextEvent := func() string {
+		t.Helper()
+		var event strings.Builder
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatalf("Stream ended after %q: %v", event.String(), err)
+			}
+			if line == "\n" {
+				return event.String()
+			}
+			event.WriteString(line)
+		}
+	}
+
+	if event := nextEvent(); !strings.HasPrefix(event, "event: status\n") {
+		t.Fatalf("Expected a status event, got %q", event)
+	}
+
+	time.Sleep(2 * writeTimeout)
+	events.Publish(utils.Event{Type: utils.EventReload})
+
+	if event := nextEvent(); !strings.HasPrefix(event, "event: status\n") {
+		t.Fatalf("Expected a status event after the write timeout, got %q", event)
+	}
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// abuseLogPath is where failed lookups and lockouts are logged. Each line
+// starts with the local time and an event name, so fail2ban can match
+// lockouts with a filter such as:
+//
+//	[Definition]
+//	failregex = ^ LOCKOUT client=<HOST>\b
+const abuseLogPath = "qsl-abuse.log"
+
+// lookupGuard counts failed QSO lookups per client and temporarily blocks
+// clients that fail too many within a window, which is what enumerating
+// callsigns or timestamps looks like. A nil lookupGuard does nothing.
+type lookupGuard struct {
+	blocks   *utils.BlockList
+	failures *utils.RateLimiter
+	limit    int
+	window   time.Duration
+	duration time.Duration
+	logPath  string
+
+	mutex sync.Mutex // Serialises writes to the log
+}
+
+// newLookupGuard builds the lookup guard from command line flags, returning
+// nil when lockouts are disabled
+func newLookupGuard(cmd *cli.Command, blocks *utils.BlockList) *lookupGuard {
+	limit := cmd.Int("lockout-failures")
+	if limit <= 0 {
+		return nil
+	}
+
+	window := cmd.Duration("lockout-window")
+	return &lookupGuard{
+		blocks:   blocks,
+		failures: utils.NewRateLimiter(limit, window),
+		limit:    limit,
+		window:   window,
+		duration: cmd.Duration("lockout-duration"),
+		logPath:  abuseLogPath,
+	}
+}
+
+// failed records a lookup by the client that found nothing, locking the
+// client out once it exceeds the limit. It reports whether it locked out.
+func (g *lookupGuard) failed(ip, callsign string, searchTime time.Time) bool {
+	if g == nil {
+		return false
+	}
+
+	// The callsign is quoted, as a visitor could otherwise forge log lines
+	// with a newline in it
+	g.logEvent("LOOKUP_FAILED client=%s call=%q time=%s", ip, callsign, searchTime.Format("2006-01-02T15:04"))
+	if g.failures.Allow(ip) {
+		return false
+	}
+
+	reason := fmt.Sprintf("More than %d failed lookups in %s", g.limit, g.window)
+	if _, err := g.blocks.Add(ip, reason, g.duration); err != nil {
+		log.Printf("Failed to lock out %s: %v", ip, err)
+		return false
+	}
+
+	log.Printf("Locked out %s for %s after repeated failed lookups", ip, g.duration)
+	g.logEvent("LOCKOUT client=%s failures=%d window=%s duration=%s", ip, g.failures.Count(ip), g.window, g.duration)
+	return true
+}
+
+// logEvent appends a timestamped event line to the abuse log
+func (g *lookupGuard) logEvent(format string, args ...any) {
+	line := time.Now().Format("2006-01-02 15:04:05") + " " + fmt.Sprintf(format, args...) + "\n"
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	logFile, err := os.OpenFile(g.logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		log.Printf("Failed to open abuse log: %v", err)
+		return
+	}
+	defer logFile.Close()
+
+	if _, err := logFile.WriteString(line); err != nil {
+		log.Printf("Failed to write abuse log: %v", err)
+	}
+}
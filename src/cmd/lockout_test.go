@@ -0,0 +1,98 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+func TestLookupGuardLockout(t *testing.T) {
+	dir := t.TempDir()
+	blocks, err := utils.NewBlockList(filepath.Join(dir, "blocklist.json"))
+	if err != nil {
+		t.Fatalf("NewBlockList: %v", err)
+	}
+	g := &lookupGuard{
+		blocks:   blocks,
+		failures: utils.NewRateLimiter(3, time.Minute),
+		limit:    3,
+		window:   time.Minute,
+		duration: time.Hour,
+		logPath:  filepath.Join(dir, "abuse.log"),
+	}
+
+	at := time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		if g.failed("192.0.2.1", "A61AA", at) {
+			t.Fatalf("locked out after %d failures", i+1)
+		}
+	}
+	if blocks.Blocked("192.0.2.1") {
+		t.Fatalf("blocked before the limit")
+	}
+
+	if !g.failed("192.0.2.1", "A61AB", at) {
+		t.Fatalf("not locked out above the limit")
+	}
+	if !blocks.Blocked("192.0.2.1") {
+		t.Errorf("client not blocked after lockout")
+	}
+	if blocks.Blocked("192.0.2.2") {
+		t.Errorf("other client blocked")
+	}
+
+	data, err := os.ReadFile(g.logPath)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("got %d log lines, want 5:\n%s", len(lines), data)
+	}
+	if !strings.Contains(lines[0], ` LOOKUP_FAILED client=192.0.2.1 call="A61AA" time=2025-03-01T12:30`) {
+		t.Errorf("unexpected failure line %q", lines[0])
+	}
+	if !strings.Contains(lines[4], " LOCKOUT client=192.0.2.1 ") {
+		t.Errorf("unexpected lockout line %q", lines[4])
+	}
+}
+
+func TestLookupGuardLogInjection(t *testing.T) {
+	dir := t.TempDir()
+	g := &lookupGuard{
+		failures: utils.NewRateLimiter(3, time.Minute),
+		limit:    3,
+		window:   time.Minute,
+		logPath:  filepath.Join(dir, "abuse.log"),
+	}
+
+	forged := "A61AA\n2025-03-01 12:30:00 LOCKOUT client=192.0.2.99 failures=3"
+	g.failed("192.0.2.1", forged, time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC))
+
+	data, err := os.ReadFile(g.logPath)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("got %d log lines, want 1:\n%s", len(lines), data)
+	}
+	if !strings.Contains(lines[0], ` call="A61AA\n2025-03-01 12:30:00 LOCKOUT client=192.0.2.99 failures=3" `) {
+		t.Errorf("callsign not quoted in %q", lines[0])
+	}
+}
+
+func TestLookupGuardNil(t *testing.T) {
+	var g *lookupGuard
+	if g.failed("192.0.2.1", "A61AA", time.Now()) {
+		t.Errorf("nil guard locked out")
+	}
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/flamego/csrf"
+	"github.com/flamego/flamego"
+	"github.com/flamego/template"
+	"github.com/urfave/cli/v3"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// lotwStorePath is where imported LoTW confirmations are kept
+const lotwStorePath = "qsl-lotw.json"
+
+// maxLoTWReportSize limits uploaded LoTW reports
+const maxLoTWReportSize = 32 << 20
+
+// CmdLoTWImport imports confirmations from a LoTW report
+var CmdLoTWImport = &cli.Command{
+	Name:  "lotw-import",
+	Usage: "Import confirmations from a LoTW report (lotwreport.adi) into the running site's data",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "report",
+			Usage:    "path to the LoTW report downloaded from lotw.arrl.org",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  "adif",
+			Usage: "path to ADIF file containing QSO logs, to list confirmations matching no QSO",
+		},
+		&cli.StringFlag{
+			Name:  "store",
+			Value: lotwStorePath,
+			Usage: "path to the site's LoTW confirmations file",
+		},
+	},
+	Action: importLoTW,
+}
+
+func importLoTW(ctx context.Context, cmd *cli.Command) error {
+	report, err := os.Open(cmd.String("report"))
+	if err != nil {
+		return fmt.Errorf("failed to open LoTW report: %w", err)
+	}
+	defer report.Close()
+
+	confirmations, err := utils.ParseLoTWReport(report)
+	if err != nil {
+		return err
+	}
+	store, err := utils.NewLoTWStore(cmd.String("store"))
+	if err != nil {
+		return err
+	}
+	added, err := store.Import(confirmations)
+	if err != nil {
+		return fmt.Errorf("failed to save LoTW confirmations: %w", err)
+	}
+	fmt.Printf("Imported %d confirmations, %d new\n", len(confirmations), added)
+
+	if adifPath := cmd.String("adif"); adifPath != "" {
+		file, err := os.Open(adifPath)
+		if err != nil {
+			return fmt.Errorf("failed to open ADIF file: %w", err)
+		}
+		defer file.Close()
+
+		parser := utils.NewADIFParser()
+		if err := parser.ParseFile(file); err != nil {
+			return fmt.Errorf("failed to parse ADIF file: %w", err)
+		}
+		unmatched := store.Unmatched(parser.GetQSOs())
+		fmt.Printf("%d confirmations match no logged QSO\n", len(unmatched))
+		for _, c := range unmatched {
+			fmt.Printf("  %-13s %s %-5s %s\n", c.Call, c.QSOTime.UTC().Format("2006-01-02 15:04"), c.Band, c.ModeGroup)
+		}
+	}
+	return nil
+}
+
+// handleAdminLoTW shows the imported LoTW confirmations and those matching
+// no logged QSO
+func handleAdminLoTW(c flamego.Context, t template.Template, data template.Data, x csrf.CSRF, rp *ReloadableParser, lotw *utils.LoTWStore) {
+	rp.mutex.RLock()
+	fileQSOs := rp.fileQSOs
+	rp.mutex.RUnlock()
+
+	data["Title"] = "Admin: LoTW"
+	data["CSRFToken"] = x.Token()
+	data["Count"] = lotw.Count()
+	data["Unmatched"] = lotw.Unmatched(fileQSOs)
+	data["Error"] = c.Query("error")
+	if imported := c.Query("imported"); imported != "" {
+		data["Imported"] = imported
+		data["Added"] = c.Query("added")
+	}
+	t.HTML(http.StatusOK, "admin-lotw")
+}
+
+// newAdminLoTWImportHandler returns a handler importing an uploaded LoTW
+// report and republishing the log so the confirmations show immediately
+func newAdminLoTWImportHandler(rp *ReloadableParser) flamego.Handler {
+	return func(c flamego.Context, lotw *utils.LoTWStore) {
+		r := c.Request().Request
+		r.Body = http.MaxBytesReader(c.ResponseWriter(), r.Body, maxLoTWReportSize)
+
+		file, _, err := r.FormFile("report")
+		if err != nil {
+			c.Redirect("/admin/lotw?error=upload", http.StatusFound)
+			return
+		}
+		defer file.Close()
+
+		confirmations, err := utils.ParseLoTWReport(file)
+		if err != nil {
+			log.Printf("Failed to parse LoTW report: %v", err)
+			c.Redirect("/admin/lotw?error=parse", http.StatusFound)
+			return
+		}
+		added, err := lotw.Import(confirmations)
+		if err != nil {
+			log.Printf("Failed to save LoTW confirmations: %v", err)
+			c.Redirect("/admin/lotw?error=save", http.StatusFound)
+			return
+		}
+		rp.refresh()
+
+		log.Printf("Imported %d LoTW confirmations, %d new", len(confirmations), added)
+		c.Redirect("/admin/lotw?imported="+strconv.Itoa(len(confirmations))+"&added="+strconv.Itoa(added), http.StatusFound)
+	}
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/flamego/flamego"
+	"github.com/flamego/session"
+	"github.com/flamego/template"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// maintenanceRetryAfter is the Retry-After value, in seconds, sent with the
+// maintenance page
+const maintenanceRetryAfter = "600"
+
+// newMaintenanceMiddleware returns a middleware serving the maintenance page
+// for public routes while maintenance mode is on. The admin area, the API,
+// the health check and static assets stay reachable, and a signed in admin
+// still sees the public pages to check on them.
+func newMaintenanceMiddleware(maintenance *utils.MaintenanceMode, assets *assetManifest) flamego.Handler {
+	return func(c flamego.Context, t template.Template, data template.Data, s session.Session, l *localizer) {
+		state := maintenance.State()
+		if !state.Enabled || s.Get(adminSessionKey) != nil {
+			return
+		}
+		p := c.Request().URL.Path
+		if routeSetOf(p) != routesPublic || assets.isAsset(p) || p == "/healthz" || p == "/theme" || p == "/locale" {
+			return
+		}
+
+		c.ResponseWriter().Header().Set("Retry-After", maintenanceRetryAfter)
+		data["Title"] = l.T("maintenance.title")
+		data["MaintenanceMessage"] = state.Message
+		t.HTML(http.StatusServiceUnavailable, "maintenance")
+	}
+}
+
+// handleHealth reports that the server is up, for load balancers and
+// monitoring. It answers even in maintenance mode.
+func handleHealth(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write([]byte("ok\n"))
+}
+
+// handleAdminMaintenance turns maintenance mode on, or updates the message
+// shown while it is on
+func handleAdminMaintenance(c flamego.Context, maintenance *utils.MaintenanceMode) {
+	message := strings.TrimSpace(c.Request().FormValue("message"))
+	if err := maintenance.Enable(message); err != nil {
+		log.Printf("Failed to enable maintenance mode: %v", err)
+		c.Redirect("/admin?maintenance=failed", http.StatusFound)
+		return
+	}
+	log.Printf("Maintenance mode enabled")
+	c.Redirect("/admin", http.StatusFound)
+}
+
+// handleAdminMaintenanceOff turns maintenance mode off
+func handleAdminMaintenanceOff(c flamego.Context, maintenance *utils.MaintenanceMode) {
+	if err := maintenance.Disable(); err != nil {
+		log.Printf("Failed to disable maintenance mode: %v", err)
+		c.Redirect("/admin?maintenance=failed", http.StatusFound)
+		return
+	}
+	log.Printf("Maintenance mode disabled")
+	c.Redirect("/admin", http.StatusFound)
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/flamego/csrf"
+	"github.com/flamego/flamego"
+	"github.com/flamego/template"
+)
+
+// mapCacheEntry is a generated map image in the cache
+type mapCacheEntry struct {
+	Name     string
+	Size     string
+	Modified time.Time
+	PagePath string // Confirmation page the map belongs to
+}
+
+// listMapCache returns the cached map images, newest first, and their total
+// size in bytes
+func listMapCache(cfg *siteConfig, store imageStore) ([]mapCacheEntry, uint64, error) {
+	images, err := store.List()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read map cache: %w", err)
+	}
+
+	var entries []mapCacheEntry
+	var total uint64
+	for _, img := range images {
+		total += uint64(img.Size)
+		entries = append(entries, mapCacheEntry{
+			Name:     img.Name,
+			Size:     humanize.IBytes(uint64(img.Size)),
+			Modified: img.Modified,
+			PagePath: mapPagePath(cfg, img.Name),
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Modified.After(entries[j].Modified)
+	})
+	return entries, total, nil
+}
+
+// mapPagePath returns the confirmation page path for a map or card file name,
+// undoing the slash replacement from mapFileNameFor
+func mapPagePath(cfg *siteConfig, name string) string {
+	base := strings.TrimSuffix(strings.TrimSuffix(name, ".png"), ".card")
+	base = strings.ReplaceAll(base, "_", "/")
+	call, at, ok := parseLegacyQSOPath(base)
+	if !ok {
+		return ""
+	}
+	return cfg.qsoPath(call, at)
+}
+
+// isMapCacheFile reports whether name is a plain map file name, so form input
+// can't be used to reach outside the cache directory
+func isMapCacheFile(name string) bool {
+	return name != "" && filepath.Base(name) == name && strings.HasSuffix(name, ".png")
+}
+
+// handleAdminMaps lists the generated map cache
+func handleAdminMaps(c flamego.Context, t template.Template, data template.Data, x csrf.CSRF, cfg *siteConfig, store imageStore) {
+	entries, total, err := listMapCache(cfg, store)
+	if err != nil {
+		data["MapError"] = err.Error()
+	}
+
+	data["Title"] = "Admin: Map Cache"
+	data["CSRFToken"] = x.Token()
+	data["Maps"] = entries
+	data["MapCount"] = len(entries)
+	data["MapTotalSize"] = humanize.IBytes(total)
+	data["Purged"] = c.Query("purged")
+	t.HTML(http.StatusOK, "admin-maps")
+}
+
+// handleAdminMapsPurge deletes the selected map images so they are rendered
+// again on the next visit
+func handleAdminMapsPurge(c flamego.Context, store imageStore) {
+	r := c.Request().Request
+	if err := r.ParseForm(); err != nil {
+		c.Redirect("/admin/maps", http.StatusFound)
+		return
+	}
+
+	purged := 0
+	for _, name := range r.Form["file"] {
+		if !isMapCacheFile(name) {
+			continue
+		}
+		if err := store.Delete(name); err != nil {
+			log.Printf("Failed to purge map %s: %v", name, err)
+			continue
+		}
+		purged++
+	}
+
+	log.Printf("Purged %d cached maps", purged)
+	c.Redirect(fmt.Sprintf("/admin/maps?purged=%d", purged), http.StatusFound)
+}
+
+// handleAdminMapsClear deletes every cached map image
+func handleAdminMapsClear(c flamego.Context, cfg *siteConfig, store imageStore) {
+	entries, _, err := listMapCache(cfg, store)
+	if err != nil {
+		log.Printf("Failed to clear map cache: %v", err)
+	}
+
+	purged := 0
+	for _, e := range entries {
+		if err := store.Delete(e.Name); err != nil {
+			log.Printf("Failed to purge map %s: %v", e.Name, err)
+			continue
+		}
+		purged++
+	}
+
+	log.Printf("Cleared map cache, removed %d maps", purged)
+	c.Redirect(fmt.Sprintf("/admin/maps?purged=%d", purged), http.StatusFound)
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flamego/flamego"
+)
+
+func TestIsMapCacheFile(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"DL1ABC-1740832200.png", true},
+		{"A66H_P-1740832200.card.png", true},
+		{"", false},
+		{"../secret.png", false},
+		{"maps/../../secret.png", false},
+		{"sub/DL1ABC-1740832200.png", false},
+		{"/etc/secret.png", false},
+		{"DL1ABC-1740832200.jpg", false},
+		{"notes.txt", false},
+		{".", false},
+		{"..", false},
+	}
+	for _, tt := range tests {
+		if got := isMapCacheFile(tt.name); got != tt.want {
+			t.Errorf("isMapCacheFile(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestListMapCache(t *testing.T) {
+	store := newMemoryImageStore(1<<20, nil)
+	at := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return at }
+	if err := store.Put("DL1ABC-1740832200.png", make([]byte, 1024)); err != nil {
+		t.Fatal(err)
+	}
+	at = at.Add(time.Hour)
+	if err := store.Put("A66H_P-1740832200.card.png", make([]byte, 512)); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, total, err := listMapCache(&siteConfig{}, store)
+	if err != nil {
+		t.Fatalf("listMapCache failed: %v", err)
+	}
+	if total != 1536 {
+		t.Errorf("Expected a total of 1536 bytes, got %d", total)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Name != "A66H_P-1740832200.card.png" || entries[0].PagePath != "/qso/A66H/P/1740832200" {
+		t.Errorf("Expected the newest card first, got %+v", entries[0])
+	}
+	if entries[1].Size != "1.0 KiB" || entries[1].PagePath != "/qso/DL1ABC/1740832200" {
+		t.Errorf("Expected the map with its size and page, got %+v", entries[1])
+	}
+}
+
+func TestAdminMapsPurgeAndClear(t *testing.T) {
+	root := t.TempDir()
+	store := diskImageStore{dir: filepath.Join(root, "maps")}
+	if err := os.Mkdir(store.dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"DL1ABC-1740832200.png", "W1AW-1740832200.png", "A66H-1740832200.png"} {
+		if err := store.Put(name, []byte("png")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A file beside the cache which form input must not reach
+	secret := filepath.Join(root, "secret.png")
+	if err := os.WriteFile(secret, []byte("keep"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	f := flamego.New()
+	f.Map(&siteConfig{})
+	f.MapTo(store, (*imageStore)(nil))
+	f.Post("/admin/maps/purge", handleAdminMapsPurge)
+	f.Post("/admin/maps/clear", handleAdminMapsClear)
+
+	post := func(path string, form url.Values) string {
+		t.Helper()
+		r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		f.ServeHTTP(rec, r)
+		if rec.Code != http.StatusFound {
+			t.Fatalf("POST %s = %d", path, rec.Code)
+		}
+		return rec.Header().Get("Location")
+	}
+
+	got := post("/admin/maps/purge", url.Values{"file": {
+		"DL1ABC-1740832200.png", "../secret.png", secret, "notes.txt",
+	}})
+	if got != "/admin/maps?purged=1" {
+		t.Errorf("Expected one map purged, got %s", got)
+	}
+	if store.Exists("DL1ABC-1740832200.png") || !store.Exists("W1AW-1740832200.png") {
+		t.Error("Expected only the selected map to be purged")
+	}
+	if _, err := os.Stat(secret); err != nil {
+		t.Errorf("Expected the file outside the cache to be kept: %v", err)
+	}
+
+	if got := post("/admin/maps/clear", nil); got != "/admin/maps?purged=2" {
+		t.Errorf("Expected the remaining 2 maps cleared, got %s", got)
+	}
+	entries, total, err := listMapCache(&siteConfig{}, store)
+	if err != nil || len(entries) != 0 || total != 0 {
+		t.Errorf("Expected an empty cache, got %d entries of %d bytes (%v)", len(entries), total, err)
+	}
+	if _, err := os.Stat(secret); err != nil {
+		t.Errorf("Expected clearing to keep the file outside the cache: %v", err)
+	}
+}
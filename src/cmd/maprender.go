@@ -0,0 +1,222 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"bytes"
+	"container/heap"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// mapPriority orders queued map renders
+type mapPriority int
+
+const (
+	// mapPrefetch renders are speculative, started when a page is viewed so
+	// the image is ready by the time the browser asks for it
+	mapPrefetch mapPriority = iota
+	// mapWaiting renders have a request waiting on them
+	mapWaiting
+)
+
+// mapJob is a queued map render
+type mapJob struct {
+	fileName string
+	qso      utils.QSO
+	priority mapPriority
+	seq      uint64 // Queue order within a priority
+	index    int    // Position in the heap, -1 once taken
+	done     chan struct{}
+	err      error
+}
+
+// mapQueue is a heap of jobs, highest priority first and oldest first
+// within a priority
+type mapQueue []*mapJob
+
+func (q mapQueue) Len() int { return len(q) }
+
+func (q mapQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority > q[j].priority
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q mapQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *mapQueue) Push(x interface{}) {
+	job := x.(*mapJob)
+	job.index = len(*q)
+	*q = append(*q, job)
+}
+
+func (q *mapQueue) Pop() interface{} {
+	old := *q
+	job := old[len(old)-1]
+	old[len(old)-1] = nil
+	job.index = -1
+	*q = old[:len(old)-1]
+	return job
+}
+
+// mapRenderer renders maps on a fixed number of workers, so a burst of
+// visits can't start an unbounded number of renders. Renders someone is
+// waiting for jump ahead of prefetches, and prefetches are dropped once too
+// many are queued.
+type mapRenderer struct {
+	store       imageStore
+	render      func(fileName string, qso utils.QSO) error
+	maxPrefetch int
+
+	mutex      sync.Mutex
+	wake       *sync.Cond
+	queue      mapQueue
+	jobs       map[string]*mapJob // Queued or running jobs by file name
+	prefetches int                // Prefetch jobs in the queue
+	seq        uint64
+}
+
+// newMapRenderer creates a renderer for maps kept in store. Workers must be
+// started with start.
+func newMapRenderer(store imageStore, render func(fileName string, qso utils.QSO) error, maxPrefetch int) *mapRenderer {
+	r := &mapRenderer{
+		store:       store,
+		render:      render,
+		maxPrefetch: maxPrefetch,
+		jobs:        make(map[string]*mapJob),
+	}
+	r.wake = sync.NewCond(&r.mutex)
+	return r
+}
+
+// start launches the render workers
+func (r *mapRenderer) start(workers int) {
+	for i := 0; i < workers; i++ {
+		go r.work()
+	}
+}
+
+func (r *mapRenderer) exists(fileName string) bool {
+	return r.store.Exists(fileName)
+}
+
+// Render renders a map unless it already exists, waiting until it's done
+func (r *mapRenderer) Render(fileName string, qso utils.QSO) error {
+	if r.exists(fileName) {
+		return nil
+	}
+
+	r.mutex.Lock()
+	job := r.enqueue(fileName, qso, mapWaiting)
+	r.mutex.Unlock()
+
+	<-job.done
+	return job.err
+}
+
+// Prefetch queues a map to be rendered in the background unless it already
+// exists, reporting false if it was dropped because the queue is full
+func (r *mapRenderer) Prefetch(fileName string, qso utils.QSO) bool {
+	if r.exists(fileName) {
+		return true
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, queued := r.jobs[fileName]; !queued && r.prefetches >= r.maxPrefetch {
+		// The map will still be rendered if the browser requests it
+		log.Printf("Map render queue is full, not prefetching %s", fileName)
+		return false
+	}
+	r.enqueue(fileName, qso, mapPrefetch)
+	return true
+}
+
+// enqueue adds a job, or raises the priority of a job already queued for the
+// same map. The caller must hold the lock.
+func (r *mapRenderer) enqueue(fileName string, qso utils.QSO, priority mapPriority) *mapJob {
+	if job, ok := r.jobs[fileName]; ok {
+		if priority > job.priority && job.index >= 0 {
+			if job.priority == mapPrefetch {
+				r.prefetches--
+			}
+			job.priority = priority
+			heap.Fix(&r.queue, job.index)
+		}
+		return job
+	}
+
+	r.seq++
+	job := &mapJob{
+		fileName: fileName,
+		qso:      qso,
+		priority: priority,
+		seq:      r.seq,
+		done:     make(chan struct{}),
+	}
+	r.jobs[fileName] = job
+	if priority == mapPrefetch {
+		r.prefetches++
+	}
+	heap.Push(&r.queue, job)
+	r.wake.Signal()
+	return job
+}
+
+func (r *mapRenderer) work() {
+	for {
+		r.mutex.Lock()
+		for r.queue.Len() == 0 {
+			r.wake.Wait()
+		}
+		job := heap.Pop(&r.queue).(*mapJob)
+		if job.priority == mapPrefetch {
+			r.prefetches--
+		}
+		r.mutex.Unlock()
+
+		// Another job may have rendered it while this one was queued
+		if !r.exists(job.fileName) {
+			job.err = r.render(job.fileName, job.qso)
+			if job.err != nil {
+				log.Printf("Failed to generate map %s: %v", job.fileName, job.err)
+			}
+		}
+
+		r.mutex.Lock()
+		delete(r.jobs, job.fileName)
+		r.mutex.Unlock()
+		close(job.done)
+	}
+}
+
+// serveMap serves a rendered map or card from the image store with
+// validators, so browsers and the QRZ page revalidate instead of downloading
+// the same image again
+func serveMap(w http.ResponseWriter, r *http.Request, store imageStore, name string) error {
+	data, info, err := store.Get(name)
+	if err != nil {
+		return fmt.Errorf("failed to read map: %w", err)
+	}
+
+	// Maps are only ever replaced by a new render, which changes the
+	// modification time
+	w.Header().Set("ETag", fmt.Sprintf(`"%x-%x"`, info.Modified.UnixNano(), info.Size))
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Content-Type", "image/png")
+	http.ServeContent(w, r, "", info.Modified, bytes.NewReader(data))
+	return nil
+}
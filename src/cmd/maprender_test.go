@@ -0,0 +1,167 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+var testMapQSO = utils.QSO{Call: "W1AW", MyGridSquare: "LL75", GridSquare: "FN31"}
+
+// recordingRenderer records render order and writes an empty map file
+type recordingRenderer struct {
+	dir   string
+	mutex sync.Mutex
+	order []string
+}
+
+func (r *recordingRenderer) render(fileName string, qso utils.QSO) error {
+	r.mutex.Lock()
+	r.order = append(r.order, fileName)
+	r.mutex.Unlock()
+	return os.WriteFile(filepath.Join(r.dir, fileName), nil, 0644)
+}
+
+func TestMapRendererPrioritisesWaitingRenders(t *testing.T) {
+	rec := &recordingRenderer{dir: t.TempDir()}
+	r := newMapRenderer(diskImageStore{dir: rec.dir}, rec.render, 10)
+
+	// Queue before starting the worker so the order is deterministic
+	r.Prefetch("a.png", testMapQSO)
+	r.Prefetch("b.png", testMapQSO)
+	done := make(chan error)
+	go func() { done <- r.Render("c.png", testMapQSO) }()
+	for {
+		r.mutex.Lock()
+		n := r.queue.Len()
+		r.mutex.Unlock()
+		if n == 3 {
+			break
+		}
+	}
+
+	r.start(1)
+	if err := <-done; err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if err := r.Render("b.png", testMapQSO); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if err := r.Render("a.png", testMapQSO); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	rec.mutex.Lock()
+	defer rec.mutex.Unlock()
+	if len(rec.order) != 3 || rec.order[0] != "c.png" {
+		t.Errorf("Expected the waiting render first, got %v", rec.order)
+	}
+}
+
+func TestMapRendererRaisesQueuedPrefetch(t *testing.T) {
+	rec := &recordingRenderer{dir: t.TempDir()}
+	r := newMapRenderer(diskImageStore{dir: rec.dir}, rec.render, 10)
+
+	r.Prefetch("a.png", testMapQSO)
+	r.Prefetch("b.png", testMapQSO)
+
+	r.mutex.Lock()
+	r.enqueue("b.png", testMapQSO, mapWaiting)
+	if r.queue.Len() != 2 {
+		t.Errorf("Expected the queued job to be reused, got %d jobs", r.queue.Len())
+	}
+	if r.queue[0].fileName != "b.png" {
+		t.Errorf("Expected b.png at the head of the queue, got %s", r.queue[0].fileName)
+	}
+	if r.prefetches != 1 {
+		t.Errorf("Expected 1 prefetch left, got %d", r.prefetches)
+	}
+	r.mutex.Unlock()
+}
+
+func TestMapRendererDropsPrefetchesWhenFull(t *testing.T) {
+	rec := &recordingRenderer{dir: t.TempDir()}
+	r := newMapRenderer(diskImageStore{dir: rec.dir}, rec.render, 2)
+
+	if !r.Prefetch("a.png", testMapQSO) || !r.Prefetch("b.png", testMapQSO) {
+		t.Fatal("Expected prefetches below the limit to be queued")
+	}
+	if r.Prefetch("c.png", testMapQSO) {
+		t.Error("Expected a prefetch beyond the limit to be dropped")
+	}
+	if !r.Prefetch("a.png", testMapQSO) {
+		t.Error("Expected an already queued prefetch to be accepted")
+	}
+
+	// Waiting renders are never dropped
+	r.start(1)
+	if err := r.Render("c.png", testMapQSO); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+}
+
+func TestMapRendererSkipsExistingMaps(t *testing.T) {
+	rec := &recordingRenderer{dir: t.TempDir()}
+	r := newMapRenderer(diskImageStore{dir: rec.dir}, rec.render, 10)
+	if err := os.WriteFile(filepath.Join(rec.dir, "a.png"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// No workers are running, so this would block if it queued a job
+	if err := r.Render("a.png", testMapQSO); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if len(rec.order) != 0 {
+		t.Errorf("Expected no renders, got %v", rec.order)
+	}
+}
+
+func TestServeMapConditional(t *testing.T) {
+	store := diskImageStore{dir: t.TempDir()}
+	if err := store.Put("a.png", []byte("png")); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	if err := serveMap(rec, httptest.NewRequest(http.MethodGet, "/qso/W1AW/1.png", nil), store, "a.png"); err != nil {
+		t.Fatalf("serveMap failed: %v", err)
+	}
+	etag, modified := rec.Header().Get("ETag"), rec.Header().Get("Last-Modified")
+	if rec.Code != http.StatusOK || rec.Body.String() != "png" {
+		t.Fatalf("Expected the map, got %d %q", rec.Code, rec.Body.String())
+	}
+	if etag == "" || modified == "" {
+		t.Fatalf("Expected validators, got ETag %q and Last-Modified %q", etag, modified)
+	}
+
+	for header, value := range map[string]string{"If-None-Match": etag, "If-Modified-Since": modified} {
+		req := httptest.NewRequest(http.MethodGet, "/qso/W1AW/1.png", nil)
+		req.Header.Set(header, value)
+		rec := httptest.NewRecorder()
+		if err := serveMap(rec, req, store, "a.png"); err != nil {
+			t.Fatalf("serveMap failed: %v", err)
+		}
+		if rec.Code != http.StatusNotModified {
+			t.Errorf("Expected 304 for %s, got %d", header, rec.Code)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/qso/W1AW/1.png", nil)
+	req.Header.Set("If-None-Match", `"stale"`)
+	rec = httptest.NewRecorder()
+	if err := serveMap(rec, req, store, "a.png"); err != nil {
+		t.Fatalf("serveMap failed: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected 200 for a stale ETag, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,164 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/flamego/flamego"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// oEmbedProviderName is the provider name given to oEmbed consumers
+const oEmbedProviderName = "Humaid Alqasimi QSL"
+
+// Size of the rendered QSO maps, used for the embed and its thumbnail
+const (
+	oEmbedWidth  = 600
+	oEmbedHeight = 400
+)
+
+// oEmbedResponse is a rich oEmbed response, see https://oembed.com
+type oEmbedResponse struct {
+	Type            string `json:"type"`
+	Version         string `json:"version"`
+	Title           string `json:"title"`
+	AuthorName      string `json:"author_name"`
+	ProviderName    string `json:"provider_name"`
+	ProviderURL     string `json:"provider_url"`
+	CacheAge        int    `json:"cache_age"`
+	HTML            string `json:"html"`
+	Width           int    `json:"width"`
+	Height          int    `json:"height"`
+	ThumbnailURL    string `json:"thumbnail_url,omitempty"`
+	ThumbnailWidth  int    `json:"thumbnail_width,omitempty"`
+	ThumbnailHeight int    `json:"thumbnail_height,omitempty"`
+}
+
+// oEmbedURL returns the oEmbed discovery URL for a page
+func oEmbedURL(baseURL, pageURL string) string {
+	return baseURL + "/oembed?url=" + url.QueryEscape(pageURL)
+}
+
+// parseConfirmationURL returns the callsign and time route parameters of a
+// QSO confirmation URL on this site
+func parseConfirmationURL(rawURL, baseURL string) (string, string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", false
+	}
+	base, err := url.Parse(baseURL)
+	if err != nil || !strings.EqualFold(u.Host, base.Host) {
+		return "", "", false
+	}
+
+	rest, ok := strings.CutPrefix(u.EscapedPath(), "/qso/")
+	if !ok {
+		return "", "", false
+	}
+	lastSlash := strings.LastIndex(rest, "/")
+	if lastSlash == -1 {
+		return "", "", false
+	}
+	call, err := url.PathUnescape(rest[:lastSlash])
+	if err != nil {
+		return "", "", false
+	}
+	return call, rest[lastSlash+1:], true
+}
+
+// oEmbedSize scales the embed down to fit the consumer's maximum size,
+// keeping the map's aspect ratio
+func oEmbedSize(maxWidth, maxHeight int) (int, int) {
+	width, height := oEmbedWidth, oEmbedHeight
+	if maxWidth > 0 && width > maxWidth {
+		height = height * maxWidth / width
+		width = maxWidth
+	}
+	if maxHeight > 0 && height > maxHeight {
+		width = width * maxHeight / height
+		height = maxHeight
+	}
+	return width, height
+}
+
+// newConfirmationOEmbed builds the rich embed for a QSO confirmation page
+func newConfirmationOEmbed(qso utils.QSO, callsign, baseURL, pagePath string, width, height int) oEmbedResponse {
+	mapURL := ""
+	if canMapQSO(qso) {
+		mapURL = pagePath + ".png"
+	}
+	og := newConfirmationOpenGraph(qso, callsign, baseURL, pagePath, mapURL)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<blockquote class="qsl-embed" style="max-width:%dpx">`, width)
+	if og.Image != "" {
+		fmt.Fprintf(&b, `<a href="%s"><img src="%s" width="%d" height="%d" alt="%s"></a>`,
+			html.EscapeString(og.URL), html.EscapeString(og.Image), width, height, html.EscapeString(og.Title))
+	}
+	fmt.Fprintf(&b, `<p><a href="%s">%s</a></p><p>%s</p></blockquote>`,
+		html.EscapeString(og.URL), html.EscapeString(og.Title), html.EscapeString(og.Description))
+
+	resp := oEmbedResponse{
+		Type:         "rich",
+		Version:      "1.0",
+		Title:        og.Title,
+		AuthorName:   callsign,
+		ProviderName: oEmbedProviderName,
+		ProviderURL:  baseURL,
+		CacheAge:     3600,
+		HTML:         b.String(),
+		Width:        width,
+		Height:       height,
+	}
+	if og.Image != "" {
+		resp.ThumbnailURL = og.Image
+		resp.ThumbnailWidth = og.ImageWidth
+		resp.ThumbnailHeight = og.ImageHeight
+	}
+	return resp
+}
+
+// handleOEmbed answers oEmbed requests for QSO confirmation URLs. Only the
+// JSON format is supported.
+func handleOEmbed(c flamego.Context, store utils.QSOStore, cfg *siteConfig) {
+	w := c.ResponseWriter()
+	r := c.Request().Request
+
+	if format := c.Query("format"); format != "" && format != "json" {
+		http.Error(w, "Only the json format is supported", http.StatusNotImplemented)
+		return
+	}
+
+	baseURL := cfg.baseURL(r)
+	call, unix, ok := parseConfirmationURL(c.Query("url"), baseURL)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	qso, ok := cfg.lookupQSO(store, call, unix)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	maxWidth, _ := strconv.Atoi(c.Query("maxwidth"))
+	maxHeight, _ := strconv.Atoi(c.Query("maxheight"))
+	width, height := oEmbedSize(maxWidth, maxHeight)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if err := json.NewEncoder(w).Encode(newConfirmationOEmbed(qso, cfg.Callsign, baseURL, cfg.confirmationPath(qso), width, height)); err != nil {
+		log.Printf("Failed to write oEmbed response: %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+func TestParseConfirmationURL(t *testing.T) {
+	const base = "https://qsl.huma.id"
+	tests := []struct {
+		url  string
+		call string
+		ok   bool
+	}{
+		{"https://qsl.huma.id/qso/DL1ABC/1740832200", "DL1ABC", true},
+		{"https://QSL.huma.id/qso/ea8/dl1abc/1740832200", "EA8/DL1ABC", true},
+		{"https://qsl.huma.id/qso/A66H%2FP/1740832200", "A66H/P", true},
+		{"https://example.com/qso/DL1ABC/1740832200", "", false},
+		{"https://qsl.huma.id/call/DL1ABC", "", false},
+		{"https://qsl.huma.id/qso/1740832200", "", false},
+		{"://bad", "", false},
+	}
+	for _, tt := range tests {
+		call, unix, ok := parseConfirmationURL(tt.url, base)
+		if ok != tt.ok || !strings.EqualFold(call, tt.call) {
+			t.Errorf("parseConfirmationURL(%q) = %q, %v, want %q, %v", tt.url, call, ok, tt.call, tt.ok)
+		}
+		if ok && unix != "1740832200" {
+			t.Errorf("parseConfirmationURL(%q) time = %q", tt.url, unix)
+		}
+	}
+}
+
+func TestOEmbedSize(t *testing.T) {
+	tests := []struct {
+		maxWidth, maxHeight int
+		width, height       int
+	}{
+		{0, 0, 600, 400},
+		{1000, 1000, 600, 400},
+		{300, 0, 300, 200},
+		{0, 200, 300, 200},
+		{450, 100, 150, 100},
+	}
+	for _, tt := range tests {
+		width, height := oEmbedSize(tt.maxWidth, tt.maxHeight)
+		if width != tt.width || height != tt.height {
+			t.Errorf("oEmbedSize(%d, %d) = %d, %d, want %d, %d",
+				tt.maxWidth, tt.maxHeight, width, height, tt.width, tt.height)
+		}
+	}
+}
+
+func TestConfirmationOEmbed(t *testing.T) {
+	qso := utils.QSO{
+		Call:         "DL1ABC",
+		Band:         "20m",
+		Mode:         "FT8",
+		QSODate:      "20250301",
+		TimeOn:       "123000",
+		MyGridSquare: "LL75",
+		GridSquare:   "JO62",
+		Timestamp:    time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC),
+	}
+	resp := newConfirmationOEmbed(qso, "A61XX", "https://qsl.huma.id", confirmationPath(qso), 300, 200)
+
+	if resp.Type != "rich" || resp.Version != "1.0" {
+		t.Errorf("type and version = %q %q", resp.Type, resp.Version)
+	}
+	if resp.AuthorName != "A61XX" {
+		t.Errorf("Expected the configured callsign as author, got %q", resp.AuthorName)
+	}
+	if resp.ThumbnailURL != "https://qsl.huma.id/qso/DL1ABC/1740832200.png" {
+		t.Errorf("ThumbnailURL = %q", resp.ThumbnailURL)
+	}
+	if !strings.Contains(resp.HTML, `href="https://qsl.huma.id/qso/DL1ABC/1740832200"`) {
+		t.Errorf("HTML does not link to the confirmation: %s", resp.HTML)
+	}
+	if !strings.Contains(resp.HTML, `width="300" height="200"`) {
+		t.Errorf("HTML does not use the requested size: %s", resp.HTML)
+	}
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// openGraph holds the Open Graph and Twitter card metadata for a page
+type openGraph struct {
+	Title       string
+	Description string
+	URL         string
+	Image       string
+	ImageWidth  int
+	ImageHeight int
+	OEmbed      string // oEmbed discovery URL (optional)
+}
+
+// confirmationTitle returns the page title for a QSO confirmation, e.g.
+// "QSO with DL1ABC on 20m FT8"
+func confirmationTitle(qso utils.QSO) string {
+	title := "QSO with " + qso.Call
+	if details := strings.TrimSpace(qso.Band + " " + qso.Mode); details != "" {
+		title += " on " + details
+	}
+	return title
+}
+
+// newConfirmationOpenGraph builds the share metadata for a QSO confirmation
+// page with my callsign. The page path and map URL are relative to the base
+// URL.
+func newConfirmationOpenGraph(qso utils.QSO, callsign, baseURL, pagePath, mapURL string) openGraph {
+	description := fmt.Sprintf("Confirmation of the QSO between %s and %s on %s at %s UTC",
+		callsign, qso.Call, qso.FormatDate(), qso.FormatTime())
+	if qso.Freq != "" {
+		description += fmt.Sprintf(", %s MHz", qso.Freq)
+	}
+	if qso.Mode != "" {
+		description += " " + qso.Mode
+	}
+	description += "."
+
+	og := openGraph{
+		Title:       confirmationTitle(qso),
+		Description: description,
+		URL:         baseURL + pagePath,
+		OEmbed:      oEmbedURL(baseURL, baseURL+pagePath),
+	}
+	if mapURL != "" {
+		og.Image = baseURL + mapURL
+		og.ImageWidth = 600
+		og.ImageHeight = 400
+	}
+
+	return og
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+func TestConfirmationTitle(t *testing.T) {
+	tests := []struct {
+		qso  utils.QSO
+		want string
+	}{
+		{utils.QSO{Call: "DL1ABC", Band: "20m", Mode: "FT8"}, "QSO with DL1ABC on 20m FT8"},
+		{utils.QSO{Call: "DL1ABC", Mode: "CW"}, "QSO with DL1ABC on CW"},
+		{utils.QSO{Call: "DL1ABC"}, "QSO with DL1ABC"},
+	}
+	for _, tt := range tests {
+		if got := confirmationTitle(tt.qso); got != tt.want {
+			t.Errorf("confirmationTitle(%+v) = %q, want %q", tt.qso, got, tt.want)
+		}
+	}
+}
+
+func TestNewConfirmationOpenGraph(t *testing.T) {
+	qso := utils.QSO{
+		Call:      "DL1ABC",
+		Band:      "20m",
+		Mode:      "FT8",
+		Freq:      "14.074",
+		QSODate:   "20250301",
+		TimeOn:    "123000",
+		Timestamp: time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC),
+	}
+	pagePath := confirmationPath(qso)
+
+	og := newConfirmationOpenGraph(qso, "A61XX", "https://qsl.example", pagePath, pagePath+".png")
+	if og.Title != "QSO with DL1ABC on 20m FT8" {
+		t.Errorf("Title = %q", og.Title)
+	}
+	want := "Confirmation of the QSO between A61XX and DL1ABC on " + qso.FormatDate() + " at " + qso.FormatTime() + " UTC, 14.074 MHz FT8."
+	if og.Description != want {
+		t.Errorf("Description = %q, want %q", og.Description, want)
+	}
+	if og.URL != "https://qsl.example/qso/DL1ABC/1740832200" {
+		t.Errorf("URL = %q", og.URL)
+	}
+	if og.Image != "https://qsl.example/qso/DL1ABC/1740832200.png" || og.ImageWidth != 600 || og.ImageHeight != 400 {
+		t.Errorf("Expected the 600x400 map preview, got %q %dx%d", og.Image, og.ImageWidth, og.ImageHeight)
+	}
+	if og.OEmbed == "" {
+		t.Error("Expected an oEmbed discovery URL")
+	}
+
+	if og := newConfirmationOpenGraph(qso, "A61XX", "https://qsl.example", pagePath, ""); og.Image != "" || og.ImageWidth != 0 {
+		t.Errorf("Expected no preview image without a map, got %q", og.Image)
+	}
+}
@@ -0,0 +1,181 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/flamego/csrf"
+	"github.com/flamego/flamego"
+	"github.com/flamego/template"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// outgoingStorePath is where the paper cards marked as sent are kept
+const outgoingStorePath = "qsl-outgoing.json"
+
+// newAdminOutgoingHandler returns a handler listing the paper QSL cards still
+// to be sent, noting where the address for direct cards comes from
+func newAdminOutgoingHandler(book *utils.AddressBook) flamego.Handler {
+	return func(c flamego.Context, t template.Template, data template.Data, x csrf.CSRF, store utils.QSOStore, qslRequests *utils.QSLRequestStore) {
+		type row struct {
+			utils.OutgoingQSL
+			PagePath      string
+			InAddressBook bool
+		}
+
+		queue := utils.OutgoingQueue(store.GetQSOs(), qslRequests.List())
+		var rows []row
+		for _, card := range queue {
+			_, inBook := book.Get(card.QSO.Call)
+			rows = append(rows, row{OutgoingQSL: card, PagePath: confirmationPath(card.QSO), InAddressBook: inBook})
+		}
+
+		data["Title"] = "Admin: Outgoing Cards"
+		data["CSRFToken"] = x.Token()
+		data["Outgoing"] = rows
+		data["BureauCards"] = len(utils.BureauCards(queue))
+		data["Labels"] = len(utils.MailingLabels(queue, book))
+		data["Failed"] = c.Query("failed") != ""
+		t.HTML(http.StatusOK, "admin-outgoing")
+	}
+}
+
+// newAdminOutgoingSentHandler returns a handler that marks the paper card for
+// a QSO as sent, along with any requests for it made on this site. With
+// writeBack the card is also recorded as sent in the ADIF file.
+func newAdminOutgoingSentHandler(rp *ReloadableParser, writeBack bool) flamego.Handler {
+	return func(c flamego.Context, store utils.QSOStore, qslRequests *utils.QSLRequestStore, outgoing *utils.OutgoingQSLStore) {
+		qso, ok := findQSO(c, store)
+		if !ok {
+			c.Redirect("/admin/outgoing", http.StatusFound)
+			return
+		}
+
+		now := time.Now()
+		if err := outgoing.MarkSent(qso.Call, qso.Timestamp, now); err != nil {
+			log.Printf("Failed to mark QSL card for %s as sent: %v", qso.Call, err)
+			c.Redirect("/admin/outgoing?failed=1", http.StatusFound)
+			return
+		}
+		for _, req := range qslRequests.List() {
+			if !req.IsSent() && utils.CorrectionKey(req.Call, req.QSOTime) == utils.CorrectionKey(qso.Call, qso.Timestamp) {
+				if _, err := qslRequests.MarkSent(req.ID); err != nil {
+					log.Printf("Failed to mark QSL request %s as sent: %v", req.ID, err)
+				}
+			}
+		}
+		rp.refresh()
+
+		if writeBack {
+			if err := rp.markSentInFile(qso, now); err != nil {
+				log.Printf("Failed to record QSL card for %s in the ADIF file: %v", qso.Call, err)
+				c.Redirect("/admin/outgoing?failed=1", http.StatusFound)
+				return
+			}
+		}
+		c.Redirect("/admin/outgoing", http.StatusFound)
+	}
+}
+
+// bureauCards returns the outgoing cards to send through the bureau
+func bureauCards(store utils.QSOStore, qslRequests *utils.QSLRequestStore) []utils.BureauCard {
+	return utils.BureauCards(utils.OutgoingQueue(store.GetQSOs(), qslRequests.List()))
+}
+
+// handleAdminBureauCSV downloads the cards to send through the bureau as CSV,
+// sorted the way the bureau wants them
+func handleAdminBureauCSV(c flamego.Context, store utils.QSOStore, qslRequests *utils.QSLRequestStore) {
+	w := c.ResponseWriter()
+	fileName := "bureau-" + time.Now().UTC().Format("2006-01-02") + ".csv"
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+fileName+`"`)
+	if err := utils.WriteBureauCSV(w, bureauCards(store, qslRequests)); err != nil {
+		log.Printf("Failed to write bureau CSV: %v", err)
+	}
+}
+
+// newAdminBureauPDFHandler returns a handler downloading the cards to send
+// through the bureau as a printable PDF checklist, titled with callsign
+func newAdminBureauPDFHandler(callsign string) flamego.Handler {
+	return func(c flamego.Context, store utils.QSOStore, qslRequests *utils.QSLRequestStore) {
+		date := time.Now().UTC().Format("2006-01-02")
+		title := strings.ToUpper(callsign) + " bureau cards " + date
+
+		w := c.ResponseWriter()
+		fileName := "bureau-" + date + ".pdf"
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+fileName+`"`)
+		if err := utils.WriteBureauPDF(w, bureauCards(store, qslRequests), title); err != nil {
+			log.Printf("Failed to write bureau PDF: %v", err)
+		}
+	}
+}
+
+// markSentInFile records the paper card for a QSO as sent in the ADIF file
+// and reloads it. The file is replaced rather than edited in place, so a
+// reader never sees it half written.
+func (rp *ReloadableParser) markSentInFile(qso utils.QSO, sent time.Time) error {
+	key := utils.CorrectionKey(qso.Call, qso.Timestamp)
+
+	changed := 0
+	rp.mutex.Lock()
+	err := func() error {
+		info, err := os.Stat(rp.filePath)
+		if err != nil {
+			return fmt.Errorf("failed to stat ADIF file: %w", err)
+		}
+		content, err := os.ReadFile(rp.filePath)
+		if err != nil {
+			return fmt.Errorf("failed to read ADIF file: %w", err)
+		}
+
+		var updated string
+		updated, changed = utils.UpdateADIF(string(content), func(q utils.QSO) map[string]string {
+			if q.QslSent.Confirmed() || utils.CorrectionKey(q.Call, q.Timestamp) != key {
+				return nil
+			}
+			return map[string]string{
+				"qsl_sent": string(utils.QslYes),
+				"qslsdate": sent.UTC().Format("20060102"),
+			}
+		})
+		if changed == 0 {
+			return nil
+		}
+
+		tmp, err := os.CreateTemp(filepath.Dir(rp.filePath), ".qsl-*.adi")
+		if err != nil {
+			return fmt.Errorf("failed to write ADIF file: %w", err)
+		}
+		defer os.Remove(tmp.Name())
+		if _, err = tmp.WriteString(updated); err == nil {
+			err = tmp.Chmod(info.Mode().Perm())
+		}
+		if closeErr := tmp.Close(); err == nil {
+			err = closeErr
+		}
+		if err == nil {
+			err = os.Rename(tmp.Name(), rp.filePath)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write ADIF file: %w", err)
+		}
+		return nil
+	}()
+	rp.mutex.Unlock()
+
+	if err != nil || changed == 0 {
+		return err
+	}
+	return rp.reload()
+}
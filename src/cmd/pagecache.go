@@ -0,0 +1,136 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// cachedPage is a rendered response kept by pageCache
+type cachedPage struct {
+	header     http.Header
+	body       []byte
+	generation uint64
+	stored     time.Time
+}
+
+// pageCache serves rendered copies of pages that only depend on the log, so
+// frequently scraped pages aren't re-rendered on every request. A copy is
+// used until its TTL runs out or the log is republished.
+type pageCache struct {
+	next       http.Handler
+	paths      map[string]bool
+	ttl        time.Duration
+	generation func() uint64
+	now        func() time.Time
+
+	mutex sync.Mutex
+	pages map[string]*cachedPage
+}
+
+// newPageCache wraps h to cache successful GET responses for paths
+func newPageCache(h http.Handler, ttl time.Duration, generation func() uint64, paths ...string) http.Handler {
+	if ttl <= 0 {
+		return h
+	}
+
+	c := &pageCache{
+		next:       h,
+		paths:      make(map[string]bool, len(paths)),
+		ttl:        ttl,
+		generation: generation,
+		now:        time.Now,
+		pages:      make(map[string]*cachedPage),
+	}
+	for _, p := range paths {
+		c.paths[p] = true
+	}
+	return c
+}
+
+func (c *pageCache) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if (r.Method != http.MethodGet && r.Method != http.MethodHead) || !c.paths[r.URL.Path] {
+		c.next.ServeHTTP(w, r)
+		return
+	}
+
+	generation := c.generation()
+	now := c.now()
+
+	c.mutex.Lock()
+	page := c.pages[r.URL.Path]
+	c.mutex.Unlock()
+
+	if page == nil || page.generation != generation || now.Sub(page.stored) >= c.ttl {
+		rec := &pageRecorder{header: make(http.Header), status: http.StatusOK}
+		c.next.ServeHTTP(rec, r)
+		if rec.status != http.StatusOK || r.Method == http.MethodHead {
+			rec.copyTo(w, r)
+			return
+		}
+
+		// Cookies belong to the visitor who rendered the page
+		header := rec.header.Clone()
+		header.Del("Set-Cookie")
+		header.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(c.ttl.Seconds())))
+		page = &cachedPage{
+			header:     header,
+			body:       rec.body.Bytes(),
+			generation: generation,
+			stored:     now,
+		}
+
+		c.mutex.Lock()
+		c.pages[r.URL.Path] = page
+		c.mutex.Unlock()
+	}
+
+	for k, v := range page.header {
+		w.Header()[k] = v
+	}
+	w.Header().Set("Age", strconv.Itoa(int(now.Sub(page.stored).Seconds())))
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		w.Write(page.body)
+	}
+}
+
+// pageRecorder buffers a response so it can be cached
+type pageRecorder struct {
+	header http.Header
+	status int
+	wrote  bool
+	body   bytes.Buffer
+}
+
+func (p *pageRecorder) Header() http.Header { return p.header }
+
+func (p *pageRecorder) WriteHeader(status int) {
+	if !p.wrote {
+		p.status = status
+		p.wrote = true
+	}
+}
+
+func (p *pageRecorder) Write(b []byte) (int, error) {
+	p.WriteHeader(http.StatusOK)
+	return p.body.Write(b)
+}
+
+// copyTo passes an uncached response through unchanged
+func (p *pageRecorder) copyTo(w http.ResponseWriter, r *http.Request) {
+	for k, v := range p.header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(p.status)
+	if r.Method != http.MethodHead {
+		w.Write(p.body.Bytes())
+	}
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPageCache(t *testing.T) {
+	renders := 0
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		renders++
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "secret"})
+		fmt.Fprintf(w, "render %d", renders)
+	})
+
+	generation := uint64(1)
+	now := time.Unix(1740832200, 0)
+	c := newPageCache(h, time.Minute, func() uint64 { return generation }, "/qrz").(*pageCache)
+	c.now = func() time.Time { return now }
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		c.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	first := get("/qrz")
+	if first.Body.String() != "render 1" {
+		t.Fatalf("Expected the first request to render, got %q", first.Body.String())
+	}
+	if first.Header().Get("Cache-Control") != "public, max-age=60" {
+		t.Errorf("Expected a public Cache-Control, got %q", first.Header().Get("Cache-Control"))
+	}
+	if first.Header().Get("Set-Cookie") != "" {
+		t.Error("Expected cookies to be stripped from cached pages")
+	}
+
+	now = now.Add(30 * time.Second)
+	if rec := get("/qrz"); rec.Body.String() != "render 1" || rec.Header().Get("Age") != "30" {
+		t.Errorf("Expected a cached copy aged 30s, got %q with age %q", rec.Body.String(), rec.Header().Get("Age"))
+	}
+
+	generation++
+	if rec := get("/qrz"); rec.Body.String() != "render 2" {
+		t.Errorf("Expected a new render after the log changed, got %q", rec.Body.String())
+	}
+
+	now = now.Add(time.Minute)
+	if rec := get("/qrz"); rec.Body.String() != "render 3" {
+		t.Errorf("Expected a new render after the TTL, got %q", rec.Body.String())
+	}
+
+	if rec := get("/"); rec.Body.String() != "render 4" || rec.Header().Get("Cache-Control") != "" {
+		t.Errorf("Expected other paths to pass through, got %q", rec.Body.String())
+	}
+}
+
+func TestPageCacheSkipsErrors(t *testing.T) {
+	renders := 0
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		renders++
+		http.Error(w, "broken", http.StatusInternalServerError)
+	})
+	c := newPageCache(h, time.Minute, func() uint64 { return 1 }, "/qrz")
+
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		c.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/qrz", nil))
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("Expected the error to pass through, got %d", rec.Code)
+		}
+	}
+	if renders != 2 {
+		t.Errorf("Expected errors not to be cached, got %d renders", renders)
+	}
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// CmdHashPassword generates a password hash for --admin-password-hash
+var CmdHashPassword = &cli.Command{
+	Name:   "hash-password",
+	Usage:  "Hash a password read from standard input with bcrypt for use as the admin password",
+	Action: hashPassword,
+}
+
+func hashPassword(ctx context.Context, cmd *cli.Command) error {
+	fmt.Fprint(os.Stderr, "Password: ")
+	hash, err := hashPasswordLine(os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(os.Stderr)
+	fmt.Println(hash)
+	return nil
+}
+
+// hashPasswordLine returns the bcrypt hash of the password on the first line
+// of r
+func hashPasswordLine(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", fmt.Errorf("password must not be empty")
+	}
+	return utils.HashPassword(password)
+}
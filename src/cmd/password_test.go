@@ -0,0 +1,33 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+func TestHashPasswordLine(t *testing.T) {
+	hash, err := hashPasswordLine(strings.NewReader("correct horse\r\n"))
+	if err != nil {
+		t.Fatalf("hashPasswordLine failed: %v", err)
+	}
+	if !utils.CheckPassword(hash, "correct horse") {
+		t.Errorf("Expected %q to match the password without its line ending", hash)
+	}
+
+	// A password typed without a final newline is still read
+	if _, err := hashPasswordLine(strings.NewReader("correct horse")); err != nil {
+		t.Errorf("Expected a password without a newline to be hashed: %v", err)
+	}
+
+	for _, input := range []string{"", "\n", strings.Repeat("a", 73) + "\n"} {
+		if _, err := hashPasswordLine(strings.NewReader(input)); err == nil {
+			t.Errorf("Expected %q to be refused", input)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flamego/flamego"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// confirmationTokenSize is how many bytes of the HMAC are kept in a token
+const confirmationTokenSize = 12
+
+// setConfirmationKey derives the key signing confirmation links from the
+// session secret, so it is separate from the CSRF key
+func (cfg *siteConfig) setConfirmationKey(secret string) {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("qso-confirmation"))
+	cfg.confirmationKey = mac.Sum(nil)
+}
+
+// confirmationToken returns the token for a confirmation link, signing the
+// upper-cased callsign and Unix time
+func (cfg *siteConfig) confirmationToken(call string, unix int64) string {
+	mac := hmac.New(sha256.New, cfg.confirmationKey)
+	mac.Write([]byte(strings.ToUpper(call) + "/" + strconv.FormatInt(unix, 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:confirmationTokenSize])
+}
+
+// qsoPath returns the public confirmation page path for a callsign and QSO
+// time. In private mode the Unix time is followed by the link's token, e.g.
+// /qso/DL1ABC/1740832200-Pq3w..., which the map, card and form routes below
+// the page inherit.
+func (cfg *siteConfig) qsoPath(call string, at time.Time) string {
+	path := qsoPath(call, at.Unix())
+	if cfg.Private {
+		path += "-" + cfg.confirmationToken(call, at.Unix())
+	}
+	return path
+}
+
+// confirmationPath returns the public confirmation page path for a QSO
+func (cfg *siteConfig) confirmationPath(qso utils.QSO) string {
+	return cfg.qsoPath(qso.Call, qso.Timestamp)
+}
+
+// lookupQSO finds the QSO addressed by a confirmation link's callsign and
+// time, checking the link's token in private mode
+func (cfg *siteConfig) lookupQSO(store utils.QSOStore, call, unix string) (utils.QSO, bool) {
+	var token string
+	if cfg.Private {
+		var signed bool
+		if unix, token, signed = strings.Cut(unix, "-"); !signed {
+			return utils.QSO{}, false
+		}
+	}
+
+	ref, at, ok := parseQSORef(call, unix)
+	if !ok {
+		return utils.QSO{}, false
+	}
+	if cfg.Private && !hmac.Equal([]byte(token), []byte(cfg.confirmationToken(ref, at.Unix()))) {
+		return utils.QSO{}, false
+	}
+
+	qsos := store.SearchQSO(ref, at, qsoSearchTolerance)
+	if len(qsos) == 0 {
+		return utils.QSO{}, false
+	}
+	return qsos[0], true
+}
+
+// findQSO looks up the QSO addressed by the public confirmation routes
+func (cfg *siteConfig) findQSO(c flamego.Context, store utils.QSOStore) (utils.QSO, bool) {
+	return cfg.lookupQSO(store, c.Param("call"), c.Param("unix"))
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+func TestPrivateConfirmationLinks(t *testing.T) {
+	qso := utils.QSO{Call: "EA8/DL1ABC", Timestamp: time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC)}
+	parser := &utils.ADIFParser{QSOs: []utils.QSO{qso}}
+
+	cfg := &siteConfig{Private: true}
+	cfg.setConfirmationKey("secret")
+
+	path := cfg.confirmationPath(qso)
+	prefix := "/qso/EA8/DL1ABC/1740832200-"
+	if !strings.HasPrefix(path, prefix) || len(path) == len(prefix) {
+		t.Fatalf("confirmationPath = %q, want %q followed by a token", path, prefix)
+	}
+	unix := strings.TrimPrefix(path, "/qso/EA8/DL1ABC/")
+
+	if got, ok := cfg.lookupQSO(parser, "ea8/dl1abc", unix); !ok || got.Call != qso.Call {
+		t.Errorf("lookupQSO with a valid token = %v, %v", got.Call, ok)
+	}
+
+	tests := map[string][2]string{
+		"no token":        {"EA8/DL1ABC", "1740832200"},
+		"empty token":     {"EA8/DL1ABC", "1740832200-"},
+		"tampered token":  {"EA8/DL1ABC", unix[:len(unix)-1] + "x"},
+		"other time":      {"EA8/DL1ABC", "1740832260" + unix[len("1740832200"):]},
+		"other callsign":  {"DL1ABC", unix},
+		"not a unix time": {"EA8/DL1ABC", "soon-" + unix},
+	}
+	for name, tt := range tests {
+		if _, ok := cfg.lookupQSO(parser, tt[0], tt[1]); ok {
+			t.Errorf("lookupQSO accepted %s: %s %s", name, tt[0], tt[1])
+		}
+	}
+
+	// A different secret gives different links
+	other := &siteConfig{Private: true}
+	other.setConfirmationKey("another secret")
+	if other.confirmationPath(qso) == path {
+		t.Errorf("confirmation links don't depend on the secret")
+	}
+}
+
+func TestPublicConfirmationLinks(t *testing.T) {
+	qso := utils.QSO{Call: "DL1ABC", Timestamp: time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC)}
+	parser := &utils.ADIFParser{QSOs: []utils.QSO{qso}}
+
+	cfg := &siteConfig{}
+	cfg.setConfirmationKey("secret")
+
+	if got := cfg.confirmationPath(qso); got != "/qso/DL1ABC/1740832200" {
+		t.Errorf("confirmationPath = %q", got)
+	}
+	if _, ok := cfg.lookupQSO(parser, "DL1ABC", "1740832200"); !ok {
+		t.Errorf("lookupQSO rejected a public link")
+	}
+	if _, ok := cfg.lookupQSO(parser, "DL1ABC", "1740832200-token"); ok {
+		t.Errorf("lookupQSO accepted a token outside private mode")
+	}
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// trustedProxies are the reverse proxies allowed to name the client of a
+// request in its X-Forwarded-For or X-Real-IP header
+type trustedProxies []netip.Prefix
+
+// parseTrustedProxies parses the --trusted-proxies addresses and networks
+func parseTrustedProxies(specs []string) (trustedProxies, error) {
+	var proxies trustedProxies
+	for _, spec := range specs {
+		if strings.TrimSpace(spec) == "" {
+			continue
+		}
+		prefix, err := utils.ParseBlockPrefix(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy: %w", err)
+		}
+		proxies = append(proxies, prefix)
+	}
+	return proxies, nil
+}
+
+// trusts reports whether an address belongs to a trusted proxy
+func (p trustedProxies) trusts(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range p {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardedClient returns the client a trusted proxy forwarded a request
+// for. X-Forwarded-For is read from the right, skipping the proxies in the
+// chain, as anything to the left of the last trusted hop could be made up by
+// the client.
+func (p trustedProxies) forwardedClient(r *http.Request) string {
+	var hops []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if net.ParseIP(hop) == nil {
+			return ""
+		}
+		if i == 0 || !p.trusts(hop) {
+			return hop
+		}
+	}
+
+	if ip := strings.TrimSpace(r.Header.Get("X-Real-IP")); net.ParseIP(ip) != nil {
+		return ip
+	}
+	return ""
+}
+
+// withTrustedProxies replaces the remote address of requests from trusted
+// proxies with the client they forwarded, so rate limits, lockouts and logs
+// see visitors rather than the proxy
+func withTrustedProxies(h http.Handler, proxies trustedProxies) http.Handler {
+	if len(proxies) == 0 {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !proxies.trusts(clientIP(r)) {
+			h.ServeHTTP(w, r)
+			return
+		}
+		client := proxies.forwardedClient(r)
+		if client == "" {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		_, port, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			port = "0"
+		}
+		r2 := new(http.Request)
+		*r2 = *r
+		r2.RemoteAddr = net.JoinHostPort(client, port)
+		h.ServeHTTP(w, r2)
+	})
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithTrustedProxies(t *testing.T) {
+	proxies, err := parseTrustedProxies([]string{"127.0.0.1", "10.0.0.0/8"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got string
+	h := withTrustedProxies(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = clientIP(r)
+	}), proxies)
+
+	tests := []struct {
+		name   string
+		remote string
+		header map[string]string
+		want   string
+	}{
+		{"direct client", "192.0.2.1:1234", nil, "192.0.2.1"},
+		{"untrusted forwarder", "192.0.2.1:1234", map[string]string{"X-Forwarded-For": "198.51.100.7"}, "192.0.2.1"},
+		{"trusted proxy", "127.0.0.1:1234", map[string]string{"X-Forwarded-For": "198.51.100.7"}, "198.51.100.7"},
+		{"spoofed hop", "127.0.0.1:1234", map[string]string{"X-Forwarded-For": "203.0.113.9, 198.51.100.7"}, "198.51.100.7"},
+		{"proxy chain", "127.0.0.1:1234", map[string]string{"X-Forwarded-For": "198.51.100.7, 10.1.2.3"}, "198.51.100.7"},
+		{"real IP", "127.0.0.1:1234", map[string]string{"X-Real-IP": "198.51.100.7"}, "198.51.100.7"},
+		{"garbage", "127.0.0.1:1234", map[string]string{"X-Forwarded-For": "not-an-ip"}, "127.0.0.1"},
+		{"no header", "127.0.0.1:1234", nil, "127.0.0.1"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = tt.remote
+		for k, v := range tt.header {
+			r.Header.Set(k, v)
+		}
+		h.ServeHTTP(httptest.NewRecorder(), r)
+		if got != tt.want {
+			t.Errorf("%s: client = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := parseTrustedProxies([]string{"proxy.example.com"}); err == nil {
+		t.Error("Expected a host name to be refused as a trusted proxy")
+	}
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/flamego/flamego"
+	"github.com/flamego/session"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// qslRequestStatusMessages maps the request result passed back to the
+// confirmation page to the key of the message shown to the visitor
+var qslRequestStatusMessages = map[string]string{
+	"requested": "qslreq.status.requested",
+	"duplicate": "qslreq.status.duplicate",
+	"invalid":   "qslreq.status.invalid",
+	"limited":   "qslreq.status.limited",
+	"failed":    "qslreq.status.failed",
+}
+
+// Limits on free text fields of a QSL request
+const (
+	maxQSLRequestName    = 100
+	maxQSLRequestAddress = 500
+	maxQSLRequestNote    = 500
+)
+
+// newQSLRequestHandler returns a handler that records a paper QSL card request
+// for a QSO and notifies subscribers. The address itself is never included in
+// notifications or logs. The form guard catches bots like on the contact
+// form.
+func newQSLRequestHandler(requests *utils.QSLRequestStore) flamego.Handler {
+	clients := utils.NewRateLimiter(5, 24*time.Hour)
+
+	return func(c flamego.Context, store utils.QSOStore, cfg *siteConfig, events *utils.EventBus, forms *utils.FormGuard, s session.Session) {
+		qso, ok := cfg.findQSO(c, store)
+		if !ok {
+			c.Redirect("/", http.StatusFound)
+			return
+		}
+		pagePath := cfg.confirmationPath(qso)
+
+		redirect := func(status string) {
+			c.Redirect(pagePath+"?qsl="+status, http.StatusFound)
+		}
+
+		r := c.Request().Request
+
+		// Pretend bots succeeded so they don't try again. A bad stamp may
+		// also be a person who left the page open for hours.
+		if err := checkFormGuard(forms, r, s); err != nil {
+			log.Printf("Dropped QSL request spam from %s: %v", clientIP(r), err)
+			if errors.Is(err, utils.ErrFormStamp) {
+				redirect("invalid")
+				return
+			}
+			redirect("requested")
+			return
+		}
+
+		req := utils.QSLRequest{
+			Call:    qso.Call,
+			QSOTime: qso.Timestamp,
+			Band:    qso.Band,
+			Mode:    qso.Mode,
+			Route:   utils.QSLRoute(r.FormValue("route")),
+			Name:    strings.TrimSpace(r.FormValue("name")),
+			Address: strings.TrimSpace(r.FormValue("address")),
+			Note:    strings.TrimSpace(r.FormValue("note")),
+		}
+
+		if email := strings.TrimSpace(r.FormValue("email")); email != "" {
+			valid, err := utils.ValidateEmailAddress(email)
+			if err != nil {
+				redirect("invalid")
+				return
+			}
+			req.Email = valid
+		}
+
+		switch {
+		case req.Route != utils.QSLRouteDirect && req.Route != utils.QSLRouteBureau,
+			req.Route == utils.QSLRouteDirect && req.Address == "",
+			len(req.Name) > maxQSLRequestName,
+			len(req.Address) > maxQSLRequestAddress,
+			len(req.Note) > maxQSLRequestNote:
+			redirect("invalid")
+			return
+		}
+
+		if requests.Pending(qso.Call, qso.Timestamp) {
+			redirect("duplicate")
+			return
+		}
+
+		if !clients.Allow(clientIP(r)) {
+			redirect("limited")
+			return
+		}
+
+		req, err := requests.Add(req)
+		if err != nil {
+			log.Printf("Failed to save QSL request from %s: %v", qso.Call, err)
+			redirect("failed")
+			return
+		}
+
+		log.Printf("QSL card requested by %s via %s", req.Call, req.Route)
+		events.Publish(utils.Event{
+			Type:     utils.EventQSLRequest,
+			Callsign: req.Call,
+			QSOs:     []utils.QSO{qso},
+			QSLRoute: req.Route,
+		})
+		redirect("requested")
+	}
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flamego/flamego"
+	"github.com/flamego/template"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// qsoSearchTolerance is how far, in minutes, a requested time may be from the
+// logged QSO time for the QSO to still be found
+const qsoSearchTolerance = 10
+
+// maxSearchSuggestions is how many near matches are offered when a search
+// finds no QSO
+const maxSearchSuggestions = 5
+
+// confirmationPath returns the confirmation page path for a QSO, of the form
+// /qso/CALLSIGN/UNIXTIME. Slashes in portable callsigns are kept as path
+// separators, which the route's glob matches.
+func confirmationPath(qso utils.QSO) string {
+	return qsoPath(qso.Call, qso.Timestamp.Unix())
+}
+
+// confirmationMapPath returns the map image path for a QSO
+func confirmationMapPath(qso utils.QSO) string {
+	return confirmationPath(qso) + ".png"
+}
+
+func qsoPath(call string, unix int64) string {
+	parts := strings.Split(strings.ToUpper(call), "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return fmt.Sprintf("/qso/%s/%d", strings.Join(parts, "/"), unix)
+}
+
+// parseQSORef validates the callsign and Unix time taken from a confirmation
+// route, returning the upper-cased callsign and the time
+func parseQSORef(call, unix string) (string, time.Time, bool) {
+	call = strings.ToUpper(strings.Trim(call, "/"))
+	if call == "" || strings.Contains(call, "//") {
+		return "", time.Time{}, false
+	}
+
+	ts, err := strconv.ParseInt(unix, 10, 64)
+	if err != nil || ts < 0 {
+		return "", time.Time{}, false
+	}
+
+	return call, time.Unix(ts, 0), true
+}
+
+// parseLegacyQSOPath parses the old CALLSIGN-UNIXTIME path format, where the
+// callsign was query-escaped and split from the time on the last dash
+func parseLegacyQSOPath(path string) (string, time.Time, bool) {
+	lastDash := strings.LastIndex(path, "-")
+	if lastDash == -1 {
+		return "", time.Time{}, false
+	}
+
+	call, err := url.QueryUnescape(path[:lastDash])
+	if err != nil {
+		return "", time.Time{}, false
+	}
+
+	return parseQSORef(call, path[lastDash+1:])
+}
+
+// findQSO looks up the QSO addressed by the call and unix route parameters
+func findQSO(c flamego.Context, store utils.QSOStore) (utils.QSO, bool) {
+	call, at, ok := parseQSORef(c.Param("call"), c.Param("unix"))
+	if !ok {
+		return utils.QSO{}, false
+	}
+
+	qsos := store.SearchQSO(call, at, qsoSearchTolerance)
+	if len(qsos) == 0 {
+		return utils.QSO{}, false
+	}
+	return qsos[0], true
+}
+
+// mapFileNameFor returns the cached map file name for a QSO
+func mapFileNameFor(qso utils.QSO) string {
+	safeCallsign := strings.ReplaceAll(qso.Call, "/", "_")
+	return fmt.Sprintf("%s-%d.png", safeCallsign, qso.Timestamp.Unix())
+}
+
+// newLegacyQSORedirect returns a handler that permanently redirects old
+// CALLSIGN-UNIXTIME confirmation and map URLs to the /qso/ scheme. The
+// redirect keeps the requested time; the new route finds the QSO as before.
+func newLegacyQSORedirect(suffix string) flamego.Handler {
+	return func(c flamego.Context, t template.Template, data template.Data, l *localizer) {
+		call, at, ok := parseLegacyQSOPath(c.Param("path"))
+		if !ok {
+			if suffix == "" {
+				renderError(t, data, l, http.StatusNotFound, "", false)
+			} else {
+				http.NotFound(c.ResponseWriter(), c.Request().Request)
+			}
+			return
+		}
+		c.Redirect(qsoPath(call, at.Unix())+suffix, http.StatusMovedPermanently)
+	}
+}
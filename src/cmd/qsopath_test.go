@@ -0,0 +1,109 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+func TestConfirmationPath(t *testing.T) {
+	ts := time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC)
+	tests := []struct {
+		call string
+		want string
+	}{
+		{"A66H", "/qso/A66H/1740832200"},
+		{"a66h/p", "/qso/A66H/P/1740832200"},
+		{"EA8/DL1ABC", "/qso/EA8/DL1ABC/1740832200"},
+		{"VP2V/W1AW/MM", "/qso/VP2V/W1AW/MM/1740832200"},
+	}
+
+	for _, tt := range tests {
+		qso := utils.QSO{Call: tt.call, Timestamp: ts}
+		if got := confirmationPath(qso); got != tt.want {
+			t.Errorf("confirmationPath(%q) = %q, want %q", tt.call, got, tt.want)
+		}
+		if got := confirmationMapPath(qso); got != tt.want+".png" {
+			t.Errorf("confirmationMapPath(%q) = %q, want %q", tt.call, got, tt.want+".png")
+		}
+	}
+}
+
+func TestParseQSORef(t *testing.T) {
+	tests := []struct {
+		call, unix string
+		wantCall   string
+		wantUnix   int64
+		ok         bool
+	}{
+		{"A66H", "1740832200", "A66H", 1740832200, true},
+		{"a66h/p", "1740832200", "A66H/P", 1740832200, true},
+		{"EA8/DL1ABC/", "1740832200", "EA8/DL1ABC", 1740832200, true},
+		{"", "1740832200", "", 0, false},
+		{"A66H//P", "1740832200", "", 0, false},
+		{"A66H", "yesterday", "", 0, false},
+		{"A66H", "-5", "", 0, false},
+	}
+
+	for _, tt := range tests {
+		call, at, ok := parseQSORef(tt.call, tt.unix)
+		if ok != tt.ok {
+			t.Errorf("parseQSORef(%q, %q) ok = %v, want %v", tt.call, tt.unix, ok, tt.ok)
+			continue
+		}
+		if ok && (call != tt.wantCall || at.Unix() != tt.wantUnix) {
+			t.Errorf("parseQSORef(%q, %q) = %q, %d, want %q, %d",
+				tt.call, tt.unix, call, at.Unix(), tt.wantCall, tt.wantUnix)
+		}
+	}
+}
+
+func TestParseLegacyQSOPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		wantCall string
+		wantUnix int64
+		ok       bool
+	}{
+		{"A66H-1740832200", "A66H", 1740832200, true},
+		{"A66H%2FP-1740832200", "A66H/P", 1740832200, true},
+		{"EA8%2FDL1ABC-1740832200", "EA8/DL1ABC", 1740832200, true},
+		{"A66H", "", 0, false},
+		{"A66H-", "", 0, false},
+		{"-1740832200", "", 0, false},
+		{"A66H%zz-1740832200", "", 0, false},
+	}
+
+	for _, tt := range tests {
+		call, at, ok := parseLegacyQSOPath(tt.path)
+		if ok != tt.ok {
+			t.Errorf("parseLegacyQSOPath(%q) ok = %v, want %v", tt.path, ok, tt.ok)
+			continue
+		}
+		if ok && (call != tt.wantCall || at.Unix() != tt.wantUnix) {
+			t.Errorf("parseLegacyQSOPath(%q) = %q, %d, want %q, %d",
+				tt.path, call, at.Unix(), tt.wantCall, tt.wantUnix)
+		}
+	}
+}
+
+func TestMapPagePath(t *testing.T) {
+	tests := map[string]string{
+		"A66H-1740832200.png":        "/qso/A66H/1740832200",
+		"A66H_P-1740832200.png":      "/qso/A66H/P/1740832200",
+		"EA8_DL1ABC-1740832200.png":  "/qso/EA8/DL1ABC/1740832200",
+		"A66H_P-1740832200.card.png": "/qso/A66H/P/1740832200",
+		"garbage.png":                "",
+	}
+
+	for name, want := range tests {
+		if got := mapPagePath(&siteConfig{}, name); got != want {
+			t.Errorf("mapPagePath(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"encoding/gob"
+	"time"
+
+	"github.com/flamego/session"
+)
+
+// recentSearchesSessionKey holds the visitor's latest QSO searches in the
+// session
+const recentSearchesSessionKey = "recent_searches"
+
+// maxRecentSearches is how many searches are remembered per visitor
+const maxRecentSearches = 5
+
+// recentSearch is a call sign and time a visitor searched for
+type recentSearch struct {
+	Call string
+	Time time.Time
+}
+
+func init() {
+	// Sessions are stored on disk with gob, which must know the type
+	gob.Register([]recentSearch{})
+}
+
+// Input returns the search time in the format of the free-form date field
+func (r recentSearch) Input() string {
+	return r.Time.UTC().Format("2006-01-02 1504")
+}
+
+// sessionRecentSearches returns the visitor's recent searches, newest first
+func sessionRecentSearches(s session.Session) []recentSearch {
+	searches, _ := s.Get(recentSearchesSessionKey).([]recentSearch)
+	return searches
+}
+
+// isRecentSearch reports whether a search repeats one of the visitor's
+// recent searches
+func isRecentSearch(s session.Session, call string, at time.Time) bool {
+	for _, search := range sessionRecentSearches(s) {
+		if search.Call == call && search.Time.Equal(at) {
+			return true
+		}
+	}
+	return false
+}
+
+// rememberSearch records a search in the session, moving a repeated search to
+// the front rather than listing it twice
+func rememberSearch(s session.Session, call string, at time.Time) {
+	searches := []recentSearch{{Call: call, Time: at.UTC()}}
+	for _, search := range sessionRecentSearches(s) {
+		if search.Call == call && search.Time.Equal(at) {
+			continue
+		}
+		if len(searches) == maxRecentSearches {
+			break
+		}
+		searches = append(searches, search)
+	}
+	s.Set(recentSearchesSessionKey, searches)
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+	"time"
+
+	"github.com/flamego/session"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// memorySession keeps session values in a map, leaving the other methods
+// unimplemented
+type memorySession struct {
+	session.Session
+	values map[interface{}]interface{}
+}
+
+func newMemorySession() *memorySession {
+	return &memorySession{values: make(map[interface{}]interface{})}
+}
+
+func (m *memorySession) Get(key interface{}) interface{} { return m.values[key] }
+func (m *memorySession) Set(key, val interface{})        { m.values[key] = val }
+func (m *memorySession) Delete(key interface{})          { delete(m.values, key) }
+
+func TestRememberSearch(t *testing.T) {
+	s := newMemorySession()
+	at := time.Date(2024, time.May, 1, 13, 5, 0, 0, time.UTC)
+
+	for i := 0; i < maxRecentSearches+2; i++ {
+		rememberSearch(s, "A62A", at.Add(time.Duration(i)*time.Hour))
+	}
+	searches := sessionRecentSearches(s)
+	if len(searches) != maxRecentSearches {
+		t.Fatalf("remembered %d searches, want %d", len(searches), maxRecentSearches)
+	}
+	if newest := at.Add(time.Duration(maxRecentSearches+1) * time.Hour); !searches[0].Time.Equal(newest) {
+		t.Errorf("newest search = %v, want %v", searches[0].Time, newest)
+	}
+
+	// Repeating a search moves it to the front instead of duplicating it
+	repeated := searches[2]
+	rememberSearch(s, repeated.Call, repeated.Time)
+	searches = sessionRecentSearches(s)
+	if len(searches) != maxRecentSearches || searches[0] != repeated {
+		t.Errorf("after repeating, searches = %+v", searches)
+	}
+	for _, search := range searches[1:] {
+		if search == repeated {
+			t.Errorf("repeated search %+v listed twice", repeated)
+		}
+	}
+
+	if got := searches[0].Input(); got != repeated.Time.Format("2006-01-02 1504") {
+		t.Errorf("Input() = %q", got)
+	}
+}
+
+func TestIsRecentSearch(t *testing.T) {
+	s := newMemorySession()
+	at := time.Date(2024, time.May, 1, 13, 5, 0, 0, time.UTC)
+	rememberSearch(s, "A62A", at)
+
+	// The recent search buttons submit the time through the free-form field
+	submitted, err := utils.ParseDateTime(sessionRecentSearches(s)[0].Input())
+	if err != nil {
+		t.Fatalf("ParseDateTime: %v", err)
+	}
+	if !isRecentSearch(s, "A62A", submitted) {
+		t.Errorf("Expected a repeated search to be recent")
+	}
+	if isRecentSearch(s, "A62B", submitted) || isRecentSearch(s, "A62A", at.Add(time.Minute)) {
+		t.Errorf("Expected other searches not to be recent")
+	}
+}
+
+func TestRecentSearchesGob(t *testing.T) {
+	// File sessions are gob encoded as a map of interface values
+	want := map[interface{}]interface{}{
+		recentSearchesSessionKey: []recentSearch{{Call: "A62A", Time: time.Unix(1714568700, 0).UTC()}},
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(want); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	got := make(map[interface{}]interface{})
+	if err := gob.NewDecoder(&buf).Decode(&got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	searches, ok := got[recentSearchesSessionKey].([]recentSearch)
+	if !ok || len(searches) != 1 || searches[0].Call != "A62A" {
+		t.Errorf("decoded %+v", got)
+	}
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// CmdSelfTest checks how the parser reads an ADIF file
+var CmdSelfTest = &cli.Command{
+	Name:  "selftest",
+	Usage: "Parse an ADIF file, check it survives being written back, and compare it with a golden report",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "adif",
+			Usage:    "path to ADIF file containing QSO logs",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  "golden",
+			Usage: "path to a JSON parse report to compare with",
+		},
+		&cli.BoolFlag{
+			Name:  "update",
+			Usage: "write the parse report to the golden file instead of comparing",
+		},
+	},
+	Action: runSelfTest,
+}
+
+func runSelfTest(ctx context.Context, cmd *cli.Command) error {
+	golden := cmd.String("golden")
+	if cmd.Bool("update") && golden == "" {
+		return errors.New("--update needs --golden")
+	}
+
+	file, err := os.Open(cmd.String("adif"))
+	if err != nil {
+		return fmt.Errorf("failed to open ADIF file: %w", err)
+	}
+	defer file.Close()
+
+	parser := utils.NewADIFParser()
+	if err := parser.ParseFile(file); err != nil {
+		return fmt.Errorf("failed to parse ADIF file: %w", err)
+	}
+	report := utils.NewParseReport(parser)
+
+	fmt.Printf("Parsed %d QSO(s) with %d warning(s)\n", len(report.QSOs), len(report.Warnings))
+	for _, warning := range report.Warnings {
+		fmt.Printf("  %s\n", warning)
+	}
+
+	roundTrip, err := utils.RoundTripDiff(parser)
+	if err != nil {
+		return fmt.Errorf("failed to write and reparse QSOs: %w", err)
+	}
+	failed := writeDiffs(os.Stdout, "Round trip", roundTrip)
+
+	switch {
+	case golden == "":
+	case cmd.Bool("update"):
+		data, err := report.MarshalGolden()
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(golden, data, 0644); err != nil {
+			return fmt.Errorf("failed to write golden report: %w", err)
+		}
+		fmt.Printf("Wrote %s\n", golden)
+	default:
+		data, err := os.ReadFile(golden)
+		if err != nil {
+			return fmt.Errorf("failed to read golden report: %w", err)
+		}
+		want, err := utils.UnmarshalGolden(data)
+		if err != nil {
+			return err
+		}
+		failed = writeDiffs(os.Stdout, "Golden report", utils.DiffParseReports(want, report)) || failed
+	}
+
+	if failed {
+		return errors.New("self test failed")
+	}
+	return nil
+}
+
+// writeDiffs writes the outcome of a check and reports whether it found
+// differences
+func writeDiffs(w io.Writer, check string, diffs []string) bool {
+	if len(diffs) == 0 {
+		fmt.Fprintf(w, "%s: OK\n", check)
+		return false
+	}
+	fmt.Fprintf(w, "%s: %d difference(s)\n", check, len(diffs))
+	for _, diff := range diffs {
+		fmt.Fprintf(w, "  %s\n", diff)
+	}
+	return true
+}
@@ -0,0 +1,27 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteDiffs(t *testing.T) {
+	var b strings.Builder
+	if writeDiffs(&b, "Round trip", nil) {
+		t.Error("Expected no differences to pass")
+	}
+	if failed := writeDiffs(&b, "Golden report", []string{`QSO 1 (A61AA): Band = "40m", want "20m"`}); !failed {
+		t.Error("Expected differences to fail")
+	}
+
+	want := "Round trip: OK\n" +
+		"Golden report: 1 difference(s)\n" +
+		"  QSO 1 (A61AA): Band = \"40m\", want \"20m\"\n"
+	if b.String() != want {
+		t.Errorf("got:\n%q\nwant:\n%q", b.String(), want)
+	}
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/flamego/session"
+	"github.com/urfave/cli/v3"
+)
+
+// sessionSecretFile holds the generated CSRF signing key when none is
+// configured, so tokens in open forms stay valid across restarts
+const sessionSecretFile = "qsl-session-secret"
+
+// newSessionOptions builds the session store and cookie settings from command
+// line flags. Sessions are kept on disk so they survive restarts.
+func newSessionOptions(cmd *cli.Command) (session.Options, error) {
+	secure := cmd.Bool("session-secure")
+	sameSite, err := parseSameSite(cmd.String("session-samesite"), secure)
+	if err != nil {
+		return session.Options{}, err
+	}
+
+	dir := cmd.String("session-dir")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return session.Options{}, fmt.Errorf("failed to create session directory: %w", err)
+	}
+
+	lifetime := cmd.Duration("session-lifetime")
+	return session.Options{
+		Initer: session.FileIniter(),
+		Config: session.FileConfig{
+			RootDir:  dir,
+			Lifetime: lifetime,
+		},
+		Cookie: session.CookieOptions{
+			Name:     cmd.String("session-cookie-name"),
+			Path:     "/",
+			MaxAge:   int(lifetime.Seconds()),
+			Secure:   secure,
+			HTTPOnly: true,
+			SameSite: sameSite,
+		},
+	}, nil
+}
+
+// parseSameSite parses a SameSite cookie mode. Browsers drop SameSite=None
+// cookies that aren't secure, which would quietly break sessions, CSRF
+// checks and admin logins, so none needs secure.
+func parseSameSite(mode string, secure bool) (http.SameSite, error) {
+	switch strings.ToLower(mode) {
+	case "lax", "":
+		return http.SameSiteLaxMode, nil
+	case "strict":
+		return http.SameSiteStrictMode, nil
+	case "none":
+		if !secure {
+			return 0, errors.New("--session-samesite=none needs --session-secure, as browsers reject SameSite=None cookies that aren't secure")
+		}
+		return http.SameSiteNoneMode, nil
+	}
+	return 0, fmt.Errorf("invalid session SameSite mode %q (expected lax, strict or none)", mode)
+}
+
+// sessionSecret returns the configured CSRF signing key, or one generated on
+// first start and kept in sessionSecretFile
+func sessionSecret(cmd *cli.Command) (string, error) {
+	if secret := cmd.String("session-secret"); secret != "" {
+		return secret, nil
+	}
+
+	data, err := os.ReadFile(sessionSecretFile)
+	if err == nil && len(strings.TrimSpace(string(data))) > 0 {
+		return strings.TrimSpace(string(data)), nil
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("failed to read session secret: %w", err)
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("failed to generate session secret: %w", err)
+	}
+	secret := hex.EncodeToString(key)
+	if err := os.WriteFile(sessionSecretFile, []byte(secret+"\n"), 0600); err != nil {
+		return "", fmt.Errorf("failed to save session secret: %w", err)
+	}
+	return secret, nil
+}
@@ -0,0 +1,32 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestParseSameSite(t *testing.T) {
+	tests := []struct {
+		mode    string
+		secure  bool
+		want    http.SameSite
+		wantErr bool
+	}{
+		{"", false, http.SameSiteLaxMode, false},
+		{"Lax", false, http.SameSiteLaxMode, false},
+		{"strict", true, http.SameSiteStrictMode, false},
+		{"none", true, http.SameSiteNoneMode, false},
+		{"none", false, 0, true},
+		{"always", true, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseSameSite(tt.mode, tt.secure)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseSameSite(%q, %v) = %v, %v, want %v (error %v)", tt.mode, tt.secure, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
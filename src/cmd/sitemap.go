@@ -0,0 +1,151 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/flamego/flamego"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// sitemapMaxURLs is the maximum number of URLs allowed in a single sitemap
+// file by the sitemap protocol
+const sitemapMaxURLs = 50000
+
+type sitemapURL struct {
+	Loc string `xml:"loc"`
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	Xmlns    string       `xml:"xmlns,attr"`
+	Sitemaps []sitemapURL `xml:"sitemap"`
+}
+
+// sitemapPaths returns the paths of all pages that should be indexed. QSO
+// confirmation and callsign history pages are only included when indexing
+// them is enabled.
+func sitemapPaths(store utils.QSOStore, cfg *siteConfig) []string {
+	paths := []string{"/"}
+	if cfg.Awards {
+		paths = append(paths, "/awards", "/awards/dxcc", "/awards/challenge", "/awards/grids")
+	}
+	if cfg.Stats {
+		paths = append(paths, "/stats")
+	}
+	if cfg.Contests {
+		paths = append(paths, "/contests")
+		for _, contest := range utils.ComputeContests(store.GetQSOs()) {
+			paths = append(paths, "/contests/"+url.PathEscape(contest.ID))
+		}
+	}
+
+	if !cfg.IndexConfirmations || cfg.Private {
+		return paths
+	}
+
+	callsigns := make(map[string]bool)
+	for _, qso := range store.GetQSOs() {
+		if qso.Timestamp.IsZero() {
+			continue
+		}
+		paths = append(paths, confirmationPath(qso))
+
+		call := strings.ToUpper(qso.Call)
+		if !callsigns[call] {
+			callsigns[call] = true
+			paths = append(paths, callsignPath(call))
+		}
+	}
+
+	return paths
+}
+
+// handleRobots serves robots.txt, allowing confirmation pages to be crawled
+// only when indexing them is enabled
+func handleRobots(c flamego.Context, cfg *siteConfig) {
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+	if cfg.IndexConfirmations && !cfg.Private {
+		b.WriteString("Allow: /\n")
+		b.WriteString("Disallow: /*.png$\n")
+		b.WriteString("Disallow: /admin\n")
+	} else {
+		b.WriteString("Allow: /$\n")
+		b.WriteString("Disallow: /*\n")
+	}
+	fmt.Fprintf(&b, "\nSitemap: %s/sitemap.xml\n", cfg.baseURL(c.Request().Request))
+
+	w := c.ResponseWriter()
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(b.String()))
+}
+
+// handleSitemap serves /sitemap.xml, which becomes a sitemap index pointing to
+// /sitemap-N.xml files when there are too many URLs for a single sitemap
+func handleSitemap(c flamego.Context, store utils.QSOStore, cfg *siteConfig) {
+	baseURL := cfg.baseURL(c.Request().Request)
+	paths := sitemapPaths(store, cfg)
+
+	if len(paths) <= sitemapMaxURLs {
+		writeSitemap(c, baseURL, paths)
+		return
+	}
+
+	index := sitemapIndex{Xmlns: "http://www.sitemaps.org/schemas/sitemap/0.9"}
+	for i := 0; i*sitemapMaxURLs < len(paths); i++ {
+		index.Sitemaps = append(index.Sitemaps, sitemapURL{
+			Loc: fmt.Sprintf("%s/sitemap-%d.xml", baseURL, i+1),
+		})
+	}
+	writeXML(c, index)
+}
+
+// handleSitemapPage serves one page of a split sitemap
+func handleSitemapPage(c flamego.Context, store utils.QSOStore, cfg *siteConfig) (int, error) {
+	page := c.ParamInt("page")
+	paths := sitemapPaths(store, cfg)
+
+	start := (page - 1) * sitemapMaxURLs
+	if page < 1 || start >= len(paths) {
+		return http.StatusNotFound, nil
+	}
+	end := start + sitemapMaxURLs
+	if end > len(paths) {
+		end = len(paths)
+	}
+
+	writeSitemap(c, cfg.baseURL(c.Request().Request), paths[start:end])
+	return http.StatusOK, nil
+}
+
+func writeSitemap(c flamego.Context, baseURL string, paths []string) {
+	set := sitemapURLSet{Xmlns: "http://www.sitemaps.org/schemas/sitemap/0.9"}
+	for _, path := range paths {
+		set.URLs = append(set.URLs, sitemapURL{Loc: baseURL + path})
+	}
+	writeXML(c, set)
+}
+
+func writeXML(c flamego.Context, v interface{}) {
+	w := c.ResponseWriter()
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(xml.Header))
+	xml.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flamego/flamego"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+func TestSitemapPaths(t *testing.T) {
+	parser := &utils.ADIFParser{QSOs: []utils.QSO{
+		{Call: "dl1abc", Timestamp: time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC)},
+		{Call: "DL1ABC", Timestamp: time.Date(2025, 3, 2, 8, 0, 0, 0, time.UTC)},
+		{Call: "A66H/P", Timestamp: time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)},
+		{Call: "W1AW"},
+	}}
+
+	tests := []struct {
+		name string
+		cfg  siteConfig
+		want []string
+	}{
+		{
+			name: "default",
+			cfg:  siteConfig{Stats: true},
+			want: []string{"/", "/stats"},
+		},
+		{
+			name: "indexed confirmations",
+			cfg:  siteConfig{Stats: true, IndexConfirmations: true},
+			want: []string{"/", "/stats",
+				"/qso/DL1ABC/1740832200", "/call/DL1ABC",
+				"/qso/DL1ABC/1740902400",
+				"/qso/A66H/P/1740992400", "/call/A66H%2FP"},
+		},
+		{
+			name: "private",
+			cfg:  siteConfig{Stats: true, IndexConfirmations: true, Private: true},
+			want: []string{"/", "/stats"},
+		},
+		{
+			name: "awards",
+			cfg:  siteConfig{Awards: true},
+			want: []string{"/", "/awards", "/awards/dxcc", "/awards/challenge", "/awards/grids"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sitemapPaths(parser, &tt.cfg)
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("sitemapPaths = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSitemapAndRobots(t *testing.T) {
+	parser := &utils.ADIFParser{QSOs: []utils.QSO{
+		{Call: "DL1ABC", Timestamp: time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC)},
+	}}
+
+	serve := func(cfg *siteConfig, path string) string {
+		t.Helper()
+		f := flamego.New()
+		f.Map(cfg)
+		f.MapTo(parser, (*utils.QSOStore)(nil))
+		f.Get("/robots.txt", handleRobots)
+		f.Get("/sitemap.xml", handleSitemap)
+
+		rec := httptest.NewRecorder()
+		f.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s = %d", path, rec.Code)
+		}
+		return rec.Body.String()
+	}
+
+	indexed := &siteConfig{BaseURL: "https://qsl.example", Stats: true, IndexConfirmations: true}
+	var set sitemapURLSet
+	if err := xml.Unmarshal([]byte(serve(indexed, "/sitemap.xml")), &set); err != nil {
+		t.Fatalf("Failed to parse sitemap: %v", err)
+	}
+	var locs []string
+	for _, u := range set.URLs {
+		locs = append(locs, u.Loc)
+	}
+	want := "https://qsl.example/ https://qsl.example/stats https://qsl.example/qso/DL1ABC/1740832200 https://qsl.example/call/DL1ABC"
+	if got := strings.Join(locs, " "); got != want {
+		t.Errorf("Expected sitemap URLs %s, got %s", want, got)
+	}
+
+	private := &siteConfig{BaseURL: "https://qsl.example", Stats: true, IndexConfirmations: true, Private: true}
+	if body := serve(private, "/sitemap.xml"); strings.Contains(body, "/qso/") || strings.Contains(body, "/call/") {
+		t.Errorf("Expected no QSO pages in the private sitemap, got %s", body)
+	}
+
+	robots := serve(indexed, "/robots.txt")
+	if !strings.Contains(robots, "Allow: /\n") || !strings.Contains(robots, "Disallow: /admin\n") {
+		t.Errorf("Expected robots.txt to allow crawling with indexing on, got %q", robots)
+	}
+	if !strings.Contains(robots, "Sitemap: https://qsl.example/sitemap.xml\n") {
+		t.Errorf("Expected robots.txt to point to the sitemap, got %q", robots)
+	}
+	for _, cfg := range []*siteConfig{{}, private} {
+		robots := serve(cfg, "/robots.txt")
+		if !strings.Contains(robots, "Allow: /$\n") || !strings.Contains(robots, "Disallow: /*\n") {
+			t.Errorf("Expected robots.txt to only allow the home page, got %q", robots)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"log"
+	"sync"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// sqliteMirror writes each published log into an SQLite store in the
+// background, as rewriting a large log is too slow to do while the log is
+// locked. Logs published while one is being written are coalesced, so only
+// the latest is written next.
+type sqliteMirror struct {
+	store *utils.SQLiteStore
+
+	mutex      sync.Mutex
+	pending    []utils.QSO
+	pendingGen uint64
+	hasPending bool
+	writing    bool
+	mirrored   uint64 // Generation of the log the store holds, 0 for none
+}
+
+// newSQLiteMirror creates a mirror writing into store
+func newSQLiteMirror(store *utils.SQLiteStore) *sqliteMirror {
+	return &sqliteMirror{store: store}
+}
+
+// publish queues the log of a generation to be written. It doesn't wait for
+// the write, so it can be called with the log locked.
+func (m *sqliteMirror) publish(qsos []utils.QSO, generation uint64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.pending, m.pendingGen, m.hasPending = qsos, generation, true
+	if !m.writing {
+		m.writing = true
+		go m.write()
+	}
+}
+
+// write writes queued logs until there are none left
+func (m *sqliteMirror) write() {
+	for {
+		m.mutex.Lock()
+		if !m.hasPending {
+			m.writing = false
+			m.mutex.Unlock()
+			return
+		}
+		qsos, generation := m.pending, m.pendingGen
+		m.pending, m.hasPending = nil, false
+		m.mutex.Unlock()
+
+		if err := m.store.Replace(qsos); err != nil {
+			log.Printf("Failed to mirror the log into SQLite: %v", err)
+			continue
+		}
+
+		m.mutex.Lock()
+		m.mirrored = generation
+		m.mutex.Unlock()
+	}
+}
+
+// holds reports whether the store has caught up with a generation of the log
+func (m *sqliteMirror) holds(generation uint64) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.mirrored == generation
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/flamego/template"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// qrpLeaderboardSize is the number of QSOs on the QRP distance per watt
+// leaderboard
+const qrpLeaderboardSize = 10
+
+// qrpRow is a QRP leaderboard entry with its distances formatted in the
+// visitor's units
+type qrpRow struct {
+	Call     string
+	Date     time.Time
+	Band     string
+	Mode     string
+	Watts    string
+	Distance string
+	PerWatt  string
+}
+
+// handleStats shows breakdowns of the whole log
+func handleStats(t template.Template, data template.Data, store utils.QSOStore, l *localizer) {
+	qsos := store.GetQSOs()
+	data["Title"] = l.T("stats.title")
+	data["StatsPage"] = true
+	data["ModeFamilies"] = utils.ModeFamilies
+	data["Modes"] = utils.ComputeModeBreakdown(qsos)
+	data["Satellites"] = utils.ComputeSatelliteStats(qsos)
+
+	qrp := utils.ComputeQRPStats(qsos, qrpLeaderboardSize)
+	rows := make([]qrpRow, 0, len(qrp.Leaderboard))
+	for _, c := range qrp.Leaderboard {
+		rows = append(rows, qrpRow{
+			Call:     c.QSO.Call,
+			Date:     c.QSO.Timestamp,
+			Band:     c.QSO.Band,
+			Mode:     c.QSO.Mode,
+			Watts:    l.Decimal(c.Watts, 1),
+			Distance: l.Distance(c.Km),
+			PerWatt:  l.Distance(c.KmPerWatt()),
+		})
+	}
+	data["QRP"] = qrp
+	data["QRPMaxWatts"] = utils.QRPMaxWatts
+	data["QRPLeaderboard"] = rows
+
+	t.HTML(http.StatusOK, "stats")
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/flamego/flamego"
+)
+
+// summaryVersion is the schema version of /api/v1/summary. Fields may be
+// added within a version, but none are removed, renamed or change type.
+const summaryVersion = 1
+
+// summaryCacheControl lets phones and proxies reuse the summary for a minute
+// and show a stale copy while revalidating, which is cheap thanks to the
+// ETag. On air status is at most a minute out of date.
+const summaryCacheControl = "public, max-age=60, stale-while-revalidate=600"
+
+// apiSummary is the body of /api/v1/summary, a compact overview of the log
+// for phone widgets and companion apps:
+//
+//	{
+//	  "version": 1,
+//	  "callsign": "A65HQ",
+//	  "totals": {"qsos": 1234, "entities": 97},
+//	  "on_air": true,
+//	  "latest": {"call": "W1AW", "country": "United States", "flag": "us",
+//	             "time": "2025-03-01T18:30:00Z", "band": "20m", "mode": "SSB",
+//	             "url": "https://qsl.huma.id/call/W1AW"}
+//	}
+//
+// latest is left out while the log is empty, and its optional fields as in
+// /widget/latest.json.
+type apiSummary struct {
+	Version  int              `json:"version"`
+	Callsign string           `json:"callsign"`
+	Totals   apiSummaryTotals `json:"totals"`
+	OnAir    bool             `json:"on_air"`
+	Latest   *widgetQSO       `json:"latest,omitempty"`
+}
+
+// apiSummaryTotals are the log totals in the summary
+type apiSummaryTotals struct {
+	QSOs     int `json:"qsos"`
+	Entities int `json:"entities"`
+}
+
+// newAPISummary builds the summary from the home page statistics. The
+// station is on air if it logged a contact within the configured window.
+func newAPISummary(home *homeStats, callsign string, cfg *siteConfig, baseURL string, now time.Time) apiSummary {
+	summary := apiSummary{
+		Version:  summaryVersion,
+		Callsign: callsign,
+		Totals: apiSummaryTotals{
+			QSOs:     home.totalQSOs,
+			Entities: home.uniqueCountries,
+		},
+	}
+	if latest := home.latestQSO; latest != nil && !latest.Timestamp.IsZero() {
+		qso := newWidgetQSO(*latest, cfg, baseURL)
+		summary.Latest = &qso
+		summary.OnAir = now.Sub(latest.Timestamp) <= cfg.OnAirWindow
+	}
+	return summary
+}
+
+// newAPISummaryHandler returns the handler for /api/v1/summary. Unlike the
+// write API it is public, and any site or app may fetch it. Responses carry
+// an ETag, so clients polling it mostly get an empty 304.
+func newAPISummaryHandler(rp *ReloadableParser, callsign string) flamego.Handler {
+	return func(c flamego.Context, cfg *siteConfig) {
+		r := c.Request().Request
+		summary := newAPISummary(rp.homeStats(), callsign, cfg, cfg.baseURL(r), time.Now())
+
+		body, err := json.Marshal(summary)
+		if err != nil {
+			log.Printf("Failed to encode summary: %v", err)
+			http.Error(c.ResponseWriter(), "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+
+		w := c.ResponseWriter()
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", summaryCacheControl)
+		sum := sha256.Sum256(body)
+		w.Header().Set("ETag", fmt.Sprintf(`"%x"`, sum[:8]))
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(body))
+	}
+}
@@ -0,0 +1,37 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+func TestAPISummary(t *testing.T) {
+	now := time.Date(2025, 3, 1, 18, 30, 0, 0, time.UTC)
+	latest := utils.QSO{Call: "W1AW", Country: "United States", Band: "20m", Mode: "SSB", Timestamp: now.Add(-10 * time.Minute)}
+	home := &homeStats{totalQSOs: 1234, uniqueCountries: 97, latestQSO: &latest}
+	cfg := &siteConfig{OnAirWindow: 15 * time.Minute}
+
+	body, err := json.Marshal(newAPISummary(home, "A65HQ", cfg, "https://qsl.example", now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"version":1,"callsign":"A65HQ","totals":{"qsos":1234,"entities":97},"on_air":true,` +
+		`"latest":{"call":"W1AW","country":"United States","flag":"us","time":"2025-03-01T18:20:00Z","band":"20m","mode":"SSB","url":"https://qsl.example/call/W1AW"}}`
+	if string(body) != expected {
+		t.Errorf("Unexpected summary:\n%s\nexpected:\n%s", body, expected)
+	}
+
+	if summary := newAPISummary(home, "A65HQ", cfg, "", now.Add(time.Hour)); summary.OnAir {
+		t.Error("Expected station off air after the window")
+	}
+	if summary := newAPISummary(&homeStats{}, "A65HQ", cfg, "", now); summary.Latest != nil || summary.OnAir {
+		t.Errorf("Expected empty log to have no latest QSO, got %+v", summary)
+	}
+}
@@ -0,0 +1,48 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"net"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSdNotify(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+	if err := sdNotify("READY=1"); err != nil {
+		t.Errorf("Expected no notification outside systemd, got %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "notify")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	t.Setenv("NOTIFY_SOCKET", path)
+	if err := sdNotify("READY=1"); err != nil {
+		t.Fatalf("sdNotify failed: %v", err)
+	}
+	buf := make([]byte, 64)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := conn.Read(buf)
+	if err != nil || string(buf[:n]) != "READY=1" {
+		t.Errorf("Expected READY=1, got %q (%v)", buf[:n], err)
+	}
+}
+
+func TestSystemdListenersIgnoresOtherProcesses(t *testing.T) {
+	// The variables were meant for the parent
+	t.Setenv("LISTEN_PID", strconv.Itoa(1))
+	t.Setenv("LISTEN_FDS", "1")
+	listeners, err := systemdListeners()
+	if err != nil || listeners != nil {
+		t.Errorf("Expected no sockets, got %v (%v)", listeners, err)
+	}
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/flamego/flamego"
+	"github.com/flamego/session"
+)
+
+// themeSessionKey holds the visitor's theme preference in the session
+const themeSessionKey = "theme"
+
+// Theme preferences. Auto follows the system colour scheme.
+const (
+	themeAuto  = "auto"
+	themeLight = "light"
+	themeDark  = "dark"
+)
+
+// parseTheme returns a known theme preference, defaulting to auto
+func parseTheme(value string) string {
+	switch value = strings.ToLower(strings.TrimSpace(value)); value {
+	case themeLight, themeDark:
+		return value
+	}
+	return themeAuto
+}
+
+// sessionTheme returns the theme preference stored in the session
+func sessionTheme(s session.Session) string {
+	theme, _ := s.Get(themeSessionKey).(string)
+	return parseTheme(theme)
+}
+
+// localRedirect returns next if it is a path on this site, or fallback
+func localRedirect(next, fallback string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return fallback
+	}
+	return next
+}
+
+// handleThemeSet stores the visitor's theme preference and returns them to
+// the page they came from
+func handleThemeSet(c flamego.Context, s session.Session) {
+	r := c.Request().Request
+	theme := parseTheme(r.FormValue("theme"))
+	if theme == themeAuto {
+		s.Delete(themeSessionKey)
+	} else {
+		s.Set(themeSessionKey, theme)
+	}
+	c.Redirect(localRedirect(r.FormValue("next"), "/"), http.StatusFound)
+}
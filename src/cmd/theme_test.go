@@ -0,0 +1,38 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import "testing"
+
+func TestParseTheme(t *testing.T) {
+	tests := map[string]string{
+		"dark":   themeDark,
+		" Light": themeLight,
+		"auto":   themeAuto,
+		"":       themeAuto,
+		"purple": themeAuto,
+	}
+	for input, want := range tests {
+		if got := parseTheme(input); got != want {
+			t.Errorf("parseTheme(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestLocalRedirect(t *testing.T) {
+	tests := map[string]string{
+		"/qso/W1AW/1740832200": "/qso/W1AW/1740832200",
+		"/awards?band=20m":     "/awards?band=20m",
+		"":                     "/",
+		"https://example.com":  "/",
+		"//example.com":        "/",
+		"/\\example.com":       "/",
+	}
+	for input, want := range tests {
+		if got := localRedirect(input, "/"); got != want {
+			t.Errorf("localRedirect(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
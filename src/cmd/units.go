@@ -0,0 +1,48 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/flamego/flamego"
+	"github.com/flamego/session"
+)
+
+// unitsSessionKey holds the visitor's measurement system in the session
+const unitsSessionKey = "units"
+
+// Measurement systems for distances
+const (
+	unitsMetric   = "metric"
+	unitsImperial = "imperial"
+)
+
+// parseUnits returns a known measurement system, defaulting to metric
+func parseUnits(value string) string {
+	if strings.EqualFold(strings.TrimSpace(value), unitsImperial) {
+		return unitsImperial
+	}
+	return unitsMetric
+}
+
+// sessionUnits returns the measurement system stored in the session
+func sessionUnits(s session.Session) string {
+	units, _ := s.Get(unitsSessionKey).(string)
+	return parseUnits(units)
+}
+
+// handleUnitsSet stores the visitor's measurement system and returns them to
+// the page they came from
+func handleUnitsSet(c flamego.Context, s session.Session) {
+	r := c.Request().Request
+	if units := parseUnits(r.FormValue("units")); units == unitsImperial {
+		s.Set(unitsSessionKey, units)
+	} else {
+		s.Delete(unitsSessionKey)
+	}
+	c.Redirect(localRedirect(r.FormValue("next"), "/"), http.StatusFound)
+}
@@ -0,0 +1,49 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"testing"
+
+	"github.com/humaidq/humaid-qsl/locales"
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+func TestParseUnits(t *testing.T) {
+	tests := map[string]string{
+		"imperial":  unitsImperial,
+		" Imperial": unitsImperial,
+		"metric":    unitsMetric,
+		"":          unitsMetric,
+		"furlongs":  unitsMetric,
+	}
+	for input, want := range tests {
+		if got := parseUnits(input); got != want {
+			t.Errorf("parseUnits(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestLocalizerDistance(t *testing.T) {
+	catalog, err := utils.LoadCatalog(locales.Locales)
+	if err != nil {
+		t.Fatalf("LoadCatalog failed: %v", err)
+	}
+
+	tests := []struct {
+		locale, units string
+		want          string
+	}{
+		{"en", unitsMetric, "2,738 km"},
+		{"en", unitsImperial, "1,701 mi"},
+		{"es", unitsMetric, "2.738 km"},
+	}
+	for _, tt := range tests {
+		l := &localizer{catalog: catalog, locale: tt.locale, units: tt.units}
+		if got := l.Distance(2738); got != tt.want {
+			t.Errorf("Distance in %s/%s = %q, want %q", tt.locale, tt.units, got, tt.want)
+		}
+	}
+}
@@ -6,24 +6,29 @@ package cmd
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	gotemplate "html/template"
+	"io"
+	"io/fs"
 	"log"
+	"net"
 	"net/http"
-	"net/url"
+	"net/netip"
 	"os"
-	"path/filepath"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/dustin/go-humanize"
 	"github.com/flamego/csrf"
 	"github.com/flamego/flamego"
 	"github.com/flamego/session"
 	"github.com/flamego/template"
+	"github.com/quic-go/quic-go/http3"
 	"github.com/urfave/cli/v3"
 
+	"github.com/humaidq/humaid-qsl/locales"
 	"github.com/humaidq/humaid-qsl/static"
 	"github.com/humaidq/humaid-qsl/templates"
 	"github.com/humaidq/humaid-qsl/utils"
@@ -39,36 +44,500 @@ var CmdStart = &cli.Command{
 			Value: "8080",
 			Usage: "the web server port",
 		},
+		&cli.StringSliceFlag{
+			Name:  "listen",
+			Usage: "address to listen on as [https://]ADDR[=ROUTES], repeatable, e.g. https://:443=public and 127.0.0.1:8081=admin,api; ROUTES is a comma-separated list of public, admin and api, defaulting to all (overrides --port). Sockets passed by systemd socket activation replace the addresses in order",
+		},
+		&cli.StringFlag{
+			Name:  "tls-cert",
+			Usage: "TLS certificate file, serving HTTPS on --port or on https:// listeners",
+		},
+		&cli.StringFlag{
+			Name:  "tls-key",
+			Usage: "TLS private key file for --tls-cert",
+		},
+		&cli.BoolFlag{
+			Name:  "http2",
+			Value: true,
+			Usage: "serve HTTP/2 to browsers that support it over HTTPS",
+		},
+		&cli.BoolFlag{
+			Name:  "http3",
+			Usage: "also serve HTTP/3 over QUIC on the UDP port of each HTTPS listener, advertised to browsers with Alt-Svc",
+		},
+		&cli.StringFlag{
+			Name:  "pidfile",
+			Usage: "write the process ID to this file; send SIGUSR2 to restart into a new binary without dropping connections",
+		},
 		&cli.BoolFlag{
 			Name:  "dev",
 			Value: false,
-			Usage: "enables development mode (for templates)",
+			Usage: "enables development mode, loading templates and static files from ./templates and ./static on every request",
 		},
 		&cli.StringFlag{
 			Name:     "adif",
 			Usage:    "path to ADIF file containing QSO logs",
 			Required: true,
 		},
+		&cli.StringFlag{
+			Name:  "sqlite",
+			Usage: "mirror the log into an SQLite database at this path and answer QSO lookups from it",
+		},
 		&cli.DurationFlag{
 			Name:  "reload-interval",
 			Value: 5 * time.Minute,
 			Usage: "interval to reload the ADIF file (e.g., 5m, 1h, 30s)",
 		},
+		&cli.StringFlag{
+			Name:  "eqsl-ag-list",
+			Usage: "path to eQSL's AG member list (AGMemberList.txt) to mark eQSL confirmations as Authenticity Guaranteed, reread on every reload",
+		},
+		&cli.StringFlag{
+			Name:  "cty",
+			Usage: "path to a cty.dat country file (from country-files.com) to find the DXCC entity of QSOs logged without COUNTRY or DXCC from the callsign, reread on every reload",
+		},
+		&cli.BoolFlag{
+			Name:  "follow",
+			Value: false,
+			Usage: "tail the ADIF file and add appended QSOs as they are written, instead of reloading it periodically",
+		},
+		&cli.BoolFlag{
+			Name:  "qsl-writeback",
+			Value: false,
+			Usage: "record paper QSL cards marked as sent in the admin area in the ADIF file; don't use while a logger is writing to the file",
+		},
+		&cli.DurationFlag{
+			Name:  "follow-interval",
+			Value: 2 * time.Second,
+			Usage: "how often to check the ADIF file for appended QSOs when following it",
+		},
+		&cli.IntFlag{
+			Name:  "map-workers",
+			Value: 2,
+			Usage: "number of maps rendered at the same time",
+		},
+		&cli.IntFlag{
+			Name:  "map-prefetch-queue",
+			Value: 100,
+			Usage: "maximum number of background map renders to queue before dropping new ones",
+		},
+		&cli.StringFlag{
+			Name:  "map-memory",
+			Usage: "keep generated maps and cards in memory up to this size (e.g., 64MiB) instead of the maps directory, so the root file system can be read-only",
+		},
+		&cli.BoolFlag{
+			Name:  "map-spill",
+			Value: false,
+			Usage: "write maps and cards evicted from memory to the maps directory or S3 bucket instead of rendering them again",
+		},
+		&cli.StringFlag{
+			Name:  "s3-bucket",
+			Usage: "keep generated maps and cards in this S3-compatible bucket instead of the maps directory, so several instances share one cache",
+		},
+		&cli.StringFlag{
+			Name:  "s3-endpoint",
+			Value: "https://s3.amazonaws.com",
+			Usage: "S3-compatible endpoint URL (e.g., https://s3.eu-central-1.amazonaws.com or a MinIO, R2 or Garage URL)",
+		},
+		&cli.StringFlag{
+			Name:  "s3-region",
+			Value: "us-east-1",
+			Usage: "region used to sign S3 requests",
+		},
+		&cli.StringFlag{
+			Name:  "s3-prefix",
+			Value: "maps/",
+			Usage: "key prefix for images in the S3 bucket",
+		},
+		&cli.StringFlag{
+			Name:  "s3-access-key",
+			Usage: "S3 access key ID",
+		},
+		&cli.StringFlag{
+			Name:  "s3-secret-key",
+			Usage: "S3 secret access key",
+		},
+		&cli.StringFlag{
+			Name:  "satellite-tle",
+			Usage: "path to a TLE file (e.g., AMSAT's nasabare.txt) to draw the satellite footprint on satellite QSO maps",
+		},
+		&cli.DurationFlag{
+			Name:  "qrz-cache-ttl",
+			Value: time.Minute,
+			Usage: "how long to serve a cached copy of the /qrz page before rendering it again (0 disables caching)",
+		},
+		&cli.StringFlag{
+			Name:  "base-url",
+			Usage: "public base URL of the site used in shared links (e.g., https://qsl.huma.id)",
+		},
+		&cli.BoolFlag{
+			Name:  "index-confirmations",
+			Value: false,
+			Usage: "allow search engines to index QSO confirmation pages and list them in the sitemap",
+		},
+		&cli.BoolFlag{
+			Name:  "awards",
+			Value: false,
+			Usage: "show the public DXCC/WAS/WAZ/VUCC awards progress page",
+		},
+		&cli.StringFlag{
+			Name:  "certificate-operator",
+			Usage: "operator name printed on award certificates under the callsign",
+		},
+		&cli.StringFlag{
+			Name:  "certificate-logo",
+			Usage: "path to a PNG logo printed at the top of award certificates",
+		},
+		&cli.BoolFlag{
+			Name:  "contests",
+			Value: false,
+			Usage: "show public per-contest pages for QSOs logged with a CONTEST_ID",
+		},
+		&cli.BoolFlag{
+			Name:  "stats",
+			Value: false,
+			Usage: "show the public log statistics page",
+		},
+		&cli.BoolFlag{
+			Name:  "private",
+			Value: false,
+			Usage: "require a signed token in QSO confirmation links so the log can't be browsed by guessing callsigns and times (links are signed with the session secret)",
+		},
+		&cli.DurationFlag{
+			Name:  "on-air-window",
+			Value: 30 * time.Minute,
+			Usage: "how recently a QSO must have been logged for the station to show as on air",
+		},
+		&cli.StringFlag{
+			Name:  "callsign",
+			Value: "A66H",
+			Usage: "station callsign used when querying spotting networks and on share previews",
+		},
+		&cli.StringFlag{
+			Name:  "station-grid",
+			Usage: "my grid square for QSOs logged without MY_GRIDSQUARE, except portable and mobile ones (e.g., LL75rb)",
+		},
+		&cli.StringFlag{
+			Name:  "station-rig",
+			Usage: "my rig for QSOs logged without MY_RIG",
+		},
+		&cli.StringFlag{
+			Name:  "station-antenna",
+			Usage: "my antenna for QSOs logged without MY_ANTENNA",
+		},
+		&cli.StringFlag{
+			Name:  "station-power",
+			Usage: "my transmit power in watts for QSOs logged without TX_PWR",
+		},
+		&cli.BoolFlag{
+			Name:  "pskreporter",
+			Value: false,
+			Usage: "show recent PSK Reporter receptions on the home page",
+		},
+		&cli.DurationFlag{
+			Name:  "pskreporter-interval",
+			Value: 10 * time.Minute,
+			Usage: "interval to refresh PSK Reporter receptions (minimum 5m)",
+		},
+		&cli.BoolFlag{
+			Name:  "rbn",
+			Value: false,
+			Usage: "show recent Reverse Beacon Network CW/RTTY skimmer spots on the home page",
+		},
+		&cli.StringFlag{
+			Name:  "rbn-addr",
+			Value: utils.RBNTelnetAddr,
+			Usage: "Reverse Beacon Network telnet feed address",
+		},
+		&cli.StringFlag{
+			Name:  "aprs-api-key",
+			Usage: "aprs.fi API key to fetch my APRS position, used as the map origin of /P and /M QSOs without MY_GRIDSQUARE",
+		},
+		&cli.StringSliceFlag{
+			Name:  "aprs-callsign",
+			Usage: "APRS stations (callsign with SSID, e.g. A66H-9) to fetch the position of (defaults to --callsign)",
+		},
+		&cli.DurationFlag{
+			Name:  "aprs-interval",
+			Value: 5 * time.Minute,
+			Usage: "interval to refresh the APRS position",
+		},
+		&cli.StringFlag{
+			Name:  "callbook",
+			Usage: "callbook to look up the grid of QSOs logged without one, qrz or hamqth (needs --callbook-username and --callbook-password)",
+		},
+		&cli.StringFlag{
+			Name:  "callbook-username",
+			Usage: "callbook username",
+		},
+		&cli.StringFlag{
+			Name:  "callbook-password",
+			Usage: "callbook password",
+		},
+		&cli.DurationFlag{
+			Name:  "callbook-interval",
+			Value: 2 * time.Second,
+			Usage: "delay between callbook lookups",
+		},
+		&cli.StringFlag{
+			Name:  "n1mm-listen",
+			Usage: "UDP address to receive N1MM Logger+ contact broadcasts on (e.g., 127.0.0.1:12060)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "n1mm-allow",
+			Value: utils.DefaultN1MMSources,
+			Usage: "addresses or networks N1MM Logger+ broadcasts are accepted from (defaults to loopback and private networks)",
+		},
+		&cli.StringFlag{
+			Name:  "dxcluster",
+			Usage: "telnet DX cluster address to watch for spots (e.g., dxc.nc7j.com:7373)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "dxcluster-watch",
+			Usage: "callsigns to watch for on the DX cluster (defaults to --callsign)",
+		},
+		&cli.StringFlag{
+			Name:  "telegram-token",
+			Usage: "Telegram bot token for notifications",
+		},
+		&cli.StringFlag{
+			Name:  "telegram-chat-id",
+			Usage: "Telegram chat ID to send notifications to",
+		},
+		&cli.StringSliceFlag{
+			Name:  "telegram-events",
+			Value: []string{string(utils.EventLookup), string(utils.EventNewQSOs), string(utils.EventQSLRequest)},
+			Usage: "events to notify on Telegram (lookup, new_qso, reload, qsl_request)",
+		},
+		&cli.StringFlag{
+			Name:  "mqtt-broker",
+			Usage: "MQTT broker address to publish events to (e.g., localhost:1883)",
+		},
+		&cli.StringFlag{
+			Name:  "mqtt-topic-prefix",
+			Value: "humaid-qsl",
+			Usage: "MQTT topic prefix for published events",
+		},
+		&cli.StringFlag{
+			Name:  "mqtt-client-id",
+			Value: "humaid-qsl",
+			Usage: "MQTT client ID",
+		},
+		&cli.StringFlag{
+			Name:  "mqtt-username",
+			Usage: "MQTT username",
+		},
+		&cli.StringFlag{
+			Name:  "mqtt-password",
+			Usage: "MQTT password",
+		},
+		&cli.StringFlag{
+			Name:  "logsync-url",
+			Usage: "base URL of a Wavelog or Cloudlog instance to push new QSOs to (e.g., https://log.example.com)",
+		},
+		&cli.StringFlag{
+			Name:  "logsync-key",
+			Usage: "Wavelog or Cloudlog API key with write access",
+		},
+		&cli.StringFlag{
+			Name:  "logsync-station-id",
+			Value: "1",
+			Usage: "Wavelog or Cloudlog station profile ID to log pushed QSOs into",
+		},
+		&cli.StringFlag{
+			Name:  "session-cookie-name",
+			Value: "qsl_session",
+			Usage: "name of the session cookie",
+		},
+		&cli.BoolFlag{
+			Name:  "session-secure",
+			Value: false,
+			Usage: "only send the session cookie over HTTPS (enable when served behind an HTTPS proxy)",
+		},
+		&cli.StringFlag{
+			Name:  "session-samesite",
+			Value: "lax",
+			Usage: "SameSite mode of the session cookie (lax, strict or none, which needs --session-secure)",
+		},
+		&cli.DurationFlag{
+			Name:  "session-lifetime",
+			Value: 24 * time.Hour,
+			Usage: "how long sessions last",
+		},
+		&cli.StringFlag{
+			Name:  "session-dir",
+			Value: "sessions",
+			Usage: "directory to store sessions in, so they survive restarts",
+		},
+		&cli.StringFlag{
+			Name:  "session-secret",
+			Usage: "key used to sign CSRF tokens (generated and kept in qsl-session-secret if empty)",
+		},
+		&cli.StringFlag{
+			Name:  "admin-user",
+			Value: "admin",
+			Usage: "username for the admin area",
+		},
+		&cli.StringFlag{
+			Name:  "admin-password-hash",
+			Usage: "password hash for the admin area, from the hash-password command (admin area is disabled if empty)",
+		},
+		&cli.StringFlag{
+			Name:  "address-book-key",
+			Usage: "64 hex digit key encrypting the admin address book and QSL request addresses (generated and kept in qsl-addressbook.key if empty; keep a copy, backups don't include it)",
+		},
+		&cli.StringFlag{
+			Name:  "api-token",
+			Usage: "bearer token for the write API under /api/v1 (API is disabled if empty)",
+		},
+		&cli.BoolFlag{
+			Name:  "strict-match",
+			Usage: "require searches to give the exact logged minute of a QSO, with no tolerance or suggestions (combine with --private so pages can only be reached by searching)",
+		},
+		&cli.BoolFlag{
+			Name:  "strict-match-band",
+			Usage: "with --strict-match, also require the band of the QSO",
+		},
+		&cli.BoolFlag{
+			Name:  "strict-match-mode",
+			Usage: "with --strict-match, also require the mode of the QSO",
+		},
+		&cli.StringFlag{
+			Name:  "anonymize-ips",
+			Usage: "anonymize client addresses in the access and lookup logs, truncate to zero the host part or hash for a keyed hash (the abuse log keeps full addresses for fail2ban)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "redact",
+			Usage: "QSO fields to hide on public pages, as name, qth or comment, optionally with :partial to shorten instead (e.g. name:partial)",
+		},
+		&cli.DurationFlag{
+			Name:  "form-min-time",
+			Value: 2 * time.Second,
+			Usage: "minimum time between showing the search or contact form and submitting it, to catch bots (0 disables)",
+		},
+		&cli.StringFlag{
+			Name:  "captcha-provider",
+			Usage: "CAPTCHA service protecting the search form, turnstile or hcaptcha (disabled if empty)",
+		},
+		&cli.StringFlag{
+			Name:  "captcha-site-key",
+			Usage: "public site key for the CAPTCHA widget",
+		},
+		&cli.StringFlag{
+			Name:  "captcha-secret",
+			Usage: "secret key for verifying CAPTCHA responses",
+		},
+		&cli.IntFlag{
+			Name:  "captcha-threshold",
+			Value: 0,
+			Usage: "searches a client may make in an hour before the CAPTCHA is required (0 always requires it)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "trusted-proxies",
+			Usage: "addresses or networks of reverse proxies whose X-Forwarded-For and X-Real-IP headers name the client (set when served behind a proxy, or every visitor shares its rate limits and lockouts)",
+		},
+		&cli.IntFlag{
+			Name:  "lockout-failures",
+			Value: 20,
+			Usage: "failed searches a client may make within the lockout window before it is blocked (0 disables lockouts)",
+		},
+		&cli.DurationFlag{
+			Name:  "lockout-window",
+			Value: 10 * time.Minute,
+			Usage: "window in which failed searches are counted towards a lockout",
+		},
+		&cli.DurationFlag{
+			Name:  "lockout-duration",
+			Value: time.Hour,
+			Usage: "how long a locked out client stays blocked (0 blocks until removed in the admin area)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "compress-types",
+			Value: defaultCompressTypes,
+			Usage: "content types to gzip or deflate when the client accepts it (an empty value disables compression)",
+		},
+		&cli.StringFlag{
+			Name:  "smtp-host",
+			Usage: "SMTP server for emailing confirmations (requires --base-url)",
+		},
+		&cli.StringFlag{
+			Name:  "smtp-port",
+			Value: "587",
+			Usage: "SMTP server port",
+		},
+		&cli.StringFlag{
+			Name:  "smtp-username",
+			Usage: "SMTP username",
+		},
+		&cli.StringFlag{
+			Name:  "smtp-password",
+			Usage: "SMTP password",
+		},
+		&cli.StringFlag{
+			Name:  "contact-email",
+			Value: "qsl@huma.id",
+			Usage: "address that contact form messages are sent to (requires --smtp-host)",
+		},
+		&cli.StringFlag{
+			Name:  "smtp-from",
+			Value: "qsl@huma.id",
+			Usage: "sender address for emails",
+		},
 	},
 	Action: start,
 }
 
+// templateFuncs returns the functions available to the page templates
+func templateFuncs(assets *assetManifest, catalog *utils.Catalog, cfg *siteConfig) gotemplate.FuncMap {
+	return gotemplate.FuncMap{
+		"asset":   assets.path,
+		"flag":    assets.flag,
+		"t":       catalog.Translate,
+		"tn":      catalog.TranslatePlural,
+		"date":    catalog.FormatDate,
+		"number":  catalog.FormatInt,
+		"qsopath": cfg.qsoPath,
+		"redact":  cfg.redact,
+	}
+}
+
 // ReloadableParser wraps ADIFParser with automatic reloading capability
 type ReloadableParser struct {
 	parser   *utils.ADIFParser
 	filePath string
 	mutex    sync.RWMutex
+
+	fileQSOs []utils.QSO
+	fileKeys map[string]bool
+	live     map[string]utils.QSO // QSOs from live sources, keyed by source ID
+	loadedAt time.Time
+	warnings []string            // Parse warnings from the last load
+	issues   []utils.SanityIssue // Suspicious QSOs found in the last load
+	loadErr  error               // Error from the last reload attempt, if it failed
+
+	fileInfo os.FileInfo // ADIF file as it was when read, to notice it being replaced
+	offset   int64       // Bytes of the ADIF file read so far, for following it
+
+	generation uint64     // Incremented whenever the served log changes
+	home       *homeStats // Home page data for the current generation
+
+	events      *utils.EventBus
+	corrections *utils.CorrectionStore
+	lotw        *utils.LoTWStore
+	outgoing    *utils.OutgoingQSLStore
+	eqslAG      *utils.EqslAGList
+	cty         *utils.CtyDat
+	callbook    *utils.Callbook
+	station     *utils.StationDefaults
+	aprs        *utils.APRS
+	sqlite      *sqliteMirror // Mirror of the served log, if enabled
 }
 
 // NewReloadableParser creates a new reloadable parser
 func NewReloadableParser(filePath string) (*ReloadableParser, error) {
 	rp := &ReloadableParser{
 		filePath: filePath,
+		live:     make(map[string]utils.QSO),
 	}
 	
 	if err := rp.reload(); err != nil {
@@ -78,27 +547,207 @@ func NewReloadableParser(filePath string) (*ReloadableParser, error) {
 	return rp, nil
 }
 
-// reload reloads the ADIF file
+// reload reloads the ADIF file, remembering the outcome for the admin area
 func (rp *ReloadableParser) reload() error {
+	err := rp.load()
+
+	rp.mutex.Lock()
+	rp.loadErr = err
+	rp.mutex.Unlock()
+
+	return err
+}
+
+// load reads and parses the ADIF file and publishes the result
+func (rp *ReloadableParser) load() error {
 	file, err := os.Open(rp.filePath)
 	if err != nil {
 		return fmt.Errorf("failed to open ADIF file: %w", err)
 	}
 	defer file.Close()
 
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat ADIF file: %w", err)
+	}
 	parser := utils.NewADIFParser()
 	if err := parser.ParseFile(file); err != nil {
 		return fmt.Errorf("failed to parse ADIF file: %w", err)
 	}
+	offset, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("failed to read ADIF file: %w", err)
+	}
+
+	issues := utils.CheckLog(parser.QSOs, time.Now())
+	if rp.lotw != nil {
+		// Picks up confirmations imported from the command line
+		if err := rp.lotw.Load(); err != nil {
+			log.Printf("Failed to load LoTW confirmations: %v", err)
+		}
+	}
+	if rp.eqslAG != nil {
+		if err := rp.eqslAG.Load(); err != nil {
+			log.Printf("Failed to load eQSL AG list: %v", err)
+		}
+	}
+	if rp.cty != nil {
+		if err := rp.cty.Load(); err != nil {
+			log.Printf("Failed to load cty.dat: %v", err)
+		}
+	}
+
+	keys := make(map[string]bool, len(parser.QSOs))
+	for _, qso := range parser.QSOs {
+		keys[liveQSOKey(qso)] = true
+	}
 
 	rp.mutex.Lock()
-	rp.parser = parser
+	// Find QSOs that weren't in the file or live log before, except on the
+	// initial load
+	var newQSOs []utils.QSO
+	if rp.fileKeys != nil {
+		newQSOs = rp.newQSOs(parser.QSOs)
+	}
+
+	rp.fileQSOs = parser.QSOs
+	rp.fileKeys = keys
+	rp.fileInfo = info
+	rp.offset = offset
+	rp.loadedAt = time.Now()
+	rp.warnings = parser.Warnings
+	rp.issues = issues
+	rp.publish()
 	rp.mutex.Unlock()
 
 	log.Printf("Reloaded %d QSOs from %s", len(parser.GetQSOs()), rp.filePath)
+	if len(parser.Warnings) > 0 {
+		log.Printf("%d ADIF records had problems, see the admin area for details", len(parser.Warnings))
+	}
+	if len(issues) > 0 {
+		log.Printf("%d QSOs look suspicious, see the admin area for details", len(issues))
+	}
+
+	rp.events.Publish(utils.Event{Type: utils.EventReload, Count: len(parser.QSOs)})
+	if len(newQSOs) > 0 {
+		rp.events.Publish(utils.Event{Type: utils.EventNewQSOs, QSOs: newQSOs})
+	}
 	return nil
 }
 
+// newQSOs returns the QSOs that aren't already in the ADIF file or live log.
+// The caller must hold the lock.
+func (rp *ReloadableParser) newQSOs(qsos []utils.QSO) []utils.QSO {
+	live := make(map[string]bool, len(rp.live))
+	for _, qso := range rp.live {
+		live[liveQSOKey(qso)] = true
+	}
+
+	var newQSOs []utils.QSO
+	for _, qso := range qsos {
+		key := liveQSOKey(qso)
+		if !rp.fileKeys[key] && !live[key] {
+			newQSOs = append(newQSOs, qso)
+		}
+	}
+	return newQSOs
+}
+
+// liveQSOKey identifies a QSO for matching live contacts against the ADIF
+// file, at minute precision since loggers differ in whether they log seconds
+func liveQSOKey(qso utils.QSO) string {
+	return fmt.Sprintf("%s|%d", qso.Call, qso.Timestamp.Truncate(time.Minute).Unix())
+}
+
+// publish builds the served parser from the ADIF file QSOs merged with QSOs
+// received from live sources, with entities derived from callsigns, grids
+// from the callbook, APRS positions, station defaults, LoTW confirmations,
+// eQSL AG status and corrections applied over them. Live QSOs that have
+// since been written to the ADIF file are dropped. The caller must hold the
+// write lock.
+func (rp *ReloadableParser) publish() {
+	qsos := rp.fileQSOs
+	if len(rp.live) > 0 {
+		qsos = make([]utils.QSO, len(rp.fileQSOs), len(rp.fileQSOs)+len(rp.live))
+		copy(qsos, rp.fileQSOs)
+
+		for id, qso := range rp.live {
+			if rp.fileKeys[liveQSOKey(qso)] {
+				delete(rp.live, id)
+				continue
+			}
+			qsos = append(qsos, qso)
+		}
+	}
+
+	parser := utils.NewADIFParser()
+	parser.Reloader = rp.reload
+	parser.QSOs = rp.corrections.Apply(rp.outgoing.Apply(rp.eqslAG.Apply(rp.lotw.Apply(rp.station.Apply(rp.aprs.Apply(rp.callbook.Apply(rp.cty.Apply(qsos))))))))
+	rp.parser = parser
+	rp.generation++
+	rp.home = nil
+
+	if rp.sqlite != nil {
+		rp.sqlite.publish(parser.QSOs, rp.generation)
+	}
+}
+
+// refresh republishes the served parser, e.g. after corrections change
+func (rp *ReloadableParser) refresh() {
+	rp.mutex.Lock()
+	defer rp.mutex.Unlock()
+	rp.publish()
+}
+
+// maxLiveQSOs caps the QSOs held from live sources. They are normally
+// written to the ADIF file soon after and dropped from the live log.
+const maxLiveQSOs = 1000
+
+// setLiveQSO adds or replaces a QSO received from a live source. New QSOs
+// are ignored once the live log is full.
+func (rp *ReloadableParser) setLiveQSO(id string, qso utils.QSO) {
+	rp.mutex.Lock()
+	_, replaced := rp.live[id]
+	if !replaced && len(rp.live) >= maxLiveQSOs {
+		rp.mutex.Unlock()
+		log.Printf("Ignoring live QSO with %s, already holding %d live QSOs", qso.Call, maxLiveQSOs)
+		return
+	}
+	rp.live[id] = qso
+	rp.publish()
+	rp.mutex.Unlock()
+
+	if !replaced {
+		rp.events.Publish(utils.Event{Type: utils.EventNewQSOs, QSOs: []utils.QSO{qso}})
+	}
+}
+
+// deleteLiveQSO removes a QSO previously received from a live source
+func (rp *ReloadableParser) deleteLiveQSO(id string) {
+	rp.mutex.Lock()
+	defer rp.mutex.Unlock()
+
+	if _, exists := rp.live[id]; !exists {
+		return
+	}
+	delete(rp.live, id)
+	rp.publish()
+}
+
+// handleN1MMContact merges an N1MM Logger+ contact broadcast into the log
+func (rp *ReloadableParser) handleN1MMContact(contact utils.N1MMContact) {
+	id := "n1mm:" + contact.ID
+
+	switch contact.Action {
+	case utils.N1MMContactDelete:
+		rp.deleteLiveQSO(id)
+		log.Printf("Removed N1MM contact %s", contact.ID)
+	default:
+		rp.setLiveQSO(id, contact.QSO)
+		log.Printf("Received N1MM contact with %s on %s %s", contact.QSO.Call, contact.QSO.Band, contact.QSO.Mode)
+	}
+}
+
 // startReloading starts the periodic reload goroutine
 func (rp *ReloadableParser) startReloading(interval time.Duration) {
 	go func() {
@@ -113,6 +762,18 @@ func (rp *ReloadableParser) startReloading(interval time.Duration) {
 	}()
 }
 
+// getStore returns the QSO store served to handlers, which is the SQLite
+// mirror if there is one holding the served log and the current parser
+// otherwise, e.g. while the mirror catches up
+func (rp *ReloadableParser) getStore() utils.QSOStore {
+	rp.mutex.RLock()
+	defer rp.mutex.RUnlock()
+	if rp.sqlite != nil && rp.sqlite.holds(rp.generation) {
+		return rp.sqlite.store
+	}
+	return rp.parser
+}
+
 // getParser returns the current parser (thread-safe)
 func (rp *ReloadableParser) getParser() *utils.ADIFParser {
 	rp.mutex.RLock()
@@ -120,53 +781,160 @@ func (rp *ReloadableParser) getParser() *utils.ADIFParser {
 	return rp.parser
 }
 
+// reloadStatus describes the last load of the ADIF file
+type reloadStatus struct {
+	LoadedAt  time.Time
+	FileCount int
+	LiveCount int
+	Warnings  []string
+	Issues    []utils.SanityIssue
+	Err       error // Error from the last attempt, if it failed
+}
+
+// status returns when the ADIF file was last loaded, how many QSOs came from
+// the file and from live sources, and any problems found
+func (rp *ReloadableParser) status() reloadStatus {
+	rp.mutex.RLock()
+	defer rp.mutex.RUnlock()
+	return reloadStatus{
+		LoadedAt:  rp.loadedAt,
+		FileCount: len(rp.fileQSOs),
+		LiveCount: len(rp.live),
+		Warnings:  rp.warnings,
+		Issues:    rp.issues,
+		Err:       rp.loadErr,
+	}
+}
+
+// homeStats is the part of the home page computed from the log, which only
+// changes when the log is republished
+type homeStats struct {
+	totalQSOs       int
+	uniqueCountries int
+	latestQSOs      []utils.QSO
+	hallOfFame      []utils.QSO
+	latestQSO       *utils.QSO
+}
+
+// currentGeneration returns the generation of the served log
+func (rp *ReloadableParser) currentGeneration() uint64 {
+	rp.mutex.RLock()
+	defer rp.mutex.RUnlock()
+	return rp.generation
+}
+
+// homeStats returns the home page statistics for the served log, computing
+// them once per generation
+func (rp *ReloadableParser) homeStats() *homeStats {
+	rp.mutex.RLock()
+	home, parser, generation := rp.home, rp.parser, rp.generation
+	rp.mutex.RUnlock()
+	if home != nil {
+		return home
+	}
+
+	home = &homeStats{
+		totalQSOs:       parser.GetTotalQSOCount(),
+		uniqueCountries: parser.GetUniqueCountriesCount(),
+		latestQSOs:      parser.GetLatestQSOs(30),
+		hallOfFame:      parser.GetPaperQSLHallOfFame(),
+		latestQSO:       parser.GetLatestQSO(),
+	}
+
+	// Don't cache over a log that was republished while computing
+	rp.mutex.Lock()
+	if rp.generation == generation {
+		rp.home = home
+	}
+	rp.mutex.Unlock()
+
+	return home
+}
+
 // populateHomeData fills the template data with common home page data
-func populateHomeData(data template.Data, parser *utils.ADIFParser, csrf csrf.CSRF) {
-	data["TotalQSOs"] = parser.GetTotalQSOCount()
-	data["UniqueCountries"] = parser.GetUniqueCountriesCount()
-	data["LatestQSOs"] = parser.GetLatestQSOs(30)
-	data["PaperQSLHallOfFame"] = parser.GetPaperQSLHallOfFame()
+func populateHomeData(data template.Data, rp *ReloadableParser, csrf csrf.CSRF, spots *utils.RecentSpots, l *localizer, s session.Session, captcha *captchaWidget) {
+	home := rp.homeStats()
+	data["TotalQSOs"] = home.totalQSOs
+	data["UniqueCountries"] = home.uniqueCountries
+	data["LatestQSOs"] = home.latestQSOs
+	data["PaperQSLHallOfFame"] = home.hallOfFame
 	data["CSRFToken"] = csrf.Token()
 
 	// Add latest QSO information
-	latestQSO := parser.GetLatestQSO()
+	latestQSO := home.latestQSO
 	if latestQSO != nil && !latestQSO.Timestamp.IsZero() {
-		data["LatestQSODate"] = latestQSO.FormatDate()
-		data["LatestQSOTimeAgo"] = humanize.Time(latestQSO.Timestamp)
+		data["LatestQSODate"] = l.Date(latestQSO.Timestamp)
+		data["LatestQSOTimeAgo"] = l.Ago(latestQSO.Timestamp)
+	}
+
+	data["RecentSearches"] = sessionRecentSearches(s)
+	if captcha != nil {
+		data["Captcha"] = captcha
+	}
+
+	// Add spots of me from the enabled spotting networks
+	if recent := spots.Recent(15); len(recent) > 0 {
+		data["Spots"] = recent
+		data["SpotterCount"] = spots.SpotterCount()
 	}
 }
 
-// generateMapIfNeeded generates a map image if it doesn't already exist
-func generateMapIfNeeded(fileName, myGrid, theirGrid string) {
-	mapPath := filepath.Join("maps", fileName)
-	
-	// Check if map already exists
-	if _, err := os.Stat(mapPath); err == nil {
-		return
+// clientIP returns the IP address of the client making the request
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
 	}
-	
-	// Generate the map
-	if err := generateMap(fileName, myGrid, theirGrid); err != nil {
-		log.Printf("Failed to generate map %s: %v", fileName, err)
+	return host
+}
+
+// canMapQSO reports whether a map can be drawn for a QSO. QSOs without both
+// grids simply have no map; malformed grids are logged so they can be fixed.
+func canMapQSO(qso utils.QSO) bool {
+	if qso.MyGridSquare == "" || qso.GridSquare == "" {
+		return false
+	}
+	for _, grid := range []string{qso.MyGridSquare, qso.GridSquare} {
+		if err := utils.ValidateGridSquare(grid); err != nil {
+			log.Printf("Skipping map for %s at %s: %v", qso.Call, qso.FormatQSOTime(), err)
+			return false
+		}
 	}
+	return true
 }
 
-// generateMap creates a map image showing the two grid locations
-func generateMap(fileName, myGrid, theirGrid string) error {
-	config := utils.MapConfig{
-		Width:      600,
-		Height:     400,
-		Zoom:       0, // Will be auto-calculated
-		OutputPath: filepath.Join("maps", fileName),
+// newMapGenerator returns a map renderer drawing the two grid locations of a
+// QSO, and the satellite footprint for satellite QSOs with a known orbit
+func newMapGenerator(store imageStore, tles *utils.TLESet) func(fileName string, qso utils.QSO) error {
+	return func(fileName string, qso utils.QSO) error {
+		config := utils.MapConfig{
+			Width:  600,
+			Height: 400,
+			Zoom:   0, // Will be auto-calculated
+		}
+		if qso.IsSatellite() {
+			if pos, ok := tles.Position(qso.SatName, qso.Timestamp); ok {
+				config.Satellite = &pos
+			}
+		}
+
+		img, err := utils.RenderGridMap(qso.MyGridSquare, qso.GridSquare, config)
+		if err != nil {
+			return err
+		}
+		data, err := utils.EncodePNG(img)
+		if err != nil {
+			return err
+		}
+		return store.Put(fileName, data)
 	}
-	
-	return utils.CreateGridMap(myGrid, theirGrid, config)
 }
 
 func start(ctx context.Context, cmd *cli.Command) (err error) {
-	// Create maps directory if it doesn't exist
-	if err := os.MkdirAll("maps", 0755); err != nil {
-		return fmt.Errorf("failed to create maps directory: %w", err)
+	// Generated maps and cards, in the maps directory or object storage
+	images, err := newImageStore(cmd)
+	if err != nil {
+		return err
 	}
 
 	// Load ADIF file with reloading capability
@@ -177,31 +945,328 @@ func start(ctx context.Context, cmd *cli.Command) (err error) {
 	if err != nil {
 		return fmt.Errorf("failed to initialize reloadable parser: %w", err)
 	}
-	
-	// Start automatic reloading
-	reloadableParser.startReloading(reloadInterval)
-	log.Printf("Started ADIF file reloading every %v", reloadInterval)
+
+	// Set up event notifications
+	events := utils.NewEventBus()
+	reloadableParser.events = events
+
+	// Corrections made in the admin area, merged over the ADIF data
+	corrections, err := utils.NewCorrectionStore("qsl-corrections.json")
+	if err != nil {
+		return fmt.Errorf("failed to load corrections: %w", err)
+	}
+	reloadableParser.corrections = corrections
+
+	// Confirmations imported from LoTW reports, merged into matching QSOs
+	lotw, err := utils.NewLoTWStore(lotwStorePath)
+	if err != nil {
+		return fmt.Errorf("failed to load LoTW confirmations: %w", err)
+	}
+	reloadableParser.lotw = lotw
+
+	// Paper QSL cards marked as sent in the admin area
+	outgoing, err := utils.NewOutgoingQSLStore(outgoingStorePath)
+	if err != nil {
+		return fmt.Errorf("failed to load outgoing QSL cards: %w", err)
+	}
+	reloadableParser.outgoing = outgoing
+
+	// eQSL Authenticity Guaranteed members, for logs without the AG flag
+	if path := cmd.String("eqsl-ag-list"); path != "" {
+		if reloadableParser.eqslAG, err = utils.NewEqslAGList(path); err != nil {
+			return err
+		}
+	}
+
+	// Country file, for the entity of QSOs logged without one
+	if path := cmd.String("cty"); path != "" {
+		if reloadableParser.cty, err = utils.NewCtyDat(path); err != nil {
+			return err
+		}
+	}
+
+	// Station details for QSOs logged without them
+	if reloadableParser.station, err = newStationDefaults(cmd); err != nil {
+		return err
+	}
+
+	// Callbook grids, for maps of QSOs logged without one
+	if service := cmd.String("callbook"); service != "" {
+		if reloadableParser.callbook, err = utils.NewCallbook("qsl-callbook.json", service,
+			cmd.String("callbook-username"), cmd.String("callbook-password")); err != nil {
+			return err
+		}
+		reloadableParser.callbook.StartFetching(cmd.Duration("callbook-interval"), reloadableParser.refresh)
+		log.Printf("Looking up missing grids on %s", service)
+	}
+
+	// APRS positions, used as the origin of portable QSOs without a grid
+	if apiKey := cmd.String("aprs-api-key"); apiKey != "" {
+		names := cmd.StringSlice("aprs-callsign")
+		if len(names) == 0 {
+			names = []string{cmd.String("callsign")}
+		}
+		reloadableParser.aprs = utils.NewAPRS(names, apiKey)
+		reloadableParser.aprs.StartFetching(cmd.Duration("aprs-interval"), reloadableParser.refresh)
+		log.Printf("Fetching APRS positions of %s", strings.Join(names, ", "))
+	}
+
+	// QSO lookups from an SQLite database, filled when the log is published
+	if path := cmd.String("sqlite"); path != "" {
+		store, err := utils.OpenSQLiteStore(path)
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+		store.Reloader = reloadableParser.reload
+		reloadableParser.sqlite = newSQLiteMirror(store)
+		log.Printf("Answering QSO lookups from SQLite database %s", path)
+	}
+	reloadableParser.refresh()
+
+	if token := cmd.String("telegram-token"); token != "" {
+		chatID := cmd.String("telegram-chat-id")
+		if chatID == "" {
+			return fmt.Errorf("--telegram-chat-id is required with --telegram-token")
+		}
+		telegram := utils.NewTelegramNotifier(token, chatID, cmd.StringSlice("telegram-events"))
+		events.Subscribe(telegram.Handle)
+		log.Printf("Sending Telegram notifications for %s", strings.Join(cmd.StringSlice("telegram-events"), ", "))
+	}
+
+	if broker := cmd.String("mqtt-broker"); broker != "" {
+		mqtt := utils.NewMQTTPublisher(broker,
+			cmd.String("mqtt-client-id"),
+			cmd.String("mqtt-username"),
+			cmd.String("mqtt-password"),
+			cmd.String("mqtt-topic-prefix"))
+		events.Subscribe(mqtt.Handle)
+		log.Printf("Publishing events to MQTT broker %s under %s/", broker, cmd.String("mqtt-topic-prefix"))
+	}
+
+	if syncURL := cmd.String("logsync-url"); syncURL != "" {
+		if cmd.String("logsync-key") == "" {
+			return fmt.Errorf("--logsync-key is required with --logsync-url")
+		}
+		logSync := utils.NewLogSync(syncURL, cmd.String("logsync-key"), cmd.String("logsync-station-id"))
+		events.Subscribe(logSync.Handle)
+		log.Printf("Pushing new QSOs to %s", syncURL)
+	}
+
+	// Start following or periodically reloading the ADIF file
+	if cmd.Bool("follow") {
+		reloadableParser.startFollowing(cmd.Duration("follow-interval"))
+		log.Printf("Following ADIF file for appended QSOs every %v", cmd.Duration("follow-interval"))
+	} else {
+		reloadableParser.startReloading(reloadInterval)
+		log.Printf("Started ADIF file reloading every %v", reloadInterval)
+	}
+
+	// Optionally merge live contacts broadcast by N1MM Logger+
+	if n1mmAddr := cmd.String("n1mm-listen"); n1mmAddr != "" {
+		var allowed []netip.Prefix
+		for _, spec := range cmd.StringSlice("n1mm-allow") {
+			prefix, err := utils.ParseBlockPrefix(spec)
+			if err != nil {
+				return fmt.Errorf("invalid --n1mm-allow: %w", err)
+			}
+			allowed = append(allowed, prefix)
+		}
+		go func() {
+			if err := utils.ListenN1MM(n1mmAddr, allowed, reloadableParser.handleN1MMContact); err != nil {
+				log.Printf("N1MM listener stopped: %v", err)
+			}
+		}()
+		log.Printf("Listening for N1MM contact broadcasts on %s", n1mmAddr)
+	}
+
+	// Spotting networks merged into the home page spots panel, each enabled
+	// by its own flag
+	var feeds []utils.SpotFeed
+
+	// Optionally fetch PSK Reporter receptions in the background
+	if cmd.Bool("pskreporter") {
+		psk := utils.NewPSKReporter(cmd.String("callsign"), time.Hour)
+		psk.StartFetching(cmd.Duration("pskreporter-interval"))
+		feeds = append(feeds, psk)
+		log.Printf("Started PSK Reporter fetching for %s", cmd.String("callsign"))
+	}
+
+	// Optionally watch the Reverse Beacon Network for skimmer spots of me
+	if cmd.Bool("rbn") {
+		rbn := utils.NewRBN(cmd.String("rbn-addr"), cmd.String("callsign"))
+		rbn.Start()
+		feeds = append(feeds, rbn)
+		log.Printf("Watching RBN %s for spots of %s", cmd.String("rbn-addr"), cmd.String("callsign"))
+	}
+
+	// Optionally watch a DX cluster for spots of my callsigns
+	if dxAddr := cmd.String("dxcluster"); dxAddr != "" {
+		watch := cmd.StringSlice("dxcluster-watch")
+		if len(watch) == 0 {
+			watch = []string{cmd.String("callsign")}
+		}
+		dx := utils.NewDXCluster(dxAddr, cmd.String("callsign"), watch)
+		dx.Start()
+		feeds = append(feeds, dx)
+		log.Printf("Watching DX cluster %s for spots of %s", dxAddr, strings.Join(watch, ", "))
+	}
+
+	// Optionally allow visitors to email themselves confirmations
+	var mailer *utils.Mailer
+	if smtpHost := cmd.String("smtp-host"); smtpHost != "" {
+		// Emailed links must not be built from the Host header, which anyone
+		// can set to point a confirmation at their own site
+		if cmd.String("base-url") == "" {
+			return fmt.Errorf("--base-url is required with --smtp-host")
+		}
+		mailer = utils.NewMailer(smtpHost,
+			cmd.String("smtp-port"),
+			cmd.String("smtp-username"),
+			cmd.String("smtp-password"),
+			cmd.String("smtp-from"))
+		log.Printf("Sending email through %s", smtpHost)
+	}
+
+	// Paper QSL card requests from other stations, with their postal
+	// addresses encrypted like the address book
+	addressKey, err := addressBookKey(cmd.String("address-book-key"))
+	if err != nil {
+		return err
+	}
+	qslRequests, err := utils.NewQSLRequestStore("qsl-requests.json", addressKey)
+	if err != nil {
+		return fmt.Errorf("failed to load QSL requests: %w", err)
+	}
+
+	blocks, err := utils.NewBlockList("qsl-blocklist.json")
+	if err != nil {
+		return fmt.Errorf("failed to load block list: %w", err)
+	}
+
+	maintenance, err := utils.NewMaintenanceMode("qsl-maintenance.json")
+	if err != nil {
+		return fmt.Errorf("failed to load maintenance mode: %w", err)
+	}
 
 	f := flamego.Classic()
 
-	// Setup flamego
-	fs, err := template.EmbedFS(templates.Templates, ".", []string{".html"})
+	// Setup flamego. In development templates and static files are read
+	// from the source directories, and the templater parses the templates
+	// again on every request.
+	dev := cmd.Bool("dev")
+	var templateOpts template.Options
+	var staticFS fs.FS = static.Static
+	if dev {
+		flamego.SetEnv(flamego.EnvTypeDev)
+		templateOpts.Directory = "templates"
+		staticFS = os.DirFS("static")
+		log.Printf("Development mode, loading templates and static files from disk")
+	} else {
+		if templateOpts.FileSystem, err = template.EmbedFS(templates.Templates, ".", []string{".html"}); err != nil {
+			panic(err)
+		}
+	}
+	sessionOpts, err := newSessionOptions(cmd)
 	if err != nil {
-		panic(err)
+		return err
 	}
-	f.Use(session.Sessioner())
-	f.Use(csrf.Csrfer())
-	f.Use(template.Templater(template.Options{
-		FileSystem: fs,
-	}))
+	secret, err := sessionSecret(cmd)
+	if err != nil {
+		return err
+	}
+	f.Use(session.Sessioner(sessionOpts))
+	f.Use(csrf.Csrfer(csrf.Options{Secret: secret}))
+	cfg := newSiteConfig(cmd)
+	cfg.setConfirmationKey(secret)
+	if cfg.redactor, err = utils.ParseRedactor(cmd.StringSlice("redact")); err != nil {
+		return err
+	}
+	branding, err := newCertificateBranding(cmd)
+	if err != nil {
+		return err
+	}
+	forms := utils.NewFormGuard(secret, cmd.Duration("form-min-time"))
+	ips, err := utils.NewIPAnonymizer(cmd.String("anonymize-ips"), secret)
+	if err != nil {
+		return err
+	}
+	assets, err := newAssetManifest(staticFS)
+	if err != nil {
+		return err
+	}
+	// Asset URLs aren't fingerprinted in development, so edited files are
+	// picked up without restarting
+	assets.fingerprint = !dev
+	catalog, err := utils.LoadCatalog(locales.Locales)
+	if err != nil {
+		return fmt.Errorf("failed to load translations: %w", err)
+	}
+	templateOpts.FuncMaps = []gotemplate.FuncMap{templateFuncs(assets, catalog, cfg)}
+	f.Use(template.Templater(templateOpts))
+	f.Use(assets.handler)
 	f.Use(flamego.Static(flamego.StaticOptions{
-		FileSystem: http.FS(static.Static),
+		FileSystem: http.FS(staticFS),
 	}))
 
-	// Inject ADIF parser into context
+	// Inject the QSO store into context
 	f.Use(func(c flamego.Context) {
-		c.Map(reloadableParser.getParser())
+		c.MapTo(reloadableParser.getStore(), (*utils.QSOStore)(nil))
 	})
+	// Orbits for drawing satellite footprints on satellite QSO maps
+	var tles *utils.TLESet
+	if path := cmd.String("satellite-tle"); path != "" {
+		if tles, err = utils.NewTLESet(path); err != nil {
+			return err
+		}
+	}
+	maps := newMapRenderer(images, newMapGenerator(images, tles), cmd.Int("map-prefetch-queue"))
+	maps.start(max(cmd.Int("map-workers"), 1))
+	f.Map(reloadableParser)
+	f.MapTo(images, (*imageStore)(nil))
+	f.Map(maps)
+	f.Map(newCardRenderer(images, maps))
+	f.Map(cfg)
+	f.Map(utils.NewRecentSpots(feeds...))
+	f.Map(events)
+	f.Map(qslRequests)
+	f.Map(outgoing)
+	f.Map(corrections)
+	f.Map(lotw)
+	f.Map(blocks)
+	f.Map(maintenance)
+	f.Map(forms)
+
+	captcha, err := newSearchCaptcha(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to set up CAPTCHA: %w", err)
+	}
+	f.Map(captcha)
+	f.Map(newLookupGuard(cmd, blocks))
+
+	// Site-wide template data used by the navigation
+	f.Use(func(c flamego.Context, data template.Data, s session.Session, x csrf.CSRF) {
+		data["AwardsEnabled"] = cfg.Awards
+		data["ContestsEnabled"] = cfg.Contests
+		data["StatsEnabled"] = cfg.Stats
+		if pos, ok := reloadableParser.aprs.Current(); ok {
+			data["OperatingFrom"] = pos
+		}
+		data["StrictMatch"] = cfg.StrictMatch
+		data["StrictBand"] = cfg.StrictBand
+		data["StrictMode"] = cfg.StrictMode
+		if cfg.StrictBand {
+			data["Bands"] = utils.BandNames()
+		}
+		data["ContactEnabled"] = mailer != nil
+		data["IsAdmin"] = s.Get(adminSessionKey) != nil
+		data["Theme"] = sessionTheme(s)
+		data["CurrentPath"] = c.Request().URL.RequestURI()
+		data["CSRFToken"] = x.Token()
+		data["FormStamp"] = forms.Stamp(time.Now(), s.ID())
+	})
+	f.Use(newLocaleHandler(catalog))
+	f.Use(recoverWithErrorPage)
 
 	// Add request logging middleware
 	f.Use(func(c flamego.Context) {
@@ -212,8 +1277,8 @@ func start(ctx context.Context, cmd *cli.Command) (err error) {
 		logEntry := fmt.Sprintf("[%s] %s %s %s - %v\n",
 			start.Format("2006-01-02 15:04:05"),
 			c.Request().Method,
-			c.Request().URL.Path,
-			c.Request().RemoteAddr,
+			maskAPIKeyPath(c.Request().URL.Path),
+			ips.Anonymize(c.Request().RemoteAddr),
 			time.Since(start))
 
 		// Append to log file
@@ -224,172 +1289,331 @@ func start(ctx context.Context, cmd *cli.Command) (err error) {
 		}
 	})
 
-	f.Get("/", func(t template.Template, data template.Data, parser *utils.ADIFParser, x csrf.CSRF) {
-		populateHomeData(data, parser, x)
+	// Reject banned clients before any search or form handler runs
+	f.Use(newBlockListMiddleware(blocks))
+
+	// Public pages show the maintenance page while a migration is underway
+	f.Use(newMaintenanceMiddleware(maintenance, assets))
+
+	f.Get("/", func(c flamego.Context, t template.Template, data template.Data, rp *ReloadableParser, x csrf.CSRF, spots *utils.RecentSpots, l *localizer, s session.Session, captcha *searchCaptcha) {
+		populateHomeData(data, rp, x, spots, l, s, captcha.widget(clientIP(c.Request().Request)))
+		// Filled in by the search form on error pages
+		data["SearchCallsign"] = strings.ToUpper(strings.TrimSpace(c.Query("callsign")))
 		t.HTML(http.StatusOK, "home")
 	})
 
-	f.Get("/qrz", func(t template.Template, data template.Data, parser *utils.ADIFParser) {
-		data["LatestQSOs"] = parser.GetLatestQSOs(30)
-		data["PaperQSLHallOfFame"] = parser.GetPaperQSLHallOfFame()
+	f.Get("/healthz", handleHealth)
+	f.Get("/robots.txt", handleRobots)
+	f.Get("/sitemap.xml", handleSitemap)
+	f.Get("/sitemap-{page}.xml", handleSitemapPage)
+
+	f.Get("/qrz", func(t template.Template, data template.Data, rp *ReloadableParser) {
+		// The page is cached and embedded on QRZ.com, so it is always English
+		data["Locale"] = utils.DefaultLocale
+		data["Dir"] = utils.Direction(utils.DefaultLocale)
+		home := rp.homeStats()
+		data["LatestQSOs"] = home.latestQSOs
+		data["PaperQSLHallOfFame"] = home.hallOfFame
 		t.HTML(http.StatusOK, "qrz")
 	})
 
-	// PNG route handler for serving cached map images (must be before the general route)
-	f.Get("/{path}.png", func(c flamego.Context, w http.ResponseWriter, parser *utils.ADIFParser) (int, error) {
-		path := c.Param("path")
-		
-		// Split on the last dash to separate callsign and timestamp
-		lastDash := strings.LastIndex(path, "-")
-		if lastDash == -1 {
-			return http.StatusNotFound, nil
-		}
-		
-		encodedCallsign := path[:lastDash]
-		timestampStr := path[lastDash+1:]
-		
-		callsign, err := url.QueryUnescape(encodedCallsign)
+	f.Get("/oembed", handleOEmbed)
+
+	// Embeddable latest QSOs for other sites
+	f.Get("/widget/latest", handleWidgetLatest)
+	f.Get("/widget/latest.json", handleWidgetLatestJSON)
+	f.Get("/api/v1/summary", newAPISummaryHandler(reloadableParser, strings.ToUpper(cmd.String("callsign"))))
+
+	// Glob so portable callsigns such as A66H/P still match. The history
+	// lists every QSO with a station, so it isn't offered in private mode.
+	if !cfg.Private {
+		f.Get("/call/{call: **}", handleCallsignHistory)
+	}
+
+	if cfg.Awards {
+		f.Get("/awards", handleAwards)
+		f.Get("/awards/dxcc", handleDXCCMatrix)
+		f.Get("/awards/challenge", handleDXCCChallenge)
+		f.Get("/awards/grids", handleGridChase)
+		f.Get("/awards/certificates/{id}", newCertificateHandler(branding))
+	}
+
+	if cfg.Stats {
+		f.Get("/stats", handleStats)
+	}
+	if cfg.Contests {
+		f.Get("/contests", handleContests)
+		f.Get("/contests/{id}", handleContest)
+	}
+
+	f.Post("/theme", csrf.Validate, handleThemeSet)
+	f.Post("/locale", csrf.Validate, newLocaleSetHandler(catalog))
+	f.Post("/units", csrf.Validate, handleUnitsSet)
+
+	f.Get("/live", handleLive)
+	f.Get("/live/events", newLiveEventsHandler(reloadableParser, events))
+	f.Get("/ws", newWebSocketHandler(reloadableParser, events, assets))
+
+	if admin := newAdminAuth(cmd); admin != nil {
+		// Postal addresses for direct cards, only reachable from the admin
+		// area
+		addressBook, err := newAddressBook(cmd)
 		if err != nil {
+			return fmt.Errorf("failed to open address book: %w", err)
+		}
+
+		f.Get("/admin/login", admin.handleLoginForm)
+		f.Post("/admin/login", csrf.Validate, admin.handleLogin)
+		f.Get("/admin", admin.require, newAdminDashboardHandler(reloadableParser, adminSettings(cmd)))
+		f.Group("/admin", func() {
+			f.Post("/logout", csrf.Validate, admin.handleLogout)
+			f.Post("/reload", csrf.Validate, newAdminReloadHandler(reloadableParser))
+			f.Post("/maintenance", csrf.Validate, handleAdminMaintenance)
+			f.Post("/maintenance/off", csrf.Validate, handleAdminMaintenanceOff)
+			f.Get("/logs/{name}", handleAdminLogs)
+			f.Get("/lookups", handleAdminLookups)
+			f.Get("/maps", handleAdminMaps)
+			f.Post("/maps/purge", csrf.Validate, handleAdminMapsPurge)
+			f.Post("/maps/clear", csrf.Validate, handleAdminMapsClear)
+			f.Get("/backup", newBackupHandler(reloadableParser))
+			f.Get("/blocklist", handleAdminBlockList)
+			f.Post("/blocklist", csrf.Validate, handleAdminBlockAdd)
+			f.Post("/blocklist/remove", csrf.Validate, handleAdminBlockRemove)
+			f.Get("/corrections", handleAdminCorrections)
+			f.Get("/lotw", handleAdminLoTW)
+			f.Post("/lotw", csrf.Validate, newAdminLoTWImportHandler(reloadableParser))
+			f.Get("/corrections/qso/{call: **}/{unix}", handleAdminCorrectionForm)
+			f.Post("/corrections/qso/{call: **}/{unix}", csrf.Validate, newAdminCorrectionSaveHandler(reloadableParser))
+			f.Get("/contests", handleAdminContests)
+			f.Get("/contests/{id}/dupes", handleAdminContestDupes)
+			f.Get("/contests/{id}/cabrillo", newAdminCabrilloHandler(cmd.String("callsign")))
+			f.Get("/qsl-requests", handleAdminQSLRequests)
+			f.Post("/qsl-requests/{id}/sent", csrf.Validate, newAdminQSLRequestSentHandler(reloadableParser))
+			f.Get("/outgoing", newAdminOutgoingHandler(addressBook))
+			f.Get("/outgoing/labels.csv", newAdminLabelsCSVHandler(addressBook))
+			f.Get("/addresses", newAdminAddressBookHandler(addressBook))
+			f.Post("/addresses", csrf.Validate, newAdminAddressBookSaveHandler(addressBook))
+			f.Get("/outgoing/bureau.csv", handleAdminBureauCSV)
+			f.Get("/outgoing/bureau.pdf", newAdminBureauPDFHandler(cmd.String("callsign")))
+			f.Post("/outgoing/qso/{call: **}/{unix}/sent", csrf.Validate, newAdminOutgoingSentHandler(reloadableParser, cmd.Bool("qsl-writeback")))
+		}, admin.require)
+		log.Printf("Admin area enabled for %s", cmd.String("admin-user"))
+	}
+
+	// The API authenticates each request with a bearer token rather than the
+	// session cookie, so its routes check the token first and skip CSRF
+	// validation. Blocked clients are still rejected by the middleware above.
+	if api := newAPIAuth(cmd); api != nil {
+		f.Group("/api/v1", func() {
+			f.Post("/reload", newAPIReloadHandler(reloadableParser))
+			f.Post("/qsos", newAPIQSOHandler(reloadableParser))
+			f.Get("/backup", newBackupHandler(reloadableParser))
+		}, api.require)
+
+		// Cloudlog's API, for loggers that already push QSOs to Cloudlog
+		for _, prefix := range []string{"/api", "/index.php/api"} {
+			f.Post(prefix+"/qso", newCloudlogQSOHandler(api, reloadableParser))
+			f.Get(prefix+"/auth/{key}", newCloudlogAuthHandler(api))
+		}
+		log.Printf("Write API enabled")
+	}
+
+	if mailer != nil {
+		f.Get("/contact", handleContact)
+		f.Post("/contact", csrf.Validate, newContactSubmitHandler(mailer, cmd.String("contact-email")))
+	}
+
+	// Confirmation routes glob the callsign so portable callsigns such as
+	// A66H/P keep their slash. The card and map routes must come before the
+	// page route.
+	f.Get("/qso/{call: **}/{unix}/card.png", handleConfirmationCard)
+	f.Get("/qso/{call: **}/{unix}.png", func(c flamego.Context, w http.ResponseWriter, store utils.QSOStore, cfg *siteConfig, maps *mapRenderer) (int, error) {
+		qso, ok := cfg.findQSO(c, store)
+		if !ok {
 			return http.StatusNotFound, nil
 		}
-		callsign = strings.ToUpper(callsign)
-		
-		// Use URL-safe filename by replacing special characters
-		safeCallsign := strings.ReplaceAll(callsign, "/", "_")
-		mapFileName := fmt.Sprintf("%s-%s.png", safeCallsign, timestampStr)
-		mapPath := filepath.Join("maps", mapFileName)
-		
-		// Check if map file exists
-		if _, err := os.Stat(mapPath); os.IsNotExist(err) {
-			// Try to find the QSO and generate the map
-			timestamp, err := strconv.ParseInt(timestampStr, 10, 64)
-			if err != nil {
-				return http.StatusNotFound, nil
-			}
-			
-			searchTime := time.Unix(timestamp, 0)
-			qsos := parser.SearchQSO(callsign, searchTime, 10)
-			
-			if len(qsos) == 0 || qsos[0].MyGridSquare == "" || qsos[0].GridSquare == "" {
+
+		mapFileName := mapFileNameFor(qso)
+
+		// Wait for the map if it isn't cached yet, ahead of any prefetches
+		if !maps.exists(mapFileName) {
+			if !canMapQSO(qso) {
 				return http.StatusNotFound, nil
 			}
-			
-			// Generate map synchronously for immediate serving
-			if err := generateMap(mapFileName, qsos[0].MyGridSquare, qsos[0].GridSquare); err != nil {
-				log.Printf("Failed to generate map for %s: %v", mapFileName, err)
+			if err := maps.Render(mapFileName, qso); err != nil {
 				return http.StatusInternalServerError, nil
 			}
 		}
-		
-		// Serve the map file
-		w.Header().Set("Content-Type", "image/png")
-		http.ServeFile(w, c.Request().Request, mapPath)
-		return http.StatusOK, nil
-	})
-
-	f.Get("/{path}", func(c flamego.Context, t template.Template, data template.Data, parser *utils.ADIFParser) {
-		path := c.Param("path")
-		
-		// Split on the last dash to separate callsign and timestamp
-		lastDash := strings.LastIndex(path, "-")
-		if lastDash == -1 {
-			c.Redirect("/", http.StatusFound)
-			return
-		}
-		
-		encodedCallsign := path[:lastDash]
-		timestampStr := path[lastDash+1:]
-		
-		callsign, err := url.QueryUnescape(encodedCallsign)
-		if err != nil {
-			c.Redirect("/", http.StatusFound)
-			return
-		}
-		callsign = strings.ToUpper(callsign)
 
-		// Parse Unix timestamp
-		timestamp, err := strconv.ParseInt(timestampStr, 10, 64)
-		if err != nil {
-			c.Redirect("/", http.StatusFound)
-			return
+		if err := serveMap(w, c.Request().Request, maps.store, mapFileName); err != nil {
+			log.Printf("Failed to serve map %s: %v", mapFileName, err)
+			return http.StatusInternalServerError, nil
 		}
+		return http.StatusOK, nil
+	})
 
-		searchTime := time.Unix(timestamp, 0)
-
-		// Search QSOs with 10-minute tolerance
-		qsos := parser.SearchQSO(callsign, searchTime, 10)
-
-		if len(qsos) == 0 {
-			c.Redirect("/", http.StatusFound)
+	f.Get("/qso/{call: **}/{unix}", func(c flamego.Context, t template.Template, data template.Data, store utils.QSOStore, x csrf.CSRF, cfg *siteConfig, qslRequests *utils.QSLRequestStore, maps *mapRenderer, l *localizer) {
+		currentQSO, ok := cfg.findQSO(c, store)
+		if !ok {
+			renderQSONotFound(c, t, data, l)
 			return
 		}
 
-		// Return single result and all QSOs for this callsign
-		currentQSO := qsos[0]
-		allQSOs := parser.GetQSOsByCallsign(callsign)
+		pagePath := cfg.confirmationPath(currentQSO)
+		allQSOs := store.GetQSOsByCallsign(currentQSO.Call)
 
 		// Generate or check for cached map
 		mapURL := ""
-		if currentQSO.MyGridSquare != "" && currentQSO.GridSquare != "" {
-			safeCallsign := strings.ReplaceAll(callsign, "/", "_")
-			mapFileName := fmt.Sprintf("%s-%s.png", safeCallsign, timestampStr)
-			// Use encoded callsign for the URL
-			encodedCallsign := url.QueryEscape(callsign)
-			mapURL = fmt.Sprintf("/%s-%s.png", encodedCallsign, timestampStr)
-			
-			// Generate map in background if it doesn't exist
-			go generateMapIfNeeded(mapFileName, currentQSO.MyGridSquare, currentQSO.GridSquare)
+		if canMapQSO(currentQSO) {
+			mapURL = pagePath + ".png"
+
+			// Start on the map before the browser asks for it
+			maps.Prefetch(mapFileNameFor(currentQSO), currentQSO)
+
+			if km, err := utils.Distance(currentQSO.MyGridSquare, currentQSO.GridSquare); err == nil {
+				data["Distance"] = l.Distance(km)
+			}
 		}
 
 		data["QSO"] = currentQSO
+		data["PagePath"] = pagePath
+		data["CorrectionURL"] = "/admin/corrections" + confirmationPath(currentQSO)
 		data["AllQSOs"] = allQSOs
-		data["Callsign"] = callsign
+		data["Callsign"] = strings.ToUpper(currentQSO.Call)
 		data["MapURL"] = mapURL
+		data["CSRFToken"] = x.Token()
+		data["Title"] = confirmationTitle(currentQSO)
+		baseURL := cfg.baseURL(c.Request().Request)
+		og := newConfirmationOpenGraph(currentQSO, cfg.Callsign, baseURL, pagePath, mapURL)
+		// Share the composed card rather than the bare map
+		og.Image = baseURL + pagePath + "/card.png"
+		og.ImageWidth, og.ImageHeight = utils.CardWidth, utils.CardHeight
+		data["OpenGraph"] = og
+		if mailer != nil {
+			data["EmailURL"] = pagePath + "/email"
+			data["EmailStatus"] = c.Query("email")
+			data["EmailMessage"] = l.T(emailStatusMessages[c.Query("email")])
+		}
+		data["QSLRequestURL"] = pagePath + "/qsl-request"
+		data["QSLRequested"] = qslRequests.Pending(currentQSO.Call, currentQSO.Timestamp)
+		data["QSLRequestStatus"] = c.Query("qsl")
+		data["QSLRequestMessage"] = l.T(qslRequestStatusMessages[c.Query("qsl")])
 		t.HTML(http.StatusOK, "result")
 	})
 
-	f.Post("/", csrf.Validate, func(c flamego.Context, t template.Template, data template.Data, parser *utils.ADIFParser, x csrf.CSRF) {
+	if mailer != nil {
+		f.Post("/qso/{call: **}/{unix}/email", csrf.Validate, newEmailConfirmationHandler(mailer))
+	}
+	f.Post("/qso/{call: **}/{unix}/qsl-request", csrf.Validate, newQSLRequestHandler(qslRequests))
+
+	// Old CALLSIGN-UNIXTIME links, kept working for shared URLs and QSL cards
+	f.Get("/{path}.png", newLegacyQSORedirect(".png"))
+	f.Get("/{path}", newLegacyQSORedirect(""))
+	f.NotFound(handleNotFound)
+
+	f.Post("/", csrf.Validate, func(c flamego.Context, t template.Template, data template.Data, store utils.QSOStore, rp *ReloadableParser, x csrf.CSRF, spots *utils.RecentSpots, events *utils.EventBus, cfg *siteConfig, l *localizer, s session.Session, captcha *searchCaptcha, guard *lookupGuard, forms *utils.FormGuard) {
 		callsign := strings.TrimSpace(strings.ToUpper(c.Request().FormValue("callsign")))
 		year := strings.TrimSpace(c.Request().FormValue("year"))
 		month := strings.TrimSpace(c.Request().FormValue("month"))
 		day := strings.TrimSpace(c.Request().FormValue("day"))
 		hour := strings.TrimSpace(c.Request().FormValue("hour"))
 		minute := strings.TrimSpace(c.Request().FormValue("minute"))
+		dateTime := strings.TrimSpace(c.Request().FormValue("datetime"))
+		band := strings.TrimSpace(c.Request().FormValue("band"))
+		mode := strings.TrimSpace(c.Request().FormValue("mode"))
 
 		// Validate inputs
 		if callsign == "" {
-			data["Error"] = "Call sign is required"
-			populateHomeData(data, parser, x)
+			data["Error"] = l.T("search.error.callsign")
+			populateHomeData(data, rp, x, spots, l, s, captcha.widget(clientIP(c.Request().Request)))
 			t.HTML(http.StatusBadRequest, "home")
 			return
 		}
-
-		if year == "" || month == "" || day == "" || hour == "" || minute == "" {
-			data["Error"] = "All date and time fields are required"
-			populateHomeData(data, parser, x)
+		if (cfg.StrictBand && band == "") || (cfg.StrictMode && mode == "") {
+			data["Error"] = l.T("search.error.strict")
+			populateHomeData(data, rp, x, spots, l, s, captcha.widget(clientIP(c.Request().Request)))
 			t.HTML(http.StatusBadRequest, "home")
 			return
 		}
 
-		// Parse timestamp from separate fields
-		timestampStr := fmt.Sprintf("%s-%02s-%02sT%02s:%02s", year, month, day, hour, minute)
-		searchTime, err := time.Parse("2006-01-02T15:04", timestampStr)
+		var searchTime time.Time
+		if dateTime != "" {
+			// The free-form field takes precedence over the separate fields
+			parsed, err := utils.ParseDateTime(dateTime)
+			if err != nil {
+				switch {
+				case errors.Is(err, utils.ErrDateTimeNoTime):
+					data["Error"] = l.T("search.error.notime")
+				case errors.Is(err, utils.ErrDateTimeFormat):
+					data["Error"] = l.T("search.error.format", dateTime)
+				default:
+					data["Error"] = l.T("search.error.invalid")
+				}
+				populateHomeData(data, rp, x, spots, l, s, captcha.widget(clientIP(c.Request().Request)))
+				t.HTML(http.StatusBadRequest, "home")
+				return
+			}
+			searchTime = parsed
+		} else {
+			if year == "" || month == "" || day == "" || hour == "" || minute == "" {
+				data["Error"] = l.T("search.error.datetime")
+				populateHomeData(data, rp, x, spots, l, s, captcha.widget(clientIP(c.Request().Request)))
+				t.HTML(http.StatusBadRequest, "home")
+				return
+			}
+
+			// Parse timestamp from separate fields
+			timestampStr := fmt.Sprintf("%s-%02s-%02sT%02s:%02s", year, month, day, hour, minute)
+			parsed, err := time.Parse("2006-01-02T15:04", timestampStr)
+			if err != nil {
+				data["Error"] = l.T("search.error.invalid")
+				populateHomeData(data, rp, x, spots, l, s, captcha.widget(clientIP(c.Request().Request)))
+				t.HTML(http.StatusBadRequest, "home")
+				return
+			}
+			searchTime = parsed
+		}
+
+		// Cheap bot checks come before the CAPTCHA. Repeating one of the
+		// session's recent searches is a single click, so it may be quick.
+		err := checkFormGuard(forms, c.Request().Request, s)
+		if errors.Is(err, utils.ErrFormTooFast) && isRecentSearch(s, callsign, searchTime) {
+			err = nil
+		}
 		if err != nil {
-			data["Error"] = "Invalid date and time values"
-			populateHomeData(data, parser, x)
+			log.Printf("Rejected search from %s: %v", clientIP(c.Request().Request), err)
+			data["Error"] = l.T("search.error.spam")
+			populateHomeData(data, rp, x, spots, l, s, captcha.widget(clientIP(c.Request().Request)))
+			t.HTML(http.StatusBadRequest, "home")
+			return
+		}
+
+		if err := captcha.check(c.Request().Request); err != nil {
+			if !errors.Is(err, utils.ErrCaptchaMissing) {
+				log.Printf("CAPTCHA check failed for %s: %v", clientIP(c.Request().Request), err)
+			}
+			data["Error"] = l.T("search.error.captcha")
+			populateHomeData(data, rp, x, spots, l, s, captcha.widget(clientIP(c.Request().Request)))
 			t.HTML(http.StatusBadRequest, "home")
 			return
 		}
 
-		// Search QSOs with 10-minute tolerance
-		qsos := parser.SearchQSO(callsign, searchTime, 10)
+		// Strict matching treats the search as proof of the QSO, so it must
+		// give the exact minute and any required band and mode
+		var qsos []utils.QSO
+		if cfg.StrictMatch {
+			qsos = store.MatchQSO(callsign, searchTime, band, mode)
+		} else {
+			qsos = store.SearchQSO(callsign, searchTime, qsoSearchTolerance)
+		}
+		rememberSearch(s, callsign, searchTime)
 
 		// Log QSO lookup
 		logEntry := fmt.Sprintf("[%s] QSO_SEARCH %s %s %s - %s\n",
 			time.Now().Format("2006-01-02 15:04:05"),
 			callsign,
 			searchTime.Format("2006-01-02 15:04"),
-			c.Request().RemoteAddr,
+			ips.Anonymize(c.Request().RemoteAddr),
 			func() string {
 				if len(qsos) > 0 {
 					return "SUCCESS"
@@ -403,32 +1627,160 @@ func start(ctx context.Context, cmd *cli.Command) (err error) {
 			logFile.Close()
 		}
 
+		events.Publish(utils.Event{
+			Type:       utils.EventLookup,
+			Callsign:   callsign,
+			SearchTime: searchTime,
+			Found:      len(qsos) > 0,
+			QSOs:       qsos,
+		})
+
 		if len(qsos) == 0 {
-			data["Error"] = fmt.Sprintf("No QSO found for %s around %s UTC", callsign, searchTime.Format("2006-01-02 15:04"))
-			populateHomeData(data, parser, x)
+			if guard.failed(clientIP(c.Request().Request), callsign, searchTime) {
+				http.Error(c.ResponseWriter(), "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			data["Error"] = l.T("search.error.notfound", callsign, l.Date(searchTime)+" "+searchTime.Format("15:04"))
+			// Suggestions would link to other stations' QSOs, and would give
+			// away the exact time strict matching asks for
+			if !cfg.Private && !cfg.StrictMatch {
+				data["Suggestions"] = store.SuggestQSOs(callsign, searchTime, qsoSearchTolerance, maxSearchSuggestions)
+			}
+			populateHomeData(data, rp, x, spots, l, s, captcha.widget(clientIP(c.Request().Request)))
 			t.HTML(http.StatusOK, "home")
 			return
 		}
 
 		// Redirect to unique QSO URL
-		qso := qsos[0]
-		unixTimestamp := qso.Timestamp.Unix()
-		encodedCallsign := url.QueryEscape(qso.Call)
-		redirectURL := fmt.Sprintf("/%s-%d", encodedCallsign, unixTimestamp)
-		c.Redirect(redirectURL, http.StatusFound)
+		c.Redirect(cfg.confirmationPath(qsos[0]), http.StatusFound)
 	})
 
-	port := cmd.String("port")
+	certFile, keyFile := cmd.String("tls-cert"), cmd.String("tls-key")
+	if (certFile == "") != (keyFile == "") {
+		return fmt.Errorf("--tls-cert and --tls-key must be given together")
+	}
+	var tlsConfig *tls.Config
+	if certFile != "" {
+		config, err := loadTLSConfig(certFile, keyFile)
+		if err != nil {
+			return err
+		}
+		tlsConfig = config
+	} else if cmd.Bool("http3") {
+		return fmt.Errorf("--http3 needs --tls-cert and --tls-key")
+	}
+
+	listeners := []listener{{addr: fmt.Sprintf("0.0.0.0:%s", cmd.String("port")), tls: certFile != ""}}
+	if specs := cmd.StringSlice("listen"); len(specs) > 0 {
+		listeners = listeners[:0]
+		for _, spec := range specs {
+			l, err := parseListener(spec)
+			if err != nil {
+				return err
+			}
+			if l.tls && certFile == "" {
+				return fmt.Errorf("--listen %s needs --tls-cert and --tls-key", spec)
+			}
+			listeners = append(listeners, l)
+		}
+	}
+
+	// The QRZ.com page is fetched often but only changes with the log
+	pages := newPageCache(f, cmd.Duration("qrz-cache-ttl"), reloadableParser.currentGeneration, "/qrz")
+	proxies, err := parseTrustedProxies(cmd.StringSlice("trusted-proxies"))
+	if err != nil {
+		return err
+	}
+	handler := withTrustedProxies(newCompressHandler(pages, cmd.StringSlice("compress-types")), proxies)
 
-	log.Printf("Starting web server on port %s\n", port)
-	srv := &http.Server{
-		Addr:         fmt.Sprintf("0.0.0.0:%s", port),
-		Handler:      f,
-		ReadTimeout:  5 * time.Second,
-		WriteTimeout: 10 * time.Second,
+	// Sockets passed by systemd socket activation, or by the process this
+	// one restarted from, are used in place of the listener addresses, in
+	// the same order
+	activated, err := systemdListeners()
+	if err != nil {
+		return err
+	}
+	inherited, err := inheritedListeners()
+	if err != nil {
+		return err
+	}
+	if len(activated) == 0 {
+		activated = inherited
+	}
+	if len(activated) > 0 && len(activated) != len(listeners) {
+		return fmt.Errorf("%d sockets were passed for %d listeners", len(activated), len(listeners))
 	}
 
-	log.Fatal(srv.ListenAndServe())
+	// A restarted process waits for the previous one to close its HTTP/3
+	// sockets, which it does before finishing in-flight requests
+	quicWait := time.Duration(0)
+	if inherited != nil {
+		quicWait = shutdownTimeout
+	}
 
-	return nil
+	errs := make(chan error, 2*len(listeners))
+	servers := make([]*http.Server, 0, len(listeners))
+	quicServers := make([]*http3.Server, 0, len(listeners))
+	sockets := make([]net.Listener, 0, len(listeners))
+	for i, l := range listeners {
+		routes := l.filter(handler, assets)
+		if l.tls && cmd.Bool("http3") {
+			h3 := l.newHTTP3Server(routes, tlsConfig)
+			quicServers = append(quicServers, h3)
+			go func() {
+				conn, err := listenQUIC(l.addr, quicWait)
+				if err != nil {
+					errs <- err
+					return
+				}
+				defer conn.Close()
+				log.Printf("Starting HTTP/3 server on %s serving %s\n", l.addr, l)
+				errs <- h3.Serve(conn)
+			}()
+			routes = withAltSvc(routes, h3)
+		}
+
+		srv := l.newServer(routes, cmd.Bool("http2"))
+		if l.tls {
+			srv.TLSConfig = tlsConfig
+		}
+
+		var ln net.Listener
+		if len(activated) > 0 {
+			ln = activated[i]
+			log.Printf("Starting web server on inherited socket %s serving %s\n", ln.Addr(), l)
+		} else {
+			if ln, err = net.Listen("tcp", l.addr); err != nil {
+				return fmt.Errorf("failed to listen on %s: %w", l.addr, err)
+			}
+			log.Printf("Starting web server on %s serving %s\n", l.addr, l)
+		}
+		servers = append(servers, srv)
+		sockets = append(sockets, ln)
+		go func() {
+			if l.tls {
+				errs <- srv.ServeTLS(ln, "", "")
+				return
+			}
+			errs <- srv.Serve(ln)
+		}()
+	}
+
+	if pidFile := cmd.String("pidfile"); pidFile != "" {
+		if err := writePidFile(pidFile); err != nil {
+			return err
+		}
+		defer removePidFile(pidFile)
+	}
+
+	// The log has been parsed and every socket is listening
+	if inherited != nil {
+		takeOver()
+	}
+	if err := sdNotify("READY=1"); err != nil {
+		log.Printf("Failed to signal readiness: %v", err)
+	}
+
+	return serve(servers, quicServers, sockets, errs)
 }
@@ -0,0 +1,117 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/flamego/flamego"
+	"github.com/flamego/template"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// Row counts for the latest QSOs widget, which can show at most the QSOs
+// kept for the home page
+const (
+	defaultWidgetRows = 10
+	maxWidgetRows     = 30
+)
+
+// widgetCacheControl lets browsers and the sites embedding the widget cache it
+// briefly
+const widgetCacheControl = "public, max-age=300"
+
+// widgetQSO is a QSO as returned by the JSON widget
+type widgetQSO struct {
+	Call    string    `json:"call"`
+	Country string    `json:"country,omitempty"`
+	Flag    string    `json:"flag,omitempty"`
+	Time    time.Time `json:"time"`
+	Band    string    `json:"band,omitempty"`
+	Mode    string    `json:"mode,omitempty"`
+	URL     string    `json:"url,omitempty"`
+}
+
+// newWidgetQSO returns a QSO as shown by the widgets, linking to the
+// callsign history page under baseURL
+func newWidgetQSO(qso utils.QSO, cfg *siteConfig, baseURL string) widgetQSO {
+	result := widgetQSO{
+		Call:    qso.Call,
+		Country: qso.Country,
+		Flag:    qso.GetFlagCode(),
+		Time:    qso.Timestamp.UTC(),
+		Band:    qso.Band,
+		Mode:    qso.Mode,
+	}
+	// Callsign history pages aren't available in private mode
+	if !cfg.Private {
+		result.URL = baseURL + callsignPath(qso.Call)
+	}
+	return result
+}
+
+// widgetRows returns the number of rows requested with ?rows=, clamped to
+// what the widget can show
+func widgetRows(r *http.Request) int {
+	rows, err := strconv.Atoi(r.URL.Query().Get("rows"))
+	if err != nil || rows < 1 {
+		return defaultWidgetRows
+	}
+	return min(rows, maxWidgetRows)
+}
+
+// widgetLatestQSOs returns the latest QSOs for the widget
+func widgetLatestQSOs(rp *ReloadableParser, rows int) []utils.QSO {
+	qsos := rp.homeStats().latestQSOs
+	if len(qsos) > rows {
+		qsos = qsos[:rows]
+	}
+	return qsos
+}
+
+// handleWidgetLatest serves the latest QSOs as a small standalone page with
+// its own styles, to be embedded in other sites with an iframe. Embedding
+// sites choose the language with ?lang= as they can't share the session.
+func handleWidgetLatest(c flamego.Context, t template.Template, data template.Data, rp *ReloadableParser, cfg *siteConfig, l *localizer) {
+	r := c.Request().Request
+	locale := r.URL.Query().Get("lang")
+	if !l.catalog.Supported(locale) {
+		locale = utils.DefaultLocale
+	}
+	data["Locale"] = locale
+	data["Dir"] = utils.Direction(locale)
+	data["LatestQSOs"] = widgetLatestQSOs(rp, widgetRows(r))
+	data["Private"] = cfg.Private
+
+	c.ResponseWriter().Header().Set("Cache-Control", widgetCacheControl)
+	t.HTML(http.StatusOK, "widget-latest")
+}
+
+// handleWidgetLatestJSON serves the latest QSOs as JSON for sites that render
+// the widget themselves
+func handleWidgetLatestJSON(c flamego.Context, rp *ReloadableParser, cfg *siteConfig) {
+	r := c.Request().Request
+	baseURL := cfg.baseURL(r)
+
+	qsos := widgetLatestQSOs(rp, widgetRows(r))
+	result := make([]widgetQSO, len(qsos))
+	for i, qso := range qsos {
+		result[i] = newWidgetQSO(qso, cfg, baseURL)
+	}
+
+	w := c.ResponseWriter()
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", widgetCacheControl)
+	// Any site may fetch the widget data from the browser
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Printf("Failed to write widget JSON: %v", err)
+	}
+}
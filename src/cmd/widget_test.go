@@ -0,0 +1,30 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWidgetRows(t *testing.T) {
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"", defaultWidgetRows},
+		{"?rows=5", 5},
+		{"?rows=0", defaultWidgetRows},
+		{"?rows=-3", defaultWidgetRows},
+		{"?rows=abc", defaultWidgetRows},
+		{"?rows=1000", maxWidgetRows},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/widget/latest"+tt.query, nil)
+		if got := widgetRows(r); got != tt.want {
+			t.Errorf("widgetRows(%q) = %d, want %d", tt.query, got, tt.want)
+		}
+	}
+}
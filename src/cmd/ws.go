@@ -0,0 +1,128 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/flamego/flamego"
+
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// maxWebSockets limits concurrent /ws connections, each holding a goroutine
+// and a socket for as long as the home page is open
+const maxWebSockets = 500
+
+// wsWriteTimeout is how long a slow visitor may take to receive a message
+// before the socket is dropped
+const wsWriteTimeout = 10 * time.Second
+
+// wsTotals is the message pushing the log totals to home page visitors
+type wsTotals struct {
+	Type         string `json:"type"` // Always "totals"
+	QSOs         int    `json:"qsos"`
+	Entities     int    `json:"entities"`
+	QSOsText     string `json:"qsos_text"`     // QSOs formatted for the visitor's language
+	EntitiesText string `json:"entities_text"` // Entities formatted for the visitor's language
+}
+
+// wsQSO is the message pushing a newly logged contact to home page visitors
+type wsQSO struct {
+	Type    string    `json:"type"` // Always "qso"
+	QSO     widgetQSO `json:"qso"`
+	Date    string    `json:"date"`               // Date formatted for the visitor's language
+	FlagURL string    `json:"flag_url,omitempty"` // Flag image of the QSO's country
+}
+
+// newWebSocketHandler returns the /ws handler, which pushes updated totals
+// and newly logged contacts to home page visitors. New contacts arrive
+// through the same events as the live page, whether from reloading or
+// following the ADIF file, the API or N1MM broadcasts.
+func newWebSocketHandler(rp *ReloadableParser, events *utils.EventBus, assets *assetManifest) flamego.Handler {
+	var open atomic.Int64
+
+	return func(c flamego.Context, cfg *siteConfig, l *localizer) {
+		if open.Add(1) > maxWebSockets {
+			open.Add(-1)
+			http.Error(c.ResponseWriter(), "Service Unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		defer open.Add(-1)
+
+		// Subscribe before upgrading, so no contact logged meanwhile is
+		// missed. Slow visitors drop contacts rather than hold up the bus.
+		updates := make(chan utils.Event, 16)
+		unsubscribe := events.Subscribe(func(e utils.Event) {
+			if e.Type != utils.EventReload && e.Type != utils.EventNewQSOs {
+				return
+			}
+			select {
+			case updates <- e:
+			default:
+			}
+		})
+		defer unsubscribe()
+
+		ws, err := utils.UpgradeWebSocket(c.ResponseWriter(), c.Request().Request)
+		if err != nil {
+			return
+		}
+		defer ws.Close()
+
+		baseURL := cfg.baseURL(c.Request().Request)
+		send := func(msg interface{}) bool {
+			payload, err := json.Marshal(msg)
+			if err != nil {
+				log.Printf("Failed to encode WebSocket message: %v", err)
+				return false
+			}
+			return ws.WriteText(payload, wsWriteTimeout) == nil
+		}
+		totals := func() wsTotals {
+			home := rp.homeStats()
+			return wsTotals{
+				Type:         "totals",
+				QSOs:         home.totalQSOs,
+				Entities:     home.uniqueCountries,
+				QSOsText:     l.catalog.FormatInt(l.locale, home.totalQSOs),
+				EntitiesText: l.catalog.FormatInt(l.locale, home.uniqueCountries),
+			}
+		}
+
+		if !send(totals()) {
+			return
+		}
+		ticker := time.NewTicker(liveHeartbeat)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ws.Closed():
+				return
+			case <-ticker.C:
+				// Keeps proxies from closing an idle socket
+				if !send(totals()) {
+					return
+				}
+			case e := <-updates:
+				for _, qso := range e.QSOs {
+					msg := wsQSO{Type: "qso", QSO: newWidgetQSO(qso, cfg, baseURL), Date: l.Date(qso.Timestamp)}
+					msg.FlagURL = assets.flag(qso.GetFlagCode())
+					if !send(msg) {
+						return
+					}
+				}
+				if !send(totals()) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/flamego/flamego"
+
+	"github.com/humaidq/humaid-qsl/locales"
+	"github.com/humaidq/humaid-qsl/utils"
+)
+
+// TestWebSocketThroughRouter upgrades /ws through the same compression
+// handler and flamego router the server uses
+func TestWebSocketThroughRouter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.adi")
+	if err := os.WriteFile(path, []byte(followHeader+"<CALL:4>W1AW<QSO_DATE:8>20250301<TIME_ON:4>1200<EOR>\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rp, err := NewReloadableParser(path)
+	if err != nil {
+		t.Fatalf("NewReloadableParser: %v", err)
+	}
+	catalog, err := utils.LoadCatalog(locales.Locales)
+	if err != nil {
+		t.Fatalf("LoadCatalog failed: %v", err)
+	}
+	assets, err := newAssetManifest(fstest.MapFS{"main.css": {Data: []byte("body{}")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := flamego.New()
+	f.Map(&siteConfig{})
+	f.Map(&localizer{catalog: catalog, locale: "en", units: unitsMetric})
+	f.Get("/ws", newWebSocketHandler(rp, utils.NewEventBus(), assets))
+	srv := httptest.NewServer(newCompressHandler(f, defaultCompressTypes))
+	defer srv.Close()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	io.WriteString(conn, "GET /ws HTTP/1.1\r\nHost: example\r\nAccept-Encoding: gzip, deflate\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n")
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatalf("Failed to read handshake: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("Expected the upgrade to succeed, got %d %v", resp.StatusCode, resp.Header)
+	}
+
+	// The first message is the totals, short enough for a one byte length
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		t.Fatalf("Failed to read message: %v", err)
+	}
+	if header[0] != 0x81 || header[1] >= 126 {
+		t.Fatalf("Unexpected frame header %x", header)
+	}
+	payload := make([]byte, header[1])
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		t.Fatalf("Failed to read message: %v", err)
+	}
+	var totals wsTotals
+	if err := json.Unmarshal(payload, &totals); err != nil {
+		t.Fatalf("Failed to decode %q: %v", payload, err)
+	}
+	if totals.Type != "totals" || totals.QSOs != 1 {
+		t.Errorf("Unexpected totals %+v", totals)
+	}
+}
@@ -9,7 +9,11 @@ pkgs.buildGoModule {
   src = ./.;
 
   # The vendor hash for Go dependencies
-  vendorHash = "sha256-BqtGo30avnbbTpDpf/cSPTTf7gX/PBgNfD9zqyAqAIw=";
+  vendorHash = "sha256-x0wiW0LgBTnsr/M7i61i9DjD1EBHRMAVxv1ENcmrsDk=";
+
+  # go-sqlite3 compiles its bundled SQLite with cgo, using the C compiler
+  # stdenv already provides
+  env.CGO_ENABLED = 1;
 
   # Build from the src directory
   subPackages = [ "." ];
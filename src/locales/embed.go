@@ -0,0 +1,12 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package locales
+
+import "embed"
+
+// Locales holds a message catalog per language, named <locale>.json
+//
+//go:embed *.json
+var Locales embed.FS
@@ -20,6 +20,11 @@ func main() {
 		Usage: "Humaid's QSL site",
 		Commands: []*cli.Command{
 			cmd.CmdStart,
+			cmd.CmdHashPassword,
+			cmd.CmdDupes,
+			cmd.CmdCertificate,
+			cmd.CmdLoTWImport,
+			cmd.CmdSelfTest,
 		},
 	}
 
@@ -0,0 +1,251 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// addressBookData is authenticated along with the encrypted address book, so
+// another file encrypted with the same key can't be swapped in
+var addressBookData = []byte("humaid-qsl address book")
+
+// maxLabelLines is how many address lines a mailing label has
+const maxLabelLines = 5
+
+// AddressBookEntry is the postal address of a station I send direct cards to
+type AddressBookEntry struct {
+	Call    string    `json:"call"`
+	Name    string    `json:"name,omitempty"`
+	Address string    `json:"address"`
+	Note    string    `json:"note,omitempty"`
+	Updated time.Time `json:"updated"`
+}
+
+// encryptedFile is the on-disk form of the address book
+type encryptedFile struct {
+	Nonce []byte `json:"nonce"`
+	Data  []byte `json:"data"`
+}
+
+// AddressBook keeps postal addresses in a file encrypted with AES-GCM, so
+// backups and copies of the data directory don't expose them without the
+// key
+type AddressBook struct {
+	path    string
+	aead    cipher.AEAD
+	mutex   sync.RWMutex
+	entries map[string]AddressBookEntry // By upper case callsign
+}
+
+// NewAddressBook opens the address book at path with a 32 byte key,
+// starting empty if the file doesn't exist yet
+func NewAddressBook(path string, key []byte) (*AddressBook, error) {
+	aead, err := newAddressBookAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	b := &AddressBook{path: path, aead: aead, entries: make(map[string]AddressBookEntry)}
+
+	var file encryptedFile
+	if err := loadJSONFile(path, &file); err != nil {
+		return nil, err
+	}
+	if file.Data == nil {
+		return b, nil
+	}
+	if len(file.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("failed to decrypt %s: invalid nonce", path)
+	}
+	plain, err := aead.Open(nil, file.Nonce, file.Data, addressBookData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s, is the key right? %w", path, err)
+	}
+	if err := json.Unmarshal(plain, &b.entries); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return b, nil
+}
+
+// newAddressBookAEAD returns the AES-GCM cipher for a 32 byte address book
+// key
+func newAddressBookAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address book key: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address book key: %w", err)
+	}
+	return aead, nil
+}
+
+// Get returns the entry for a callsign
+func (b *AddressBook) Get(call string) (AddressBookEntry, bool) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	entry, ok := b.entries[strings.ToUpper(strings.TrimSpace(call))]
+	return entry, ok
+}
+
+// List returns all entries sorted by callsign
+func (b *AddressBook) List() []AddressBookEntry {
+	b.mutex.RLock()
+	entries := make([]AddressBookEntry, 0, len(b.entries))
+	for _, entry := range b.entries {
+		entries = append(entries, entry)
+	}
+	b.mutex.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Call < entries[j].Call
+	})
+	return entries
+}
+
+// Set adds or replaces the entry for a callsign. An entry without an
+// address removes it.
+func (b *AddressBook) Set(entry AddressBookEntry) error {
+	entry.Call = strings.ToUpper(strings.TrimSpace(entry.Call))
+	if entry.Call == "" {
+		return errors.New("address book entry has no callsign")
+	}
+	entry.Updated = time.Now().UTC()
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	previous, existed := b.entries[entry.Call]
+	if strings.TrimSpace(entry.Address) == "" {
+		delete(b.entries, entry.Call)
+	} else {
+		b.entries[entry.Call] = entry
+	}
+	if err := b.save(); err != nil {
+		if existed {
+			b.entries[entry.Call] = previous
+		} else {
+			delete(b.entries, entry.Call)
+		}
+		return err
+	}
+	return nil
+}
+
+// save encrypts the entries with a fresh nonce and writes them to disk. The
+// caller must hold the lock.
+func (b *AddressBook) save() error {
+	plain, err := json.Marshal(b.entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode address book: %w", err)
+	}
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	file := encryptedFile{Nonce: nonce, Data: b.aead.Seal(nil, nonce, plain, addressBookData)}
+	return saveJSONFile(b.path, file, 0600)
+}
+
+// MailingLabel is an envelope label for direct cards to a station
+type MailingLabel struct {
+	Call  string
+	Name  string
+	Lines []string // Address lines
+	Cards int      // Cards going in the envelope
+}
+
+// MailingLabels returns a label for each station with direct cards to send,
+// using the address given with the request or else the address book, sorted
+// by callsign. Cards requested through the bureau and stations without a
+// known address are left out.
+func MailingLabels(queue []OutgoingQSL, book *AddressBook) []MailingLabel {
+	labels := make(map[string]*MailingLabel)
+	for _, card := range queue {
+		if card.Request != nil && card.Request.Route == QSLRouteBureau {
+			continue
+		}
+		call := strings.ToUpper(card.QSO.Call)
+		if label, ok := labels[call]; ok {
+			label.Cards++
+			continue
+		}
+
+		var name, address string
+		if card.Request != nil && card.Request.Address != "" {
+			name, address = card.Request.Name, card.Request.Address
+		} else if book != nil {
+			if entry, ok := book.Get(call); ok {
+				name, address = entry.Name, entry.Address
+			}
+		}
+		if strings.TrimSpace(address) == "" {
+			continue
+		}
+		labels[call] = &MailingLabel{Call: call, Name: name, Lines: addressLines(address), Cards: 1}
+	}
+
+	sorted := make([]MailingLabel, 0, len(labels))
+	for _, label := range labels {
+		sorted = append(sorted, *label)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Call < sorted[j].Call
+	})
+	return sorted
+}
+
+// addressLines splits a postal address into its non-empty lines, joining
+// any beyond what fits on a label onto the last line
+func addressLines(address string) []string {
+	var lines []string
+	for _, line := range strings.Split(address, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) > maxLabelLines {
+		lines = append(lines[:maxLabelLines-1], strings.Join(lines[maxLabelLines-1:], ", "))
+	}
+	return lines
+}
+
+// WriteLabelsCSV writes mailing labels as CSV for a word processor's mail
+// merge, with a column for each address line
+func WriteLabelsCSV(w io.Writer, labels []MailingLabel) error {
+	cw := csv.NewWriter(w)
+	header := []string{"call", "name"}
+	for i := 1; i <= maxLabelLines; i++ {
+		header = append(header, "line"+strconv.Itoa(i))
+	}
+	cw.Write(append(header, "cards"))
+
+	for _, label := range labels {
+		row := []string{label.Call, label.Name}
+		for i := 0; i < maxLabelLines; i++ {
+			line := ""
+			if i < len(label.Lines) {
+				line = label.Lines[i]
+			}
+			row = append(row, line)
+		}
+		cw.Write(append(row, strconv.Itoa(label.Cards)))
+	}
+	cw.Flush()
+	return cw.Error()
+}
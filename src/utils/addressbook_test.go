@@ -0,0 +1,89 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAddressBook(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "addressbook.json")
+	key := bytes.Repeat([]byte{7}, 32)
+	book, err := NewAddressBook(path, key)
+	if err != nil {
+		t.Fatalf("Failed to create address book: %v", err)
+	}
+
+	if err := book.Set(AddressBookEntry{Call: "a61xx", Name: "Ali", Address: "PO Box 1\nAbu Dhabi"}); err != nil {
+		t.Fatalf("Failed to save entry: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read address book: %v", err)
+	}
+	if strings.Contains(string(data), "Abu Dhabi") || strings.Contains(string(data), "A61XX") {
+		t.Error("Expected the address book to be encrypted")
+	}
+
+	reloaded, err := NewAddressBook(path, key)
+	if err != nil {
+		t.Fatalf("Failed to reopen address book: %v", err)
+	}
+	if entry, ok := reloaded.Get("A61XX"); !ok || entry.Name != "Ali" {
+		t.Errorf("Expected entry to persist, got %+v", entry)
+	}
+
+	if _, err := NewAddressBook(path, bytes.Repeat([]byte{8}, 32)); err == nil {
+		t.Error("Expected opening with the wrong key to fail")
+	}
+
+	if err := reloaded.Set(AddressBookEntry{Call: "A61XX"}); err != nil {
+		t.Fatalf("Failed to remove entry: %v", err)
+	}
+	if len(reloaded.List()) != 0 {
+		t.Error("Expected entry without an address to be removed")
+	}
+}
+
+func TestMailingLabels(t *testing.T) {
+	book, err := NewAddressBook(filepath.Join(t.TempDir(), "addressbook.json"), bytes.Repeat([]byte{7}, 32))
+	if err != nil {
+		t.Fatalf("Failed to create address book: %v", err)
+	}
+	if err := book.Set(AddressBookEntry{Call: "W1AW", Name: "ARRL", Address: "225 Main St\nNewington CT 06111"}); err != nil {
+		t.Fatalf("Failed to save entry: %v", err)
+	}
+
+	day := time.Date(2025, 3, 1, 18, 30, 0, 0, time.UTC)
+	queue := []OutgoingQSL{
+		{QSO: QSO{Call: "W1AW", Timestamp: day}},
+		{QSO: QSO{Call: "W1AW", Timestamp: day.Add(time.Hour)}},
+		{QSO: QSO{Call: "JA1ABC", Timestamp: day}, Request: &QSLRequest{Route: QSLRouteDirect, Name: "Taro", Address: "1-2-3 Chiyoda\nTokyo"}},
+		{QSO: QSO{Call: "G4ABC", Timestamp: day}, Request: &QSLRequest{Route: QSLRouteBureau}},
+		{QSO: QSO{Call: "DL1ABC", Timestamp: day}},
+	}
+
+	labels := MailingLabels(queue, book)
+	if len(labels) != 2 || labels[0].Call != "JA1ABC" || labels[1].Call != "W1AW" {
+		t.Fatalf("Unexpected labels %+v", labels)
+	}
+	if labels[1].Cards != 2 || len(labels[1].Lines) != 2 || labels[0].Name != "Taro" {
+		t.Errorf("Unexpected label %+v", labels[1])
+	}
+
+	var buf bytes.Buffer
+	if err := WriteLabelsCSV(&buf, labels); err != nil {
+		t.Fatalf("Failed to write labels: %v", err)
+	}
+	if !strings.Contains(buf.String(), "W1AW,ARRL,225 Main St,Newington CT 06111,,,,2") {
+		t.Errorf("Unexpected CSV:\n%s", buf.String())
+	}
+}
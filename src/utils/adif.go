@@ -7,10 +7,11 @@ package utils
 import (
 	"fmt"
 	"io"
-	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 type QslStatus string
@@ -20,9 +21,49 @@ const (
 	QslNo        QslStatus = "N" // No
 	QslRequested QslStatus = "R" // Requested
 	QslInvalid   QslStatus = "I" // Invalid/Ignore
+	QslVerified  QslStatus = "V" // Verified (received and checked)
+	QslQueued    QslStatus = "Q" // Queued to be sent
 	QslEmpty     QslStatus = ""  // Empty/Unknown
 )
 
+// ParseQslStatus parses an ADIF QSL status value case-insensitively.
+// Unrecognised values are treated as empty.
+func ParseQslStatus(value string) QslStatus {
+	s := QslStatus(strings.ToUpper(strings.TrimSpace(value)))
+	for _, known := range []QslStatus{QslYes, QslNo, QslRequested, QslInvalid, QslVerified, QslQueued} {
+		if s == known {
+			// The constant, so statuses don't hold on to the parsed text
+			return known
+		}
+	}
+	return QslEmpty
+}
+
+// Confirmed reports whether the status records a completed sent or received
+// QSL, counting the deprecated V (verified) value as well as Y
+func (s QslStatus) Confirmed() bool {
+	return s == QslYes || s == QslVerified
+}
+
+// Label returns a human-readable name for the QSL status
+func (s QslStatus) Label() string {
+	switch s {
+	case QslYes:
+		return "Yes"
+	case QslNo:
+		return "No"
+	case QslRequested:
+		return "Requested"
+	case QslInvalid:
+		return "Ignored"
+	case QslVerified:
+		return "Verified"
+	case QslQueued:
+		return "Queued"
+	}
+	return "-"
+}
+
 type QSO struct {
 	Call         string
 	QSODate      string // YYYYMMDD format
@@ -31,6 +72,7 @@ type QSO struct {
 	TimeOff      string // HHMMSS format (optional)
 	Band         string
 	Mode         string
+	Submode      string // e.g. FT4 for MODE MFSK
 	Freq         string
 	RSTSent      string
 	RSTRcvd      string
@@ -38,24 +80,46 @@ type QSO struct {
 	Name         string
 	Comment      string
 	GridSquare   string
+	GridEnriched bool // GridSquare was filled in from a callbook, not logged
 	Country      string
 	DXCC         string
+	State        string // US state or Canadian province
+	CQZone       string
+	VUCCGrids    string // Comma-separated grids for rovers and grid line QSOs
 	MyGridSquare string
 	StationCall  string
 	MyRig        string
 	MyAntenna    string
 	TxPwr        string
+	PropMode     string // Propagation mode, e.g. SAT or EME
+	SatName      string
+	SatMode      string // Satellite uplink and downlink bands, e.g. U/V
+	ContestID    string
+	SRX          string // Received contest serial number
+	STX          string // Sent contest serial number
+	SRXString    string // Received contest exchange
+	STXString    string // Sent contest exchange
 	QslSent      QslStatus
 	QslRcvd      QslStatus
+	QslSentDate  string // YYYYMMDD format (optional)
+	QslRcvdDate  string // YYYYMMDD format (optional)
 	LotwSent     QslStatus
 	LotwRcvd     QslStatus
+	LotwRcvdDate string // YYYYMMDD format (optional)
 	EqslSent     QslStatus
 	EqslRcvd     QslStatus
+	EqslAG       bool      // eQSL confirmation is Authenticity Guaranteed
 	Timestamp    time.Time // Parsed datetime for easier searching
+	Note         string    // Public note added through a correction
+	Corrected    bool      // Whether a correction was applied
 }
 
 type ADIFParser struct {
-	QSOs []QSO
+	QSOs     []QSO
+	Warnings []string // Problems with records found while parsing
+
+	// Reloader rereads the log this parser was published from, if set
+	Reloader func() error
 }
 
 func NewADIFParser() *ADIFParser {
@@ -64,6 +128,15 @@ func NewADIFParser() *ADIFParser {
 	}
 }
 
+// Reload rereads the log through the Reloader. Without one there is nothing
+// to reread.
+func (p *ADIFParser) Reload() error {
+	if p.Reloader == nil {
+		return nil
+	}
+	return p.Reloader()
+}
+
 func (p *ADIFParser) ParseFile(reader io.Reader) error {
 	content, err := io.ReadAll(reader)
 	if err != nil {
@@ -75,25 +148,49 @@ func (p *ADIFParser) ParseFile(reader io.Reader) error {
 
 func (p *ADIFParser) parseContent(content string) error {
 	// Remove header if present (everything before <EOH>)
-	eohIndex := strings.Index(strings.ToUpper(content), "<EOH>")
-	if eohIndex != -1 {
-		content = content[eohIndex+5:]
+	if _, end := findEndTag(content, "eoh"); end != -1 {
+		content = content[end:]
+	}
+
+	// Size the QSO slice up front, as growing it means copying every QSO
+	if count := countTagFold(content, "<eor>") + 1; cap(p.QSOs)-len(p.QSOs) < count {
+		qsos := make([]QSO, len(p.QSOs), len(p.QSOs)+count)
+		copy(qsos, p.QSOs)
+		p.QSOs = qsos
 	}
 
-	// Split into records using <eor> delimiter (case insensitive)
-	records := regexp.MustCompile(`(?i)<eor>`).Split(content, -1)
+	// Field values are slices of content, so every stored value is either
+	// interned or copied to let the file contents be freed after parsing
+	pool := make(stringPool)
+	var fields []adifField
+
+	n := 0
+	for len(content) > 0 {
+		// Split into records at their <EOR> tags
+		var record string
+		if start, end := findEndTag(content, "eor"); start != -1 {
+			record, content = content[:start], content[end:]
+		} else {
+			record, content = content, ""
+		}
 
-	for _, record := range records {
 		record = strings.TrimSpace(record)
 		if record == "" {
 			continue
 		}
+		n++
 
-		qso, err := p.parseRecord(record)
+		fields = appendFields(fields[:0], record, pool)
+		qso, err := p.parseRecord(fields, pool)
 		if err != nil {
 			// Skip malformed records but continue parsing
+			p.Warnings = append(p.Warnings, fmt.Sprintf("record %d skipped: %v", n, err))
 			continue
 		}
+		if qso.Timestamp.IsZero() {
+			p.Warnings = append(p.Warnings, fmt.Sprintf("record %d (%s): no valid QSO date and time (QSO_DATE %q, TIME_ON %q, TIME_OFF %q)",
+				n, qso.Call, qso.QSODate, qso.TimeOn, qso.TimeOff))
+		}
 
 		p.QSOs = append(p.QSOs, qso)
 	}
@@ -101,33 +198,195 @@ func (p *ADIFParser) parseContent(content string) error {
 	return nil
 }
 
-func (p *ADIFParser) parseRecord(record string) (QSO, error) {
-	qso := QSO{}
-
-	// Regex to match ADIF fields: <FIELDNAME:LENGTH>DATA
-	fieldRegex := regexp.MustCompile(`<([^:>]+):(\d+)>([^<]*)`)
-	matches := fieldRegex.FindAllStringSubmatch(record, -1)
+// indexTagFold returns the index of the first occurrence of tag in s,
+// ignoring ASCII case, or -1. tag must start with '<'.
+func indexTagFold(s, tag string) int {
+	for i := 0; ; i++ {
+		j := strings.IndexByte(s[i:], '<')
+		if j == -1 {
+			return -1
+		}
+		i += j
+		if len(s)-i >= len(tag) && strings.EqualFold(s[i:i+len(tag)], tag) {
+			return i
+		}
+	}
+}
 
-	for _, match := range matches {
-		if len(match) < 4 {
+// nextTag returns the bounds of the first tag in s at or after i, from its
+// '<' to just after its '>'. A '<' before the closing '>' starts the tag
+// again, so a stray '<' in text doesn't swallow the tag after it.
+func nextTag(s string, i int) (open, end int, ok bool) {
+	for {
+		j := strings.IndexByte(s[i:], '<')
+		if j == -1 {
+			return 0, 0, false
+		}
+		open = i + j
+		k := strings.IndexAny(s[open+1:], "<>")
+		if k == -1 {
+			return 0, 0, false
+		}
+		if s[open+1+k] == '<' {
+			i = open + 1 + k
 			continue
 		}
+		return open, open + k + 2, true
+	}
+}
 
-		fieldName := strings.ToLower(strings.TrimSpace(match[1]))
-		lengthStr := match[2]
-		data := match[3]
+// fieldLength returns the declared data length of a tag such as
+// <NAME:LENGTH> or <NAME:LENGTH:TYPE>, and false for tags without data such
+// as <EOR>
+func fieldLength(tag string) (name string, length int, ok bool) {
+	name, spec, ok := strings.Cut(tag, ":")
+	if !ok {
+		return "", 0, false
+	}
+	lengthStr, _, _ := strings.Cut(spec, ":")
+	length, err := strconv.Atoi(lengthStr)
+	if err != nil || length < 0 {
+		return "", 0, false
+	}
+	return name, length, true
+}
 
-		// Parse field length and extract the correct amount of data
-		length, err := strconv.Atoi(lengthStr)
-		if err != nil {
-			continue
+// findEndTag returns the bounds of the first <EOR> or <EOH> tag in s, as
+// given by name, or -1, -1. Field data is skipped by its declared length, so
+// a value containing the tag, e.g. in a comment, doesn't end the record.
+func findEndTag(s, name string) (start, end int) {
+	i := 0
+	for {
+		open, tagEnd, ok := nextTag(s, i)
+		if !ok {
+			return -1, -1
+		}
+		tag := s[open+1 : tagEnd-1]
+		i = tagEnd
+		if _, length, ok := fieldLength(tag); ok {
+			i = min(i+length, len(s))
+		} else if strings.EqualFold(strings.TrimSpace(tag), name) {
+			return open, tagEnd
 		}
+	}
+}
+
+// countTagFold counts the occurrences of tag in s, ignoring ASCII case
+func countTagFold(s, tag string) int {
+	count := 0
+	for {
+		i := indexTagFold(s, tag)
+		if i == -1 {
+			return count
+		}
+		count++
+		s = s[i+len(tag):]
+	}
+}
+
+// adifField is a single field of an ADIF record
+type adifField struct {
+	name  string // Lower case
+	value string
+}
+
+// parseFields extracts the fields of a record. ADIF field lengths are byte
+// counts, so data is taken by length from the raw record rather than up to the
+// next tag, which keeps values containing '<' or multi-byte characters intact.
+func parseFields(record string) []adifField {
+	return appendFields(nil, record, make(stringPool))
+}
+
+// appendFields appends the fields of a record to fields, taking lower case
+// field names from pool so they aren't allocated for every record
+func appendFields(fields []adifField, record string, pool stringPool) []adifField {
+	var nameBuf [64]byte
 
-		if len(data) < length {
+	i := 0
+	for {
+		open, end, ok := nextTag(record, i)
+		if !ok {
+			break
+		}
+
+		name, length, ok := fieldLength(record[open+1 : end-1])
+		i = end
+		if !ok {
 			continue
 		}
 
-		fieldValue := strings.TrimSpace(data[:length])
+		dataEnd := i + length
+		if dataEnd > len(record) {
+			// Trailing whitespace of the record may have been trimmed
+			dataEnd = len(record)
+		}
+		value := decodeFieldValue(record[i:dataEnd])
+		i = dataEnd
+
+		fields = append(fields, adifField{
+			name:  pool.internLower(strings.TrimSpace(name), nameBuf[:0]),
+			value: strings.TrimSpace(value),
+		})
+	}
+
+	return fields
+}
+
+// decodeFieldValue returns field data as UTF-8. Data that isn't valid UTF-8
+// is taken to be Latin-1, which older loggers write, so accented names keep
+// their accents.
+func decodeFieldValue(data string) string {
+	if utf8.ValidString(data) {
+		return data
+	}
+	runes := make([]rune, len(data))
+	for i := 0; i < len(data); i++ {
+		runes[i] = rune(data[i])
+	}
+	return string(runes)
+}
+
+// internedFields are fields whose values repeat across many QSOs
+var internedFields = map[string]bool{
+	"qso_date":         true,
+	"qso_date_off":     true,
+	"band":             true,
+	"mode":             true,
+	"submode":          true,
+	"freq":             true,
+	"rst_sent":         true,
+	"rst_rcvd":         true,
+	"gridsquare":       true,
+	"country":          true,
+	"dxcc":             true,
+	"state":            true,
+	"cqz":              true,
+	"my_gridsquare":    true,
+	"station_callsign": true,
+	"my_rig":           true,
+	"my_antenna":       true,
+	"tx_pwr":           true,
+	"prop_mode":        true,
+	"sat_name":         true,
+	"sat_mode":         true,
+	"contest_id":       true,
+	"stx_string":       true,
+	"qslsdate":         true,
+	"qslrdate":         true,
+	"lotw_qslrdate":    true,
+}
+
+func (p *ADIFParser) parseRecord(fields []adifField, pool stringPool) (QSO, error) {
+	qso := QSO{}
+
+	for _, field := range fields {
+		fieldName := field.name
+		fieldValue := field.value
+		if internedFields[fieldName] {
+			fieldValue = pool.intern(fieldValue)
+		} else {
+			fieldValue = strings.Clone(fieldValue)
+		}
 
 		// Map fields to QSO struct
 		switch fieldName {
@@ -145,6 +404,8 @@ func (p *ADIFParser) parseRecord(record string) (QSO, error) {
 			qso.Band = fieldValue
 		case "mode":
 			qso.Mode = fieldValue
+		case "submode":
+			qso.Submode = fieldValue
 		case "freq":
 			qso.Freq = fieldValue
 		case "rst_sent":
@@ -163,6 +424,12 @@ func (p *ADIFParser) parseRecord(record string) (QSO, error) {
 			qso.Country = fieldValue
 		case "dxcc":
 			qso.DXCC = fieldValue
+		case "state":
+			qso.State = pool.intern(strings.ToUpper(fieldValue))
+		case "cqz":
+			qso.CQZone = fieldValue
+		case "vucc_grids":
+			qso.VUCCGrids = strings.ToUpper(fieldValue)
 		case "my_gridsquare":
 			qso.MyGridSquare = fieldValue
 		case "station_callsign":
@@ -173,18 +440,42 @@ func (p *ADIFParser) parseRecord(record string) (QSO, error) {
 			qso.MyAntenna = fieldValue
 		case "tx_pwr":
 			qso.TxPwr = fieldValue
+		case "prop_mode":
+			qso.PropMode = strings.ToUpper(fieldValue)
+		case "sat_name":
+			qso.SatName = strings.ToUpper(fieldValue)
+		case "sat_mode":
+			qso.SatMode = strings.ToUpper(fieldValue)
+		case "contest_id":
+			qso.ContestID = strings.ToUpper(fieldValue)
+		case "srx":
+			qso.SRX = fieldValue
+		case "stx":
+			qso.STX = fieldValue
+		case "srx_string":
+			qso.SRXString = fieldValue
+		case "stx_string":
+			qso.STXString = fieldValue
 		case "qsl_sent":
-			qso.QslSent = QslStatus(fieldValue)
+			qso.QslSent = ParseQslStatus(fieldValue)
 		case "qsl_rcvd":
-			qso.QslRcvd = QslStatus(fieldValue)
+			qso.QslRcvd = ParseQslStatus(fieldValue)
+		case "qslsdate":
+			qso.QslSentDate = fieldValue
+		case "qslrdate":
+			qso.QslRcvdDate = fieldValue
+		case "lotw_qslrdate":
+			qso.LotwRcvdDate = fieldValue
 		case "lotw_qsl_sent":
-			qso.LotwSent = QslStatus(fieldValue)
+			qso.LotwSent = ParseQslStatus(fieldValue)
 		case "lotw_qsl_rcvd":
-			qso.LotwRcvd = QslStatus(fieldValue)
+			qso.LotwRcvd = ParseQslStatus(fieldValue)
 		case "eqsl_qsl_sent":
-			qso.EqslSent = QslStatus(fieldValue)
+			qso.EqslSent = ParseQslStatus(fieldValue)
 		case "eqsl_qsl_rcvd":
-			qso.EqslRcvd = QslStatus(fieldValue)
+			qso.EqslRcvd = ParseQslStatus(fieldValue)
+		case "app_eqsl_ag":
+			qso.EqslAG = ParseQslStatus(fieldValue) == QslYes
 		}
 	}
 
@@ -194,6 +485,16 @@ func (p *ADIFParser) parseRecord(record string) (QSO, error) {
 		if err == nil {
 			qso.Timestamp = timestamp
 		}
+	} else if qso.TimeOff != "" {
+		// Some loggers only record when the QSO ended
+		date := qso.QSODateOff
+		if date == "" {
+			date = qso.QSODate
+		}
+		timestamp, err := p.parseTimestamp(date, qso.TimeOff)
+		if err == nil {
+			qso.Timestamp = timestamp
+		}
 	}
 
 	// Validate required fields
@@ -206,9 +507,9 @@ func (p *ADIFParser) parseRecord(record string) (QSO, error) {
 
 func (p *ADIFParser) parseTimestamp(date, timeOn string) (time.Time, error) {
 	// ADIF date format: YYYYMMDD
-	// ADIF time format: HHMMSS
+	// ADIF time format: HHMMSS or HHMM
 
-	if len(date) != 8 || len(timeOn) != 6 {
+	if len(date) != 8 || (len(timeOn) != 6 && len(timeOn) != 4) {
 		return time.Time{}, fmt.Errorf("invalid date/time format")
 	}
 
@@ -233,9 +534,12 @@ func (p *ADIFParser) parseTimestamp(date, timeOn string) (time.Time, error) {
 	if err != nil {
 		return time.Time{}, err
 	}
-	second, err := strconv.Atoi(timeOn[4:6])
-	if err != nil {
-		return time.Time{}, err
+	second := 0
+	if len(timeOn) == 6 {
+		second, err = strconv.Atoi(timeOn[4:6])
+		if err != nil {
+			return time.Time{}, err
+		}
 	}
 
 	return time.Date(year, time.Month(month), day, hour, minute, second, 0, time.UTC), nil
@@ -299,40 +603,50 @@ func (p *ADIFParser) GetTotalQSOCount() int {
 	return len(p.QSOs)
 }
 
-// GetUniqueCountriesCount returns the number of unique countries worked
+// GetUniqueCountriesCount returns the number of unique DXCC entities worked,
+// normalising country names so different spellings count once
 func (p *ADIFParser) GetUniqueCountriesCount() int {
 	countries := make(map[string]bool)
 	for _, qso := range p.QSOs {
-		if qso.Country != "" {
-			countries[qso.Country] = true
+		if entity := qso.Entity(); entity != "" {
+			countries[entity] = true
 		}
 	}
 	return len(countries)
 }
 
-// GetLatestQSOs returns the most recent QSOs, sorted by timestamp
+// GetLatestQSOs returns the most recent QSOs, sorted by timestamp. QSOs
+// with the same timestamp keep their log order.
 func (p *ADIFParser) GetLatestQSOs(limit int) []QSO {
-	if len(p.QSOs) == 0 {
+	if len(p.QSOs) == 0 || limit <= 0 {
 		return []QSO{}
 	}
 
-	// Create a copy and sort by timestamp (newest first)
-	qsos := make([]QSO, len(p.QSOs))
-	copy(qsos, p.QSOs)
+	// Keep the newest QSOs seen so far in order, which avoids sorting the
+	// whole log for a short list
+	latest := make([]int, 0, limit+1)
+	for i := range p.QSOs {
+		ts := p.QSOs[i].Timestamp
+		if len(latest) == limit && !ts.After(p.QSOs[latest[limit-1]].Timestamp) {
+			continue
+		}
 
-	// Simple bubble sort by timestamp (newest first)
-	for i := 0; i < len(qsos)-1; i++ {
-		for j := 0; j < len(qsos)-i-1; j++ {
-			if qsos[j].Timestamp.Before(qsos[j+1].Timestamp) {
-				qsos[j], qsos[j+1] = qsos[j+1], qsos[j]
-			}
+		pos := sort.Search(len(latest), func(j int) bool {
+			return p.QSOs[latest[j]].Timestamp.Before(ts)
+		})
+		latest = append(latest, 0)
+		copy(latest[pos+1:], latest[pos:])
+		latest[pos] = i
+		if len(latest) > limit {
+			latest = latest[:limit]
 		}
 	}
 
-	if len(qsos) < limit {
-		return qsos
+	qsos := make([]QSO, len(latest))
+	for i, idx := range latest {
+		qsos[i] = p.QSOs[idx]
 	}
-	return qsos[:limit]
+	return qsos
 }
 
 // GetQSOs returns all parsed QSOs
@@ -359,40 +673,50 @@ func (p *ADIFParser) GetLatestQSO() *QSO {
 	return latest
 }
 
-// GetPaperQSLHallOfFame returns deduplicated QSOs where paper QSL was received
+// GetPaperQSLHallOfFame returns QSOs where paper QSL was received,
+// deduplicated by callsign and DXCC entity so a station worked from several
+// entities is listed once per entity. The entity is resolved from the DXCC
+// code or the country name, so QSOs logged with either count once.
 func (p *ADIFParser) GetPaperQSLHallOfFame() []QSO {
-	seen := make(map[string]QSO)
-	
+	type entry struct {
+		qso    QSO
+		entity string
+	}
+	seen := make(map[string]entry)
+
 	for _, qso := range p.QSOs {
 		// Only include QSOs where paper QSL was received
-		if qso.QslRcvd == QslYes {
-			// Use callsign as the key for deduplication
-			if existing, exists := seen[qso.Call]; !exists {
-				seen[qso.Call] = qso
+		if qso.QslRcvd.Confirmed() {
+			entity := qso.Entity()
+			key := strings.ToUpper(qso.Call) + "|" + entity
+			if existing, exists := seen[key]; !exists {
+				seen[key] = entry{qso, entity}
 			} else {
-				// If we already have this callsign, prefer the one with a name
-				if qso.Name != "" && existing.Name == "" {
-					seen[qso.Call] = qso
+				// If we already have this station, prefer the one with a name
+				if qso.Name != "" && existing.qso.Name == "" {
+					seen[key] = entry{qso, entity}
 				}
 			}
 		}
 	}
-	
-	// Convert map to slice and sort by callsign
-	var result []QSO
-	for _, qso := range seen {
-		result = append(result, qso)
-	}
-	
-	// Simple bubble sort by callsign
-	for i := 0; i < len(result)-1; i++ {
-		for j := 0; j < len(result)-i-1; j++ {
-			if result[j].Call > result[j+1].Call {
-				result[j], result[j+1] = result[j+1], result[j]
-			}
+
+	// Convert map to slice and sort by callsign, then entity
+	entries := make([]entry, 0, len(seen))
+	for _, e := range seen {
+		entries = append(entries, e)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].qso.Call != entries[j].qso.Call {
+			return entries[i].qso.Call < entries[j].qso.Call
 		}
+		return entries[i].entity < entries[j].entity
+	})
+
+	var result []QSO
+	for _, e := range entries {
+		result = append(result, e.qso)
 	}
-	
 	return result
 }
 
@@ -417,103 +741,114 @@ func (qso QSO) FormatTime() string {
 	if len(qso.TimeOn) >= 4 {
 		return fmt.Sprintf("%s:%s", qso.TimeOn[0:2], qso.TimeOn[2:4])
 	}
+	if qso.TimeOn == "" && !qso.Timestamp.IsZero() {
+		// Timestamp came from TIME_OFF
+		return qso.Timestamp.UTC().Format("15:04")
+	}
 	return qso.TimeOn
 }
 
-// GetFlagCode returns the ISO 3166-1 alpha-2 country code for flagcdn.com
+// countryFlagCodes maps DXCC entity and country names to the ISO 3166-1
+// alpha-2 codes that name the flag images vendored from flag-icons under
+// static/flags
+var countryFlagCodes = map[string]string{
+	// From ADIF data analysis
+	"Albania":              "al",
+	"Armenia":              "am",
+	"Asiatic Russia":       "ru",
+	"Asiatic Turkey":       "tr",
+	"Australia":            "au",
+	"Austria":              "at",
+	"Bahrain":              "bh",
+	"Belarus":              "by",
+	"Belgium":              "be",
+	"Bosnia-Herzegovina":   "ba",
+	"Brazil":               "br",
+	"Brunei Darussalam":    "bn",
+	"Bulgaria":             "bg",
+	"Canary Islands":       "es", // Part of Spain
+	"Chile":                "cl",
+	"China":                "cn",
+	"Comoros":              "km",
+	"Crete":                "gr", // Part of Greece
+	"Croatia":              "hr",
+	"Cyprus":               "cy",
+	"Czech Republic":       "cz",
+	"Denmark":              "dk",
+	"Dodecanese":           "gr", // Part of Greece
+	"England":              "gb",
+	"Estonia":              "ee",
+	"European Russia":      "ru",
+	"Fed. Rep. of Germany": "de",
+	"Finland":              "fi",
+	"France":               "fr",
+	"Georgia":              "ge",
+	"Greece":               "gr",
+	"Hungary":              "hu",
+	"India":                "in",
+	"Indonesia":            "id",
+	"Iraq":                 "iq",
+	"Israel":               "il",
+	"Italy":                "it",
+	"Japan":                "jp",
+	"Jersey":               "je",
+	"Kazakhstan":           "kz",
+	"Kyrgyzstan":           "kg",
+	"Laos":                 "la",
+	"Latvia":               "lv",
+	"Lebanon":              "lb",
+	"Lithuania":            "lt",
+	"Madeira Islands":      "pt", // Part of Portugal
+	"Malawi":               "mw",
+	"Montenegro":           "me",
+	"Namibia":              "na",
+	"Netherlands":          "nl",
+	"Northern Ireland":     "gb",
+	"Norway":               "no",
+	"Pakistan":             "pk",
+	"Poland":               "pl",
+	"Portugal":             "pt",
+	"Puerto Rico":          "pr",
+	"Qatar":                "qa",
+	"Republic of Korea":    "kr",
+	"Romania":              "ro",
+	"Sardinia":             "it", // Part of Italy
+	"Saudi Arabia":         "sa",
+	"Scotland":             "gb",
+	"Serbia":               "rs",
+	"Singapore":            "sg",
+	"Slovak Republic":      "sk",
+	"Slovenia":             "si",
+	"South Africa":         "za",
+	"Spain":                "es",
+	"Sri Lanka":            "lk",
+	"Sweden":               "se",
+	"Switzerland":          "ch",
+	"Taiwan":               "tw",
+	"Thailand":             "th",
+	"Ukraine":              "ua",
+	"United Arab Emirates": "ae",
+	"United States":        "us",
+	"Uzbekistan":           "uz",
+	"Wales":                "gb",
+	"West Malaysia":        "my",
+
+	// Additional common mappings
+	"Germany":        "de",
+	"United Kingdom": "gb",
+	"Russia":         "ru",
+	"Turkey":         "tr",
+	"South Korea":    "kr",
+	"Malaysia":       "my",
+}
+
+// GetFlagCode returns the ISO 3166-1 alpha-2 country code, which names the
+// flag image vendored from flag-icons under static/flags
 func (qso QSO) GetFlagCode() string {
-	countryMap := map[string]string{
-		// From ADIF data analysis
-		"Albania":              "al",
-		"Armenia":              "am",
-		"Asiatic Russia":       "ru",
-		"Asiatic Turkey":       "tr",
-		"Australia":            "au",
-		"Austria":              "at",
-		"Bahrain":              "bh",
-		"Belarus":              "by",
-		"Belgium":              "be",
-		"Bosnia-Herzegovina":   "ba",
-		"Brazil":               "br",
-		"Brunei Darussalam":    "bn",
-		"Bulgaria":             "bg",
-		"Canary Islands":       "es", // Part of Spain
-		"Chile":                "cl",
-		"China":                "cn",
-		"Comoros":              "km",
-		"Crete":                "gr", // Part of Greece
-		"Croatia":              "hr",
-		"Cyprus":               "cy",
-		"Czech Republic":       "cz",
-		"Denmark":              "dk",
-		"Dodecanese":           "gr", // Part of Greece
-		"England":              "gb",
-		"Estonia":              "ee",
-		"European Russia":      "ru",
-		"Fed. Rep. of Germany": "de",
-		"Finland":              "fi",
-		"France":               "fr",
-		"Georgia":              "ge",
-		"Greece":               "gr",
-		"Hungary":              "hu",
-		"India":                "in",
-		"Indonesia":            "id",
-		"Iraq":                 "iq",
-		"Israel":               "il",
-		"Italy":                "it",
-		"Japan":                "jp",
-		"Jersey":               "je",
-		"Kazakhstan":           "kz",
-		"Kyrgyzstan":           "kg",
-		"Laos":                 "la",
-		"Latvia":               "lv",
-		"Lebanon":              "lb",
-		"Lithuania":            "lt",
-		"Madeira Islands":      "pt", // Part of Portugal
-		"Malawi":               "mw",
-		"Montenegro":           "me",
-		"Namibia":              "na",
-		"Netherlands":          "nl",
-		"Northern Ireland":     "gb",
-		"Norway":               "no",
-		"Pakistan":             "pk",
-		"Poland":               "pl",
-		"Portugal":             "pt",
-		"Puerto Rico":          "pr",
-		"Qatar":                "qa",
-		"Republic of Korea":    "kr",
-		"Romania":              "ro",
-		"Sardinia":             "it", // Part of Italy
-		"Saudi Arabia":         "sa",
-		"Scotland":             "gb",
-		"Serbia":               "rs",
-		"Singapore":            "sg",
-		"Slovak Republic":      "sk",
-		"Slovenia":             "si",
-		"South Africa":         "za",
-		"Spain":                "es",
-		"Sri Lanka":            "lk",
-		"Sweden":               "se",
-		"Switzerland":          "ch",
-		"Taiwan":               "tw",
-		"Thailand":             "th",
-		"Ukraine":              "ua",
-		"United Arab Emirates": "ae",
-		"United States":        "us",
-		"Uzbekistan":           "uz",
-		"Wales":                "gb",
-		"West Malaysia":        "my",
-
-		// Additional common mappings
-		"Germany":        "de",
-		"United Kingdom": "gb",
-		"Russia":         "ru",
-		"Turkey":         "tr",
-		"South Korea":    "kr",
-		"Malaysia":       "my",
-	}
-
-	if code, exists := countryMap[qso.Country]; exists {
+	if code, exists := countryFlagCodes[qso.Entity()]; exists {
+		return code
+	}
+	if code, exists := countryFlagCodes[qso.Country]; exists {
 		return code
 	}
 
@@ -0,0 +1,159 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// benchmarkQSOCount is the size of the synthetic log used by the benchmarks,
+// matching the largest logs the site is expected to serve
+const benchmarkQSOCount = 1_000_000
+
+var benchmarkBands = []struct{ band, freq string }{
+	{"160M", "1.840"}, {"80M", "3.573"}, {"40M", "7.074"}, {"20M", "14.074"},
+	{"15M", "21.074"}, {"10M", "28.074"}, {"6M", "50.313"},
+}
+
+var benchmarkCountries = []struct{ dxcc, country string }{
+	{"291", "United States"}, {"230", "Fed. Rep. of Germany"}, {"", "Germany"},
+	{"339", "Japan"}, {"391", "United Arab Emirates"}, {"223", "England"},
+}
+
+// syntheticADIF generates an ADIF log of n QSOs, one minute apart, in the
+// shape a contest logger exports
+func syntheticADIF(n int) string {
+	var b strings.Builder
+	b.Grow(n * 330)
+	b.WriteString("Synthetic benchmark log\n<ADIF_VER:5>3.1.4 <PROGRAMID:4>test <EOH>\n")
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < n; i++ {
+		ts := start.Add(time.Duration(i) * time.Minute)
+		band := benchmarkBands[i%len(benchmarkBands)]
+		country := benchmarkCountries[i%len(benchmarkCountries)]
+		call := fmt.Sprintf("K%dABC", i%50000)
+		fields := [][2]string{
+			{"CALL", call},
+			{"QSO_DATE", ts.Format("20060102")},
+			{"TIME_ON", ts.Format("150405")},
+			{"BAND", band.band},
+			{"FREQ", band.freq},
+			{"MODE", "FT8"},
+			{"RST_SENT", "-10"},
+			{"RST_RCVD", "-12"},
+			{"DXCC", country.dxcc},
+			{"COUNTRY", country.country},
+			{"GRIDSQUARE", "FN31"},
+			{"MY_GRIDSQUARE", "LL75RB"},
+			{"STATION_CALLSIGN", "A66H"},
+			{"MY_RIG", "IC-7300"},
+			{"TX_PWR", "100"},
+			{"QSL_RCVD", []string{"Y", "N", "R"}[i%3]},
+			{"LOTW_QSL_RCVD", "n"},
+		}
+		for _, f := range fields {
+			if f[1] != "" {
+				fmt.Fprintf(&b, "<%s:%d>%s ", f[0], len(f[1]), f[1])
+			}
+		}
+		b.WriteString("<EOR>\n")
+	}
+	return b.String()
+}
+
+var benchmarkLog struct {
+	content string
+	parser  *ADIFParser
+}
+
+// benchmarkParser returns a parser loaded with the synthetic log, generated
+// once and shared between benchmarks
+func benchmarkParser(b *testing.B) *ADIFParser {
+	b.Helper()
+	if benchmarkLog.parser == nil {
+		benchmarkLog.content = syntheticADIF(benchmarkQSOCount)
+		benchmarkLog.parser = NewADIFParser()
+		if err := benchmarkLog.parser.parseContent(benchmarkLog.content); err != nil {
+			b.Fatalf("Failed to parse synthetic log: %v", err)
+		}
+		if len(benchmarkLog.parser.QSOs) != benchmarkQSOCount {
+			b.Fatalf("Expected %d QSOs, got %d", benchmarkQSOCount, len(benchmarkLog.parser.QSOs))
+		}
+	}
+	b.ResetTimer()
+	return benchmarkLog.parser
+}
+
+func BenchmarkParseContent(b *testing.B) {
+	benchmarkParser(b)
+	content := benchmarkLog.content
+	b.SetBytes(int64(len(content)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		parser := NewADIFParser()
+		if err := parser.parseContent(content); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSearchQSO(b *testing.B) {
+	parser := benchmarkParser(b)
+	target := parser.QSOs[len(parser.QSOs)/2]
+
+	for i := 0; i < b.N; i++ {
+		if len(parser.SearchQSO(target.Call, target.Timestamp, 10)) != 1 {
+			b.Fatal("QSO not found")
+		}
+	}
+}
+
+func BenchmarkGetQSOsByCallsign(b *testing.B) {
+	parser := benchmarkParser(b)
+	for i := 0; i < b.N; i++ {
+		parser.GetQSOsByCallsign("K123ABC")
+	}
+}
+
+func BenchmarkGetUniqueCountriesCount(b *testing.B) {
+	parser := benchmarkParser(b)
+	for i := 0; i < b.N; i++ {
+		parser.GetUniqueCountriesCount()
+	}
+}
+
+func BenchmarkGetLatestQSOs(b *testing.B) {
+	parser := benchmarkParser(b)
+	for i := 0; i < b.N; i++ {
+		parser.GetLatestQSOs(30)
+	}
+}
+
+func BenchmarkGetPaperQSLHallOfFame(b *testing.B) {
+	parser := benchmarkParser(b)
+	for i := 0; i < b.N; i++ {
+		parser.GetPaperQSLHallOfFame()
+	}
+}
+
+func BenchmarkComputeAwards(b *testing.B) {
+	parser := benchmarkParser(b)
+	for i := 0; i < b.N; i++ {
+		ComputeAwards(parser.QSOs)
+	}
+}
+
+func BenchmarkCheckLog(b *testing.B) {
+	parser := benchmarkParser(b)
+	now := time.Now()
+	for i := 0; i < b.N; i++ {
+		CheckLog(parser.QSOs, now)
+	}
+}
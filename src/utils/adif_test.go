@@ -0,0 +1,327 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/humaidq/humaid-qsl/static"
+)
+
+func TestParseFileWarnings(t *testing.T) {
+	adif := `Test log<EOH>
+<CALL:5>A61XX<QSO_DATE:8>20250301<TIME_ON:6>183000<EOR>
+<CALL:4>W1AW<TIME_ON:6>120000<EOR>
+<CALL:5>K6XYZ<QSO_DATE:8>20250301<TIME_ON:3>999<EOR>
+`
+
+	parser := NewADIFParser()
+	if err := parser.ParseFile(strings.NewReader(adif)); err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	if len(parser.QSOs) != 2 {
+		t.Errorf("Expected 2 QSOs, got %d", len(parser.QSOs))
+	}
+	if len(parser.Warnings) != 2 {
+		t.Fatalf("Expected 2 warnings, got %q", parser.Warnings)
+	}
+	if !strings.HasPrefix(parser.Warnings[0], "record 2 skipped") {
+		t.Errorf("Expected record 2 to be skipped, got %q", parser.Warnings[0])
+	}
+	if !strings.Contains(parser.Warnings[1], "K6XYZ") {
+		t.Errorf("Expected warning about K6XYZ, got %q", parser.Warnings[1])
+	}
+}
+
+func TestParseFileUTF8(t *testing.T) {
+	// Lengths are byte counts, as the ADIF specification requires
+	adif := "Generated by a logger with ſ in the header <EOH>\n" +
+		"<CALL:5>HB9XX<QSO_DATE:8>20250301<TIME_ON:6>183000<NAME:13>José Müller<QTH:18>Zürich <Old Town><EOR>\n" +
+		"<CALL:5>A61XX<QSO_DATE:8>20250302<TIME_ON:6>090000<NAME:8>محمد<QTH:13>日本 東京<GRIDSQUARE:6>LL75RB<EOR>\n"
+
+	parser := NewADIFParser()
+	if err := parser.ParseFile(strings.NewReader(adif)); err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if len(parser.QSOs) != 2 {
+		t.Fatalf("Expected 2 QSOs, got %d (warnings %q)", len(parser.QSOs), parser.Warnings)
+	}
+
+	first := parser.QSOs[0]
+	if first.Name != "José Müller" || first.QTH != "Zürich <Old Town>" {
+		t.Errorf("Unexpected name/QTH %q / %q", first.Name, first.QTH)
+	}
+
+	second := parser.QSOs[1]
+	if second.Name != "محمد" || second.QTH != "日本 東京" || second.GridSquare != "LL75RB" {
+		t.Errorf("Unexpected fields %q / %q / %q", second.Name, second.QTH, second.GridSquare)
+	}
+}
+
+func TestParseTagsInsideValues(t *testing.T) {
+	adif := "Header with a <COMMENT:5><EOH> in it<EOH>\n" +
+		"<CALL:4>W1AW<QSO_DATE:8>20250301<TIME_ON:4>1200<COMMENT:19>Says <eor> a lot 73<EOR>\n" +
+		"<CALL:5>A61XX<QSO_DATE:8>20250301<TIME_ON:4>1300<EOR>\n"
+
+	parser := NewADIFParser()
+	if err := parser.ParseFile(strings.NewReader(adif)); err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if len(parser.QSOs) != 2 || len(parser.Warnings) != 0 {
+		t.Fatalf("Expected 2 QSOs without warnings, got %+v (warnings %q)", parser.QSOs, parser.Warnings)
+	}
+	if got := parser.QSOs[0].Comment; got != "Says <eor> a lot 73" {
+		t.Errorf("Expected the comment to keep its <eor>, got %q", got)
+	}
+	if got := parser.QSOs[1].Call; got != "A61XX" {
+		t.Errorf("Expected the next record to be A61XX, got %q", got)
+	}
+}
+
+func TestParseLatin1Values(t *testing.T) {
+	// "José Müller" as written by loggers using Latin-1
+	adif := "<CALL:5>HB9XX<QSO_DATE:8>20250301<TIME_ON:4>1200<NAME:11>Jos\xe9 M\xfcller<EOR>\n"
+
+	parser := NewADIFParser()
+	if err := parser.ParseFile(strings.NewReader(adif)); err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if len(parser.QSOs) != 1 {
+		t.Fatalf("Expected 1 QSO, got %d (warnings %q)", len(parser.QSOs), parser.Warnings)
+	}
+	if got := parser.QSOs[0].Name; got != "José Müller" {
+		t.Errorf("Expected the Latin-1 name to be decoded, got %q", got)
+	}
+}
+
+func TestParseFieldsTypeAndTruncation(t *testing.T) {
+	fields := parseFields("<CALL:4:S>W1AW<NAME:10>Bob")
+	if len(fields) != 2 {
+		t.Fatalf("Expected 2 fields, got %d", len(fields))
+	}
+	if fields[0].name != "call" || fields[0].value != "W1AW" {
+		t.Errorf("Unexpected typed field %+v", fields[0])
+	}
+	if fields[1].value != "Bob" {
+		t.Errorf("Expected trimmed trailing field to be kept, got %q", fields[1].value)
+	}
+}
+
+func TestParseTimeOffFallback(t *testing.T) {
+	adif := `<EOH>
+<CALL:5>A61XX<QSO_DATE:8>20250301<TIME_OFF:6>183500<EOR>
+<CALL:4>W1AW<QSO_DATE:8>20250301<QSO_DATE_OFF:8>20250302<TIME_OFF:6>000500<EOR>
+<CALL:5>K6XYZ<QSO_DATE:8>20250301<TIME_ON:6>120000<TIME_OFF:6>121000<EOR>
+`
+
+	parser := NewADIFParser()
+	if err := parser.ParseFile(strings.NewReader(adif)); err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	want := []time.Time{
+		time.Date(2025, 3, 1, 18, 35, 0, 0, time.UTC),
+		time.Date(2025, 3, 2, 0, 5, 0, 0, time.UTC),
+		time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+	for i, qso := range parser.QSOs {
+		if !qso.Timestamp.Equal(want[i]) {
+			t.Errorf("%s: expected timestamp %v, got %v", qso.Call, want[i], qso.Timestamp)
+		}
+	}
+	if len(parser.Warnings) != 0 {
+		t.Errorf("Expected no warnings, got %q", parser.Warnings)
+	}
+}
+
+func TestParseTimestampFormats(t *testing.T) {
+	parser := NewADIFParser()
+
+	tests := []struct {
+		date, time string
+		want       time.Time
+		ok         bool
+	}{
+		{"20250301", "183045", time.Date(2025, 3, 1, 18, 30, 45, 0, time.UTC), true},
+		{"20250301", "1830", time.Date(2025, 3, 1, 18, 30, 0, 0, time.UTC), true},
+		{"20250301", "0005", time.Date(2025, 3, 1, 0, 5, 0, 0, time.UTC), true},
+		{"20250301", "183", time.Time{}, false},
+		{"20250301", "18304", time.Time{}, false},
+		{"20250301", "18:30", time.Time{}, false},
+		{"2025031", "1830", time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		got, err := parser.parseTimestamp(tt.date, tt.time)
+		if (err == nil) != tt.ok {
+			t.Errorf("parseTimestamp(%q, %q) error = %v, want ok %v", tt.date, tt.time, err, tt.ok)
+			continue
+		}
+		if tt.ok && !got.Equal(tt.want) {
+			t.Errorf("parseTimestamp(%q, %q) = %v, want %v", tt.date, tt.time, got, tt.want)
+		}
+	}
+}
+
+func TestParseHHMMRecord(t *testing.T) {
+	adif := "<EOH><CALL:5>A61XX<QSO_DATE:8>20250301<TIME_ON:4>1830<EOR>"
+
+	parser := NewADIFParser()
+	if err := parser.ParseFile(strings.NewReader(adif)); err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if len(parser.QSOs) != 1 || parser.QSOs[0].Timestamp.IsZero() {
+		t.Fatalf("Expected HHMM record to get a timestamp, got %+v", parser.QSOs)
+	}
+	if got := parser.QSOs[0].FormatTime(); got != "18:30" {
+		t.Errorf("Expected 18:30, got %s", got)
+	}
+}
+
+func TestPaperQSLHallOfFameByEntity(t *testing.T) {
+	parser := &ADIFParser{QSOs: []QSO{
+		{Call: "W1AW", DXCC: "291", Band: "20M", Mode: "SSB", QslRcvd: QslYes},
+		{Call: "W1AW", DXCC: "291", Band: "40M", Mode: "CW", QslRcvd: QslYes, Name: "Hiram"},
+		{Call: "W1AW", DXCC: "202", Band: "15M", Mode: "FT8", QslRcvd: QslYes},
+		{Call: "A61XX", DXCC: "391", Band: "10M", Mode: "SSB", QslRcvd: QslNo},
+	}}
+
+	got := parser.GetPaperQSLHallOfFame()
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 entries, got %d: %+v", len(got), got)
+	}
+	if got[0].DXCC != "202" || got[0].Band != "15M" || got[0].Mode != "FT8" {
+		t.Errorf("Expected the DXCC 202 entry first, got %+v", got[0])
+	}
+	if got[1].DXCC != "291" || got[1].Name != "Hiram" || got[1].Band != "40M" {
+		t.Errorf("Expected the named DXCC 291 QSO to be kept, got %+v", got[1])
+	}
+}
+
+func TestPaperQSLHallOfFameResolvesEntity(t *testing.T) {
+	// One QSO carries the DXCC code, the other only the country name
+	parser := &ADIFParser{QSOs: []QSO{
+		{Call: "W1AW", DXCC: "291", Country: "United States", Band: "20M", QslRcvd: QslYes},
+		{Call: "W1AW", Country: "USA", Band: "40M", QslRcvd: QslYes, Name: "Hiram"},
+	}}
+
+	got := parser.GetPaperQSLHallOfFame()
+	if len(got) != 1 {
+		t.Fatalf("Expected one entry for the United States, got %d: %+v", len(got), got)
+	}
+	if got[0].Name != "Hiram" {
+		t.Errorf("Expected the named QSO to be kept, got %+v", got[0])
+	}
+}
+
+func TestParseQslStatus(t *testing.T) {
+	tests := map[string]QslStatus{
+		"Y":   QslYes,
+		"y":   QslYes,
+		" n ": QslNo,
+		"r":   QslRequested,
+		"I":   QslInvalid,
+		"v":   QslVerified,
+		"Q":   QslQueued,
+		"":    QslEmpty,
+		"X":   QslEmpty,
+		"YES": QslEmpty,
+	}
+
+	for in, want := range tests {
+		if got := ParseQslStatus(in); got != want {
+			t.Errorf("ParseQslStatus(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	if !QslVerified.Confirmed() || !QslYes.Confirmed() || QslQueued.Confirmed() {
+		t.Error("Expected only Y and V to count as confirmed")
+	}
+}
+
+func TestParseLowercaseQslRcvd(t *testing.T) {
+	adif := "<EOH><CALL:5>A61XX<QSO_DATE:8>20250301<TIME_ON:6>183000<QSL_RCVD:1>v<QSL_SENT:1>q<EOR>"
+
+	parser := NewADIFParser()
+	if err := parser.ParseFile(strings.NewReader(adif)); err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	qso := parser.QSOs[0]
+	if qso.QslRcvd != QslVerified || qso.QslSent != QslQueued {
+		t.Errorf("Expected V/Q statuses, got %q/%q", qso.QslRcvd, qso.QslSent)
+	}
+	if len(parser.GetPaperQSLHallOfFame()) != 1 {
+		t.Error("Expected a verified card to appear in the hall of fame")
+	}
+}
+
+func TestParseInternsRepeatedValues(t *testing.T) {
+	adif := "<EOH>" +
+		"<CALL:5>A61XX<QSO_DATE:8>20250301<TIME_ON:4>1830<BAND:3>20m<MODE:3>FT8<MY_RIG:6>IC-705<EOR>" +
+		"<CALL:5>A65BB<QSO_DATE:8>20250301<TIME_ON:4>1831<BAND:3>20m<MODE:3>FT8<MY_RIG:6>IC-705<EOR>"
+
+	parser := NewADIFParser()
+	if err := parser.ParseFile(strings.NewReader(adif)); err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	a, b := parser.QSOs[0], parser.QSOs[1]
+	for name, pair := range map[string][2]string{
+		"band":     {a.Band, b.Band},
+		"mode":     {a.Mode, b.Mode},
+		"my_rig":   {a.MyRig, b.MyRig},
+		"qso_date": {a.QSODate, b.QSODate},
+	} {
+		if unsafe.StringData(pair[0]) != unsafe.StringData(pair[1]) {
+			t.Errorf("Expected %s values to share storage", name)
+		}
+	}
+}
+
+func TestGetLatestQSOs(t *testing.T) {
+	at := func(h int) time.Time { return time.Date(2025, 3, 1, h, 0, 0, 0, time.UTC) }
+	parser := &ADIFParser{QSOs: []QSO{
+		{Call: "A", Timestamp: at(1)},
+		{Call: "B", Timestamp: at(5)},
+		{Call: "C", Timestamp: at(3)},
+		{Call: "D", Timestamp: at(5)},
+		{Call: "E"},
+		{Call: "F", Timestamp: at(4)},
+	}}
+
+	var calls []string
+	for _, qso := range parser.GetLatestQSOs(4) {
+		calls = append(calls, qso.Call)
+	}
+	if got := strings.Join(calls, ","); got != "B,D,F,C" {
+		t.Errorf("Expected B,D,F,C, got %s", got)
+	}
+
+	if got := len(parser.GetLatestQSOs(10)); got != 6 {
+		t.Errorf("Expected all 6 QSOs when the limit is larger, got %d", got)
+	}
+	if got := parser.GetLatestQSOs(10)[5].Call; got != "E" {
+		t.Errorf("Expected the QSO without a time last, got %s", got)
+	}
+}
+
+// TestFlagCodesHaveFlags checks that every code GetFlagCode can return has a
+// vendored flag image
+func TestFlagCodesHaveFlags(t *testing.T) {
+	if _, err := fs.Stat(static.Static, "flags"); err != nil {
+		t.Fatalf("static/flags is missing, run scripts/fetch-flags.sh to vendor the flags: %v", err)
+	}
+	for country := range countryFlagCodes {
+		code := QSO{Country: country}.GetFlagCode()
+		if _, err := fs.Stat(static.Static, "flags/"+code+".svg"); err != nil {
+			t.Errorf("No flag for %s (%q): %v", country, code, err)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// UpdateADIF returns ADIF text with fields set on the records that set
+// returns fields for, and how many records were changed. Field names are
+// lower case, and an empty value removes the field. The header, other
+// records and the other fields of changed records are kept byte for byte.
+func UpdateADIF(content string, set func(QSO) map[string]string) (string, int) {
+	var b strings.Builder
+	b.Grow(len(content))
+
+	if _, end := findEndTag(content, "eoh"); end != -1 {
+		b.WriteString(content[:end])
+		content = content[end:]
+	}
+
+	changed := 0
+	for len(content) > 0 {
+		start, end := findEndTag(content, "eor")
+		if start == -1 {
+			b.WriteString(content)
+			break
+		}
+		record, eor := content[:start], content[start:end]
+		content = content[end:]
+
+		if qso, err := ParseADIFRecord(record + "<EOR>"); err == nil {
+			if fields := set(qso); len(fields) > 0 {
+				record = setADIFFields(record, fields)
+				changed++
+			}
+		}
+		b.WriteString(record)
+		b.WriteString(eor)
+	}
+	return b.String(), changed
+}
+
+// setADIFFields replaces the given fields of a raw record, removing the
+// existing tags and adding the new ones after the last field
+func setADIFFields(record string, fields map[string]string) string {
+	var b strings.Builder
+	i := 0
+	for {
+		open := strings.IndexByte(record[i:], '<')
+		if open == -1 {
+			break
+		}
+		open += i
+		end := strings.IndexByte(record[open:], '>')
+		if end == -1 {
+			break
+		}
+		end += open
+
+		name, spec, ok := strings.Cut(record[open+1:end], ":")
+		lengthStr, _, _ := strings.Cut(spec, ":")
+		length, err := strconv.Atoi(lengthStr)
+		if !ok || err != nil || length < 0 {
+			b.WriteString(record[i : end+1])
+			i = end + 1
+			continue
+		}
+		dataEnd := min(end+1+length, len(record))
+
+		if _, replaced := fields[strings.ToLower(strings.TrimSpace(name))]; replaced {
+			b.WriteString(record[i:open])
+			// Drop the separator after the removed field
+			if dataEnd < len(record) && record[dataEnd] == ' ' {
+				dataEnd++
+			}
+		} else {
+			b.WriteString(record[i:dataEnd])
+		}
+		i = dataEnd
+	}
+	b.WriteString(record[i:])
+
+	kept := b.String()
+	trimmed := strings.TrimRight(kept, " \t\r\n")
+	tail := kept[len(trimmed):]
+	if tail == "" {
+		tail = " "
+	}
+
+	names := make([]string, 0, len(fields))
+	for name, value := range fields {
+		if value != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var out strings.Builder
+	out.WriteString(trimmed)
+	for _, name := range names {
+		if out.Len() > 0 {
+			out.WriteByte(' ')
+		}
+		fmt.Fprintf(&out, "<%s:%d>%s", strings.ToUpper(name), len(fields[name]), fields[name])
+	}
+	out.WriteString(tail)
+	return out.String()
+}
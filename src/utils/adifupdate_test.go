@@ -0,0 +1,52 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUpdateADIF(t *testing.T) {
+	content := "Exported log\n<ADIF_VER:5>3.1.4 <EOH>\n" +
+		"<CALL:5>A61XX <QSO_DATE:8>20250301 <TIME_ON:4>1830 <QSL_SENT:1>R <EOR>\n" +
+		"<CALL:4>W1AW <QSO_DATE:8>20250302 <TIME_ON:4>1200 <eor>\n"
+
+	updated, changed := UpdateADIF(content, func(qso QSO) map[string]string {
+		if qso.Call != "A61XX" {
+			return nil
+		}
+		return map[string]string{"qsl_sent": "Y", "qslsdate": "20250310"}
+	})
+
+	if changed != 1 {
+		t.Errorf("Expected 1 changed record, got %d", changed)
+	}
+	expected := "Exported log\n<ADIF_VER:5>3.1.4 <EOH>\n" +
+		"<CALL:5>A61XX <QSO_DATE:8>20250301 <TIME_ON:4>1830 <QSL_SENT:1>Y <QSLSDATE:8>20250310 <EOR>\n" +
+		"<CALL:4>W1AW <QSO_DATE:8>20250302 <TIME_ON:4>1200 <eor>\n"
+	if updated != expected {
+		t.Errorf("Unexpected update:\n%s", updated)
+	}
+
+	p := NewADIFParser()
+	if err := p.parseContent(updated); err != nil {
+		t.Fatalf("Failed to parse updated log: %v", err)
+	}
+	if p.QSOs[0].QslSent != QslYes || p.QSOs[0].QslSentDate != "20250310" {
+		t.Errorf("Expected card recorded as sent, got %+v", p.QSOs[0])
+	}
+}
+
+func TestSetADIFFieldsRemove(t *testing.T) {
+	record := "<CALL:5>A61XX <QSL_SENT:1>R <NAME:3>Ali\n"
+	updated := setADIFFields(record, map[string]string{"qsl_sent": ""})
+	if updated != "<CALL:5>A61XX <NAME:3>Ali\n" {
+		t.Errorf("Expected field removed, got %q", updated)
+	}
+	if strings.Contains(setADIFFields(record, map[string]string{"name": "Bob"}), "Ali") {
+		t.Error("Expected existing value replaced")
+	}
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ErrNoADIFRecord is returned when ADIF text holds no QSO record
+var ErrNoADIFRecord = errors.New("no ADIF record found")
+
+// adifFields returns a QSO's non-empty fields in the order they are written
+func (qso QSO) adifFields() []adifField {
+	all := []adifField{
+		{"call", qso.Call},
+		{"qso_date", qso.QSODate},
+		{"time_on", qso.TimeOn},
+		{"qso_date_off", qso.QSODateOff},
+		{"time_off", qso.TimeOff},
+		{"band", qso.Band},
+		{"mode", qso.Mode},
+		{"submode", qso.Submode},
+		{"freq", qso.Freq},
+		{"rst_sent", qso.RSTSent},
+		{"rst_rcvd", qso.RSTRcvd},
+		{"name", qso.Name},
+		{"qth", qso.QTH},
+		{"gridsquare", qso.GridSquare},
+		{"vucc_grids", qso.VUCCGrids},
+		{"country", qso.Country},
+		{"dxcc", qso.DXCC},
+		{"state", qso.State},
+		{"cqz", qso.CQZone},
+		{"comment", qso.Comment},
+		{"station_callsign", qso.StationCall},
+		{"my_gridsquare", qso.MyGridSquare},
+		{"my_rig", qso.MyRig},
+		{"my_antenna", qso.MyAntenna},
+		{"tx_pwr", qso.TxPwr},
+		{"prop_mode", qso.PropMode},
+		{"sat_name", qso.SatName},
+		{"sat_mode", qso.SatMode},
+		{"contest_id", qso.ContestID},
+		{"srx", qso.SRX},
+		{"stx", qso.STX},
+		{"srx_string", qso.SRXString},
+		{"stx_string", qso.STXString},
+		{"qsl_sent", string(qso.QslSent)},
+		{"qsl_rcvd", string(qso.QslRcvd)},
+		{"qslsdate", qso.QslSentDate},
+		{"qslrdate", qso.QslRcvdDate},
+		{"lotw_qsl_sent", string(qso.LotwSent)},
+		{"lotw_qsl_rcvd", string(qso.LotwRcvd)},
+		{"lotw_qslrdate", qso.LotwRcvdDate},
+		{"eqsl_qsl_sent", string(qso.EqslSent)},
+		{"eqsl_qsl_rcvd", string(qso.EqslRcvd)},
+	}
+	if qso.EqslAG {
+		all = append(all, adifField{"app_eqsl_ag", "Y"})
+	}
+
+	fields := all[:0]
+	for _, f := range all {
+		if f.value != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// FormatADIFRecord returns a QSO as a single line ADIF record ending in <EOR>.
+// Field lengths are byte counts, as the ADIF specification requires.
+func FormatADIFRecord(qso QSO) string {
+	var b strings.Builder
+	for _, f := range qso.adifFields() {
+		fmt.Fprintf(&b, "<%s:%d>%s ", strings.ToUpper(f.name), len(f.value), f.value)
+	}
+	b.WriteString("<EOR>")
+	return b.String()
+}
+
+// WriteADIF writes QSOs as an ADIF file, one record per line after a header
+// naming the program that wrote it
+func WriteADIF(w io.Writer, programID string, qsos []QSO) error {
+	bw := bufio.NewWriter(w)
+	created := time.Now().UTC().Format("20060102 150405")
+	fmt.Fprintf(bw, "Exported by %s\n", programID)
+	fmt.Fprintf(bw, "<ADIF_VER:5>3.1.4 <CREATED_TIMESTAMP:%d>%s <PROGRAMID:%d>%s <EOH>\n", len(created), created, len(programID), programID)
+	for _, qso := range qsos {
+		fmt.Fprintln(bw, FormatADIFRecord(qso))
+	}
+	return bw.Flush()
+}
+
+// ParseADIFRecord parses the single QSO record in ADIF text, which may start
+// with a header. Records that would be skipped when loading a file are
+// returned as errors.
+func ParseADIFRecord(record string) (QSO, error) {
+	p := NewADIFParser()
+	if err := p.parseContent(record); err != nil {
+		return QSO{}, err
+	}
+	if len(p.Warnings) > 0 {
+		return QSO{}, errors.New(p.Warnings[0])
+	}
+	switch len(p.QSOs) {
+	case 0:
+		return QSO{}, ErrNoADIFRecord
+	case 1:
+		return p.QSOs[0], nil
+	}
+	return QSO{}, fmt.Errorf("expected one ADIF record, found %d", len(p.QSOs))
+}
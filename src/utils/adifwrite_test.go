@@ -0,0 +1,67 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFormatADIFRecordRoundTrip(t *testing.T) {
+	qso := QSO{
+		Call:    "EA4ABC",
+		QSODate: "20250301",
+		TimeOn:  "120500",
+		Band:    "20m",
+		Mode:    "MFSK",
+		Submode: "FT4",
+		Name:    "José <Pepe>",
+		QslRcvd: QslYes,
+	}
+
+	record := FormatADIFRecord(qso)
+	if !strings.Contains(record, "<NAME:12>José <Pepe>") {
+		t.Errorf("name not written with its byte length: %s", record)
+	}
+
+	parsed, err := ParseADIFRecord(record)
+	if err != nil {
+		t.Fatalf("ParseADIFRecord: %v", err)
+	}
+	if parsed.Name != qso.Name || parsed.Submode != "FT4" || parsed.QslRcvd != QslYes || parsed.Timestamp.IsZero() {
+		t.Errorf("round trip lost fields: %+v", parsed)
+	}
+}
+
+func TestParseADIFRecordErrors(t *testing.T) {
+	if _, err := ParseADIFRecord("header only <EOH>"); !errors.Is(err, ErrNoADIFRecord) {
+		t.Errorf("empty record error = %v, want ErrNoADIFRecord", err)
+	}
+	if _, err := ParseADIFRecord("<CALL:4>W1AW<EOR>"); err == nil {
+		t.Error("record without a date accepted")
+	}
+	two := "<CALL:4>W1AW<QSO_DATE:8>20250301<EOR><CALL:4>K1AB<QSO_DATE:8>20250301<EOR>"
+	if _, err := ParseADIFRecord(two); err == nil {
+		t.Error("two records accepted")
+	}
+}
+
+func TestWriteADIF(t *testing.T) {
+	var buf bytes.Buffer
+	qsos := []QSO{{Call: "W1AW", QSODate: "20250301", TimeOn: "1200"}, {Call: "K1AB", QSODate: "20250302", TimeOn: "1300"}}
+	if err := WriteADIF(&buf, "humaid-qsl", qsos); err != nil {
+		t.Fatalf("WriteADIF: %v", err)
+	}
+
+	p := NewADIFParser()
+	if err := p.ParseFile(&buf); err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	if len(p.QSOs) != 2 || p.QSOs[1].Call != "K1AB" {
+		t.Errorf("unexpected QSOs %+v", p.QSOs)
+	}
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/netip"
+)
+
+// Networks kept when truncating addresses, e.g. 192.0.2.0 for 192.0.2.123
+const (
+	anonymizeIPv4Bits = 24
+	anonymizeIPv6Bits = 48
+)
+
+// IPAnonymizer hides client addresses in logs, either truncating them to
+// their network or replacing them with a keyed hash. Both keep requests from
+// one client recognisable for rate analysis. A nil IPAnonymizer keeps
+// addresses as they are.
+type IPAnonymizer struct {
+	key []byte // Hash key, or nil when truncating
+}
+
+// NewIPAnonymizer creates an anonymizer for mode, which is truncate or hash.
+// Hashes are keyed with a key derived from secret so they can't be reversed
+// by hashing every address. An empty mode returns nil.
+func NewIPAnonymizer(mode, secret string) (*IPAnonymizer, error) {
+	switch mode {
+	case "":
+		return nil, nil
+	case "truncate":
+		return &IPAnonymizer{}, nil
+	case "hash":
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte("ip-anonymizer"))
+		return &IPAnonymizer{key: mac.Sum(nil)}, nil
+	}
+	return nil, fmt.Errorf("unknown IP anonymization mode %q, expected truncate or hash", mode)
+}
+
+// Anonymize returns the anonymized form of a client address, which may
+// include a port. The port is dropped.
+func (a *IPAnonymizer) Anonymize(addr string) string {
+	if a == nil {
+		return addr
+	}
+
+	host := addr
+	if h, _, err := net.SplitHostPort(addr); err == nil {
+		host = h
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return "unknown"
+	}
+	ip = ip.Unmap().WithZone("")
+
+	if a.key != nil {
+		mac := hmac.New(sha256.New, a.key)
+		mac.Write([]byte(ip.String()))
+		return "h:" + hex.EncodeToString(mac.Sum(nil)[:8])
+	}
+
+	bits := anonymizeIPv6Bits
+	if ip.Is4() {
+		bits = anonymizeIPv4Bits
+	}
+	prefix, _ := ip.Prefix(bits)
+	return prefix.Addr().String()
+}
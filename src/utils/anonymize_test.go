@@ -0,0 +1,69 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIPAnonymizerTruncate(t *testing.T) {
+	a, err := NewIPAnonymizer("truncate", "secret")
+	if err != nil {
+		t.Fatalf("NewIPAnonymizer: %v", err)
+	}
+
+	tests := []struct {
+		addr, want string
+	}{
+		{"192.0.2.123:54321", "192.0.2.0"},
+		{"192.0.2.123", "192.0.2.0"},
+		{"[2001:db8:1234:5678::1]:443", "2001:db8:1234::"},
+		{"[::ffff:192.0.2.9]:80", "192.0.2.0"},
+		{"@", "unknown"},
+	}
+	for _, tt := range tests {
+		if got := a.Anonymize(tt.addr); got != tt.want {
+			t.Errorf("Anonymize(%q) = %q, want %q", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestIPAnonymizerHash(t *testing.T) {
+	a, err := NewIPAnonymizer("hash", "secret")
+	if err != nil {
+		t.Fatalf("NewIPAnonymizer: %v", err)
+	}
+
+	first := a.Anonymize("192.0.2.123:1000")
+	if !strings.HasPrefix(first, "h:") || strings.Contains(first, "192.0.2") {
+		t.Errorf("hashed address = %q", first)
+	}
+	if got := a.Anonymize("192.0.2.123:2000"); got != first {
+		t.Errorf("same client hashed differently: %q and %q", first, got)
+	}
+	if got := a.Anonymize("192.0.2.124:1000"); got == first {
+		t.Errorf("different clients hashed the same")
+	}
+
+	other, _ := NewIPAnonymizer("hash", "other")
+	if got := other.Anonymize("192.0.2.123:1000"); got == first {
+		t.Errorf("hash doesn't depend on the secret")
+	}
+}
+
+func TestIPAnonymizerDisabled(t *testing.T) {
+	a, err := NewIPAnonymizer("", "secret")
+	if err != nil || a != nil {
+		t.Fatalf("NewIPAnonymizer(\"\") = %v, %v, want nil", a, err)
+	}
+	if got := a.Anonymize("192.0.2.123:54321"); got != "192.0.2.123:54321" {
+		t.Errorf("disabled anonymizer changed address to %q", got)
+	}
+
+	if _, err := NewIPAnonymizer("scramble", "secret"); err == nil {
+		t.Errorf("unknown mode accepted")
+	}
+}
@@ -0,0 +1,285 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const aprsFiURL = "https://api.aprs.fi/api/get"
+
+const (
+	// aprsOriginWindow is how close to a beacon a portable QSO must be
+	// logged for the beacon to be used as its origin
+	aprsOriginWindow = 3 * time.Hour
+	// aprsRecentWindow is how recent a beacon must be to show where I am
+	// currently operating from
+	aprsRecentWindow = 2 * time.Hour
+	// aprsMaxHistory bounds the beacons remembered for matching QSOs
+	aprsMaxHistory = 200
+)
+
+// APRSPosition is a position beacon of my station reported by aprs.fi
+type APRSPosition struct {
+	Name    string // Callsign with SSID, e.g. A66H-9
+	Lat     float64
+	Lng     float64
+	Grid    string
+	Comment string
+	Since   time.Time // When the station first reported this position
+	Time    time.Time // When the position was last heard
+}
+
+// MapURL returns the aprs.fi map link for the beaconing station
+func (p APRSPosition) MapURL() string {
+	return "https://aprs.fi/#!call=a%2F" + url.QueryEscape(p.Name)
+}
+
+type aprsFiResponse struct {
+	Result      string `json:"result"`
+	Description string `json:"description"`
+	Entries     []struct {
+		Name     string `json:"name"`
+		Time     string `json:"time"`
+		LastTime string `json:"lasttime"`
+		Lat      string `json:"lat"`
+		Lng      string `json:"lng"`
+		Comment  string `json:"comment"`
+	} `json:"entries"`
+}
+
+// IsPortable reports whether a callsign has a portable or mobile suffix,
+// e.g. A66H/P or A66H/M
+func IsPortable(call string) bool {
+	call = strings.ToUpper(call)
+	return strings.HasSuffix(call, "/P") || strings.HasSuffix(call, "/M")
+}
+
+// APRS periodically fetches and remembers my position beacons from aprs.fi
+type APRS struct {
+	names  []string
+	apiKey string
+	client *http.Client
+
+	mutex   sync.RWMutex
+	history []APRSPosition // Oldest first
+}
+
+// NewAPRS creates an aprs.fi client for the given station names (callsigns
+// with optional SSIDs)
+func NewAPRS(names []string, apiKey string) *APRS {
+	calls := make([]string, 0, len(names))
+	for _, name := range names {
+		if name = strings.ToUpper(strings.TrimSpace(name)); name != "" {
+			calls = append(calls, name)
+		}
+	}
+
+	return &APRS{
+		names:  calls,
+		apiKey: apiKey,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Fetch queries aprs.fi for the latest position of my stations, reporting
+// whether a new position was recorded
+func (a *APRS) Fetch(ctx context.Context) (bool, error) {
+	params := url.Values{}
+	params.Set("name", strings.Join(a.names, ","))
+	params.Set("what", "loc")
+	params.Set("apikey", a.apiKey)
+	params.Set("format", "json")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, aprsFiURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create aprs.fi request: %w", err)
+	}
+	req.Header.Set("User-Agent", "humaid-qsl")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to query aprs.fi: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("aprs.fi returned status %d", resp.StatusCode)
+	}
+
+	var result aprsFiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, fmt.Errorf("failed to decode aprs.fi response: %w", err)
+	}
+	if result.Result != "ok" {
+		return false, fmt.Errorf("aprs.fi request failed: %s", result.Description)
+	}
+
+	// Several SSIDs may be watched; the most recently heard one is where I am
+	var latest *APRSPosition
+	for _, e := range result.Entries {
+		lastTime, err := strconv.ParseInt(e.LastTime, 10, 64)
+		if err != nil {
+			continue
+		}
+		since, err := strconv.ParseInt(e.Time, 10, 64)
+		if err != nil {
+			since = lastTime
+		}
+		lat, errLat := strconv.ParseFloat(e.Lat, 64)
+		lng, errLng := strconv.ParseFloat(e.Lng, 64)
+		if errLat != nil || errLng != nil {
+			continue
+		}
+
+		pos := APRSPosition{
+			Name:    strings.ToUpper(e.Name),
+			Lat:     lat,
+			Lng:     lng,
+			Grid:    GridFromLatLng(lat, lng),
+			Comment: strings.TrimSpace(e.Comment),
+			Since:   time.Unix(since, 0).UTC(),
+			Time:    time.Unix(lastTime, 0).UTC(),
+		}
+		if latest == nil || pos.Time.After(latest.Time) {
+			latest = &pos
+		}
+	}
+	if latest == nil {
+		return false, nil
+	}
+
+	return a.record(*latest), nil
+}
+
+// record remembers a position unless it repeats the last one, reporting
+// whether it was new
+func (a *APRS) record(pos APRSPosition) bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if n := len(a.history); n > 0 {
+		last := &a.history[n-1]
+		if !pos.Time.After(last.Time) {
+			return false
+		}
+		// The same spot heard again only moves the time on
+		if pos.Name == last.Name && pos.Grid == last.Grid {
+			last.Time = pos.Time
+			last.Comment = pos.Comment
+			return true
+		}
+	}
+
+	a.history = append(a.history, pos)
+	if len(a.history) > aprsMaxHistory {
+		a.history = a.history[len(a.history)-aprsMaxHistory:]
+	}
+	return true
+}
+
+// StartFetching starts the periodic fetch goroutine, calling onChange after a
+// new position is recorded
+func (a *APRS) StartFetching(interval time.Duration, onChange func()) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			changed, err := a.Fetch(ctx)
+			if err != nil {
+				log.Printf("Failed to fetch APRS position: %v", err)
+			}
+			cancel()
+
+			if changed && onChange != nil {
+				onChange()
+			}
+
+			<-ticker.C
+		}
+	}()
+}
+
+// Current returns my latest position if it was heard recently
+func (a *APRS) Current() (APRSPosition, bool) {
+	if a == nil {
+		return APRSPosition{}, false
+	}
+
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	if len(a.history) == 0 {
+		return APRSPosition{}, false
+	}
+	pos := a.history[len(a.history)-1]
+	if time.Since(pos.Time) > aprsRecentWindow {
+		return APRSPosition{}, false
+	}
+	return pos, true
+}
+
+// Apply returns the QSOs with the grid of the nearest beacon in time filled
+// in as MY_GRIDSQUARE for portable and mobile QSOs logged without one. The
+// input slice is not modified.
+func (a *APRS) Apply(qsos []QSO) []QSO {
+	if a == nil {
+		return qsos
+	}
+
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	if len(a.history) == 0 {
+		return qsos
+	}
+
+	located := make([]QSO, len(qsos))
+	for i, qso := range qsos {
+		if qso.MyGridSquare == "" && IsPortable(qso.StationCall) && !qso.Timestamp.IsZero() {
+			if pos, ok := a.nearest(qso.Timestamp); ok {
+				qso.MyGridSquare = pos.Grid
+			}
+		}
+		located[i] = qso
+	}
+	return located
+}
+
+// nearest returns the remembered position closest to t within the origin
+// window. The caller must hold the lock.
+func (a *APRS) nearest(t time.Time) (APRSPosition, bool) {
+	var best APRSPosition
+	bestDistance := aprsOriginWindow + 1
+	for _, pos := range a.history {
+		if d := pos.distance(t); d <= aprsOriginWindow && d < bestDistance {
+			best, bestDistance = pos, d
+		}
+	}
+	return best, bestDistance <= aprsOriginWindow
+}
+
+// distance returns how far t is from the time the station was at the
+// position, which is zero while it was there
+func (p APRSPosition) distance(t time.Time) time.Duration {
+	switch {
+	case t.Before(p.Since):
+		return p.Since.Sub(t)
+	case t.After(p.Time):
+		return t.Sub(p.Time)
+	}
+	return 0
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAPRSApplyUsesNearestPosition(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Minute)
+
+	aprs := NewAPRS([]string{"a66h-9"}, "key")
+	if _, ok := aprs.Current(); ok {
+		t.Error("Expected no current position before any beacon")
+	}
+
+	aprs.record(APRSPosition{Name: "A66H-9", Grid: "LL75qb", Since: now.Add(-8 * time.Hour), Time: now.Add(-6 * time.Hour)})
+	aprs.record(APRSPosition{Name: "A66H-9", Grid: "LL74ax", Since: now.Add(-2 * time.Hour), Time: now.Add(-time.Hour)})
+	if aprs.record(APRSPosition{Name: "A66H-9", Grid: "LL74ax", Since: now.Add(-2 * time.Hour), Time: now.Add(-time.Hour)}) {
+		t.Error("Expected a repeated beacon not to be recorded")
+	}
+	if !aprs.record(APRSPosition{Name: "A66H-9", Grid: "LL74ax", Since: now.Add(-2 * time.Hour), Time: now.Add(-30 * time.Minute)}) {
+		t.Error("Expected a later beacon at the same position to be recorded")
+	}
+
+	pos, ok := aprs.Current()
+	if !ok || pos.Grid != "LL74ax" {
+		t.Fatalf("Expected current position LL74ax, got %v (%v)", pos.Grid, ok)
+	}
+
+	qsos := []QSO{
+		{Call: "DL1ABC", StationCall: "A66H/P", Timestamp: now.Add(-7 * time.Hour)},
+		{Call: "DL1ABC", StationCall: "A66H/M", Timestamp: now.Add(-45 * time.Minute)},
+		{Call: "DL1ABC", StationCall: "A66H/P", Timestamp: now.Add(-3*time.Hour - 30*time.Minute)},
+		{Call: "DL1ABC", StationCall: "A66H", Timestamp: now.Add(-45 * time.Minute)},
+		{Call: "DL1ABC", StationCall: "A66H/P", MyGridSquare: "LL65", Timestamp: now.Add(-45 * time.Minute)},
+		{Call: "DL1ABC", StationCall: "A66H/P", Timestamp: now.Add(-20 * 24 * time.Hour)},
+	}
+	located := aprs.Apply(qsos)
+
+	want := []string{"LL75qb", "LL74ax", "LL74ax", "", "LL65", ""}
+	for i, grid := range want {
+		if located[i].MyGridSquare != grid {
+			t.Errorf("QSO %d: expected MY_GRIDSQUARE %q, got %q", i, grid, located[i].MyGridSquare)
+		}
+	}
+	if qsos[0].MyGridSquare != "" {
+		t.Error("Expected the input QSOs not to be modified")
+	}
+
+	var disabled *APRS
+	if got := disabled.Apply(qsos); len(got) != len(qsos) {
+		t.Error("Expected a nil APRS to return the QSOs unchanged")
+	}
+	if _, ok := disabled.Current(); ok {
+		t.Error("Expected a nil APRS to have no position")
+	}
+}
+
+func TestIsPortable(t *testing.T) {
+	for call, want := range map[string]bool{"A66H/P": true, "a66h/m": true, "A66H": false, "A6/A66H": false, "A66H/MM": false} {
+		if got := IsPortable(call); got != want {
+			t.Errorf("IsPortable(%q) = %v, want %v", call, got, want)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AwardBand is the progress towards an award on a single band
+type AwardBand struct {
+	Band      string
+	Target    int
+	Worked    int
+	Confirmed int
+}
+
+// AwardProgress is the progress towards an award, overall and per band
+type AwardProgress struct {
+	Name        string
+	Description string
+	Target      int
+	Worked      int
+	Confirmed   int
+	Bands       []AwardBand
+}
+
+// Percent returns the confirmed progress towards the target, capped at 100
+func (a AwardProgress) Percent() int {
+	if a.Target == 0 {
+		return 0
+	}
+	if a.Confirmed >= a.Target {
+		return 100
+	}
+	return a.Confirmed * 100 / a.Target
+}
+
+// usStates are the 50 states counted for Worked All States
+var usStates = strings.Fields(`AL AK AZ AR CA CO CT DE FL GA HI ID IL IN IA KS
+	KY LA ME MD MA MI MN MS MO MT NE NV NH NJ NM NY NC ND OH OK OR PA RI SC SD
+	TN TX UT VT VA WA WV WI WY`)
+
+// usEntities are the DXCC entity codes for the United States, Alaska and Hawaii
+var usEntities = map[string]bool{"291": true, "6": true, "110": true}
+
+// vuccTargets are the grid counts needed for VUCC on each band
+var vuccTargets = map[string]int{
+	"6m":     100,
+	"2m":     100,
+	"1.25m":  50,
+	"70cm":   50,
+	"33cm":   25,
+	"23cm":   25,
+	"13cm":   10,
+	"9cm":    10,
+	"6cm":    10,
+	"3cm":    10,
+	"1.25cm": 10,
+}
+
+// awardTally counts distinct worked and confirmed keys overall and per band
+type awardTally struct {
+	worked        map[string]bool
+	confirmed     map[string]bool
+	bandWorked    map[string]map[string]bool
+	bandConfirmed map[string]map[string]bool
+}
+
+func newAwardTally() *awardTally {
+	return &awardTally{
+		worked:        make(map[string]bool),
+		confirmed:     make(map[string]bool),
+		bandWorked:    make(map[string]map[string]bool),
+		bandConfirmed: make(map[string]map[string]bool),
+	}
+}
+
+func (t *awardTally) add(key, band string, confirmed bool) {
+	if key == "" {
+		return
+	}
+	band = strings.ToLower(band)
+
+	t.worked[key] = true
+	if t.bandWorked[band] == nil {
+		t.bandWorked[band] = make(map[string]bool)
+		t.bandConfirmed[band] = make(map[string]bool)
+	}
+	t.bandWorked[band][key] = true
+
+	if confirmed {
+		t.confirmed[key] = true
+		t.bandConfirmed[band][key] = true
+	}
+}
+
+// progress converts the tally into an AwardProgress, ordering bands from
+// lowest to highest frequency
+func (t *awardTally) progress(name, description string, target int, bandTarget func(string) int) AwardProgress {
+	a := AwardProgress{
+		Name:        name,
+		Description: description,
+		Target:      target,
+		Worked:      len(t.worked),
+		Confirmed:   len(t.confirmed),
+	}
+
+	for band, worked := range t.bandWorked {
+		if band == "" {
+			continue
+		}
+		a.Bands = append(a.Bands, AwardBand{
+			Band:      band,
+			Target:    bandTarget(band),
+			Worked:    len(worked),
+			Confirmed: len(t.bandConfirmed[band]),
+		})
+	}
+	sort.Slice(a.Bands, func(i, j int) bool {
+		return bandIndex(a.Bands[i].Band) < bandIndex(a.Bands[j].Band)
+	})
+
+	return a
+}
+
+// bandIndex returns the position of a band in the band plan, with unknown
+// bands sorted last
+func bandIndex(name string) int {
+	for i, b := range bands {
+		if strings.EqualFold(b.Name, name) {
+			return i
+		}
+	}
+	return len(bands)
+}
+
+// IsAwardConfirmed reports whether a QSO counts as confirmed for awards,
+// which requires a received paper QSL or LoTW confirmation
+func IsAwardConfirmed(qso QSO) bool {
+	return qso.QslRcvd.Confirmed() || qso.LotwRcvd.Confirmed()
+}
+
+// ComputeAwards returns DXCC, WAS, WAZ and VUCC progress for a set of QSOs
+func ComputeAwards(qsos []QSO) []AwardProgress {
+	dxcc := newAwardTally()
+	was := newAwardTally()
+	waz := newAwardTally()
+	vucc := newAwardTally()
+
+	states := make(map[string]bool, len(usStates))
+	for _, s := range usStates {
+		states[s] = true
+	}
+
+	for _, qso := range qsos {
+		confirmed := IsAwardConfirmed(qso)
+
+		if entity := qso.Entity(); entity != "" {
+			dxcc.add(strings.ToUpper(entity), qso.Band, confirmed)
+		}
+
+		if usEntities[qso.DXCC] || strings.EqualFold(qso.Country, "United States") {
+			if states[qso.State] {
+				was.add(qso.State, qso.Band, confirmed)
+			}
+		}
+
+		if zone, err := strconv.Atoi(qso.CQZone); err == nil && zone >= 1 && zone <= 40 {
+			waz.add(strconv.Itoa(zone), qso.Band, confirmed)
+		}
+
+		if _, ok := vuccTargets[strings.ToLower(qso.Band)]; ok {
+			for _, grid := range qsoGrids(qso) {
+				vucc.add(grid, qso.Band, confirmed)
+			}
+		}
+	}
+
+	fixed := func(n int) func(string) int {
+		return func(string) int { return n }
+	}
+
+	return []AwardProgress{
+		dxcc.progress("DXCC", "DX Century Club: 100 DXCC entities", 100, fixed(100)),
+		was.progress("WAS", "Worked All States: all 50 US states", len(usStates), fixed(len(usStates))),
+		waz.progress("WAZ", "Worked All Zones: all 40 CQ zones", 40, fixed(40)),
+		vucc.progress("VUCC", "VHF/UHF Century Club: Maidenhead grid squares per band", 0, func(band string) int {
+			return vuccTargets[band]
+		}),
+	}
+}
+
+// qsoGrids returns the 4-character grid squares a QSO counts for
+func qsoGrids(qso QSO) []string {
+	var grids []string
+	if qso.VUCCGrids != "" {
+		for _, g := range strings.Split(qso.VUCCGrids, ",") {
+			if g = strings.TrimSpace(g); len(g) >= 4 {
+				grids = append(grids, strings.ToUpper(g[:4]))
+			}
+		}
+		return grids
+	}
+	if len(qso.GridSquare) >= 4 {
+		grids = append(grids, strings.ToUpper(qso.GridSquare[:4]))
+	}
+	return grids
+}
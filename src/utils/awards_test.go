@@ -0,0 +1,41 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import "testing"
+
+func TestComputeAwards(t *testing.T) {
+	qsos := []QSO{
+		{Call: "W1AW", Band: "20m", DXCC: "291", State: "CT", CQZone: "5", LotwRcvd: QslYes},
+		{Call: "K6XX", Band: "40m", DXCC: "291", State: "CA", CQZone: "3"},
+		{Call: "W1AW", Band: "40m", DXCC: "291", State: "CT", CQZone: "5"},
+		{Call: "A65BB", Band: "6m", DXCC: "391", CQZone: "21", GridSquare: "LL75rb", QslRcvd: QslYes},
+		{Call: "A61XX", Band: "6m", DXCC: "391", VUCCGrids: "LL74,LL84", CQZone: "21"},
+	}
+
+	awards := ComputeAwards(qsos)
+	byName := make(map[string]AwardProgress)
+	for _, a := range awards {
+		byName[a.Name] = a
+	}
+
+	dxcc := byName["DXCC"]
+	if dxcc.Worked != 2 || dxcc.Confirmed != 2 {
+		t.Errorf("Expected DXCC 2 worked/2 confirmed, got %d/%d", dxcc.Worked, dxcc.Confirmed)
+	}
+	if len(dxcc.Bands) != 3 || dxcc.Bands[0].Band != "40m" || dxcc.Bands[2].Band != "6m" {
+		t.Errorf("Expected DXCC bands ordered 40m, 20m, 6m, got %+v", dxcc.Bands)
+	}
+
+	was := byName["WAS"]
+	if was.Worked != 2 || was.Confirmed != 1 {
+		t.Errorf("Expected WAS 2 worked/1 confirmed, got %d/%d", was.Worked, was.Confirmed)
+	}
+
+	vucc := byName["VUCC"]
+	if len(vucc.Bands) != 1 || vucc.Bands[0].Worked != 3 || vucc.Bands[0].Confirmed != 1 || vucc.Bands[0].Target != 100 {
+		t.Errorf("Expected VUCC 6m 3 worked/1 confirmed of 100, got %+v", vucc.Bands)
+	}
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Band is an amateur band edge definition as per the ADIF band enumeration
+type Band struct {
+	Name  string
+	Lower float64 // MHz
+	Upper float64 // MHz
+}
+
+var bands = []Band{
+	{"2190m", 0.1357, 0.1378},
+	{"630m", 0.472, 0.479},
+	{"560m", 0.501, 0.504},
+	{"160m", 1.8, 2.0},
+	{"80m", 3.5, 4.0},
+	{"60m", 5.06, 5.45},
+	{"40m", 7.0, 7.3},
+	{"30m", 10.1, 10.15},
+	{"20m", 14.0, 14.35},
+	{"17m", 18.068, 18.168},
+	{"15m", 21.0, 21.45},
+	{"12m", 24.890, 24.99},
+	{"10m", 28.0, 29.7},
+	{"8m", 40, 45},
+	{"6m", 50, 54},
+	{"5m", 54.000001, 69.9},
+	{"4m", 70, 71},
+	{"2m", 144, 148},
+	{"1.25m", 222, 225},
+	{"70cm", 420, 450},
+	{"33cm", 902, 928},
+	{"23cm", 1240, 1300},
+	{"13cm", 2300, 2450},
+	{"9cm", 3300, 3500},
+	{"6cm", 5650, 5925},
+	{"3cm", 10000, 10500},
+	{"1.25cm", 24000, 24250},
+}
+
+// BandFromFrequency returns the ADIF band name for a frequency in MHz, or an
+// empty string when it is outside all amateur bands
+func BandFromFrequency(mhz float64) string {
+	for _, b := range bands {
+		if mhz >= b.Lower && mhz <= b.Upper {
+			return b.Name
+		}
+	}
+	return ""
+}
+
+// ParseFrequency parses an ADIF frequency string in MHz
+func ParseFrequency(freq string) (float64, bool) {
+	freq = strings.TrimSpace(freq)
+	if freq == "" {
+		return 0, false
+	}
+	mhz, err := strconv.ParseFloat(freq, 64)
+	if err != nil || mhz <= 0 {
+		return 0, false
+	}
+	return mhz, true
+}
+
+// bandByName returns the band plan entry for an ADIF band name
+func bandByName(name string) (Band, bool) {
+	for _, b := range bands {
+		if strings.EqualFold(b.Name, name) {
+			return b, true
+		}
+	}
+	return Band{}, false
+}
+
+// BandNames returns the ADIF band names from lowest to highest frequency
+func BandNames() []string {
+	names := make([]string, len(bands))
+	for i, b := range bands {
+		names[i] = b.Name
+	}
+	return names
+}
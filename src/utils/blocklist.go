@@ -0,0 +1,188 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrBlockNotFound is returned when removing a prefix that isn't blocked
+var ErrBlockNotFound = errors.New("block not found")
+
+// Block is a banned IP address or network
+type Block struct {
+	Prefix  string    `json:"prefix"` // Canonical CIDR, e.g. 192.0.2.1/32
+	Reason  string    `json:"reason,omitempty"`
+	Created time.Time `json:"created"`
+	Expires time.Time `json:"expires,omitempty"` // Zero for a permanent block
+}
+
+// IsExpired reports whether the block has expired at the given time
+func (b Block) IsExpired(now time.Time) bool {
+	return !b.Expires.IsZero() && !now.Before(b.Expires)
+}
+
+// BlockList keeps banned IP addresses and networks in a JSON file
+type BlockList struct {
+	path   string
+	mutex  sync.RWMutex
+	blocks []Block
+	nets   []netip.Prefix // Parsed prefixes, parallel to blocks
+}
+
+// NewBlockList loads the block list stored at path, starting empty if the
+// file doesn't exist yet
+func NewBlockList(path string) (*BlockList, error) {
+	var blocks []Block
+	if err := loadJSONFile(path, &blocks); err != nil {
+		return nil, err
+	}
+
+	bl := &BlockList{path: path}
+	for _, b := range blocks {
+		prefix, err := netip.ParsePrefix(b.Prefix)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blocked prefix %q: %w", b.Prefix, err)
+		}
+		bl.blocks = append(bl.blocks, b)
+		bl.nets = append(bl.nets, prefix)
+	}
+	return bl, nil
+}
+
+// ParseBlockPrefix parses an IP address or CIDR network into a canonical
+// prefix, treating a bare address as a single host
+func ParseBlockPrefix(s string) (netip.Prefix, error) {
+	s = strings.TrimSpace(s)
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid network %q", s)
+		}
+		return prefix.Masked(), nil
+	}
+
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid IP address %q", s)
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// Add blocks an IP address or network. A zero duration blocks it permanently.
+// Blocking an already blocked prefix replaces the existing block.
+func (bl *BlockList) Add(s, reason string, duration time.Duration) (Block, error) {
+	prefix, err := ParseBlockPrefix(s)
+	if err != nil {
+		return Block{}, err
+	}
+
+	block := Block{
+		Prefix:  prefix.String(),
+		Reason:  reason,
+		Created: time.Now().UTC(),
+	}
+	if duration > 0 {
+		block.Expires = block.Created.Add(duration)
+	}
+
+	bl.mutex.Lock()
+	defer bl.mutex.Unlock()
+
+	bl.removeExpired(time.Now())
+	bl.remove(block.Prefix)
+	bl.blocks = append(bl.blocks, block)
+	bl.nets = append(bl.nets, prefix)
+	return block, bl.save()
+}
+
+// Remove unblocks a prefix previously added
+func (bl *BlockList) Remove(prefix string) error {
+	bl.mutex.Lock()
+	defer bl.mutex.Unlock()
+
+	if !bl.remove(prefix) {
+		return ErrBlockNotFound
+	}
+	return bl.save()
+}
+
+// Blocked reports whether an IP address is covered by an active block
+func (bl *BlockList) Blocked(ip string) bool {
+	if bl == nil {
+		return false
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	bl.mutex.RLock()
+	defer bl.mutex.RUnlock()
+
+	now := time.Now()
+	for i, prefix := range bl.nets {
+		if prefix.Contains(addr) && !bl.blocks[i].IsExpired(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// List returns the active blocks, newest first
+func (bl *BlockList) List() []Block {
+	bl.mutex.RLock()
+	defer bl.mutex.RUnlock()
+
+	now := time.Now()
+	var blocks []Block
+	for _, b := range bl.blocks {
+		if !b.IsExpired(now) {
+			blocks = append(blocks, b)
+		}
+	}
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i].Created.After(blocks[j].Created)
+	})
+	return blocks
+}
+
+// remove deletes a prefix, reporting whether it was present. The caller must
+// hold the lock.
+func (bl *BlockList) remove(prefix string) bool {
+	for i, b := range bl.blocks {
+		if b.Prefix == prefix {
+			bl.blocks = append(bl.blocks[:i], bl.blocks[i+1:]...)
+			bl.nets = append(bl.nets[:i], bl.nets[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// removeExpired drops expired blocks. The caller must hold the lock.
+func (bl *BlockList) removeExpired(now time.Time) {
+	for i := 0; i < len(bl.blocks); {
+		if bl.blocks[i].IsExpired(now) {
+			bl.blocks = append(bl.blocks[:i], bl.blocks[i+1:]...)
+			bl.nets = append(bl.nets[:i], bl.nets[i+1:]...)
+			continue
+		}
+		i++
+	}
+}
+
+// save writes the block list to disk. The caller must hold the lock.
+func (bl *BlockList) save() error {
+	return saveJSONFile(bl.path, bl.blocks, 0644)
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBlockList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.json")
+	bl, err := NewBlockList(path)
+	if err != nil {
+		t.Fatalf("Failed to create block list: %v", err)
+	}
+
+	if _, err := bl.Add("198.51.100.7", "spam", 0); err != nil {
+		t.Fatalf("Failed to block address: %v", err)
+	}
+	if _, err := bl.Add("2001:db8::1/48", "", time.Hour); err != nil {
+		t.Fatalf("Failed to block network: %v", err)
+	}
+	if _, err := bl.Add("not an ip", "", 0); err == nil {
+		t.Error("Expected invalid address to be rejected")
+	}
+
+	for ip, want := range map[string]bool{
+		"198.51.100.7":          true,
+		"::ffff:198.51.100.7":   true,
+		"198.51.100.8":          false,
+		"2001:db8:0:ffff::1234": true,
+		"2001:db9::1":           false,
+	} {
+		if got := bl.Blocked(ip); got != want {
+			t.Errorf("Blocked(%s) = %v, want %v", ip, got, want)
+		}
+	}
+
+	reloaded, err := NewBlockList(path)
+	if err != nil {
+		t.Fatalf("Failed to reload block list: %v", err)
+	}
+	if len(reloaded.List()) != 2 {
+		t.Errorf("Expected 2 persisted blocks, got %d", len(reloaded.List()))
+	}
+
+	if err := reloaded.Remove("2001:db8::/48"); err != nil {
+		t.Errorf("Failed to remove block: %v", err)
+	}
+	if reloaded.Blocked("2001:db8::1") {
+		t.Error("Expected removed block to no longer apply")
+	}
+}
+
+func TestBlockExpiry(t *testing.T) {
+	now := time.Now()
+	b := Block{Expires: now.Add(-time.Minute)}
+	if !b.IsExpired(now) {
+		t.Error("Expected block to have expired")
+	}
+	if (Block{}).IsExpired(now) {
+		t.Error("Expected permanent block never to expire")
+	}
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// BureauCard is an outgoing card in the order the QSL bureau sorts them
+type BureauCard struct {
+	OutgoingQSL
+	Entity string // DXCC entity the card is routed to
+	Prefix string // Prefix block within the entity, e.g. JA1
+}
+
+// BureauPrefix returns the prefix block a callsign is sorted under: the
+// part deciding its entity up to and including its last digit, so W1AW is
+// sorted under W1 and EA8/W1AW under EA8
+func BureauPrefix(call string) string {
+	part := ctyPrefixPart(strings.ToUpper(strings.TrimSpace(call)))
+	if i := strings.LastIndexAny(part, "0123456789"); i != -1 {
+		return part[:i+1]
+	}
+	return part
+}
+
+// BureauCards returns the outgoing cards to send through the bureau, leaving
+// out those requested direct, sorted by entity, then prefix block, then
+// callsign, the order bureaus want cards bundled in
+func BureauCards(queue []OutgoingQSL) []BureauCard {
+	var cards []BureauCard
+	for _, card := range queue {
+		if card.Request != nil && card.Request.Route == QSLRouteDirect {
+			continue
+		}
+		entity := card.QSO.Entity()
+		if entity == "" {
+			entity = "Unknown"
+		}
+		cards = append(cards, BureauCard{
+			OutgoingQSL: card,
+			Entity:      entity,
+			Prefix:      BureauPrefix(card.QSO.Call),
+		})
+	}
+
+	sort.SliceStable(cards, func(i, j int) bool {
+		a, b := cards[i], cards[j]
+		switch {
+		case a.Entity != b.Entity:
+			return a.Entity < b.Entity
+		case a.Prefix != b.Prefix:
+			return a.Prefix < b.Prefix
+		case a.QSO.Call != b.QSO.Call:
+			return a.QSO.Call < b.QSO.Call
+		}
+		return a.QSO.Timestamp.Before(b.QSO.Timestamp)
+	})
+	return cards
+}
+
+// WriteBureauCSV writes bureau sorted cards as CSV with a header row
+func WriteBureauCSV(w io.Writer, cards []BureauCard) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"entity", "dxcc", "prefix", "call", "date", "time", "band", "mode"})
+	for _, card := range cards {
+		qso := card.QSO
+		cw.Write([]string{
+			card.Entity,
+			qso.DXCC,
+			card.Prefix,
+			qso.Call,
+			qso.Timestamp.UTC().Format("2006-01-02"),
+			qso.Timestamp.UTC().Format("1504"),
+			qso.Band,
+			qso.Mode,
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteBureauPDF writes bureau sorted cards as a printable list, with a
+// heading for each entity
+func WriteBureauPDF(w io.Writer, cards []BureauCard, title string) error {
+	lines := []string{title, ""}
+	for i, card := range cards {
+		if i == 0 || card.Entity != cards[i-1].Entity {
+			count := 0
+			for _, c := range cards[i:] {
+				if c.Entity != card.Entity {
+					break
+				}
+				count++
+			}
+			if i > 0 {
+				lines = append(lines, "")
+			}
+			lines = append(lines, fmt.Sprintf("%s (%d)", card.Entity, count))
+		}
+		qso := card.QSO
+		lines = append(lines, fmt.Sprintf("  [ ] %-6s %-12s %s  %-5s %s",
+			card.Prefix, qso.Call, qso.Timestamp.UTC().Format("2006-01-02 1504"), qso.Band, qso.Mode))
+	}
+	if len(cards) == 0 {
+		lines = append(lines, "No cards to send through the bureau.")
+	}
+	return WriteTextPDF(w, lines, title)
+}
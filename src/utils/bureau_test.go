@@ -0,0 +1,70 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBureauPrefix(t *testing.T) {
+	tests := map[string]string{
+		"W1AW":     "W1",
+		"ja1abc":   "JA1",
+		"A61XX":    "A61",
+		"9K2AB":    "9K2",
+		"EA8/W1AW": "EA8",
+		"G4ABC/P":  "G4",
+	}
+	for call, expected := range tests {
+		if got := BureauPrefix(call); got != expected {
+			t.Errorf("BureauPrefix(%q) = %q, expected %q", call, got, expected)
+		}
+	}
+}
+
+func TestBureauCards(t *testing.T) {
+	day := time.Date(2025, 3, 1, 18, 30, 0, 0, time.UTC)
+	queue := []OutgoingQSL{
+		{QSO: QSO{Call: "JA2XYZ", DXCC: "339", Timestamp: day}},
+		{QSO: QSO{Call: "W1AW", DXCC: "291", Timestamp: day}},
+		{QSO: QSO{Call: "JA1ABC", DXCC: "339", Timestamp: day}},
+		{QSO: QSO{Call: "K2ABC", DXCC: "291", Timestamp: day}, Request: &QSLRequest{Route: QSLRouteDirect}},
+		{QSO: QSO{Call: "A61XX", Country: "United Arab Emirates", Timestamp: day}, Request: &QSLRequest{Route: QSLRouteBureau}},
+	}
+
+	cards := BureauCards(queue)
+	var calls []string
+	for _, card := range cards {
+		calls = append(calls, card.QSO.Call)
+	}
+	if got := strings.Join(calls, " "); got != "JA1ABC JA2XYZ A61XX W1AW" {
+		t.Errorf("Unexpected bureau order %q", got)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteBureauCSV(&buf, cards); err != nil {
+		t.Fatalf("Failed to write CSV: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read CSV: %v", err)
+	}
+	if len(rows) != 5 || rows[1][2] != "JA1" || rows[1][3] != "JA1ABC" {
+		t.Errorf("Unexpected CSV rows %v", rows)
+	}
+
+	buf.Reset()
+	if err := WriteBureauPDF(&buf, cards, "Bureau cards"); err != nil {
+		t.Fatalf("Failed to write PDF: %v", err)
+	}
+	pdf := buf.String()
+	if !strings.HasPrefix(pdf, "%PDF-") || !strings.Contains(pdf, "(Japan \\(2\\))") || !strings.Contains(pdf, "JA1ABC") {
+		t.Error("Expected a PDF listing the cards")
+	}
+}
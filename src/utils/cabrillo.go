@@ -0,0 +1,136 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CabrilloHeader is the station information written at the top of a
+// Cabrillo log. Category and operator lines are left for the entrant to add.
+type CabrilloHeader struct {
+	Contest   string
+	Callsign  string
+	CreatedBy string
+}
+
+// cabrilloBands are the Cabrillo frequency designators for bands above HF,
+// where the exact frequency isn't given
+var cabrilloBands = map[string]string{
+	"6m":     "50",
+	"4m":     "70",
+	"2m":     "144",
+	"1.25m":  "222",
+	"70cm":   "432",
+	"33cm":   "902",
+	"23cm":   "1.2G",
+	"13cm":   "2.3G",
+	"9cm":    "3.4G",
+	"6cm":    "5.7G",
+	"3cm":    "10G",
+	"1.25cm": "24G",
+}
+
+// cabrilloModes maps ADIF modes to Cabrillo modes. Other modes are digital.
+var cabrilloModes = map[string]string{
+	"CW":   "CW",
+	"SSB":  "PH",
+	"USB":  "PH",
+	"LSB":  "PH",
+	"AM":   "PH",
+	"FM":   "FM",
+	"RTTY": "RY",
+}
+
+// CabrilloFrequency returns the Cabrillo frequency field for a QSO: kHz on
+// HF, or the band designator above it. It is empty when neither the frequency
+// nor the band is known.
+func CabrilloFrequency(qso QSO) string {
+	band := strings.ToLower(qso.Band)
+	if mhz, ok := ParseFrequency(qso.Freq); ok {
+		if band == "" {
+			band = BandFromFrequency(mhz)
+		}
+		if designator, ok := cabrilloBands[band]; ok {
+			return designator
+		}
+		return strconv.Itoa(int(mhz*1000 + 0.5))
+	}
+
+	if designator, ok := cabrilloBands[band]; ok {
+		return designator
+	}
+	if b, ok := bandByName(band); ok {
+		return strconv.Itoa(int(b.Lower*1000 + 0.5))
+	}
+	return ""
+}
+
+// CabrilloMode returns the Cabrillo mode for an ADIF mode
+func CabrilloMode(mode string) string {
+	if m, ok := cabrilloModes[strings.ToUpper(mode)]; ok {
+		return m
+	}
+	return "DG"
+}
+
+// cabrilloExchange joins the non-empty parts of an exchange
+func cabrilloExchange(parts ...string) string {
+	var exchange []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			exchange = append(exchange, p)
+		}
+	}
+	return strings.Join(exchange, " ")
+}
+
+// WriteCabrillo writes QSOs as a Cabrillo 3.0 log in time order. The sent
+// exchange is the sent report followed by STX and STX_STRING, and the received
+// exchange likewise from SRX and SRX_STRING.
+func WriteCabrillo(w io.Writer, header CabrilloHeader, qsos []QSO) error {
+	sorted := make([]QSO, len(qsos))
+	copy(sorted, qsos)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "START-OF-LOG: 3.0")
+	if header.CreatedBy != "" {
+		fmt.Fprintf(bw, "CREATED-BY: %s\n", header.CreatedBy)
+	}
+	fmt.Fprintf(bw, "CONTEST: %s\n", header.Contest)
+	fmt.Fprintf(bw, "CALLSIGN: %s\n", header.Callsign)
+
+	for _, qso := range sorted {
+		if qso.Timestamp.IsZero() {
+			continue
+		}
+
+		myCall := qso.StationCall
+		if myCall == "" {
+			myCall = header.Callsign
+		}
+		line := fmt.Sprintf("QSO: %5s %-2s %s %s %-13s %-10s %-13s %s",
+			CabrilloFrequency(qso),
+			CabrilloMode(qso.Mode),
+			qso.Timestamp.UTC().Format("2006-01-02"),
+			qso.Timestamp.UTC().Format("1504"),
+			strings.ToUpper(myCall),
+			cabrilloExchange(qso.RSTSent, qso.STX, qso.STXString),
+			qso.Call,
+			cabrilloExchange(qso.RSTRcvd, qso.SRX, qso.SRXString))
+		fmt.Fprintln(bw, strings.TrimRight(line, " "))
+	}
+
+	fmt.Fprintln(bw, "END-OF-LOG:")
+	return bw.Flush()
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCabrilloFrequency(t *testing.T) {
+	tests := []struct {
+		qso  QSO
+		want string
+	}{
+		{QSO{Freq: "14.0255", Band: "20m"}, "14026"},
+		{QSO{Freq: "7.074"}, "7074"},
+		{QSO{Band: "40m"}, "7000"},
+		{QSO{Freq: "144.300", Band: "2m"}, "144"},
+		{QSO{Band: "23cm"}, "1.2G"},
+		{QSO{}, ""},
+	}
+	for _, tt := range tests {
+		if got := CabrilloFrequency(tt.qso); got != tt.want {
+			t.Errorf("CabrilloFrequency(%+v) = %q, want %q", tt.qso, got, tt.want)
+		}
+	}
+}
+
+func TestWriteCabrillo(t *testing.T) {
+	start := time.Date(2024, 10, 26, 0, 5, 0, 0, time.UTC)
+	qsos := []QSO{
+		{Call: "JA1ABC", Freq: "14.2", Mode: "SSB", RSTSent: "59", STXString: "21", RSTRcvd: "59", SRXString: "25", Timestamp: start.Add(time.Hour)},
+		{Call: "DL1ABC", Freq: "7.0123", Mode: "CW", RSTSent: "599", STX: "1", RSTRcvd: "599", SRX: "42", StationCall: "A66H", Timestamp: start},
+	}
+
+	var b strings.Builder
+	if err := WriteCabrillo(&b, CabrilloHeader{Contest: "CQ-WW-SSB", Callsign: "A66H", CreatedBy: "test"}, qsos); err != nil {
+		t.Fatalf("WriteCabrillo: %v", err)
+	}
+
+	want := `START-OF-LOG: 3.0
+CREATED-BY: test
+CONTEST: CQ-WW-SSB
+CALLSIGN: A66H
+QSO:  7012 CW 2024-10-26 0005 A66H          599 1      DL1ABC        599 42
+QSO: 14200 PH 2024-10-26 0105 A66H          59 21      JA1ABC        59 25
+END-OF-LOG:
+`
+	if b.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
@@ -0,0 +1,353 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// callbookRetryAfter is how long a callsign the callbook didn't have a grid
+// for is left before asking again
+const callbookRetryAfter = 30 * 24 * time.Hour
+
+// errCallbookNotFound is returned by callbook services for unknown callsigns
+var errCallbookNotFound = errors.New("callsign not found")
+
+// errCallbookSession is returned by callbook services when the session key
+// has expired, to log in again
+var errCallbookSession = errors.New("session expired")
+
+// callbookService is a callbook XML API, QRZ.com or HamQTH
+type callbookService interface {
+	// login returns a new session key
+	login(ctx context.Context) (string, error)
+	// grid returns the grid square listed for a callsign
+	grid(ctx context.Context, session, call string) (string, error)
+}
+
+// CallbookEntry is a cached callbook result. An entry without a grid records
+// that the callbook didn't list one.
+type CallbookEntry struct {
+	Grid    string    `json:"grid,omitempty"`
+	Fetched time.Time `json:"fetched"`
+}
+
+// Callbook fills in the grid of QSOs logged without one from a callbook,
+// caching results in a JSON file so each callsign is looked up once. A nil
+// Callbook fills in nothing.
+type Callbook struct {
+	path    string
+	service callbookService
+	client  *http.Client
+	session string
+
+	mutex   sync.Mutex
+	entries map[string]CallbookEntry
+	queue   []string
+	queued  map[string]bool
+	wake    chan struct{}
+}
+
+// NewCallbook creates a callbook for the named service, "qrz" or "hamqth",
+// loading the cache at path
+func NewCallbook(path, service, username, password string) (*Callbook, error) {
+	c := &Callbook{
+		path:    path,
+		client:  &http.Client{Timeout: 30 * time.Second},
+		entries: make(map[string]CallbookEntry),
+		queued:  make(map[string]bool),
+		wake:    make(chan struct{}, 1),
+	}
+	switch strings.ToLower(strings.TrimSpace(service)) {
+	case "qrz":
+		c.service = &qrzCallbook{url: "https://xmldata.qrz.com/xml/current/", username: username, password: password, client: c.client}
+	case "hamqth":
+		c.service = &hamQTHCallbook{url: "https://www.hamqth.com/xml.php", username: username, password: password, client: c.client}
+	default:
+		return nil, fmt.Errorf("unknown callbook %q (expected qrz or hamqth)", service)
+	}
+
+	if err := loadJSONFile(path, &c.entries); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Apply returns the QSOs with the cached grid filled in for those logged
+// without one, marked as enriched. Callsigns not cached yet are queued for
+// lookup. The input slice is not modified.
+func (c *Callbook) Apply(qsos []QSO) []QSO {
+	if c == nil {
+		return qsos
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	queued := false
+	enriched := make([]QSO, len(qsos))
+	for i, qso := range qsos {
+		if qso.GridSquare == "" && qso.Call != "" {
+			call := strings.ToUpper(qso.Call)
+			entry, ok := c.entries[call]
+			switch {
+			case ok && entry.Grid != "":
+				qso.GridSquare = entry.Grid
+				qso.GridEnriched = true
+			case (!ok || time.Since(entry.Fetched) > callbookRetryAfter) && !c.queued[call]:
+				c.queue = append(c.queue, call)
+				c.queued[call] = true
+				queued = true
+			}
+		}
+		enriched[i] = qso
+	}
+
+	if queued {
+		select {
+		case c.wake <- struct{}{}:
+		default:
+		}
+	}
+	return enriched
+}
+
+// StartFetching starts the lookup goroutine, which looks up queued callsigns
+// one at a time at most every interval and calls onChange once the queue
+// is drained, if any grids were found
+func (c *Callbook) StartFetching(interval time.Duration, onChange func()) {
+	go func() {
+		found := false
+		for {
+			call, ok := c.next()
+			if !ok {
+				if found && onChange != nil {
+					onChange()
+				}
+				found = false
+				<-c.wake
+				continue
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			grid, err := c.lookup(ctx, call)
+			cancel()
+			if err != nil && !errors.Is(err, errCallbookNotFound) {
+				// Likely to fail for the rest too, so they wait for the
+				// log to be published again
+				log.Printf("Failed to look up %s in the callbook: %v", call, err)
+				c.clear()
+			} else {
+				c.done(call, CallbookEntry{Grid: grid, Fetched: time.Now().UTC()})
+				found = found || grid != ""
+			}
+
+			time.Sleep(interval)
+		}
+	}()
+}
+
+// clear empties the queue, so the callsigns are queued again when the log
+// is next published
+func (c *Callbook) clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.queue = nil
+	c.queued = make(map[string]bool)
+}
+
+// next returns the next queued callsign
+func (c *Callbook) next() (string, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if len(c.queue) == 0 {
+		return "", false
+	}
+	return c.queue[0], true
+}
+
+// done removes a looked up callsign from the queue and caches the result
+func (c *Callbook) done(call string, entry CallbookEntry) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.queue = c.queue[1:]
+	delete(c.queued, call)
+	c.entries[call] = entry
+	if err := saveJSONFile(c.path, c.entries, 0644); err != nil {
+		log.Printf("Failed to save callbook cache: %v", err)
+	}
+}
+
+// lookup returns the callbook grid of a callsign, logging in first if
+// there is no session yet or it has expired. Invalid grids are dropped.
+func (c *Callbook) lookup(ctx context.Context, call string) (string, error) {
+	for attempt := 0; ; attempt++ {
+		if c.session == "" {
+			session, err := c.service.login(ctx)
+			if err != nil {
+				return "", err
+			}
+			c.session = session
+		}
+
+		grid, err := c.service.grid(ctx, c.session, call)
+		if errors.Is(err, errCallbookSession) && attempt == 0 {
+			c.session = ""
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		grid = strings.TrimSpace(grid)
+		if ValidateGridSquare(grid) != nil {
+			return "", nil
+		}
+		return strings.ToUpper(grid), nil
+	}
+}
+
+// callbookGet fetches a callbook XML API URL and decodes the response
+func callbookGet(ctx context.Context, client *http.Client, rawURL string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create callbook request: %w", err)
+	}
+	req.Header.Set("User-Agent", "humaid-qsl")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to query callbook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("callbook returned status %d", resp.StatusCode)
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode callbook response: %w", err)
+	}
+	return nil
+}
+
+// qrzCallbook is the QRZ.com XML data service, which needs a subscription
+// to return grids
+type qrzCallbook struct {
+	url      string
+	username string
+	password string
+	client   *http.Client
+}
+
+type qrzResponse struct {
+	Callsign struct {
+		Grid string `xml:"grid"`
+	} `xml:"Callsign"`
+	Session struct {
+		Key   string `xml:"Key"`
+		Error string `xml:"Error"`
+	} `xml:"Session"`
+}
+
+func (q *qrzCallbook) login(ctx context.Context) (string, error) {
+	params := url.Values{}
+	params.Set("username", q.username)
+	params.Set("password", q.password)
+	params.Set("agent", "humaid-qsl")
+
+	var resp qrzResponse
+	if err := callbookGet(ctx, q.client, q.url+"?"+params.Encode(), &resp); err != nil {
+		return "", err
+	}
+	if resp.Session.Key == "" {
+		return "", fmt.Errorf("QRZ.com login failed: %s", resp.Session.Error)
+	}
+	return resp.Session.Key, nil
+}
+
+func (q *qrzCallbook) grid(ctx context.Context, session, call string) (string, error) {
+	params := url.Values{}
+	params.Set("s", session)
+	params.Set("callsign", call)
+
+	var resp qrzResponse
+	if err := callbookGet(ctx, q.client, q.url+"?"+params.Encode(), &resp); err != nil {
+		return "", err
+	}
+	switch {
+	case strings.HasPrefix(resp.Session.Error, "Not found"):
+		return "", errCallbookNotFound
+	case resp.Session.Key == "":
+		return "", errCallbookSession
+	case resp.Session.Error != "":
+		return "", fmt.Errorf("QRZ.com lookup failed: %s", resp.Session.Error)
+	}
+	return resp.Callsign.Grid, nil
+}
+
+// hamQTHCallbook is the free HamQTH.com XML service
+type hamQTHCallbook struct {
+	url      string
+	username string
+	password string
+	client   *http.Client
+}
+
+type hamQTHResponse struct {
+	Session struct {
+		ID    string `xml:"session_id"`
+		Error string `xml:"error"`
+	} `xml:"session"`
+	Search struct {
+		Grid string `xml:"grid"`
+	} `xml:"search"`
+}
+
+func (h *hamQTHCallbook) login(ctx context.Context) (string, error) {
+	params := url.Values{}
+	params.Set("u", h.username)
+	params.Set("p", h.password)
+
+	var resp hamQTHResponse
+	if err := callbookGet(ctx, h.client, h.url+"?"+params.Encode(), &resp); err != nil {
+		return "", err
+	}
+	if resp.Session.ID == "" {
+		return "", fmt.Errorf("HamQTH login failed: %s", resp.Session.Error)
+	}
+	return resp.Session.ID, nil
+}
+
+func (h *hamQTHCallbook) grid(ctx context.Context, session, call string) (string, error) {
+	params := url.Values{}
+	params.Set("id", session)
+	params.Set("callsign", call)
+	params.Set("prg", "humaid-qsl")
+
+	var resp hamQTHResponse
+	if err := callbookGet(ctx, h.client, h.url+"?"+params.Encode(), &resp); err != nil {
+		return "", err
+	}
+	switch {
+	case resp.Session.Error == "":
+		return resp.Search.Grid, nil
+	case strings.Contains(resp.Session.Error, "not found"):
+		return "", errCallbookNotFound
+	case strings.Contains(resp.Session.Error, "Session"):
+		return "", errCallbookSession
+	}
+	return "", fmt.Errorf("HamQTH lookup failed: %s", resp.Session.Error)
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestCallbookQRZ(t *testing.T) {
+	logins := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		w.Header().Set("Content-Type", "text/xml")
+		switch {
+		case q.Get("username") != "":
+			logins++
+			if q.Get("password") != "secret" {
+				fmt.Fprint(w, `<QRZDatabase><Session><Error>Username/password incorrect</Error></Session></QRZDatabase>`)
+				return
+			}
+			fmt.Fprintf(w, `<QRZDatabase xmlns="http://xmldata.qrz.com"><Session><Key>key%d</Key></Session></QRZDatabase>`, logins)
+		case q.Get("s") != fmt.Sprintf("key%d", logins) || logins == 1:
+			// The first session has expired
+			fmt.Fprint(w, `<QRZDatabase><Session><Error>Session Timeout</Error></Session></QRZDatabase>`)
+		case q.Get("callsign") == "W1AW":
+			fmt.Fprint(w, `<QRZDatabase xmlns="http://xmldata.qrz.com"><Callsign><call>W1AW</call><grid>fn31pr</grid></Callsign><Session><Key>key2</Key></Session></QRZDatabase>`)
+		default:
+			fmt.Fprintf(w, `<QRZDatabase><Session><Key>key2</Key><Error>Not found: %s</Error></Session></QRZDatabase>`, q.Get("callsign"))
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewCallbook(filepath.Join(t.TempDir(), "callbook.json"), "qrz", "user", "secret")
+	if err != nil {
+		t.Fatalf("NewCallbook: %v", err)
+	}
+	c.service.(*qrzCallbook).url = srv.URL
+
+	grid, err := c.lookup(context.Background(), "W1AW")
+	if err != nil || grid != "FN31PR" {
+		t.Errorf("lookup(W1AW) = %q, %v, want FN31PR", grid, err)
+	}
+	if logins != 2 {
+		t.Errorf("Expected an expired session to log in again, got %d logins", logins)
+	}
+	if _, err := c.lookup(context.Background(), "K1ABC"); err != errCallbookNotFound {
+		t.Errorf("Expected an unknown callsign to be not found, got %v", err)
+	}
+
+	c.session = ""
+	c.service.(*qrzCallbook).password = "wrong"
+	if _, err := c.lookup(context.Background(), "W1AW"); err == nil {
+		t.Error("Expected a failed login to fail the lookup")
+	}
+}
+
+func TestCallbookHamQTH(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch {
+		case q.Get("u") != "":
+			fmt.Fprint(w, `<HamQTH version="2.8" xmlns="https://www.hamqth.com"><session><session_id>abc</session_id></session></HamQTH>`)
+		case q.Get("callsign") == "DL1ABC":
+			fmt.Fprint(w, `<HamQTH version="2.8" xmlns="https://www.hamqth.com"><search><callsign>DL1ABC</callsign><grid>JO62</grid></search></HamQTH>`)
+		case q.Get("callsign") == "DL2ABC":
+			fmt.Fprint(w, `<HamQTH><search><callsign>DL2ABC</callsign><grid>somewhere</grid></search></HamQTH>`)
+		default:
+			fmt.Fprint(w, `<HamQTH><session><error>Callsign not found</error></session></HamQTH>`)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewCallbook(filepath.Join(t.TempDir(), "callbook.json"), "HamQTH", "user", "secret")
+	if err != nil {
+		t.Fatalf("NewCallbook: %v", err)
+	}
+	c.service.(*hamQTHCallbook).url = srv.URL
+
+	for call, want := range map[string]string{"DL1ABC": "JO62", "DL2ABC": ""} {
+		if grid, err := c.lookup(context.Background(), call); err != nil || grid != want {
+			t.Errorf("lookup(%s) = %q, %v, want %q", call, grid, err, want)
+		}
+	}
+	if _, err := c.lookup(context.Background(), "K1ABC"); err != errCallbookNotFound {
+		t.Errorf("Expected an unknown callsign to be not found, got %v", err)
+	}
+
+	if _, err := NewCallbook(filepath.Join(t.TempDir(), "callbook.json"), "clublog", "", ""); err == nil {
+		t.Error("Expected an unknown callbook to be rejected")
+	}
+}
+
+func TestCallbookApply(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "callbook.json")
+	c, err := NewCallbook(path, "hamqth", "user", "secret")
+	if err != nil {
+		t.Fatalf("NewCallbook: %v", err)
+	}
+
+	qsos := []QSO{{Call: "W1AW"}, {Call: "K1ABC", GridSquare: "FN42"}, {Call: "w1aw"}}
+	applied := c.Apply(qsos)
+	if applied[0].GridSquare != "" || applied[0].GridEnriched {
+		t.Errorf("Expected nothing to be filled in before lookup, got %+v", applied[0])
+	}
+	if len(c.queue) != 1 || c.queue[0] != "W1AW" {
+		t.Fatalf("Expected W1AW to be queued once, got %v", c.queue)
+	}
+
+	call, _ := c.next()
+	c.done(call, CallbookEntry{Grid: "FN31PR"})
+	applied = c.Apply(qsos)
+	if applied[0].GridSquare != "FN31PR" || !applied[0].GridEnriched || applied[2].GridSquare != "FN31PR" {
+		t.Errorf("Expected the cached grid to be filled in, got %+v", applied)
+	}
+	if applied[1].GridSquare != "FN42" || applied[1].GridEnriched {
+		t.Errorf("Expected a logged grid to be kept, got %+v", applied[1])
+	}
+	if qsos[0].GridSquare != "" {
+		t.Error("Apply modified its input")
+	}
+	if len(c.queue) != 0 {
+		t.Errorf("Expected nothing to be queued, got %v", c.queue)
+	}
+
+	// The cache is reloaded from disk
+	c, err = NewCallbook(path, "hamqth", "user", "secret")
+	if err != nil {
+		t.Fatalf("NewCallbook: %v", err)
+	}
+	if applied := c.Apply(qsos); applied[0].GridSquare != "FN31PR" {
+		t.Errorf("Expected the cache to be saved, got %+v", applied[0])
+	}
+
+	var none *Callbook
+	if applied := none.Apply(qsos); applied[0].GridSquare != "" {
+		t.Error("Expected a nil Callbook to fill in nothing")
+	}
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrCaptchaMissing is returned when a form was submitted without solving
+// the CAPTCHA
+var ErrCaptchaMissing = errors.New("no CAPTCHA response")
+
+// captchaProvider describes a CAPTCHA service's widget and verification API
+type captchaProvider struct {
+	scriptURL     string
+	widgetClass   string
+	responseField string
+	verifyURL     string
+}
+
+// captchaProviders are the supported CAPTCHA services by name
+var captchaProviders = map[string]captchaProvider{
+	"turnstile": {
+		scriptURL:     "https://challenges.cloudflare.com/turnstile/v0/api.js",
+		widgetClass:   "cf-turnstile",
+		responseField: "cf-turnstile-response",
+		verifyURL:     "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+	},
+	"hcaptcha": {
+		scriptURL:     "https://js.hcaptcha.com/1/api.js",
+		widgetClass:   "h-captcha",
+		responseField: "h-captcha-response",
+		verifyURL:     "https://api.hcaptcha.com/siteverify",
+	},
+}
+
+// CaptchaVerifier checks CAPTCHA responses with Cloudflare Turnstile or
+// hCaptcha
+type CaptchaVerifier struct {
+	provider captchaProvider
+	siteKey  string
+	secret   string
+	client   *http.Client
+}
+
+// NewCaptchaVerifier creates a verifier for the named provider, "turnstile"
+// or "hcaptcha"
+func NewCaptchaVerifier(provider, siteKey, secret string) (*CaptchaVerifier, error) {
+	p, ok := captchaProviders[strings.ToLower(strings.TrimSpace(provider))]
+	if !ok {
+		return nil, fmt.Errorf("unknown CAPTCHA provider %q (expected turnstile or hcaptcha)", provider)
+	}
+	if siteKey == "" || secret == "" {
+		return nil, fmt.Errorf("CAPTCHA provider %s needs a site key and secret", provider)
+	}
+
+	return &CaptchaVerifier{
+		provider: p,
+		siteKey:  siteKey,
+		secret:   secret,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// SiteKey returns the public key embedded in the widget
+func (v *CaptchaVerifier) SiteKey() string { return v.siteKey }
+
+// ScriptURL returns the provider's widget script
+func (v *CaptchaVerifier) ScriptURL() string { return v.provider.scriptURL }
+
+// WidgetClass returns the class of the element the widget is rendered in
+func (v *CaptchaVerifier) WidgetClass() string { return v.provider.widgetClass }
+
+// ResponseField returns the form field the widget submits its response in
+func (v *CaptchaVerifier) ResponseField() string { return v.provider.responseField }
+
+// Verify checks a widget response with the provider
+func (v *CaptchaVerifier) Verify(ctx context.Context, response, remoteIP string) error {
+	if response == "" {
+		return ErrCaptchaMissing
+	}
+
+	form := url.Values{
+		"secret":   {v.secret},
+		"response": {response},
+	}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.provider.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create CAPTCHA request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to verify CAPTCHA: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("CAPTCHA verification returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Success    bool     `json:"success"`
+		ErrorCodes []string `json:"error-codes"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode CAPTCHA verification: %w", err)
+	}
+	if !result.Success {
+		return fmt.Errorf("CAPTCHA was not solved: %s", strings.Join(result.ErrorCodes, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewCaptchaVerifier(t *testing.T) {
+	if _, err := NewCaptchaVerifier("recaptcha", "site", "secret"); err == nil {
+		t.Errorf("unknown provider accepted")
+	}
+	if _, err := NewCaptchaVerifier("turnstile", "", "secret"); err == nil {
+		t.Errorf("missing site key accepted")
+	}
+	v, err := NewCaptchaVerifier("hCaptcha", "site", "secret")
+	if err != nil {
+		t.Fatalf("NewCaptchaVerifier: %v", err)
+	}
+	if v.ResponseField() != "h-captcha-response" || v.WidgetClass() != "h-captcha" || v.SiteKey() != "site" {
+		t.Errorf("unexpected hCaptcha settings: %+v", v)
+	}
+}
+
+func TestCaptchaVerify(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("secret") != "secret" || r.FormValue("remoteip") != "192.0.2.1" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if r.FormValue("response") == "good" {
+			fmt.Fprint(w, `{"success": true}`)
+			return
+		}
+		fmt.Fprint(w, `{"success": false, "error-codes": ["invalid-input-response"]}`)
+	}))
+	defer srv.Close()
+
+	v, err := NewCaptchaVerifier("turnstile", "site", "secret")
+	if err != nil {
+		t.Fatalf("NewCaptchaVerifier: %v", err)
+	}
+	v.provider.verifyURL = srv.URL
+
+	ctx := context.Background()
+	if err := v.Verify(ctx, "good", "192.0.2.1"); err != nil {
+		t.Errorf("Verify(good) = %v", err)
+	}
+	if err := v.Verify(ctx, "bad", "192.0.2.1"); err == nil {
+		t.Errorf("Verify(bad) succeeded")
+	}
+	if err := v.Verify(ctx, "", "192.0.2.1"); !errors.Is(err, ErrCaptchaMissing) {
+		t.Errorf("Verify with no response = %v, want ErrCaptchaMissing", err)
+	}
+	if err := v.Verify(ctx, "good", "198.51.100.1"); err == nil {
+		t.Errorf("Verify succeeded despite an error status")
+	}
+}
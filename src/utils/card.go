@@ -0,0 +1,167 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"sync"
+
+	"github.com/fogleman/gg"
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// Size of confirmation cards, the usual size for social share images
+const (
+	CardWidth  = 1200
+	CardHeight = 630
+)
+
+// ConfirmationCard is the content of a QSO confirmation share image
+type ConfirmationCard struct {
+	MyCall      string
+	Call        string
+	Country     string
+	CountryCode string      // ISO country code shown beside the country (optional)
+	Details     string      // Band, mode and frequency
+	When        string      // Date and time of the QSO
+	Footer      string      // Distance or site address (optional)
+	Map         image.Image // Grid square map, ideally 600x400 (optional)
+}
+
+// cardFonts holds the parsed fonts, which are loaded on first use
+var cardFonts = sync.OnceValues(func() ([2]*truetype.Font, error) {
+	regular, err := truetype.Parse(goregular.TTF)
+	if err != nil {
+		return [2]*truetype.Font{}, fmt.Errorf("failed to parse regular font: %w", err)
+	}
+	bold, err := truetype.Parse(gobold.TTF)
+	if err != nil {
+		return [2]*truetype.Font{}, fmt.Errorf("failed to parse bold font: %w", err)
+	}
+	return [2]*truetype.Font{regular, bold}, nil
+})
+
+// CreateConfirmationCard draws a share image for a QSO and writes it as a
+// PNG. The image is written to a temporary file first so a partly written
+// card is never served.
+func CreateConfirmationCard(card ConfirmationCard, outputPath string) error {
+	img, err := DrawConfirmationCard(card)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := outputPath + ".tmp"
+	if err := saveImage(img, tmpPath); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, outputPath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to save card: %w", err)
+	}
+	return nil
+}
+
+// DrawConfirmationCard draws the share image for a QSO
+func DrawConfirmationCard(card ConfirmationCard) (image.Image, error) {
+	fonts, err := cardFonts()
+	if err != nil {
+		return nil, err
+	}
+	regular, bold := fonts[0], fonts[1]
+	face := func(f *truetype.Font, size float64) font.Face {
+		return truetype.NewFace(f, &truetype.Options{Size: size})
+	}
+
+	dc := gg.NewContext(CardWidth, CardHeight)
+	// fit sets the largest font size up to size at which s fits in maxWidth,
+	// so long portable callsigns don't overflow
+	fit := func(f *truetype.Font, s string, size, maxWidth float64) {
+		for ; size > 24; size -= 4 {
+			dc.SetFontFace(face(f, size))
+			if w, _ := dc.MeasureString(s); w <= maxWidth {
+				return
+			}
+		}
+		dc.SetFontFace(face(f, size))
+	}
+
+	dc.SetHexColor("#f4f6fb")
+	dc.Clear()
+
+	// Header bar
+	dc.SetHexColor("#134dae")
+	dc.DrawRectangle(0, 0, CardWidth, 90)
+	dc.Fill()
+	dc.SetHexColor("#ffffff")
+	dc.SetFontFace(face(bold, 36))
+	dc.DrawStringAnchored("QSO Confirmation", 60, 45, 0, 0.35)
+	dc.SetFontFace(face(regular, 30))
+	dc.DrawStringAnchored(card.MyCall, CardWidth-60, 45, 1, 0.35)
+
+	// Callsigns and QSO details on the left
+	dc.SetHexColor("#555555")
+	dc.SetFontFace(face(regular, 30))
+	dc.DrawString(card.MyCall+" worked", 60, 170)
+
+	dc.SetHexColor("#222222")
+	fit(bold, card.Call, 80, 460)
+	dc.DrawString(card.Call, 56, 255)
+
+	y := 300.0
+	if card.Country != "" {
+		dc.SetFontFace(face(bold, 24))
+		x := 60.0
+		if card.CountryCode != "" {
+			w, _ := dc.MeasureString(card.CountryCode)
+			dc.SetHexColor("#134dae")
+			dc.DrawRoundedRectangle(x, y, w+24, 40, 8)
+			dc.Fill()
+			dc.SetHexColor("#ffffff")
+			dc.DrawStringAnchored(card.CountryCode, x+12, y+20, 0, 0.35)
+			x += w + 40
+		}
+		dc.SetHexColor("#222222")
+		fit(regular, card.Country, 30, 480-(x-60))
+		dc.DrawStringAnchored(card.Country, x, y+20, 0, 0.35)
+		y += 70
+	}
+
+	dc.SetHexColor("#333333")
+	dc.SetFontFace(face(regular, 32))
+	if card.Details != "" {
+		dc.DrawString(card.Details, 60, y+30)
+		y += 55
+	}
+	dc.DrawString(card.When, 60, y+30)
+
+	if card.Footer != "" {
+		dc.SetHexColor("#666666")
+		dc.SetFontFace(face(regular, 26))
+		dc.DrawString(card.Footer, 60, CardHeight-50)
+	}
+
+	// Map on the right, or a plain panel when the grids are unknown
+	const mapX, mapY, mapW, mapH = 560, 150, 600, 400
+	dc.SetHexColor("#d0d7e6")
+	dc.DrawRectangle(mapX-4, mapY-4, mapW+8, mapH+8)
+	dc.Fill()
+	if card.Map != nil {
+		dc.DrawImage(card.Map, mapX, mapY)
+	} else {
+		dc.SetHexColor("#e8ecf5")
+		dc.DrawRectangle(mapX, mapY, mapW, mapH)
+		dc.Fill()
+		dc.SetHexColor("#134dae")
+		fit(bold, card.Call, 120, mapW-80)
+		dc.DrawStringAnchored(card.Call, mapX+mapW/2, mapY+mapH/2, 0.5, 0.35)
+	}
+
+	return dc.Image(), nil
+}
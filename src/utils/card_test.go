@@ -0,0 +1,43 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCreateConfirmationCard(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "card.png")
+	card := ConfirmationCard{
+		MyCall:      "A66H",
+		Call:        "VP2V/W1AW/MM",
+		Country:     "British Virgin Islands",
+		CountryCode: "VG",
+		Details:     "20m · FT8 · 14.074 MHz",
+		When:        "2025-03-01 12:30 UTC",
+	}
+	if err := CreateConfirmationCard(card, path); err != nil {
+		t.Fatalf("CreateConfirmationCard: %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("card not written: %v", err)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatalf("card is not a PNG: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != CardWidth || b.Dy() != CardHeight {
+		t.Errorf("card size = %dx%d, want %dx%d", b.Dx(), b.Dy(), CardWidth, CardHeight)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary file left behind: %v", err)
+	}
+}
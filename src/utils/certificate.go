@@ -0,0 +1,193 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fogleman/gg"
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+)
+
+// Size of certificate pages, A4 landscape in points
+const (
+	CertificatePageWidth  = 841.89
+	CertificatePageHeight = 595.28
+)
+
+// Size of the drawn certificate, about 200 dpi on A4
+const (
+	certificateWidth  = 2339
+	certificateHeight = 1654
+)
+
+// Milestones that earn a certificate
+var (
+	certificateEntityMilestones = []int{100, 150, 200, 250, 300}
+	certificateYearMilestones   = []int{1000, 2500, 5000, 10000}
+)
+
+// Certificate is a self-issued certificate for a milestone reached in the log
+type Certificate struct {
+	ID     string    // e.g. dxcc-100 or qsos-2025-1000
+	Title  string    // e.g. 100 DXCC Entities
+	Detail string    // What was achieved, e.g. "for working 100 DXCC entities"
+	Earned time.Time // Time of the QSO that reached the milestone
+}
+
+// CertificateBranding is the operator's details printed on certificates
+type CertificateBranding struct {
+	Callsign string
+	Operator string      // Operator's name (optional)
+	Site     string      // Web address shown in the footer (optional)
+	Logo     image.Image // Shown at the top, ideally about 300px tall (optional)
+}
+
+// EarnedCertificates returns the certificates earned by the QSOs in a log,
+// most recently earned first. DXCC milestones count entities worked, and
+// yearly milestones count QSOs made in a calendar year in UTC.
+func EarnedCertificates(qsos []QSO) []Certificate {
+	sorted := make([]QSO, 0, len(qsos))
+	for _, qso := range qsos {
+		if !qso.Timestamp.IsZero() {
+			sorted = append(sorted, qso)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	var certs []Certificate
+	entities := make(map[string]bool)
+	years := make(map[int]int)
+	for _, qso := range sorted {
+		if entity := qso.Entity(); entity != "" && !entities[entity] {
+			entities[entity] = true
+			for _, n := range certificateEntityMilestones {
+				if len(entities) == n {
+					certs = append(certs, Certificate{
+						ID:     "dxcc-" + strconv.Itoa(n),
+						Title:  fmt.Sprintf("%d DXCC Entities", n),
+						Detail: fmt.Sprintf("for working %d DXCC entities", n),
+						Earned: qso.Timestamp,
+					})
+				}
+			}
+		}
+
+		year := qso.Timestamp.UTC().Year()
+		years[year]++
+		for _, n := range certificateYearMilestones {
+			if years[year] == n {
+				count := humanize.Comma(int64(n))
+				certs = append(certs, Certificate{
+					ID:     fmt.Sprintf("qsos-%d-%d", year, n),
+					Title:  fmt.Sprintf("%s QSOs in %d", count, year),
+					Detail: fmt.Sprintf("for making %s QSOs in %d", count, year),
+					Earned: qso.Timestamp,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(certs, func(i, j int) bool {
+		return certs[i].Earned.After(certs[j].Earned)
+	})
+	return certs
+}
+
+// FindCertificate returns an earned certificate by its ID
+func FindCertificate(qsos []QSO, id string) (Certificate, bool) {
+	for _, cert := range EarnedCertificates(qsos) {
+		if cert.ID == id {
+			return cert, true
+		}
+	}
+	return Certificate{}, false
+}
+
+// DrawCertificate draws a certificate page
+func DrawCertificate(cert Certificate, branding CertificateBranding) (image.Image, error) {
+	fonts, err := cardFonts()
+	if err != nil {
+		return nil, err
+	}
+	regular, bold := fonts[0], fonts[1]
+	face := func(f *truetype.Font, size float64) font.Face {
+		return truetype.NewFace(f, &truetype.Options{Size: size})
+	}
+
+	const w, h = certificateWidth, certificateHeight
+	dc := gg.NewContext(w, h)
+	dc.SetHexColor("#fdfcf7")
+	dc.Clear()
+
+	// Double border
+	dc.SetHexColor("#134dae")
+	dc.SetLineWidth(16)
+	dc.DrawRectangle(60, 60, w-120, h-120)
+	dc.Stroke()
+	dc.SetLineWidth(4)
+	dc.DrawRectangle(100, 100, w-200, h-200)
+	dc.Stroke()
+
+	y := 300.0
+	if branding.Logo != nil {
+		dc.DrawImageAnchored(branding.Logo, w/2, 260, 0.5, 0.5)
+		y = 470
+	}
+
+	dc.SetHexColor("#134dae")
+	dc.SetFontFace(face(bold, 110))
+	dc.DrawStringAnchored("Certificate of Achievement", w/2, y, 0.5, 0.35)
+
+	dc.SetHexColor("#555555")
+	dc.SetFontFace(face(regular, 54))
+	dc.DrawStringAnchored("This certificate is presented to", w/2, y+170, 0.5, 0.35)
+
+	dc.SetHexColor("#222222")
+	dc.SetFontFace(face(bold, 150))
+	dc.DrawStringAnchored(branding.Callsign, w/2, y+340, 0.5, 0.35)
+	if branding.Operator != "" {
+		dc.SetHexColor("#444444")
+		dc.SetFontFace(face(regular, 64))
+		dc.DrawStringAnchored(branding.Operator, w/2, y+470, 0.5, 0.35)
+	}
+
+	dc.SetHexColor("#134dae")
+	dc.SetFontFace(face(bold, 96))
+	dc.DrawStringAnchored(cert.Title, w/2, y+640, 0.5, 0.35)
+	dc.SetHexColor("#444444")
+	dc.SetFontFace(face(regular, 54))
+	dc.DrawStringAnchored(cert.Detail, w/2, y+760, 0.5, 0.35)
+
+	dc.SetHexColor("#666666")
+	dc.SetFontFace(face(regular, 44))
+	dc.DrawStringAnchored("Achieved "+cert.Earned.UTC().Format("2 January 2006"), 220, h-220, 0, 0.35)
+	if branding.Site != "" {
+		dc.DrawStringAnchored(branding.Site, w-220, h-220, 1, 0.35)
+	}
+	dc.SetFontFace(face(regular, 32))
+	dc.DrawStringAnchored("Self-issued from the station log", w/2, h-160, 0.5, 0.35)
+
+	return dc.Image(), nil
+}
+
+// WriteCertificatePDF draws a certificate and writes it as an A4 landscape PDF
+func WriteCertificatePDF(w io.Writer, cert Certificate, branding CertificateBranding) error {
+	img, err := DrawCertificate(cert, branding)
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("%s - %s", branding.Callsign, cert.Title)
+	return WriteImagePDF(w, img, title, CertificatePageWidth, CertificatePageHeight)
+}
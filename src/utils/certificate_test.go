@@ -0,0 +1,57 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestEarnedCertificates(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var qsos []QSO
+	for i := 0; i < 1000; i++ {
+		qsos = append(qsos, QSO{
+			Call:      fmt.Sprintf("W%dAW", i),
+			Country:   fmt.Sprintf("Country %d", i%120),
+			Timestamp: start.Add(time.Duration(i) * time.Hour),
+		})
+	}
+
+	certs := EarnedCertificates(qsos)
+	if len(certs) != 2 {
+		t.Fatalf("got %d certificates, want 2: %+v", len(certs), certs)
+	}
+	if certs[0].ID != "qsos-2025-1000" || certs[0].Title != "1,000 QSOs in 2025" {
+		t.Errorf("unexpected first certificate %+v", certs[0])
+	}
+	if certs[1].ID != "dxcc-100" || !certs[1].Earned.Equal(start.Add(99*time.Hour)) {
+		t.Errorf("unexpected second certificate %+v", certs[1])
+	}
+
+	if _, ok := FindCertificate(qsos, "dxcc-150"); ok {
+		t.Error("dxcc-150 found without 150 entities")
+	}
+}
+
+func TestWriteCertificatePDF(t *testing.T) {
+	cert := Certificate{ID: "dxcc-100", Title: "100 DXCC Entities", Detail: "for working 100 DXCC entities", Earned: time.Now()}
+	var buf bytes.Buffer
+	if err := WriteCertificatePDF(&buf, cert, CertificateBranding{Callsign: "A66H"}); err != nil {
+		t.Fatalf("WriteCertificatePDF: %v", err)
+	}
+
+	pdf := buf.Bytes()
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Error("output is not a complete PDF")
+	}
+	for _, want := range []string{"/DCTDecode", "/MediaBox [0 0 841.89 595.28]", "(A66H - 100 DXCC Entities)"} {
+		if !bytes.Contains(pdf, []byte(want)) {
+			t.Errorf("PDF missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"slices"
+	"sort"
+	"time"
+)
+
+// challengeBands are the bands counted for the ARRL DXCC Challenge
+var challengeBands = []string{"160m", "80m", "40m", "30m", "20m", "17m", "15m", "12m", "10m", "6m"}
+
+// ChallengeMonth is the number of band-entity slots first worked in a month
+type ChallengeMonth struct {
+	Month time.Time // First day of the month, in UTC
+	Slots int
+}
+
+// ChallengeSlot is a band-entity slot counted for the DXCC Challenge
+type ChallengeSlot struct {
+	Entity      string
+	Band        string
+	FirstWorked time.Time
+}
+
+// DXCCChallenge is the progress towards the DXCC Challenge, which counts each
+// DXCC entity once per band from 160m to 6m
+type DXCCChallenge struct {
+	Worked      int
+	Confirmed   int
+	Bands       []AwardBand
+	Months      []ChallengeMonth // Months in which new slots were worked, newest first
+	Unconfirmed []ChallengeSlot  // Worked but unconfirmed slots, newest first
+}
+
+// ComputeDXCCChallenge returns DXCC Challenge progress for a set of QSOs
+func ComputeDXCCChallenge(qsos []QSO) DXCCChallenge {
+	var c DXCCChallenge
+	bands := make(map[string]*AwardBand)
+	months := make(map[time.Time]int)
+
+	for _, entitySlots := range dxccSlots(qsos) {
+		for band, slot := range entitySlots {
+			if !slices.Contains(challengeBands, band) {
+				continue
+			}
+
+			b := bands[band]
+			if b == nil {
+				b = &AwardBand{Band: band}
+				bands[band] = b
+			}
+			c.Worked++
+			b.Worked++
+			if slot.Confirmed {
+				c.Confirmed++
+				b.Confirmed++
+			} else {
+				c.Unconfirmed = append(c.Unconfirmed, ChallengeSlot{
+					Entity:      slot.Entity,
+					Band:        band,
+					FirstWorked: slot.FirstWorked,
+				})
+			}
+
+			if !slot.FirstWorked.IsZero() {
+				t := slot.FirstWorked.UTC()
+				months[time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)]++
+			}
+		}
+	}
+
+	for _, b := range bands {
+		c.Bands = append(c.Bands, *b)
+	}
+	sort.Slice(c.Bands, func(i, j int) bool {
+		return bandIndex(c.Bands[i].Band) < bandIndex(c.Bands[j].Band)
+	})
+
+	for month, slots := range months {
+		c.Months = append(c.Months, ChallengeMonth{Month: month, Slots: slots})
+	}
+	sort.Slice(c.Months, func(i, j int) bool {
+		return c.Months[i].Month.After(c.Months[j].Month)
+	})
+
+	sort.Slice(c.Unconfirmed, func(i, j int) bool {
+		a, b := c.Unconfirmed[i], c.Unconfirmed[j]
+		if !a.FirstWorked.Equal(b.FirstWorked) {
+			return a.FirstWorked.After(b.FirstWorked)
+		}
+		if a.Entity != b.Entity {
+			return a.Entity < b.Entity
+		}
+		return bandIndex(a.Band) < bandIndex(b.Band)
+	})
+
+	return c
+}
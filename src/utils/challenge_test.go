@@ -0,0 +1,36 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeDXCCChallenge(t *testing.T) {
+	may := time.Date(2024, 5, 3, 12, 0, 0, 0, time.UTC)
+	june := time.Date(2024, 6, 9, 12, 0, 0, 0, time.UTC)
+	c := ComputeDXCCChallenge([]QSO{
+		{Call: "JA1ABC", DXCC: "339", Band: "20m", QslRcvd: QslYes, Timestamp: may},
+		{Call: "JA1ABC", DXCC: "339", Band: "20m", Timestamp: june},
+		{Call: "JA1ABC", DXCC: "339", Band: "40m", Timestamp: june},
+		{Call: "DL1ABC", DXCC: "230", Band: "20m", Timestamp: may},
+		{Call: "DL1ABC", DXCC: "230", Band: "2m", Timestamp: may},
+		{Call: "DL1ABC", DXCC: "230", Band: "60m", Timestamp: may},
+	})
+
+	if c.Worked != 3 || c.Confirmed != 1 {
+		t.Errorf("worked %d confirmed %d, want 3 and 1", c.Worked, c.Confirmed)
+	}
+	if len(c.Bands) != 2 || c.Bands[0].Band != "40m" || c.Bands[1].Worked != 2 || c.Bands[1].Confirmed != 1 {
+		t.Errorf("unexpected bands %+v", c.Bands)
+	}
+	if len(c.Months) != 2 || c.Months[0].Slots != 1 || c.Months[1].Slots != 2 {
+		t.Errorf("unexpected months %+v", c.Months)
+	}
+	if len(c.Unconfirmed) != 2 || c.Unconfirmed[0].Band != "40m" || c.Unconfirmed[1].Entity != "Fed. Rep. of Germany" {
+		t.Errorf("unexpected unconfirmed slots %+v", c.Unconfirmed)
+	}
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import "strings"
+
+// Ways a QSO can be confirmed, as named in a ConfirmationSummary
+const (
+	ConfirmationLotw  = "lotw"
+	ConfirmationPaper = "paper"
+	ConfirmationEqsl  = "eqsl"
+)
+
+// ConfirmationSummary combines the paper, LoTW and eQSL confirmations
+// recorded for a QSO
+type ConfirmationSummary struct {
+	// Sources that confirmed the QSO, strongest first: LoTW, paper, then
+	// eQSL, which doesn't count for most awards
+	Sources []string
+	// EqslAuthentic is set when the eQSL confirmation is Authenticity
+	// Guaranteed
+	EqslAuthentic bool
+}
+
+// ConfirmationSummary returns the sources that confirmed the QSO
+func (qso QSO) ConfirmationSummary() ConfirmationSummary {
+	var s ConfirmationSummary
+	if qso.LotwRcvd.Confirmed() {
+		s.Sources = append(s.Sources, ConfirmationLotw)
+	}
+	if qso.QslRcvd.Confirmed() {
+		s.Sources = append(s.Sources, ConfirmationPaper)
+	}
+	if qso.EqslRcvd.Confirmed() {
+		s.Sources = append(s.Sources, ConfirmationEqsl)
+		s.EqslAuthentic = qso.EqslAG
+	}
+	return s
+}
+
+// Confirmed reports whether any source confirmed the QSO
+func (s ConfirmationSummary) Confirmed() bool {
+	return len(s.Sources) > 0
+}
+
+// Has reports whether source confirmed the QSO
+func (s ConfirmationSummary) Has(source string) bool {
+	for _, src := range s.Sources {
+		if src == source {
+			return true
+		}
+	}
+	return false
+}
+
+// String joins the sources, e.g. "lotw+paper", or returns "none"
+func (s ConfirmationSummary) String() string {
+	if len(s.Sources) == 0 {
+		return "none"
+	}
+	return strings.Join(s.Sources, "+")
+}
@@ -0,0 +1,39 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import "testing"
+
+func TestConfirmationSummary(t *testing.T) {
+	tests := []struct {
+		qso  QSO
+		want string
+		ag   bool
+	}{
+		{QSO{}, "none", false},
+		{QSO{QslRcvd: QslYes, LotwRcvd: QslYes}, "lotw+paper", false},
+		{QSO{QslRcvd: QslVerified, EqslRcvd: QslYes}, "paper+eqsl", false},
+		{QSO{LotwRcvd: QslRequested, EqslRcvd: QslYes, EqslAG: true}, "eqsl", true},
+		{QSO{EqslRcvd: QslNo, EqslAG: true}, "none", false},
+		{QSO{QslRcvd: QslYes, LotwRcvd: QslYes, EqslRcvd: QslYes}, "lotw+paper+eqsl", false},
+	}
+	for _, tt := range tests {
+		s := tt.qso.ConfirmationSummary()
+		if got := s.String(); got != tt.want {
+			t.Errorf("ConfirmationSummary() = %q, want %q", got, tt.want)
+		}
+		if s.Confirmed() != (tt.want != "none") {
+			t.Errorf("Confirmed() = %v for %q", s.Confirmed(), tt.want)
+		}
+		if s.EqslAuthentic != tt.ag {
+			t.Errorf("EqslAuthentic = %v for %q, want %v", s.EqslAuthentic, tt.want, tt.ag)
+		}
+	}
+
+	s := QSO{QslRcvd: QslYes, LotwRcvd: QslYes}.ConfirmationSummary()
+	if !s.Has(ConfirmationPaper) || !s.Has(ConfirmationLotw) || s.Has(ConfirmationEqsl) {
+		t.Errorf("Has() gave the wrong sources for %v", s)
+	}
+}
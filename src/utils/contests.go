@@ -0,0 +1,178 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// ContestBand is a contest's QSOs and multipliers on a single band
+type ContestBand struct {
+	Band     string
+	QSOs     int
+	Entities int
+	Zones    int
+}
+
+// ContestHour is the number of QSOs made in a clock hour, in UTC
+type ContestHour struct {
+	Start time.Time
+	QSOs  int
+}
+
+// ContestSummary summarises the QSOs logged for one CONTEST_ID
+type ContestSummary struct {
+	ID       string
+	Start    time.Time // First QSO
+	End      time.Time // Last QSO
+	QSOs     int
+	Entities int // Distinct DXCC entities worked
+	Zones    int // Distinct CQ zones worked
+	Dupes    int // Repeated contacts on the same band and mode
+	Bands    []ContestBand
+	Hours    []ContestHour // Hours with QSOs, in order
+	BestHour ContestHour
+}
+
+// Multipliers returns the total of entity and zone multipliers
+func (c ContestSummary) Multipliers() int {
+	return c.Entities + c.Zones
+}
+
+// Rate returns the average QSOs per operating hour, counting only hours in
+// which QSOs were made
+func (c ContestSummary) Rate() float64 {
+	if len(c.Hours) == 0 {
+		return 0
+	}
+	return float64(c.QSOs) / float64(len(c.Hours))
+}
+
+// contestEntity returns the key a QSO counts towards for entity multipliers
+func contestEntity(qso QSO) string {
+	if qso.DXCC != "" {
+		return qso.DXCC
+	}
+	return strings.ToUpper(qso.Country)
+}
+
+// ContestQSOs returns the QSOs logged for a contest, matching the ID
+// regardless of case
+func ContestQSOs(qsos []QSO, id string) []QSO {
+	var matches []QSO
+	for _, qso := range qsos {
+		if qso.ContestID != "" && strings.EqualFold(qso.ContestID, id) {
+			matches = append(matches, qso)
+		}
+	}
+	return matches
+}
+
+// ComputeContests summarises every contest in the log, most recent first
+func ComputeContests(qsos []QSO) []ContestSummary {
+	byID := make(map[string][]QSO)
+	for _, qso := range qsos {
+		if qso.ContestID != "" {
+			byID[qso.ContestID] = append(byID[qso.ContestID], qso)
+		}
+	}
+
+	summaries := make([]ContestSummary, 0, len(byID))
+	for id, contestQSOs := range byID {
+		summaries = append(summaries, summarizeContest(id, contestQSOs))
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if !summaries[i].End.Equal(summaries[j].End) {
+			return summaries[i].End.After(summaries[j].End)
+		}
+		return summaries[i].ID < summaries[j].ID
+	})
+	return summaries
+}
+
+// ComputeContest summarises a single contest, reporting false if the log has
+// no QSOs for it
+func ComputeContest(qsos []QSO, id string) (ContestSummary, bool) {
+	contestQSOs := ContestQSOs(qsos, id)
+	if len(contestQSOs) == 0 {
+		return ContestSummary{}, false
+	}
+	return summarizeContest(contestQSOs[0].ContestID, contestQSOs), true
+}
+
+// summarizeContest computes the summary for one contest's QSOs
+func summarizeContest(id string, qsos []QSO) ContestSummary {
+	c := ContestSummary{ID: id, QSOs: len(qsos)}
+
+	entities := make(map[string]bool)
+	zones := make(map[string]bool)
+	bandQSOs := make(map[string]int)
+	bandEntities := make(map[string]map[string]bool)
+	bandZones := make(map[string]map[string]bool)
+	hours := make(map[time.Time]int)
+
+	for _, qso := range qsos {
+		band := strings.ToLower(qso.Band)
+		if bandEntities[band] == nil {
+			bandEntities[band] = make(map[string]bool)
+			bandZones[band] = make(map[string]bool)
+		}
+		bandQSOs[band]++
+
+		if entity := contestEntity(qso); entity != "" {
+			entities[entity] = true
+			bandEntities[band][entity] = true
+		}
+		if qso.CQZone != "" {
+			zones[qso.CQZone] = true
+			bandZones[band][qso.CQZone] = true
+		}
+
+		if qso.Timestamp.IsZero() {
+			continue
+		}
+		if c.Start.IsZero() || qso.Timestamp.Before(c.Start) {
+			c.Start = qso.Timestamp
+		}
+		if qso.Timestamp.After(c.End) {
+			c.End = qso.Timestamp
+		}
+		hours[qso.Timestamp.UTC().Truncate(time.Hour)]++
+	}
+
+	c.Entities = len(entities)
+	c.Zones = len(zones)
+	for _, dupe := range FindDupes(qsos) {
+		c.Dupes += len(dupe.QSOs) - 1
+	}
+
+	for band, count := range bandQSOs {
+		c.Bands = append(c.Bands, ContestBand{
+			Band:     band,
+			QSOs:     count,
+			Entities: len(bandEntities[band]),
+			Zones:    len(bandZones[band]),
+		})
+	}
+	sort.Slice(c.Bands, func(i, j int) bool {
+		return bandIndex(c.Bands[i].Band) < bandIndex(c.Bands[j].Band)
+	})
+
+	for start, count := range hours {
+		c.Hours = append(c.Hours, ContestHour{Start: start, QSOs: count})
+	}
+	sort.Slice(c.Hours, func(i, j int) bool {
+		return c.Hours[i].Start.Before(c.Hours[j].Start)
+	})
+	for _, h := range c.Hours {
+		if h.QSOs > c.BestHour.QSOs {
+			c.BestHour = h
+		}
+	}
+
+	return c
+}
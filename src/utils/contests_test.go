@@ -0,0 +1,61 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeContest(t *testing.T) {
+	start := time.Date(2024, 10, 26, 0, 0, 0, 0, time.UTC)
+	qsos := []QSO{
+		{Call: "DL1ABC", ContestID: "CQ-WW-SSB", Band: "20m", DXCC: "230", CQZone: "14", Timestamp: start.Add(5 * time.Minute)},
+		{Call: "JA1ABC", ContestID: "CQ-WW-SSB", Band: "20m", DXCC: "339", CQZone: "25", Timestamp: start.Add(20 * time.Minute)},
+		{Call: "DL2ABC", ContestID: "CQ-WW-SSB", Band: "40m", DXCC: "230", CQZone: "14", Timestamp: start.Add(70 * time.Minute)},
+		{Call: "W1AW", ContestID: "ARRL-DX-CW", Band: "20m", DXCC: "291", CQZone: "5", Timestamp: start.Add(-30 * 24 * time.Hour)},
+		{Call: "A61AA", Band: "20m", Timestamp: start},
+	}
+
+	contest, ok := ComputeContest(qsos, "cq-ww-ssb")
+	if !ok {
+		t.Fatalf("contest not found")
+	}
+	if contest.ID != "CQ-WW-SSB" || contest.QSOs != 3 {
+		t.Errorf("got %s with %d QSOs, want CQ-WW-SSB with 3", contest.ID, contest.QSOs)
+	}
+	if contest.Entities != 2 || contest.Zones != 2 || contest.Multipliers() != 4 {
+		t.Errorf("got %d entities and %d zones, want 2 and 2", contest.Entities, contest.Zones)
+	}
+	if len(contest.Bands) != 2 || contest.Bands[0].Band != "40m" || contest.Bands[1].QSOs != 2 {
+		t.Errorf("unexpected bands %+v", contest.Bands)
+	}
+	if len(contest.Hours) != 2 || contest.Rate() != 1.5 {
+		t.Errorf("got %d hours at %.1f per hour, want 2 at 1.5", len(contest.Hours), contest.Rate())
+	}
+	if !contest.BestHour.Start.Equal(start) || contest.BestHour.QSOs != 2 {
+		t.Errorf("unexpected best hour %+v", contest.BestHour)
+	}
+	if !contest.Start.Equal(start.Add(5*time.Minute)) || !contest.End.Equal(start.Add(70*time.Minute)) {
+		t.Errorf("unexpected period %v to %v", contest.Start, contest.End)
+	}
+
+	if _, ok := ComputeContest(qsos, "IARU-HF"); ok {
+		t.Errorf("found a contest that isn't in the log")
+	}
+}
+
+func TestComputeContests(t *testing.T) {
+	start := time.Date(2024, 10, 26, 0, 0, 0, 0, time.UTC)
+	contests := ComputeContests([]QSO{
+		{Call: "W1AW", ContestID: "ARRL-DX-CW", Timestamp: start.Add(-time.Hour)},
+		{Call: "DL1ABC", ContestID: "CQ-WW-SSB", Timestamp: start},
+		{Call: "A61AA", Timestamp: start},
+	})
+
+	if len(contests) != 2 || contests[0].ID != "CQ-WW-SSB" || contests[1].ID != "ARRL-DX-CW" {
+		t.Errorf("unexpected contests %+v", contests)
+	}
+}
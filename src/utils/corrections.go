@@ -0,0 +1,165 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Correction fixes fields of a QSO without editing the ADIF file. Empty fields
+// leave the logged value unchanged.
+type Correction struct {
+	Call       string    `json:"call"`
+	QSOTime    time.Time `json:"qso_time"`
+	GridSquare string    `json:"gridsquare,omitempty"`
+	Name       string    `json:"name,omitempty"`
+	Note       string    `json:"note,omitempty"`
+	Updated    time.Time `json:"updated"`
+}
+
+// Key returns the key identifying the corrected QSO
+func (c Correction) Key() string {
+	return CorrectionKey(c.Call, c.QSOTime)
+}
+
+// IsEmpty reports whether the correction changes nothing
+func (c Correction) IsEmpty() bool {
+	return c.GridSquare == "" && c.Name == "" && c.Note == ""
+}
+
+// Apply returns the QSO with the correction merged over it
+func (c Correction) Apply(qso QSO) QSO {
+	if c.GridSquare != "" {
+		qso.GridSquare = c.GridSquare
+		qso.GridEnriched = false
+	}
+	if c.Name != "" {
+		qso.Name = c.Name
+	}
+	if c.Note != "" {
+		qso.Note = c.Note
+	}
+	qso.Corrected = true
+	return qso
+}
+
+// CorrectionKey identifies a QSO by callsign and exact start time
+func CorrectionKey(call string, qsoTime time.Time) string {
+	return fmt.Sprintf("%s|%d", strings.ToUpper(call), qsoTime.Unix())
+}
+
+// CorrectionStore keeps QSO corrections in a JSON file
+type CorrectionStore struct {
+	path        string
+	mutex       sync.RWMutex
+	corrections map[string]Correction
+}
+
+// NewCorrectionStore loads the corrections stored at path, starting empty if
+// the file doesn't exist yet
+func NewCorrectionStore(path string) (*CorrectionStore, error) {
+	var corrections []Correction
+	if err := loadJSONFile(path, &corrections); err != nil {
+		return nil, err
+	}
+
+	s := &CorrectionStore{
+		path:        path,
+		corrections: make(map[string]Correction, len(corrections)),
+	}
+	for _, c := range corrections {
+		s.corrections[c.Key()] = c
+	}
+	return s, nil
+}
+
+// Get returns the correction for a QSO, if any
+func (s *CorrectionStore) Get(call string, qsoTime time.Time) (Correction, bool) {
+	if s == nil {
+		return Correction{}, false
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	c, ok := s.corrections[CorrectionKey(call, qsoTime)]
+	return c, ok
+}
+
+// Set stores a correction, replacing any existing one for the same QSO. An
+// empty correction removes it instead.
+func (s *CorrectionStore) Set(c Correction) error {
+	c.Call = strings.ToUpper(c.Call)
+	c.Updated = time.Now().UTC()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	previous, existed := s.corrections[c.Key()]
+	if c.IsEmpty() {
+		delete(s.corrections, c.Key())
+	} else {
+		s.corrections[c.Key()] = c
+	}
+
+	if err := s.save(); err != nil {
+		if existed {
+			s.corrections[c.Key()] = previous
+		} else {
+			delete(s.corrections, c.Key())
+		}
+		return err
+	}
+	return nil
+}
+
+// List returns all corrections, most recently updated first
+func (s *CorrectionStore) List() []Correction {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.list()
+}
+
+// Apply returns the QSOs with corrections merged over them. The input slice
+// is not modified.
+func (s *CorrectionStore) Apply(qsos []QSO) []QSO {
+	if s == nil {
+		return qsos
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if len(s.corrections) == 0 {
+		return qsos
+	}
+
+	corrected := make([]QSO, len(qsos))
+	for i, qso := range qsos {
+		if c, ok := s.corrections[CorrectionKey(qso.Call, qso.Timestamp)]; ok {
+			qso = c.Apply(qso)
+		}
+		corrected[i] = qso
+	}
+	return corrected
+}
+
+// list returns the corrections sorted. The caller must hold the lock.
+func (s *CorrectionStore) list() []Correction {
+	corrections := make([]Correction, 0, len(s.corrections))
+	for _, c := range s.corrections {
+		corrections = append(corrections, c)
+	}
+	sort.Slice(corrections, func(i, j int) bool {
+		return corrections[i].Updated.After(corrections[j].Updated)
+	})
+	return corrections
+}
+
+// save writes the corrections to disk. The caller must hold the lock.
+func (s *CorrectionStore) save() error {
+	return saveJSONFile(s.path, s.list(), 0644)
+}
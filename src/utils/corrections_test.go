@@ -0,0 +1,52 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCorrectionStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "corrections.json")
+	store, err := NewCorrectionStore(path)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	qsoTime := time.Date(2025, 3, 1, 18, 30, 0, 0, time.UTC)
+	qsos := []QSO{
+		{Call: "A61XX", Timestamp: qsoTime, GridSquare: "LL74", Name: "Ali"},
+		{Call: "W1AW", Timestamp: qsoTime, GridSquare: "FN31"},
+	}
+
+	if err := store.Set(Correction{Call: "a61xx", QSOTime: qsoTime, GridSquare: "LL75"}); err != nil {
+		t.Fatalf("Failed to set correction: %v", err)
+	}
+
+	corrected := store.Apply(qsos)
+	if corrected[0].GridSquare != "LL75" || corrected[0].Name != "Ali" || !corrected[0].Corrected {
+		t.Errorf("Expected corrected grid with name kept, got %+v", corrected[0])
+	}
+	if corrected[1].Corrected || qsos[0].GridSquare != "LL74" {
+		t.Error("Expected other QSOs and the input slice to be untouched")
+	}
+
+	reloaded, err := NewCorrectionStore(path)
+	if err != nil {
+		t.Fatalf("Failed to reload store: %v", err)
+	}
+	if _, ok := reloaded.Get("A61XX", qsoTime); !ok {
+		t.Error("Expected correction to persist")
+	}
+
+	if err := reloaded.Set(Correction{Call: "A61XX", QSOTime: qsoTime}); err != nil {
+		t.Fatalf("Failed to clear correction: %v", err)
+	}
+	if len(reloaded.List()) != 0 {
+		t.Error("Expected empty correction to remove it")
+	}
+}
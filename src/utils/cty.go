@@ -0,0 +1,195 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CtyEntity is a DXCC entity as listed in cty.dat
+type CtyEntity struct {
+	Name      string
+	DXCC      string // ADIF entity code, empty if the name isn't known
+	CQZone    string
+	Continent string
+}
+
+// CtyDat is the cty.dat country file from country-files.com, used to find
+// the DXCC entity of a callsign from its prefix. A nil CtyDat finds nothing.
+type CtyDat struct {
+	path     string
+	mutex    sync.RWMutex
+	prefixes map[string]CtyEntity // Prefixes, matched longest first
+	calls    map[string]CtyEntity // Full callsigns listed with =
+	longest  int
+}
+
+// NewCtyDat loads the country file at path
+func NewCtyDat(path string) (*CtyDat, error) {
+	c := &CtyDat{path: path}
+	if err := c.Load(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// entityCodes maps entity names to ADIF DXCC entity codes
+var entityCodes = func() map[string]string {
+	codes := make(map[string]string, len(dxccEntities))
+	for code, name := range dxccEntities {
+		codes[name] = strconv.Itoa(code)
+	}
+	return codes
+}()
+
+// Load rereads the country file, picking up a newly downloaded copy. Each
+// entity has a line of colon separated fields (name, CQ zone, ITU zone,
+// continent, latitude, longitude, UTC offset and primary prefix) followed
+// by its comma separated prefixes, ending with a semicolon. Entities whose
+// primary prefix starts with * only count for the WAE award and are skipped,
+// so their callsigns fall back to the DXCC entity.
+func (c *CtyDat) Load() error {
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return fmt.Errorf("failed to open cty.dat: %w", err)
+	}
+
+	prefixes := make(map[string]CtyEntity)
+	calls := make(map[string]CtyEntity)
+	longest := 0
+	for _, record := range strings.Split(string(data), ";") {
+		header, list, ok := cutCtyHeader(record)
+		if !ok {
+			continue
+		}
+		fields := strings.Split(header, ":")
+		if len(fields) < 8 {
+			continue
+		}
+		primary := strings.TrimSpace(fields[7])
+		if strings.HasPrefix(primary, "*") {
+			continue
+		}
+		entity := CtyEntity{
+			Name:      strings.TrimSpace(fields[0]),
+			CQZone:    strings.TrimSpace(fields[1]),
+			Continent: strings.TrimSpace(fields[3]),
+		}
+		entity.DXCC = entityCodes[entity.Name]
+
+		for _, prefix := range strings.Split(list, ",") {
+			prefix, exact := strings.CutPrefix(strings.TrimSpace(prefix), "=")
+			// Drop zone and location overrides such as (14)[28]
+			if i := strings.IndexAny(prefix, "([<{~"); i != -1 {
+				prefix = prefix[:i]
+			}
+			prefix = strings.ToUpper(prefix)
+			if prefix == "" {
+				continue
+			}
+			if exact {
+				calls[prefix] = entity
+				continue
+			}
+			prefixes[prefix] = entity
+			longest = max(longest, len(prefix))
+		}
+	}
+	if len(prefixes) == 0 {
+		return fmt.Errorf("no entities found in %s", c.path)
+	}
+
+	c.mutex.Lock()
+	c.prefixes = prefixes
+	c.calls = calls
+	c.longest = longest
+	c.mutex.Unlock()
+	return nil
+}
+
+// cutCtyHeader splits a cty.dat record into its entity line and prefix
+// list. The entity line ends with the colon after the primary prefix.
+func cutCtyHeader(record string) (header, list string, ok bool) {
+	record = strings.TrimSpace(record)
+	i := strings.LastIndex(record, ":")
+	if i == -1 {
+		return "", "", false
+	}
+	return record[:i], record[i+1:], true
+}
+
+// Lookup returns the entity of a callsign, using the prefix before a slash
+// for operation from another entity, as in EA8/W1AW
+func (c *CtyDat) Lookup(call string) (CtyEntity, bool) {
+	if c == nil {
+		return CtyEntity{}, false
+	}
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	call = strings.ToUpper(strings.TrimSpace(call))
+	if entity, ok := c.calls[call]; ok {
+		return entity, true
+	}
+	call = ctyPrefixPart(call)
+	for n := min(len(call), c.longest); n > 0; n-- {
+		if entity, ok := c.prefixes[call[:n]]; ok {
+			return entity, true
+		}
+	}
+	return CtyEntity{}, false
+}
+
+// ctyPrefixPart returns the part of a callsign that decides its entity:
+// the callsign itself without suffixes such as /P or /QRP, or the shorter
+// part when another entity's prefix is added, as in EA8/W1AW or W1AW/VE3
+func ctyPrefixPart(call string) string {
+	var parts []string
+	for _, part := range strings.Split(call, "/") {
+		switch part {
+		case "", "P", "M", "MM", "AM", "QRP", "A", "B":
+			continue
+		}
+		// Call area suffixes such as /4 keep the entity
+		if len(part) == 1 && part[0] >= '0' && part[0] <= '9' {
+			continue
+		}
+		parts = append(parts, part)
+	}
+	switch len(parts) {
+	case 0:
+		return call
+	case 1:
+		return parts[0]
+	}
+	if len(parts[1]) < len(parts[0]) {
+		return parts[1]
+	}
+	return parts[0]
+}
+
+// Apply returns the QSOs with the entity of those logged without a country
+// or DXCC code derived from the callsign. The input slice is not modified.
+func (c *CtyDat) Apply(qsos []QSO) []QSO {
+	if c == nil {
+		return qsos
+	}
+
+	filled := make([]QSO, len(qsos))
+	for i, qso := range qsos {
+		if qso.Country == "" && qso.DXCC == "" {
+			if entity, ok := c.Lookup(qso.Call); ok {
+				qso.Country = entity.Name
+				qso.DXCC = entity.DXCC
+			}
+		}
+		filled[i] = qso
+	}
+	return filled
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testCtyDat = `Fed. Rep. of Germany:     14:  28:  EU:   51.00:   -10.00:    -1.0:  DL:
+    DA,DB,DC,DD,DE,DF,DG,DH,DI,DJ,DK,DL,DM,DN,DO,DP,DQ,DR,Y2,Y3,Y4,Y5,Y6,Y7,Y8,Y9;
+United States:            05:  08:  NA:   37.53:    91.67:     5.0:  K:
+    AA,AB,AC,K,N,W,=VE3XYZ/W(4)[8];
+Canary Islands:           33:  36:  AF:   28.32:    15.85:     0.0:  EA8:
+    AM8,AN8,EA8,EB8,EC8,ED8,EE8,EF8,EG8,EH8;
+Spain:                    14:  37:  EU:   40.37:     4.88:    -1.0:  EA:
+    AM,AN,AO,EA,EB,EC,ED,EE,EF,EG,EH;
+Sicily:                   15:  28:  EU:   37.50:   -14.00:    -1.0:  *IT9:
+    IT9,IW9;
+Italy:                    15:  28:  EU:   42.82:   -12.58:    -1.0:  I:
+    I,IW(15)[28];
+United Arab Emirates:     21:  39:  AS:   24.00:   -54.00:    -4.0:  A6:
+    A6;
+`
+
+func TestCtyDat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cty.dat")
+	if err := os.WriteFile(path, []byte(testCtyDat), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cty, err := NewCtyDat(path)
+	if err != nil {
+		t.Fatalf("NewCtyDat: %v", err)
+	}
+	for call, want := range map[string]string{
+		"DL1ABC":   "Fed. Rep. of Germany",
+		"w1aw":     "United States",
+		"W1AW/P":   "United States",
+		"W1AW/4":   "United States",
+		"EA8ABC":   "Canary Islands",
+		"EA1ABC":   "Spain",
+		"EA8/W1AW": "Canary Islands",
+		"W1AW/EA8": "Canary Islands",
+		"VE3XYZ/W": "United States",
+		"IT9ABC":   "Italy",
+		"A66H":     "United Arab Emirates",
+		"A66H/QRP": "United Arab Emirates",
+		"ZZ9ZZZ":   "",
+		"":         "",
+	} {
+		entity, ok := cty.Lookup(call)
+		if entity.Name != want || ok != (want != "") {
+			t.Errorf("Lookup(%q) = %q, %v, want %q", call, entity.Name, ok, want)
+		}
+	}
+	if entity, _ := cty.Lookup("A66H"); entity.DXCC != "391" || entity.CQZone != "21" || entity.Continent != "AS" {
+		t.Errorf("Lookup(A66H) = %+v", entity)
+	}
+
+	qsos := cty.Apply([]QSO{
+		{Call: "DL1ABC"},
+		{Call: "DL1ABC", Country: "Germany"},
+		{Call: "W1AW", DXCC: "291"},
+		{Call: "ZZ9ZZZ"},
+	})
+	if qsos[0].Country != "Fed. Rep. of Germany" || qsos[0].DXCC != "230" {
+		t.Errorf("Apply didn't fill in the entity: %+v", qsos[0])
+	}
+	if qsos[1].Country != "Germany" || qsos[1].DXCC != "" || qsos[2].Country != "" || qsos[3].Country != "" {
+		t.Errorf("Apply changed a logged entity: %+v", qsos[1:])
+	}
+
+	var none *CtyDat
+	if _, ok := none.Lookup("W1AW"); ok || len(none.Apply([]QSO{{Call: "W1AW"}})[0].Country) != 0 {
+		t.Error("Expected a nil CtyDat to find nothing")
+	}
+
+	if err := os.WriteFile(path, []byte("not a country file"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := cty.Load(); err == nil {
+		t.Error("Expected an empty country file to fail to load")
+	}
+}
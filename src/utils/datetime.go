@@ -0,0 +1,98 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrDateTimeNoTime is returned when a date was given without a time
+var ErrDateTimeNoTime = errors.New("date has no time")
+
+// ErrDateTimeFormat is returned when a date and time isn't in a known format
+var ErrDateTimeFormat = errors.New("unrecognised date and time format")
+
+// dateTimeLayouts are the accepted date and time formats, after dashes,
+// slashes and "T" separators are normalised by ParseDateTime
+var dateTimeLayouts = []string{
+	"2006-01-02 1504",
+	"2006-01-02 15:04",
+	"2006-01-02 150405",
+	"2006-01-02 15:04:05",
+	"20060102 1504",
+	"20060102 15:04",
+	"20060102 150405",
+	"20060102 15:04:05",
+	"200601021504",
+	"2 Jan 2006 1504",
+	"2 Jan 2006 15:04",
+	"2 January 2006 1504",
+	"2 January 2006 15:04",
+	"Jan 2 2006 1504",
+	"Jan 2 2006 15:04",
+	"January 2 2006 1504",
+	"January 2 2006 15:04",
+}
+
+// dateLayouts are dates without a time, used to give a clearer error
+var dateLayouts = []string{
+	"2006-01-02",
+	"20060102",
+	"2 Jan 2006",
+	"2 January 2006",
+	"Jan 2 2006",
+	"January 2 2006",
+}
+
+// ParseDateTime parses a free-form UTC date and time such as
+// "2024-05-01 1305", "1 May 2024 13:05 UTC" or ADIF-style "20240501 1305"
+func ParseDateTime(value string) (time.Time, error) {
+	s := normalizeDateTime(value)
+	if s == "" {
+		return time.Time{}, ErrDateTimeFormat
+	}
+
+	var rangeErr error
+	for _, layout := range dateTimeLayouts {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return t, nil
+		}
+		// A layout that matched apart from a value being out of range, such as
+		// hour 25, is a better error than an unknown format
+		var parseErr *time.ParseError
+		if errors.As(err, &parseErr) && strings.Contains(parseErr.Message, "out of range") {
+			rangeErr = err
+		}
+	}
+	if rangeErr != nil {
+		return time.Time{}, rangeErr
+	}
+
+	for _, layout := range dateLayouts {
+		if _, err := time.Parse(layout, s); err == nil {
+			return time.Time{}, ErrDateTimeNoTime
+		}
+	}
+	return time.Time{}, ErrDateTimeFormat
+}
+
+// normalizeDateTime trims a UTC suffix, commas and extra spaces, and
+// writes dates with dashes so fewer layouts are needed
+func normalizeDateTime(value string) string {
+	s := strings.TrimSpace(value)
+	for _, suffix := range []string{"UTC", "utc", "GMT", "gmt", "Z", "z"} {
+		s = strings.TrimSpace(strings.TrimSuffix(s, suffix))
+	}
+	s = strings.NewReplacer(",", " ", "/", "-").Replace(s)
+
+	// ISO 8601 separates the date and time with a T
+	if len(s) > 10 && (s[10] == 'T' || s[10] == 't') && s[4] == '-' {
+		s = s[:10] + " " + s[11:]
+	}
+	return strings.Join(strings.Fields(s), " ")
+}
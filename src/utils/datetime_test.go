@@ -0,0 +1,70 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseDateTime(t *testing.T) {
+	want := time.Date(2024, time.May, 1, 13, 5, 0, 0, time.UTC)
+	inputs := []string{
+		"2024-05-01 1305",
+		"2024-05-01 13:05",
+		"2024/05/01 13:05",
+		"2024-05-01T13:05:00Z",
+		"1 May 2024 13:05 UTC",
+		"1 May 2024, 13:05",
+		"May 1, 2024 1305",
+		"01 May 2024 1305",
+		"20240501 1305",
+		"202405011305",
+		"  20240501   13:05  ",
+	}
+	for _, input := range inputs {
+		got, err := ParseDateTime(input)
+		if err != nil {
+			t.Errorf("ParseDateTime(%q) error: %v", input, err)
+			continue
+		}
+		if !got.Equal(want) {
+			t.Errorf("ParseDateTime(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
+
+func TestParseDateTimeErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  error
+	}{
+		{"", ErrDateTimeFormat},
+		{"yesterday", ErrDateTimeFormat},
+		{"01-05-2024 1305", ErrDateTimeFormat},
+		{"2024-05-01", ErrDateTimeNoTime},
+		{"1 May 2024", ErrDateTimeNoTime},
+		{"20240501", ErrDateTimeNoTime},
+	}
+	for _, tt := range tests {
+		_, err := ParseDateTime(tt.input)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("ParseDateTime(%q) error = %v, want %v", tt.input, err, tt.want)
+		}
+	}
+
+	// Out of range values report the range rather than an unknown format
+	for _, input := range []string{"2024-05-01 2505", "2024-02-30 1305", "20240501 1375"} {
+		_, err := ParseDateTime(input)
+		if err == nil {
+			t.Errorf("ParseDateTime(%q) succeeded, want error", input)
+			continue
+		}
+		if errors.Is(err, ErrDateTimeFormat) || errors.Is(err, ErrDateTimeNoTime) {
+			t.Errorf("ParseDateTime(%q) error = %v, want a range error", input, err)
+		}
+	}
+}
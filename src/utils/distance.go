@@ -0,0 +1,45 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"fmt"
+
+	"github.com/golang/geo/s2"
+	"github.com/pd0mz/go-maidenhead"
+)
+
+// EarthRadiusKm is the mean radius of the Earth used for distance calculations
+const EarthRadiusKm = 6371.0088
+
+// KmPerMile is the length of an international mile in kilometres
+const KmPerMile = 1.609344
+
+// Distance returns the great-circle distance in kilometres between the
+// centres of two grid locators
+func Distance(gridA, gridB string) (float64, error) {
+	a, err := gridLatLng(gridA)
+	if err != nil {
+		return 0, err
+	}
+	b, err := gridLatLng(gridB)
+	if err != nil {
+		return 0, err
+	}
+
+	return a.Distance(b).Radians() * EarthRadiusKm, nil
+}
+
+func gridLatLng(grid string) (s2.LatLng, error) {
+	if err := ValidateGridSquare(grid); err != nil {
+		return s2.LatLng{}, err
+	}
+	p, err := maidenhead.ParseLocator(grid)
+	if err != nil {
+		return s2.LatLng{}, fmt.Errorf("failed to parse grid locator %s: %w", grid, err)
+	}
+
+	return s2.LatLngFromDegrees(p.Latitude, p.Longitude), nil
+}
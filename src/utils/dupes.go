@@ -0,0 +1,57 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"sort"
+	"strings"
+)
+
+// ContestDupe is a station worked more than once on the same band and mode
+// in a contest
+type ContestDupe struct {
+	Call string
+	Band string
+	Mode string // Cabrillo mode, so USB and LSB count as the same phone mode
+	QSOs []QSO  // In time order, the first being the valid contact
+}
+
+// FindDupes finds repeated contacts with the same call, band and mode among a
+// contest's QSOs, ordered by the time of the first contact
+func FindDupes(qsos []QSO) []ContestDupe {
+	type dupeKey struct{ call, band, mode string }
+	groups := make(map[dupeKey][]QSO)
+	for _, qso := range qsos {
+		key := dupeKey{
+			call: strings.ToUpper(qso.Call),
+			band: strings.ToLower(qso.Band),
+			mode: CabrilloMode(qso.Mode),
+		}
+		if key.band == "" {
+			if mhz, ok := ParseFrequency(qso.Freq); ok {
+				key.band = BandFromFrequency(mhz)
+			}
+		}
+		groups[key] = append(groups[key], qso)
+	}
+
+	var dupes []ContestDupe
+	for key, group := range groups {
+		if len(group) < 2 {
+			continue
+		}
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].Timestamp.Before(group[j].Timestamp)
+		})
+		dupes = append(dupes, ContestDupe{Call: key.call, Band: key.band, Mode: key.mode, QSOs: group})
+	}
+	sort.Slice(dupes, func(i, j int) bool {
+		if !dupes[i].QSOs[0].Timestamp.Equal(dupes[j].QSOs[0].Timestamp) {
+			return dupes[i].QSOs[0].Timestamp.Before(dupes[j].QSOs[0].Timestamp)
+		}
+		return dupes[i].Call < dupes[j].Call
+	})
+	return dupes
+}
@@ -0,0 +1,34 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFindDupes(t *testing.T) {
+	start := time.Date(2024, 10, 26, 0, 0, 0, 0, time.UTC)
+	qsos := []QSO{
+		{Call: "DL1ABC", Band: "20m", Mode: "SSB", Timestamp: start.Add(3 * time.Hour)},
+		{Call: "DL1ABC", Band: "20m", Mode: "USB", Timestamp: start},
+		{Call: "DL1ABC", Band: "40m", Mode: "SSB", Timestamp: start.Add(time.Hour)},
+		{Call: "dl1abc", Freq: "14.2", Mode: "SSB", Timestamp: start.Add(5 * time.Hour)},
+		{Call: "JA1ABC", Band: "20m", Mode: "CW", Timestamp: start.Add(-time.Hour)},
+		{Call: "JA1ABC", Band: "20m", Mode: "SSB", Timestamp: start.Add(time.Hour)},
+	}
+
+	dupes := FindDupes(qsos)
+	if len(dupes) != 1 {
+		t.Fatalf("got %d dupes, want 1: %+v", len(dupes), dupes)
+	}
+	d := dupes[0]
+	if d.Call != "DL1ABC" || d.Band != "20m" || d.Mode != "PH" || len(d.QSOs) != 3 {
+		t.Errorf("unexpected dupe %s %s %s with %d QSOs", d.Call, d.Band, d.Mode, len(d.QSOs))
+	}
+	if !d.QSOs[0].Timestamp.Equal(start) {
+		t.Errorf("first QSO at %v, want %v", d.QSOs[0].Timestamp, start)
+	}
+}
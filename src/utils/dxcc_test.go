@@ -0,0 +1,46 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import "testing"
+
+func TestNormalizeCountry(t *testing.T) {
+	tests := map[string]string{
+		"Fed. Rep. of Germany":   "Fed. Rep. of Germany",
+		"Germany":                "Fed. Rep. of Germany",
+		"FED REP OF GERMANY":     "Fed. Rep. of Germany",
+		"USA":                    "United States",
+		"Canary Is.":             "Canary Islands",
+		"turks & caicos islands": "Turks & Caicos Islands",
+		"Guinea Bissau":          "Guinea-Bissau",
+		"  Japan ":               "Japan",
+		"Atlantis":               "Atlantis",
+		"":                       "",
+	}
+
+	for in, want := range tests {
+		if got := NormalizeCountry(in); got != want {
+			t.Errorf("NormalizeCountry(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestUniqueCountriesNormalized(t *testing.T) {
+	parser := &ADIFParser{QSOs: []QSO{
+		{Call: "DL1ABC", Country: "Fed. Rep. of Germany"},
+		{Call: "DL2ABC", Country: "Germany"},
+		{Call: "DL3ABC", DXCC: "230", Country: "Deutschland"},
+		{Call: "W1AW", DXCC: "291"},
+		{Call: "K1ABC", Country: "United States of America"},
+		{Call: "A61XX", DXCC: "0", Country: "United Arab Emirates"},
+	}}
+
+	if got := parser.GetUniqueCountriesCount(); got != 3 {
+		t.Errorf("Expected 3 unique entities, got %d", got)
+	}
+	if got := parser.QSOs[1].GetFlagCode(); got != "de" {
+		t.Errorf("Expected de flag for Germany, got %q", got)
+	}
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// SlotStatus is whether a band-entity slot has been worked or confirmed
+type SlotStatus int
+
+const (
+	SlotNone SlotStatus = iota
+	SlotWorked
+	SlotConfirmed
+)
+
+// Class returns the CSS class colouring a slot in the matrix
+func (s SlotStatus) Class() string {
+	switch s {
+	case SlotWorked:
+		return "slot-worked"
+	case SlotConfirmed:
+		return "slot-confirmed"
+	}
+	return ""
+}
+
+// dxccSlot is a DXCC entity worked on a band
+type dxccSlot struct {
+	Entity      string
+	Band        string
+	FirstWorked time.Time
+	Confirmed   bool
+}
+
+// dxccSlots collects the band-entity slots worked in a log, keyed by entity
+// and then band
+func dxccSlots(qsos []QSO) map[string]map[string]*dxccSlot {
+	slots := make(map[string]map[string]*dxccSlot)
+	for _, qso := range qsos {
+		entity := qso.Entity()
+		band := strings.ToLower(qso.Band)
+		if entity == "" || band == "" {
+			continue
+		}
+
+		if slots[entity] == nil {
+			slots[entity] = make(map[string]*dxccSlot)
+		}
+		slot := slots[entity][band]
+		if slot == nil {
+			slot = &dxccSlot{Entity: entity, Band: band, FirstWorked: qso.Timestamp}
+			slots[entity][band] = slot
+		}
+		if !qso.Timestamp.IsZero() && (slot.FirstWorked.IsZero() || qso.Timestamp.Before(slot.FirstWorked)) {
+			slot.FirstWorked = qso.Timestamp
+		}
+		if IsAwardConfirmed(qso) {
+			slot.Confirmed = true
+		}
+	}
+	return slots
+}
+
+// DXCCMatrixRow is one entity's status on each band of the matrix
+type DXCCMatrixRow struct {
+	Entity    string
+	Slots     []SlotStatus // In the order of DXCCMatrix.Bands
+	Worked    int
+	Confirmed int
+}
+
+// DXCCMatrix is the worked and confirmed status of every DXCC entity in the
+// log on every band it has QSOs on
+type DXCCMatrix struct {
+	Bands []string // From lowest to highest frequency
+	Rows  []DXCCMatrixRow
+}
+
+// ComputeDXCCMatrix builds the entity by band matrix for a set of QSOs,
+// listing entities alphabetically
+func ComputeDXCCMatrix(qsos []QSO) DXCCMatrix {
+	slots := dxccSlots(qsos)
+
+	bandSet := make(map[string]bool)
+	for _, bands := range slots {
+		for band := range bands {
+			bandSet[band] = true
+		}
+	}
+	var m DXCCMatrix
+	for band := range bandSet {
+		m.Bands = append(m.Bands, band)
+	}
+	sort.Slice(m.Bands, func(i, j int) bool {
+		if bandIndex(m.Bands[i]) != bandIndex(m.Bands[j]) {
+			return bandIndex(m.Bands[i]) < bandIndex(m.Bands[j])
+		}
+		return m.Bands[i] < m.Bands[j]
+	})
+
+	for entity, bands := range slots {
+		row := DXCCMatrixRow{Entity: entity, Slots: make([]SlotStatus, len(m.Bands))}
+		for i, band := range m.Bands {
+			slot := bands[band]
+			switch {
+			case slot == nil:
+				continue
+			case slot.Confirmed:
+				row.Slots[i] = SlotConfirmed
+				row.Confirmed++
+			default:
+				row.Slots[i] = SlotWorked
+			}
+			row.Worked++
+		}
+		m.Rows = append(m.Rows, row)
+	}
+	sort.Slice(m.Rows, func(i, j int) bool {
+		return m.Rows[i].Entity < m.Rows[j].Entity
+	})
+	return m
+}
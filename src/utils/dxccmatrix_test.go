@@ -0,0 +1,39 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeDXCCMatrix(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	m := ComputeDXCCMatrix([]QSO{
+		{Call: "DL1ABC", Country: "Germany", Band: "20m", Timestamp: at},
+		{Call: "DL2ABC", Country: "Germany", Band: "20M", LotwRcvd: QslYes, Timestamp: at},
+		{Call: "DL1ABC", Country: "Germany", Band: "40m", Timestamp: at},
+		{Call: "JA1ABC", Country: "Japan", Band: "10m", Timestamp: at},
+		{Call: "A61AA", Band: "20m", Timestamp: at},
+	})
+
+	if len(m.Bands) != 3 || m.Bands[0] != "40m" || m.Bands[1] != "20m" || m.Bands[2] != "10m" {
+		t.Fatalf("unexpected bands %v", m.Bands)
+	}
+	if len(m.Rows) != 2 || m.Rows[0].Entity != "Fed. Rep. of Germany" || m.Rows[1].Entity != "Japan" {
+		t.Fatalf("unexpected rows %+v", m.Rows)
+	}
+
+	germany := m.Rows[0]
+	want := []SlotStatus{SlotWorked, SlotConfirmed, SlotNone}
+	for i, status := range want {
+		if germany.Slots[i] != status {
+			t.Errorf("Germany on %s = %v, want %v", m.Bands[i], germany.Slots[i], status)
+		}
+	}
+	if germany.Worked != 2 || germany.Confirmed != 1 {
+		t.Errorf("Germany worked %d confirmed %d, want 2 and 1", germany.Worked, germany.Confirmed)
+	}
+}
@@ -0,0 +1,217 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DXSpot is a spot announced on a DX cluster
+type DXSpot struct {
+	Spotter   string
+	Spotted   string
+	Frequency float64 // kHz
+	Comment   string
+	Time      time.Time
+}
+
+// Example: "DX de W3LPL:     14025.0  A66H         CW 599                 1234Z"
+var dxSpotRegex = regexp.MustCompile(`^DX de ([A-Za-z0-9/#-]+):?\s+([0-9.]+)\s+([A-Za-z0-9/]+)\s+(.*?)\s*(\d{4})Z`)
+
+// ParseDXSpot parses a DX cluster spot announcement line
+func ParseDXSpot(line string) (DXSpot, bool) {
+	match := dxSpotRegex.FindStringSubmatch(strings.TrimSpace(line))
+	if match == nil {
+		return DXSpot{}, false
+	}
+
+	freq, err := strconv.ParseFloat(match[2], 64)
+	if err != nil {
+		return DXSpot{}, false
+	}
+
+	return DXSpot{
+		Spotter:   strings.ToUpper(strings.TrimSuffix(match[1], "-#")),
+		Spotted:   strings.ToUpper(match[3]),
+		Frequency: freq,
+		Comment:   strings.TrimSpace(match[4]),
+		Time:      time.Now().UTC(),
+	}, true
+}
+
+// MatchesCallsign reports whether a spotted call is the given callsign,
+// including portable prefixes and suffixes (e.g. A66H/P)
+func MatchesCallsign(spotted, callsign string) bool {
+	for _, part := range strings.Split(strings.ToUpper(spotted), "/") {
+		if part == callsign {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	dxClusterMaxSpots     = 50
+	dxClusterSpotLifetime = 2 * time.Hour
+	dxClusterDedupWindow  = 10 * time.Minute
+	dxClusterMinBackoff   = time.Minute
+	dxClusterMaxBackoff   = 30 * time.Minute
+)
+
+// DXCluster is a telnet DX cluster client watching for spots of my callsigns
+type DXCluster struct {
+	addr  string
+	login string
+	watch []string
+
+	mutex sync.RWMutex
+	spots []DXSpot
+}
+
+// NewDXCluster creates a DX cluster client that logs in to addr with the
+// login callsign and records spots of any of the watched callsigns
+func NewDXCluster(addr, login string, watch []string) *DXCluster {
+	calls := make([]string, 0, len(watch))
+	for _, call := range watch {
+		if call = strings.ToUpper(strings.TrimSpace(call)); call != "" {
+			calls = append(calls, call)
+		}
+	}
+
+	return &DXCluster{
+		addr:  addr,
+		login: strings.ToUpper(strings.TrimSpace(login)),
+		watch: calls,
+	}
+}
+
+// Start connects to the cluster in the background, reconnecting with
+// exponential backoff when the connection drops
+func (d *DXCluster) Start() {
+	startTelnetFeed("DX cluster", d.addr, d.login, d.handleLine)
+}
+
+// handleLine records a spot line if it spots one of the watched callsigns
+func (d *DXCluster) handleLine(line string) {
+	spot, ok := ParseDXSpot(line)
+	if !ok {
+		return
+	}
+
+	for _, call := range d.watch {
+		if MatchesCallsign(spot.Spotted, call) {
+			d.addSpot(spot)
+			return
+		}
+	}
+}
+
+// startTelnetFeed holds a connection to a telnet spot feed in the background,
+// passing each received line to handle and reconnecting with exponential
+// backoff when the connection drops
+func startTelnetFeed(name, addr, login string, handle func(line string)) {
+	go func() {
+		backoff := dxClusterMinBackoff
+		for {
+			connected := time.Now()
+			if err := runTelnetFeed(name, addr, login, handle); err != nil {
+				log.Printf("%s connection to %s failed: %v", name, addr, err)
+			}
+
+			// Reset the backoff after a connection that stayed up for a while
+			if time.Since(connected) > dxClusterMaxBackoff {
+				backoff = dxClusterMinBackoff
+			}
+
+			time.Sleep(backoff)
+			backoff *= 2
+			if backoff > dxClusterMaxBackoff {
+				backoff = dxClusterMaxBackoff
+			}
+		}
+	}()
+}
+
+// runTelnetFeed holds a single feed connection until it fails
+func runTelnetFeed(name, addr, login string, handle func(line string)) error {
+	conn, err := net.DialTimeout("tcp", addr, 30*time.Second)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := fmt.Fprintf(conn, "%s\r\n", login); err != nil {
+		return fmt.Errorf("failed to log in: %w", err)
+	}
+	log.Printf("Connected to %s %s as %s", name, addr, login)
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		handle(scanner.Text())
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("connection closed")
+}
+
+// addSpot records a spot, ignoring repeats from the same spotter on the same
+// band within the dedup window
+func (d *DXCluster) addSpot(spot DXSpot) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	for _, existing := range d.spots {
+		if existing.Spotter == spot.Spotter && spotBand(existing.Frequency) == spotBand(spot.Frequency) &&
+			spot.Time.Sub(existing.Time) < dxClusterDedupWindow {
+			return
+		}
+	}
+
+	// Newest first, dropping expired spots and keeping the cache bounded
+	spots := []DXSpot{spot}
+	for _, existing := range d.spots {
+		if len(spots) >= dxClusterMaxSpots {
+			break
+		}
+		if spot.Time.Sub(existing.Time) > dxClusterSpotLifetime {
+			continue
+		}
+		spots = append(spots, existing)
+	}
+	d.spots = spots
+}
+
+// Recent returns up to limit of the most recent unexpired spots
+func (d *DXCluster) Recent(limit int) []DXSpot {
+	if d == nil {
+		return nil
+	}
+
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	var result []DXSpot
+	for _, spot := range d.spots {
+		if time.Since(spot.Time) > dxClusterSpotLifetime {
+			continue
+		}
+		result = append(result, spot)
+		if len(result) >= limit {
+			break
+		}
+	}
+
+	return result
+}
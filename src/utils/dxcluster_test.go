@@ -0,0 +1,31 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import "testing"
+
+func TestParseDXSpot(t *testing.T) {
+	spot, ok := ParseDXSpot("DX de W3LPL-#:   14025.0  A66H/P       CW 24 dB 25 WPM CQ             1234Z")
+	if !ok {
+		t.Fatal("Expected spot line to parse")
+	}
+
+	if spot.Spotter != "W3LPL" {
+		t.Errorf("Expected spotter W3LPL, got %s", spot.Spotter)
+	}
+	if spot.Frequency != 14025.0 {
+		t.Errorf("Expected 14025.0 kHz, got %v", spot.Frequency)
+	}
+	if !MatchesCallsign(spot.Spotted, "A66H") {
+		t.Errorf("Expected %s to match A66H", spot.Spotted)
+	}
+	if MatchesCallsign(spot.Spotted, "A66") {
+		t.Errorf("Expected %s not to match A66", spot.Spotted)
+	}
+
+	if _, ok := ParseDXSpot("WWV de VE7CC <18>:   SFI=142, A=8, K=2"); ok {
+		t.Error("Expected WWV announcement not to parse as a spot")
+	}
+}
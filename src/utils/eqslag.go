@@ -0,0 +1,123 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// EqslAuthentic reports whether the QSO has an eQSL confirmation that is
+// Authenticity Guaranteed, meaning eQSL has checked the sender's licence
+func (qso QSO) EqslAuthentic() bool {
+	return qso.EqslAG && qso.EqslRcvd.Confirmed()
+}
+
+// EqslAGList is eQSL's list of Authenticity Guaranteed members
+// (AGMemberList.txt), used to mark eQSL confirmations as AG when the log
+// doesn't record it. A nil EqslAGList marks nothing.
+type EqslAGList struct {
+	path    string
+	mutex   sync.RWMutex
+	members map[string]bool
+}
+
+// NewEqslAGList loads the AG member list at path
+func NewEqslAGList(path string) (*EqslAGList, error) {
+	l := &EqslAGList{path: path}
+	if err := l.Load(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Load rereads the member list, picking up a newly downloaded copy. The list
+// has one callsign per line after a header line, which is skipped along with
+// anything else that isn't a callsign.
+func (l *EqslAGList) Load() error {
+	file, err := os.Open(l.path)
+	if err != nil {
+		return fmt.Errorf("failed to open eQSL AG list: %w", err)
+	}
+	defer file.Close()
+
+	members := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		call := strings.ToUpper(strings.TrimSpace(scanner.Text()))
+		if isCallsign(call) {
+			members[call] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read eQSL AG list: %w", err)
+	}
+
+	l.mutex.Lock()
+	l.members = members
+	l.mutex.Unlock()
+	return nil
+}
+
+// Contains reports whether a callsign is an AG member. Portable and
+// prefixed callsigns such as EA8/W1AW/P match the member's own callsign.
+func (l *EqslAGList) Contains(call string) bool {
+	if l == nil {
+		return false
+	}
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	call = strings.ToUpper(call)
+	if l.members[call] {
+		return true
+	}
+	for _, part := range strings.Split(call, "/") {
+		if l.members[part] {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply returns the QSOs with eQSL confirmations from AG members marked as
+// AG. The input slice is not modified.
+func (l *EqslAGList) Apply(qsos []QSO) []QSO {
+	if l == nil {
+		return qsos
+	}
+
+	marked := make([]QSO, len(qsos))
+	for i, qso := range qsos {
+		if !qso.EqslAG && qso.EqslRcvd.Confirmed() && l.Contains(qso.Call) {
+			qso.EqslAG = true
+		}
+		marked[i] = qso
+	}
+	return marked
+}
+
+// isCallsign reports whether s looks like a callsign: letters and digits,
+// including at least one of each, with optional slashes
+func isCallsign(s string) bool {
+	if len(s) < 3 || len(s) > 20 {
+		return false
+	}
+	letter, digit := false, false
+	for _, r := range s {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			letter = true
+		case r >= '0' && r <= '9':
+			digit = true
+		case r != '/':
+			return false
+		}
+	}
+	return letter && digit
+}
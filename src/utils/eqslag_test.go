@@ -0,0 +1,48 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEqslAGList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "AGMemberList.txt")
+	list := "List of Authenticity Guaranteed members, last updated 2025-03-01\n\nW1AW\ndl1abc\n"
+	if err := os.WriteFile(path, []byte(list), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ag, err := NewEqslAGList(path)
+	if err != nil {
+		t.Fatalf("NewEqslAGList: %v", err)
+	}
+	for call, want := range map[string]bool{"W1AW": true, "EA8/DL1ABC/P": true, "K1ABC": false, "LIST": false} {
+		if got := ag.Contains(call); got != want {
+			t.Errorf("Contains(%q) = %v, want %v", call, got, want)
+		}
+	}
+
+	qsos := ag.Apply([]QSO{
+		{Call: "W1AW", EqslRcvd: QslYes},
+		{Call: "DL1ABC", EqslRcvd: QslNo},
+		{Call: "K1ABC", EqslRcvd: QslYes},
+	})
+	if !qsos[0].EqslAuthentic() || qsos[1].EqslAG || qsos[2].EqslAuthentic() {
+		t.Errorf("unexpected AG status %v %v %v", qsos[0].EqslAG, qsos[1].EqslAG, qsos[2].EqslAG)
+	}
+}
+
+func TestParseEqslAGField(t *testing.T) {
+	qso, err := ParseADIFRecord("<CALL:4>W1AW<QSO_DATE:8>20250301<TIME_ON:4>1200<EQSL_QSL_RCVD:1>Y<APP_EQSL_AG:1>Y<EOR>")
+	if err != nil {
+		t.Fatalf("ParseADIFRecord: %v", err)
+	}
+	if !qso.EqslAuthentic() {
+		t.Error("APP_EQSL_AG not parsed")
+	}
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// EventType identifies a kind of station or site activity
+type EventType string
+
+const (
+	EventReload     EventType = "reload"      // ADIF file was reloaded
+	EventNewQSOs    EventType = "new_qso"     // QSOs appeared that weren't in the log before
+	EventLookup     EventType = "lookup"      // A visitor searched for a QSO
+	EventQSLRequest EventType = "qsl_request" // A station requested a paper QSL card
+)
+
+// Event describes something that happened, for delivery to notifiers
+type Event struct {
+	Type EventType
+	Time time.Time
+
+	// Reload
+	Count int
+
+	// New QSOs, the matched QSO of a successful lookup, or the QSO a QSL card
+	// was requested for
+	QSOs []QSO
+
+	// Lookup and QSL request
+	Callsign   string
+	SearchTime time.Time
+	Found      bool
+
+	// QSL request
+	QSLRoute QSLRoute
+}
+
+// EventBus delivers events to subscribers
+type EventBus struct {
+	mutex       sync.RWMutex
+	subscribers []*subscription
+}
+
+type subscription struct {
+	fn func(Event)
+}
+
+// NewEventBus creates an event bus with no subscribers
+func NewEventBus() *EventBus {
+	return &EventBus{}
+}
+
+// Subscribe registers fn to be called for every published event. The returned
+// function removes the subscription again.
+func (b *EventBus) Subscribe(fn func(Event)) func() {
+	sub := &subscription{fn: fn}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.subscribers = append(b.subscribers, sub)
+
+	return func() {
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+		for i, s := range b.subscribers {
+			if s == sub {
+				b.subscribers = append(b.subscribers[:i:i], b.subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Publish delivers an event to all subscribers. Each subscriber is called in
+// its own goroutine so slow notifiers never hold up request handling.
+func (b *EventBus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	for _, sub := range b.subscribers {
+		go sub.fn(e)
+	}
+}
@@ -0,0 +1,41 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFormGuard(t *testing.T) {
+	g := NewFormGuard("secret", 2*time.Second)
+	rendered := time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC)
+	stamp := g.Stamp(rendered, "session")
+
+	tests := []struct {
+		name     string
+		honeypot string
+		stamp    string
+		session  string
+		at       time.Time
+		want     error
+	}{
+		{"valid", "", stamp, "session", rendered.Add(10 * time.Second), nil},
+		{"honeypot", "https://spam.example", stamp, "session", rendered.Add(10 * time.Second), ErrFormHoneypot},
+		{"too fast", "", stamp, "session", rendered.Add(time.Second), ErrFormTooFast},
+		{"missing stamp", "", "", "session", rendered.Add(10 * time.Second), ErrFormStamp},
+		{"forged stamp", "", "1740832200.AAAAAAAAAAAAAAAA", "session", rendered.Add(10 * time.Second), ErrFormStamp},
+		{"other secret", "", NewFormGuard("other", 0).Stamp(rendered, "session"), "session", rendered.Add(10 * time.Second), ErrFormStamp},
+		{"other session", "", stamp, "bot", rendered.Add(10 * time.Second), ErrFormStamp},
+		{"expired", "", stamp, "session", rendered.Add(3 * time.Hour), ErrFormStamp},
+	}
+
+	for _, tt := range tests {
+		if err := g.Check(tt.honeypot, tt.stamp, tt.session, tt.at); !errors.Is(err, tt.want) {
+			t.Errorf("%s: Check() = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
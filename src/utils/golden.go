@@ -0,0 +1,147 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ParseReport is what parsing an ADIF file produced, in the form kept in
+// golden files. Each QSO lists its non-empty fields by QSO field name, so
+// adding a field only changes the golden files of logs that use it.
+type ParseReport struct {
+	QSOs     []map[string]string `json:"qsos"`
+	Warnings []string            `json:"warnings"`
+}
+
+// NewParseReport returns the report of a parser's QSOs and warnings
+func NewParseReport(p *ADIFParser) ParseReport {
+	report := ParseReport{
+		QSOs:     make([]map[string]string, len(p.QSOs)),
+		Warnings: append([]string{}, p.Warnings...),
+	}
+	for i, qso := range p.QSOs {
+		report.QSOs[i] = qsoFieldMap(qso)
+	}
+	return report
+}
+
+// ParseReportOf parses an ADIF file and returns its report
+func ParseReportOf(r io.Reader) (ParseReport, error) {
+	p := NewADIFParser()
+	if err := p.ParseFile(r); err != nil {
+		return ParseReport{}, err
+	}
+	return NewParseReport(p), nil
+}
+
+// qsoFieldMap returns the non-empty fields of a QSO as text, with the
+// timestamp in RFC 3339
+func qsoFieldMap(qso QSO) map[string]string {
+	fields := make(map[string]string)
+	v := reflect.ValueOf(qso)
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if field.IsZero() {
+			continue
+		}
+		name := v.Type().Field(i).Name
+		switch value := field.Interface().(type) {
+		case time.Time:
+			fields[name] = value.UTC().Format(time.RFC3339)
+		case bool:
+			fields[name] = strconv.FormatBool(value)
+		default:
+			fields[name] = field.String()
+		}
+	}
+	return fields
+}
+
+// MarshalGolden encodes the report as indented JSON ending in a newline
+func (r ParseReport) MarshalGolden() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(r); err != nil {
+		return nil, fmt.Errorf("failed to encode parse report: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalGolden decodes a report written by MarshalGolden
+func UnmarshalGolden(data []byte) (ParseReport, error) {
+	var r ParseReport
+	if err := json.Unmarshal(data, &r); err != nil {
+		return ParseReport{}, fmt.Errorf("failed to decode parse report: %w", err)
+	}
+	return r, nil
+}
+
+// DiffParseReports describes how got differs from want, one line per
+// difference, or returns nil if they match
+func DiffParseReports(want, got ParseReport) []string {
+	var diffs []string
+	if len(got.QSOs) != len(want.QSOs) {
+		diffs = append(diffs, fmt.Sprintf("%d QSOs, want %d", len(got.QSOs), len(want.QSOs)))
+	}
+	for i := range min(len(got.QSOs), len(want.QSOs)) {
+		names := make(map[string]bool)
+		for name := range want.QSOs[i] {
+			names[name] = true
+		}
+		for name := range got.QSOs[i] {
+			names[name] = true
+		}
+		sorted := make([]string, 0, len(names))
+		for name := range names {
+			sorted = append(sorted, name)
+		}
+		sort.Strings(sorted)
+
+		call := want.QSOs[i]["Call"]
+		for _, name := range sorted {
+			if g, w := got.QSOs[i][name], want.QSOs[i][name]; g != w {
+				diffs = append(diffs, fmt.Sprintf("QSO %d (%s): %s = %q, want %q", i+1, call, name, g, w))
+			}
+		}
+	}
+
+	if len(got.Warnings) != len(want.Warnings) {
+		diffs = append(diffs, fmt.Sprintf("%d warnings, want %d", len(got.Warnings), len(want.Warnings)))
+	}
+	for i := range min(len(got.Warnings), len(want.Warnings)) {
+		if got.Warnings[i] != want.Warnings[i] {
+			diffs = append(diffs, fmt.Sprintf("warning %d = %q, want %q", i+1, got.Warnings[i], want.Warnings[i]))
+		}
+	}
+	return diffs
+}
+
+// RoundTripDiff writes a parser's QSOs back out as ADIF, parses them again
+// and describes any field that didn't survive the trip. Warnings aren't
+// compared, as skipped records aren't written.
+func RoundTripDiff(p *ADIFParser) ([]string, error) {
+	var buf bytes.Buffer
+	if err := WriteADIF(&buf, "humaid-qsl selftest", p.QSOs); err != nil {
+		return nil, err
+	}
+	reparsed, err := ParseReportOf(&buf)
+	if err != nil {
+		return nil, err
+	}
+
+	want := NewParseReport(p)
+	want.Warnings, reparsed.Warnings = nil, nil
+	return DiffParseReports(want, reparsed), nil
+}
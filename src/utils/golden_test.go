@@ -0,0 +1,100 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden parse reports in testdata/golden")
+
+// TestParseGolden parses the logger exports in testdata/golden and compares
+// each with the report in the .json file of the same name. Run with -update
+// after an intended parser change and review the diff.
+func TestParseGolden(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "golden", "*.adi"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no fixtures found in testdata/golden")
+	}
+
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".adi")
+		t.Run(name, func(t *testing.T) {
+			f, err := os.Open(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			p := NewADIFParser()
+			if err := p.ParseFile(f); err != nil {
+				t.Fatalf("Failed to parse: %v", err)
+			}
+			got := NewParseReport(p)
+
+			golden := strings.TrimSuffix(file, ".adi") + ".json"
+			if *updateGolden {
+				data, err := got.MarshalGolden()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(golden, data, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			data, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("Failed to read golden report (run with -update to create it): %v", err)
+			}
+			want, err := UnmarshalGolden(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, diff := range DiffParseReports(want, got) {
+				t.Error(diff)
+			}
+
+			diffs, err := RoundTripDiff(p)
+			if err != nil {
+				t.Fatalf("Failed to write and reparse: %v", err)
+			}
+			for _, diff := range diffs {
+				t.Errorf("round trip: %s", diff)
+			}
+		})
+	}
+}
+
+func TestDiffParseReports(t *testing.T) {
+	want := ParseReport{
+		QSOs:     []map[string]string{{"Call": "A61AA", "Band": "20m"}},
+		Warnings: []string{"record 2 skipped"},
+	}
+	if diffs := DiffParseReports(want, want); len(diffs) != 0 {
+		t.Errorf("Expected no differences, got %q", diffs)
+	}
+
+	got := ParseReport{
+		QSOs: []map[string]string{{"Call": "A61AA", "Band": "40m", "Mode": "CW"}, {"Call": "W1AW"}},
+	}
+	expected := []string{
+		"2 QSOs, want 1",
+		`QSO 1 (A61AA): Band = "40m", want "20m"`,
+		`QSO 1 (A61AA): Mode = "CW", want ""`,
+		"0 warnings, want 1",
+	}
+	diffs := DiffParseReports(want, got)
+	if strings.Join(diffs, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("DiffParseReports() = %q, want %q", diffs, expected)
+	}
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidateGridSquare checks that s is a Maidenhead locator of 2, 4, 6 or 8
+// characters, e.g. LL, LL75, LL75rb or LL75rb12
+func ValidateGridSquare(s string) error {
+	if s == "" {
+		return fmt.Errorf("empty grid locator")
+	}
+	if len(s)%2 != 0 || len(s) > 8 {
+		return fmt.Errorf("grid locator %q must have 2, 4, 6 or 8 characters", s)
+	}
+
+	g := strings.ToUpper(s)
+	for i := 0; i < len(g); i++ {
+		c := g[i]
+		var ok bool
+		switch i / 2 {
+		case 0: // Field
+			ok = c >= 'A' && c <= 'R'
+		case 1, 3: // Square, extended square
+			ok = c >= '0' && c <= '9'
+		case 2: // Subsquare
+			ok = c >= 'A' && c <= 'X'
+		}
+		if !ok {
+			return fmt.Errorf("grid locator %q has an invalid character at position %d", s, i+1)
+		}
+	}
+
+	return nil
+}
+
+// GridFromLatLng returns the 6 character Maidenhead locator of a position,
+// e.g. LL75rb
+func GridFromLatLng(lat, lng float64) string {
+	// Shift to positive ranges, keeping the poles and antimeridian inside the
+	// last field
+	lng = min(max(lng+180, 0), 360-1e-9)
+	lat = min(max(lat+90, 0), 180-1e-9)
+
+	return string([]byte{
+		'A' + byte(lng/20),
+		'A' + byte(lat/10),
+		'0' + byte(int(lng)%20/2),
+		'0' + byte(int(lat)%10),
+		'a' + byte((lng-2*float64(int(lng/2)))*12),
+		'a' + byte((lat-float64(int(lat)))*24),
+	})
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+func TestValidateGridSquare(t *testing.T) {
+	valid := []string{"LL", "LL75", "LL75rb", "ll75RB", "FN31pr", "JO62qm45", "AA00aa00", "RR99xx99"}
+	for _, g := range valid {
+		if err := ValidateGridSquare(g); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", g, err)
+		}
+	}
+
+	invalid := []string{"", "L", "LL7", "SS00", "LLA5", "LL75yz", "LL75rb1", "LL75rbAA", "LL75rb1234", "LL 75"}
+	for _, g := range invalid {
+		if err := ValidateGridSquare(g); err == nil {
+			t.Errorf("Expected %q to be invalid", g)
+		}
+	}
+}
+
+func TestGridFromLatLng(t *testing.T) {
+	tests := []struct {
+		lat, lng float64
+		want     string
+	}{
+		{41.714775, -72.727260, "FN31pr"},
+		{52.5, 13.39, "JO62qm"},
+		{-90, -180, "AA00aa"},
+		{90, 180, "RR99xx"},
+	}
+
+	for _, tt := range tests {
+		if got := GridFromLatLng(tt.lat, tt.lng); got != tt.want {
+			t.Errorf("GridFromLatLng(%v, %v) = %s, want %s", tt.lat, tt.lng, got, tt.want)
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"FN31pr", "DM79hx", 2738},
+		{"LL75rb", "JO01aa", 5477},
+		{"LL75rb", "LL75rb", 0},
+	}
+
+	for _, tt := range tests {
+		got, err := Distance(tt.a, tt.b)
+		if err != nil {
+			t.Fatalf("Distance(%q, %q) returned error: %v", tt.a, tt.b, err)
+		}
+		if math.Abs(got-tt.want) > 1 {
+			t.Errorf("Distance(%q, %q) = %.1f km, want about %.0f km", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	if _, err := Distance("LL75rb", ""); err == nil {
+		t.Error("Distance with an empty grid should return an error")
+	}
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// gridChaseBands are the VHF and UHF bands with a grid chase
+var gridChaseBands = []string{"6m", "2m", "70cm"}
+
+// GridChaseGrid is a 4-character grid square worked on a band
+type GridChaseGrid struct {
+	Grid        string
+	FirstWorked time.Time
+	Confirmed   bool
+	New         bool // First worked this year
+}
+
+// Status returns the grid's slot status, for colouring
+func (g GridChaseGrid) Status() SlotStatus {
+	if g.Confirmed {
+		return SlotConfirmed
+	}
+	return SlotWorked
+}
+
+// GridChaseBand is the grid chase on one band
+type GridChaseBand struct {
+	Band        string
+	Worked      int
+	Confirmed   int
+	NewThisYear int
+	Grids       []GridChaseGrid // Alphabetically
+}
+
+// ComputeGridChase returns the grids worked on 6m, 2m and 70cm, marking those
+// first worked in the year of now as new. Bands without QSOs are left out.
+func ComputeGridChase(qsos []QSO, now time.Time) []GridChaseBand {
+	grids := make(map[string]map[string]*GridChaseGrid)
+	for _, qso := range qsos {
+		band := strings.ToLower(qso.Band)
+		if !slices.Contains(gridChaseBands, band) {
+			continue
+		}
+		if grids[band] == nil {
+			grids[band] = make(map[string]*GridChaseGrid)
+		}
+
+		for _, grid := range qsoGrids(qso) {
+			g := grids[band][grid]
+			if g == nil {
+				g = &GridChaseGrid{Grid: grid, FirstWorked: qso.Timestamp}
+				grids[band][grid] = g
+			}
+			if !qso.Timestamp.IsZero() && (g.FirstWorked.IsZero() || qso.Timestamp.Before(g.FirstWorked)) {
+				g.FirstWorked = qso.Timestamp
+			}
+			if IsAwardConfirmed(qso) {
+				g.Confirmed = true
+			}
+		}
+	}
+
+	var chase []GridChaseBand
+	for _, band := range gridChaseBands {
+		if len(grids[band]) == 0 {
+			continue
+		}
+
+		b := GridChaseBand{Band: band}
+		for _, g := range grids[band] {
+			g.New = !g.FirstWorked.IsZero() && g.FirstWorked.UTC().Year() == now.UTC().Year()
+			b.Worked++
+			if g.Confirmed {
+				b.Confirmed++
+			}
+			if g.New {
+				b.NewThisYear++
+			}
+			b.Grids = append(b.Grids, *g)
+		}
+		sort.Slice(b.Grids, func(i, j int) bool {
+			return b.Grids[i].Grid < b.Grids[j].Grid
+		})
+		chase = append(chase, b)
+	}
+	return chase
+}
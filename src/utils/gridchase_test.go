@@ -0,0 +1,39 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeGridChase(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	lastYear := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+	thisYear := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	chase := ComputeGridChase([]QSO{
+		{Call: "A61AA", Band: "6m", GridSquare: "LL75ab", Timestamp: lastYear},
+		{Call: "A61AA", Band: "6m", GridSquare: "LL75", QslRcvd: QslYes, Timestamp: thisYear},
+		{Call: "A61BB", Band: "6m", GridSquare: "LL65", Timestamp: thisYear},
+		{Call: "A61CC", Band: "2m", VUCCGrids: "LL74,LL84", Timestamp: thisYear},
+		{Call: "A61DD", Band: "20m", GridSquare: "LL55", Timestamp: thisYear},
+	}, now)
+
+	if len(chase) != 2 || chase[0].Band != "6m" || chase[1].Band != "2m" {
+		t.Fatalf("unexpected bands %+v", chase)
+	}
+
+	six := chase[0]
+	if six.Worked != 2 || six.Confirmed != 1 || six.NewThisYear != 1 {
+		t.Errorf("6m worked %d confirmed %d new %d, want 2, 1 and 1", six.Worked, six.Confirmed, six.NewThisYear)
+	}
+	if six.Grids[0].Grid != "LL65" || !six.Grids[0].New || six.Grids[1].New || !six.Grids[1].Confirmed {
+		t.Errorf("unexpected 6m grids %+v", six.Grids)
+	}
+	if chase[1].Worked != 2 {
+		t.Errorf("2m worked %d grids, want 2", chase[1].Worked)
+	}
+}
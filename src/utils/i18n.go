@@ -0,0 +1,167 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale is used when no supported locale is requested, and for
+// messages missing from another catalog
+const DefaultLocale = "en"
+
+// rtlLocales are written right to left
+var rtlLocales = map[string]bool{"ar": true, "fa": true, "he": true, "ur": true}
+
+// Locale is a supported language shown in the language switcher
+type Locale struct {
+	Code string
+	Name string // Name of the language in that language
+}
+
+// Catalog holds translated messages for each supported locale
+type Catalog struct {
+	messages map[string]map[string]string
+	locales  []Locale
+}
+
+// LoadCatalog loads every <locale>.json message file in fsys. Each file is a
+// flat object of message keys to text, with fmt verbs for arguments.
+func LoadCatalog(fsys fs.FS) (*Catalog, error) {
+	files, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list message catalogs: %w", err)
+	}
+
+	c := &Catalog{messages: make(map[string]map[string]string)}
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+
+		code := strings.TrimSuffix(path.Base(file), ".json")
+		c.messages[code] = messages
+		c.locales = append(c.locales, Locale{Code: code, Name: messages["language.name"]})
+	}
+
+	if _, ok := c.messages[DefaultLocale]; !ok {
+		return nil, fmt.Errorf("missing %s message catalog", DefaultLocale)
+	}
+	sort.Slice(c.locales, func(i, j int) bool { return c.locales[i].Code < c.locales[j].Code })
+	return c, nil
+}
+
+// Locales returns the supported locales ordered by code
+func (c *Catalog) Locales() []Locale {
+	return c.locales
+}
+
+// Supported reports whether there is a catalog for locale
+func (c *Catalog) Supported(locale string) bool {
+	_, ok := c.messages[locale]
+	return ok
+}
+
+// Translate returns the message for key in locale, formatted with args. It
+// falls back to the default locale, then to the key itself.
+func (c *Catalog) Translate(locale, key string, args ...interface{}) string {
+	if key == "" {
+		return ""
+	}
+	msg, ok := c.messages[locale][key]
+	if !ok {
+		if msg, ok = c.messages[DefaultLocale][key]; !ok {
+			msg = key
+		}
+	}
+	// Some plural forms spell out the number, so have no verbs to fill
+	if len(args) > 0 && strings.Contains(msg, "%") {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// TranslatePlural returns the plural form of key for n, such as key+".one"
+// or key+".other", formatted with n followed by args. Forms missing from a
+// catalog fall back to key+".other".
+func (c *Catalog) TranslatePlural(locale, key string, n int, args ...interface{}) string {
+	if !c.Supported(locale) {
+		locale = DefaultLocale
+	}
+	form := key + "." + pluralForm(locale, n)
+	if _, ok := c.messages[locale][form]; !ok {
+		form = key + ".other"
+	}
+	return c.Translate(locale, form, append([]interface{}{n}, args...)...)
+}
+
+// pluralForm returns the CLDR plural category of n in locale
+func pluralForm(locale string, n int) string {
+	if n < 0 {
+		n = -n
+	}
+	if locale == "ar" {
+		switch mod := n % 100; {
+		case n == 0:
+			return "zero"
+		case n == 1:
+			return "one"
+		case n == 2:
+			return "two"
+		case mod >= 3 && mod <= 10:
+			return "few"
+		case mod >= 11 && mod <= 99:
+			return "many"
+		}
+		return "other"
+	}
+	if n == 1 {
+		return "one"
+	}
+	return "other"
+}
+
+// Negotiate picks the supported locale best matching an Accept-Language
+// header, or the default locale
+func (c *Catalog) Negotiate(acceptLanguage string) string {
+	best, bestQ := DefaultLocale, 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+
+		// Catalogs are per language, so en-GB uses the en catalog
+		lang, _, _ := strings.Cut(tag, "-")
+		if q > bestQ && c.Supported(lang) {
+			best, bestQ = lang, q
+		}
+	}
+	return best
+}
+
+// Direction returns the text direction of locale for the HTML dir attribute
+func Direction(locale string) string {
+	if rtlLocales[locale] {
+		return "rtl"
+	}
+	return "ltr"
+}
@@ -0,0 +1,154 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/humaidq/humaid-qsl/locales"
+)
+
+func testCatalog(t *testing.T) *Catalog {
+	t.Helper()
+	c, err := LoadCatalog(fstest.MapFS{
+		"en.json": {Data: []byte(`{"language.name": "English", "greeting": "Hello %s", "only.en": "English only", "qso.one": "%d QSO", "qso.other": "%d QSOs"}`)},
+		"ar.json": {Data: []byte(`{"language.name": "العربية", "greeting": "مرحباً %s"}`)},
+	})
+	if err != nil {
+		t.Fatalf("LoadCatalog failed: %v", err)
+	}
+	return c
+}
+
+func TestTranslate(t *testing.T) {
+	c := testCatalog(t)
+
+	tests := []struct {
+		locale, key string
+		args        []interface{}
+		want        string
+	}{
+		{"en", "greeting", []interface{}{"A66H"}, "Hello A66H"},
+		{"ar", "greeting", []interface{}{"A66H"}, "مرحباً A66H"},
+		{"ar", "only.en", nil, "English only"},
+		{"fr", "greeting", []interface{}{"A66H"}, "Hello A66H"},
+		{"en", "missing.key", nil, "missing.key"},
+		{"en", "", nil, ""},
+	}
+	for _, tt := range tests {
+		if got := c.Translate(tt.locale, tt.key, tt.args...); got != tt.want {
+			t.Errorf("Translate(%q, %q) = %q, want %q", tt.locale, tt.key, got, tt.want)
+		}
+	}
+
+	if got := c.TranslatePlural("en", "qso", 1); got != "1 QSO" {
+		t.Errorf("Expected singular form, got %q", got)
+	}
+	if got := c.TranslatePlural("en", "qso", 3); got != "3 QSOs" {
+		t.Errorf("Expected plural form, got %q", got)
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	c := testCatalog(t)
+
+	tests := map[string]string{
+		"":                        "en",
+		"ar":                      "ar",
+		"ar-AE,ar;q=0.9,en;q=0.8": "ar",
+		"fr-FR,fr;q=0.9,en;q=0.5": "en",
+		"en-GB,en;q=0.9,ar;q=0.8": "en",
+		"de;q=0.9, ar;q=0.7":      "ar",
+		"fr, de":                  "en",
+		"en;q=0.2, ar;q=0.8":      "ar",
+	}
+	for header, want := range tests {
+		if got := c.Negotiate(header); got != want {
+			t.Errorf("Negotiate(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestLoadCatalogRequiresDefault(t *testing.T) {
+	_, err := LoadCatalog(fstest.MapFS{"ar.json": {Data: []byte(`{}`)}})
+	if err == nil {
+		t.Error("Expected an error without the default catalog")
+	}
+}
+
+func TestBundledCatalogsComplete(t *testing.T) {
+	c, err := LoadCatalog(locales.Locales)
+	if err != nil {
+		t.Fatalf("LoadCatalog failed: %v", err)
+	}
+
+	for _, locale := range c.Locales() {
+		if locale.Name == "" {
+			t.Errorf("Catalog %s has no language.name", locale.Code)
+		}
+		for key := range c.messages[DefaultLocale] {
+			if _, ok := c.messages[locale.Code][key]; !ok {
+				t.Errorf("Catalog %s is missing %q", locale.Code, key)
+			}
+		}
+	}
+}
+
+func TestLocaleFormatting(t *testing.T) {
+	c, err := LoadCatalog(locales.Locales)
+	if err != nil {
+		t.Fatalf("LoadCatalog failed: %v", err)
+	}
+
+	qsoTime := time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC)
+	if got := c.FormatDate("es", qsoTime); got != "01/03/2025" {
+		t.Errorf("Expected a day-first Spanish date, got %q", got)
+	}
+	if got := c.FormatDate("en", time.Time{}); got != "" {
+		t.Errorf("Expected no date for a zero time, got %q", got)
+	}
+
+	numbers := []struct {
+		locale   string
+		v        float64
+		decimals int
+		want     string
+	}{
+		{"en", 1234567, 0, "1,234,567"},
+		{"es", 1234567.891, 2, "1.234.567,89"},
+		{"en", -2738.4, 0, "-2,738"},
+		{"en", 999, 0, "999"},
+	}
+	for _, n := range numbers {
+		if got := c.FormatFloat(n.locale, n.v, n.decimals); got != n.want {
+			t.Errorf("FormatFloat(%q, %v, %d) = %q, want %q", n.locale, n.v, n.decimals, got, n.want)
+		}
+	}
+
+	relative := []struct {
+		locale string
+		ago    time.Duration
+		want   string
+	}{
+		{"en", 0, "just now"},
+		{"en", 90 * time.Second, "1 minute ago"},
+		{"en", 3 * time.Hour, "3 hours ago"},
+		{"es", 2 * 24 * time.Hour, "hace 2 días"},
+		{"ar", 2 * time.Hour, "منذ ساعتين"},
+		{"ar", 5 * time.Minute, "منذ 5 دقائق"},
+		{"en", -10 * 24 * time.Hour, "1 week from now"},
+	}
+	for _, r := range relative {
+		if got := c.RelativeTime(r.locale, qsoTime.Add(-r.ago), qsoTime); got != r.want {
+			t.Errorf("RelativeTime(%q, %v) = %q, want %q", r.locale, r.ago, got, r.want)
+		}
+	}
+
+	if got := c.TranslatePlural("ar", "callsign.count", 1, "W1AW"); got != "اتصال واحد مسجل بين A66H و W1AW." {
+		t.Errorf("Expected the spelled out Arabic singular, got %q", got)
+	}
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// relativeUnits are the units used for relative times, largest first
+var relativeUnits = []struct {
+	key string
+	d   time.Duration
+}{
+	{"time.year", 365 * 24 * time.Hour},
+	{"time.month", 30 * 24 * time.Hour},
+	{"time.week", 7 * 24 * time.Hour},
+	{"time.day", 24 * time.Hour},
+	{"time.hour", time.Hour},
+	{"time.minute", time.Minute},
+	{"time.second", time.Second},
+}
+
+// FormatDate formats the UTC date of t using the locale's "format.date"
+// layout
+func (c *Catalog) FormatDate(locale string, t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(c.Translate(locale, "format.date"))
+}
+
+// FormatDateTime formats the UTC date and time of t using the locale's
+// "format.datetime" layout
+func (c *Catalog) FormatDateTime(locale string, t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(c.Translate(locale, "format.datetime"))
+}
+
+// RelativeTime describes t relative to now, such as "3 hours ago", in the
+// largest whole unit
+func (c *Catalog) RelativeTime(locale string, t, now time.Time) string {
+	d := now.Sub(t)
+	key := "time.ago"
+	if d < 0 {
+		d, key = -d, "time.fromnow"
+	}
+	if d < time.Second {
+		return c.Translate(locale, "time.now")
+	}
+
+	for _, unit := range relativeUnits {
+		if d >= unit.d {
+			return c.Translate(locale, key, c.TranslatePlural(locale, unit.key, int(d/unit.d)))
+		}
+	}
+	return c.Translate(locale, "time.now")
+}
+
+// FormatInt formats n with the locale's digit grouping
+func (c *Catalog) FormatInt(locale string, n int) string {
+	return c.FormatFloat(locale, float64(n), 0)
+}
+
+// FormatFloat formats v with the given number of decimals, using the
+// locale's decimal separator and digit grouping
+func (c *Catalog) FormatFloat(locale string, v float64, decimals int) string {
+	s := strconv.FormatFloat(v, 'f', decimals, 64)
+
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	whole, frac, _ := strings.Cut(s, ".")
+
+	group := c.Translate(locale, "format.group")
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(group)
+		}
+		b.WriteRune(digit)
+	}
+	if frac != "" {
+		b.WriteString(c.Translate(locale, "format.decimal"))
+		b.WriteString(frac)
+	}
+	return b.String()
+}
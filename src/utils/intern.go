@@ -0,0 +1,43 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import "strings"
+
+// stringPool interns strings so that values repeated across many QSOs, such
+// as bands, modes and my own station details, share one copy
+type stringPool map[string]string
+
+// intern returns the pooled copy of s, adding a copy of it on first use. The
+// copy keeps the pool from holding on to the larger string s may be part of.
+func (p stringPool) intern(s string) string {
+	if s == "" {
+		return ""
+	}
+	if v, ok := p[s]; ok {
+		return v
+	}
+	v := strings.Clone(s)
+	p[v] = v
+	return v
+}
+
+// internLower returns the pooled lower case form of an ASCII name, using buf
+// as scratch space so that names seen before don't allocate
+func (p stringPool) internLower(name string, buf []byte) string {
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c >= 'A' && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		buf = append(buf, c)
+	}
+	if v, ok := p[string(buf)]; ok {
+		return v
+	}
+	v := string(buf)
+	p[v] = v
+	return v
+}
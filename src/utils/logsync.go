@@ -0,0 +1,99 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogSync pushes new QSOs to a Wavelog or Cloudlog instance through its
+// api/qso endpoint, which both share, for operators who also keep their log
+// there
+type LogSync struct {
+	endpoint  string
+	key       string
+	stationID string
+	client    *http.Client
+	mutex     sync.Mutex // Pushes one batch at a time, in no particular order
+}
+
+// logSyncRequest is the body of an api/qso request
+type logSyncRequest struct {
+	Key              string `json:"key"`
+	StationProfileID string `json:"station_profile_id"`
+	Type             string `json:"type"`
+	String           string `json:"string"`
+}
+
+// NewLogSync creates a sync to the Wavelog or Cloudlog instance at baseURL,
+// logging QSOs with the API key into the given station profile
+func NewLogSync(baseURL, key, stationID string) *LogSync {
+	return &LogSync{
+		endpoint:  strings.TrimSuffix(baseURL, "/") + "/index.php/api/qso",
+		key:       key,
+		stationID: stationID,
+		client:    &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Handle pushes the QSOs of new QSO events. It is meant to be subscribed to
+// an EventBus.
+func (s *LogSync) Handle(e Event) {
+	if e.Type != EventNewQSOs || len(e.QSOs) == 0 {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	pushed := 0
+	for _, qso := range e.QSOs {
+		if err := s.Push(qso); err != nil {
+			log.Printf("Failed to push QSO with %s to %s: %v", qso.Call, s.endpoint, err)
+			continue
+		}
+		pushed++
+	}
+	log.Printf("Pushed %d of %d new QSOs to %s", pushed, len(e.QSOs), s.endpoint)
+}
+
+// Push sends a single QSO to the remote log
+func (s *LogSync) Push(qso QSO) error {
+	body, err := json.Marshal(logSyncRequest{
+		Key:              s.key,
+		StationProfileID: s.stationID,
+		Type:             "adif",
+		String:           FormatADIFRecord(qso),
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Post(s.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		var failed struct {
+			Reason string `json:"reason"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(data, &failed) == nil && failed.Reason != "" {
+			return fmt.Errorf("remote log returned status %d: %s", resp.StatusCode, failed.Reason)
+		}
+		return fmt.Errorf("remote log returned status %d", resp.StatusCode)
+	}
+	return nil
+}
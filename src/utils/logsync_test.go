@@ -0,0 +1,45 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLogSyncPush(t *testing.T) {
+	var got logSyncRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/index.php/api/qso" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("invalid request: %v", err)
+		}
+		if got.Key != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"status":"failed","reason":"missing api key"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	qso := QSO{Call: "W1AW", QSODate: "20250301", TimeOn: "1200", Band: "20m", Mode: "CW"}
+	if err := NewLogSync(server.URL+"/", "secret", "2").Push(qso); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+	if got.StationProfileID != "2" || got.Type != "adif" || !strings.Contains(got.String, "<CALL:4>W1AW") {
+		t.Errorf("unexpected request %+v", got)
+	}
+
+	err := NewLogSync(server.URL, "wrong", "2").Push(qso)
+	if err == nil || !strings.Contains(err.Error(), "missing api key") {
+		t.Errorf("Push with a wrong key = %v, want the remote reason", err)
+	}
+}
@@ -0,0 +1,185 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LookupLogEntry is a QSO search recorded in the lookup log
+type LookupLogEntry struct {
+	Time       time.Time // When the search was made
+	Callsign   string
+	SearchTime time.Time // The QSO time searched for
+	RemoteAddr string
+	Found      bool
+}
+
+// CallsignCount is the number of searches for a callsign
+type CallsignCount struct {
+	Callsign string
+	Count    int
+	Found    int
+}
+
+// DailyLookups is the number of searches made on a day
+type DailyLookups struct {
+	Date     string
+	Found    int
+	NotFound int
+
+	// Bar widths as a percentage of the busiest day, for charts
+	FoundPercent    int
+	NotFoundPercent int
+}
+
+// LookupStats is an aggregate view of the lookup log
+type LookupStats struct {
+	Total    int
+	Found    int
+	NotFound int
+
+	TopCallsigns []CallsignCount
+	Misses       []LookupLogEntry // Most recent searches that found nothing, newest first
+	Daily        []DailyLookups   // Oldest first
+}
+
+// SuccessRate returns the percentage of searches that found a QSO
+func (s LookupStats) SuccessRate() int {
+	if s.Total == 0 {
+		return 0
+	}
+	return s.Found * 100 / s.Total
+}
+
+// ParseLookupLogLine parses a line of the lookup log, in the form
+// [2006-01-02 15:04:05] QSO_SEARCH CALL 2006-01-02 15:04 ADDR - SUCCESS
+func ParseLookupLogLine(line string) (LookupLogEntry, bool) {
+	var entry LookupLogEntry
+
+	end := strings.Index(line, "]")
+	if !strings.HasPrefix(line, "[") || end == -1 {
+		return entry, false
+	}
+	logged, err := time.Parse("2006-01-02 15:04:05", line[1:end])
+	if err != nil {
+		return entry, false
+	}
+
+	fields := strings.Fields(line[end+1:])
+	if len(fields) != 7 || fields[0] != "QSO_SEARCH" || fields[5] != "-" {
+		return entry, false
+	}
+
+	searched, err := time.Parse("2006-01-02 15:04", fields[2]+" "+fields[3])
+	if err != nil {
+		return entry, false
+	}
+
+	switch fields[6] {
+	case "SUCCESS":
+		entry.Found = true
+	case "NOT_FOUND":
+	default:
+		return entry, false
+	}
+
+	entry.Time = logged
+	entry.Callsign = fields[1]
+	entry.SearchTime = searched
+	entry.RemoteAddr = fields[4]
+	return entry, true
+}
+
+// ComputeLookupStats aggregates a lookup log, keeping the top callsigns, the
+// most recent misses, and daily totals for the given number of days up to now
+func ComputeLookupStats(r io.Reader, now time.Time, days, top, misses int) (LookupStats, error) {
+	var stats LookupStats
+
+	counts := make(map[string]*CallsignCount)
+	daily := make(map[string]*DailyLookups)
+	var missed []LookupLogEntry
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		entry, ok := ParseLookupLogLine(scanner.Text())
+		if !ok {
+			continue
+		}
+
+		stats.Total++
+		cc := counts[entry.Callsign]
+		if cc == nil {
+			cc = &CallsignCount{Callsign: entry.Callsign}
+			counts[entry.Callsign] = cc
+		}
+		cc.Count++
+
+		day := entry.Time.Format("2006-01-02")
+		d := daily[day]
+		if d == nil {
+			d = &DailyLookups{Date: day}
+			daily[day] = d
+		}
+
+		if entry.Found {
+			stats.Found++
+			cc.Found++
+			d.Found++
+		} else {
+			stats.NotFound++
+			d.NotFound++
+			missed = append(missed, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return stats, fmt.Errorf("failed to read lookup log: %w", err)
+	}
+
+	for _, cc := range counts {
+		stats.TopCallsigns = append(stats.TopCallsigns, *cc)
+	}
+	sort.Slice(stats.TopCallsigns, func(i, j int) bool {
+		a, b := stats.TopCallsigns[i], stats.TopCallsigns[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Callsign < b.Callsign
+	})
+	if len(stats.TopCallsigns) > top {
+		stats.TopCallsigns = stats.TopCallsigns[:top]
+	}
+
+	for i := len(missed) - 1; i >= 0 && len(stats.Misses) < misses; i-- {
+		stats.Misses = append(stats.Misses, missed[i])
+	}
+
+	// Fill every day in the range, including quiet ones, so charts line up
+	busiest := 0
+	for i := days - 1; i >= 0; i-- {
+		day := now.AddDate(0, 0, -i).Format("2006-01-02")
+		d := DailyLookups{Date: day}
+		if counted := daily[day]; counted != nil {
+			d = *counted
+		}
+		if total := d.Found + d.NotFound; total > busiest {
+			busiest = total
+		}
+		stats.Daily = append(stats.Daily, d)
+	}
+	if busiest > 0 {
+		for i := range stats.Daily {
+			stats.Daily[i].FoundPercent = stats.Daily[i].Found * 100 / busiest
+			stats.Daily[i].NotFoundPercent = stats.Daily[i].NotFound * 100 / busiest
+		}
+	}
+
+	return stats, nil
+}
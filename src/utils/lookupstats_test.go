@@ -0,0 +1,56 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLookupLogLine(t *testing.T) {
+	entry, ok := ParseLookupLogLine("[2025-03-01 10:15:00] QSO_SEARCH A61XX 2025-02-28 18:30 192.0.2.1:51234 - NOT_FOUND")
+	if !ok {
+		t.Fatal("Expected log line to parse")
+	}
+	if entry.Callsign != "A61XX" || entry.Found {
+		t.Errorf("Expected unsuccessful search for A61XX, got %+v", entry)
+	}
+	if !entry.SearchTime.Equal(time.Date(2025, 2, 28, 18, 30, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected search time %v", entry.SearchTime)
+	}
+
+	if _, ok := ParseLookupLogLine("[2025-03-01 10:15:00] GET / 192.0.2.1:51234 - 1ms"); ok {
+		t.Error("Expected access log line not to parse")
+	}
+}
+
+func TestComputeLookupStats(t *testing.T) {
+	log := strings.Join([]string{
+		"[2025-03-01 10:15:00] QSO_SEARCH A61XX 2025-02-28 18:30 192.0.2.1:1 - NOT_FOUND",
+		"[2025-03-01 10:16:00] QSO_SEARCH A61XX 2025-02-28 18:40 192.0.2.1:1 - SUCCESS",
+		"[2025-03-02 09:00:00] QSO_SEARCH W1AW 2025-03-01 12:00 192.0.2.2:1 - NOT_FOUND",
+		"garbage",
+	}, "\n")
+
+	now := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+	stats, err := ComputeLookupStats(strings.NewReader(log), now, 3, 10, 10)
+	if err != nil {
+		t.Fatalf("Failed to compute stats: %v", err)
+	}
+
+	if stats.Total != 3 || stats.Found != 1 || stats.SuccessRate() != 33 {
+		t.Errorf("Expected 3 searches with 1 found, got %d/%d", stats.Total, stats.Found)
+	}
+	if stats.TopCallsigns[0].Callsign != "A61XX" || stats.TopCallsigns[0].Count != 2 {
+		t.Errorf("Expected A61XX to be the top callsign, got %+v", stats.TopCallsigns)
+	}
+	if len(stats.Misses) != 2 || stats.Misses[0].Callsign != "W1AW" {
+		t.Errorf("Expected 2 misses newest first, got %+v", stats.Misses)
+	}
+	if len(stats.Daily) != 3 || stats.Daily[1].Found != 1 || stats.Daily[1].FoundPercent != 50 {
+		t.Errorf("Unexpected daily totals %+v", stats.Daily)
+	}
+}
@@ -0,0 +1,341 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// lotwMatchWindow is how far apart the logged and confirmed QSO times may be,
+// as LoTW itself allows
+const lotwMatchWindow = 30 * time.Minute
+
+// LoTWConfirmation is a confirmed QSO from a LoTW report (lotwreport.adi),
+// with the confirming station's details and my station location the QSO was
+// uploaded from
+type LoTWConfirmation struct {
+	Call      string    `json:"call"`
+	QSOTime   time.Time `json:"qso_time"`
+	Band      string    `json:"band"`
+	ModeGroup string    `json:"mode_group"` // CW, PHONE or DATA
+	QSLDate   time.Time `json:"qsl_date"`
+
+	// My station location, from the report's MY_ fields
+	StationCall  string `json:"station_callsign,omitempty"`
+	MyGridSquare string `json:"my_gridsquare,omitempty"`
+
+	// The other station's details as they uploaded them
+	GridSquare string   `json:"gridsquare,omitempty"`
+	DXCC       string   `json:"dxcc,omitempty"`
+	Country    string   `json:"country,omitempty"`
+	CQZone     string   `json:"cqz,omitempty"`
+	State      string   `json:"state,omitempty"`
+	Credit     []string `json:"credit,omitempty"` // Award credit granted, e.g. DXCC:CW
+}
+
+// Key identifies the confirmed QSO
+func (c LoTWConfirmation) Key() string {
+	return fmt.Sprintf("%s|%d|%s", c.Call, c.QSOTime.Unix(), c.Band)
+}
+
+// LoTWModeGroup returns the LoTW mode group of an ADIF mode
+func LoTWModeGroup(mode string) string {
+	switch strings.ToUpper(strings.TrimSpace(mode)) {
+	case "CW":
+		return "CW"
+	case "SSB", "USB", "LSB", "AM", "FM", "DIGITALVOICE":
+		return "PHONE"
+	}
+	return "DATA"
+}
+
+// matches reports whether the confirmation is for a logged QSO. Besides the
+// callsign, band, mode group and time, my station callsign and grid must
+// agree when both are known, so QSOs made from different station locations,
+// such as portable operations, aren't confused.
+func (c LoTWConfirmation) matches(qso QSO) bool {
+	if !strings.EqualFold(c.Call, qso.Call) || !strings.EqualFold(c.Band, qso.Band) {
+		return false
+	}
+	if c.ModeGroup != "" && c.ModeGroup != LoTWModeGroup(qso.Mode) {
+		return false
+	}
+	if d := c.QSOTime.Sub(qso.Timestamp); d > lotwMatchWindow || d < -lotwMatchWindow {
+		return false
+	}
+	if c.StationCall != "" && qso.StationCall != "" && !strings.EqualFold(c.StationCall, qso.StationCall) {
+		return false
+	}
+	if len(c.MyGridSquare) >= 4 && len(qso.MyGridSquare) >= 4 && !strings.EqualFold(c.MyGridSquare[:4], qso.MyGridSquare[:4]) {
+		return false
+	}
+	return true
+}
+
+// apply marks a QSO confirmed on LoTW and fills in details the log is
+// missing from the other station's upload
+func (c LoTWConfirmation) apply(qso QSO) QSO {
+	qso.LotwSent = QslYes
+	qso.LotwRcvd = QslYes
+	if qso.LotwRcvdDate == "" && !c.QSLDate.IsZero() {
+		qso.LotwRcvdDate = c.QSLDate.UTC().Format("20060102")
+	}
+	for _, f := range []struct {
+		field *string
+		value string
+	}{
+		{&qso.GridSquare, c.GridSquare},
+		{&qso.DXCC, c.DXCC},
+		{&qso.Country, c.Country},
+		{&qso.CQZone, c.CQZone},
+		{&qso.State, c.State},
+	} {
+		if *f.field == "" {
+			*f.field = f.value
+		}
+	}
+	return qso
+}
+
+// ParseLoTWReport parses the confirmed QSOs in a LoTW report. Unconfirmed
+// QSOs, which are included when the report is downloaded with qso_qsl=no,
+// are skipped.
+func ParseLoTWReport(r io.Reader) ([]LoTWConfirmation, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read LoTW report: %w", err)
+	}
+	content := string(data)
+	if _, end := findEndTag(content, "eoh"); end != -1 {
+		content = content[end:]
+	}
+
+	p := NewADIFParser()
+	pool := make(stringPool)
+	var confirmations []LoTWConfirmation
+	for len(content) > 0 {
+		var record string
+		if start, end := findEndTag(content, "eor"); start != -1 {
+			record, content = content[:start], content[end:]
+		} else {
+			record, content = content, ""
+		}
+
+		fields := appendFields(nil, record, pool)
+		values := make(map[string]string, len(fields))
+		for _, f := range fields {
+			values[f.name] = f.value
+		}
+		if ParseQslStatus(values["qsl_rcvd"]) != QslYes || values["call"] == "" {
+			continue
+		}
+
+		qsoTime, err := p.parseTimestamp(values["qso_date"], values["time_on"])
+		if err != nil {
+			continue
+		}
+		c := LoTWConfirmation{
+			Call:         strings.ToUpper(values["call"]),
+			QSOTime:      qsoTime,
+			Band:         strings.ToLower(values["band"]),
+			ModeGroup:    strings.ToUpper(values["app_lotw_modegroup"]),
+			StationCall:  strings.ToUpper(values["station_callsign"]),
+			MyGridSquare: values["my_gridsquare"],
+			GridSquare:   values["gridsquare"],
+			DXCC:         values["dxcc"],
+			Country:      values["country"],
+			CQZone:       values["cqz"],
+			State:        strings.ToUpper(values["state"]),
+		}
+		if c.ModeGroup == "" {
+			c.ModeGroup = LoTWModeGroup(values["mode"])
+		}
+		if qslDate, err := time.Parse("20060102", values["qslrdate"]); err == nil {
+			c.QSLDate = qslDate
+		}
+		if credit := values["credit_granted"]; credit != "" {
+			c.Credit = strings.Split(credit, ",")
+		}
+		confirmations = append(confirmations, c)
+	}
+	return confirmations, nil
+}
+
+// LoTWStore keeps imported LoTW confirmations in a JSON file and merges them
+// into the log, so confirmations show without editing the ADIF file
+type LoTWStore struct {
+	path          string
+	mutex         sync.RWMutex
+	confirmations map[string]LoTWConfirmation
+	byCall        map[string][]LoTWConfirmation
+}
+
+// NewLoTWStore loads the confirmations stored at path, starting empty if the
+// file doesn't exist yet
+func NewLoTWStore(path string) (*LoTWStore, error) {
+	s := &LoTWStore{path: path}
+	if err := s.Load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Load rereads the stored confirmations, picking up imports made from the
+// command line
+func (s *LoTWStore) Load() error {
+	var confirmations []LoTWConfirmation
+	if err := loadJSONFile(s.path, &confirmations); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.set(confirmations)
+	return nil
+}
+
+// Import merges confirmations into the store, replacing earlier copies of the
+// same QSOs, and returns how many were new
+func (s *LoTWStore) Import(confirmations []LoTWConfirmation) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	previous := s.list()
+	added := 0
+	for _, c := range confirmations {
+		if _, exists := s.confirmations[c.Key()]; !exists {
+			added++
+		}
+	}
+	// Later copies replace earlier ones, so new imports win
+	s.set(append(slices.Clip(previous), confirmations...))
+
+	if err := s.save(); err != nil {
+		s.set(previous)
+		return 0, err
+	}
+	return added, nil
+}
+
+// Count returns the number of stored confirmations
+func (s *LoTWStore) Count() int {
+	if s == nil {
+		return 0
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return len(s.confirmations)
+}
+
+// Apply returns the QSOs with their LoTW confirmations merged in. The input
+// slice is not modified.
+func (s *LoTWStore) Apply(qsos []QSO) []QSO {
+	if s == nil {
+		return qsos
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if len(s.confirmations) == 0 {
+		return qsos
+	}
+
+	merged := make([]QSO, len(qsos))
+	for i, qso := range qsos {
+		if c, ok := s.match(qso); ok {
+			qso = c.apply(qso)
+		}
+		merged[i] = qso
+	}
+	return merged
+}
+
+// Unmatched returns the stored confirmations that match none of the QSOs,
+// oldest first
+func (s *LoTWStore) Unmatched(qsos []QSO) []LoTWConfirmation {
+	if s == nil {
+		return nil
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	matched := make(map[string]bool)
+	for _, qso := range qsos {
+		if c, ok := s.match(qso); ok {
+			matched[c.Key()] = true
+		}
+	}
+
+	var unmatched []LoTWConfirmation
+	for _, c := range s.list() {
+		if !matched[c.Key()] {
+			unmatched = append(unmatched, c)
+		}
+	}
+	return unmatched
+}
+
+// match returns the confirmation closest in time to a QSO. The caller must
+// hold the lock.
+func (s *LoTWStore) match(qso QSO) (LoTWConfirmation, bool) {
+	var best LoTWConfirmation
+	found := false
+	for _, c := range s.byCall[strings.ToUpper(qso.Call)] {
+		if !c.matches(qso) {
+			continue
+		}
+		if !found || absDuration(c.QSOTime.Sub(qso.Timestamp)) < absDuration(best.QSOTime.Sub(qso.Timestamp)) {
+			best, found = c, true
+		}
+	}
+	return best, found
+}
+
+// set replaces the stored confirmations. The caller must hold the write
+// lock.
+func (s *LoTWStore) set(confirmations []LoTWConfirmation) {
+	s.confirmations = make(map[string]LoTWConfirmation, len(confirmations))
+	for _, c := range confirmations {
+		s.confirmations[c.Key()] = c
+	}
+	s.byCall = make(map[string][]LoTWConfirmation)
+	for _, c := range s.confirmations {
+		s.byCall[c.Call] = append(s.byCall[c.Call], c)
+	}
+}
+
+// list returns the confirmations by QSO time. The caller must hold the lock.
+func (s *LoTWStore) list() []LoTWConfirmation {
+	confirmations := make([]LoTWConfirmation, 0, len(s.confirmations))
+	for _, c := range s.confirmations {
+		confirmations = append(confirmations, c)
+	}
+	sort.Slice(confirmations, func(i, j int) bool {
+		if !confirmations[i].QSOTime.Equal(confirmations[j].QSOTime) {
+			return confirmations[i].QSOTime.Before(confirmations[j].QSOTime)
+		}
+		return confirmations[i].Call < confirmations[j].Call
+	})
+	return confirmations
+}
+
+// save writes the confirmations to disk. The caller must hold the lock.
+func (s *LoTWStore) save() error {
+	return saveJSONFile(s.path, s.list(), 0644)
+}
+
+// absDuration returns the absolute value of d
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
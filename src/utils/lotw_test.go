@@ -0,0 +1,113 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const lotwReport = `ARRL Logbook of the World Status Report
+<PROGRAMID:4>LoTW
+<APP_LoTW_NUMREC:1>3
+<eoh>
+<APP_LoTW_OWNCALL:4>A66H
+<STATION_CALLSIGN:4>A66H
+<MY_GRIDSQUARE:6>LL75rm
+<CALL:4>W1AW
+<BAND:3>20M
+<MODE:3>FT8
+<APP_LoTW_MODEGROUP:4>DATA
+<QSO_DATE:8>20250301
+<TIME_ON:6>120200
+<QSL_RCVD:1>Y
+<QSLRDATE:8>20250305
+<GRIDSQUARE:4>FN31
+<DXCC:3>291
+<CQZ:1>5
+<CREDIT_GRANTED:13>DXCC,DXCC_BAND
+<eor>
+<STATION_CALLSIGN:6>A66H/P
+<MY_GRIDSQUARE:4>LL64
+<CALL:6>DL1ABC
+<BAND:3>40M
+<MODE:2>CW
+<QSO_DATE:8>20250302
+<TIME_ON:4>1800
+<QSL_RCVD:1>Y
+<eor>
+<CALL:5>K1ABC
+<BAND:3>20M
+<MODE:3>SSB
+<QSO_DATE:8>20250303
+<TIME_ON:4>0900
+<QSL_RCVD:1>N
+<eor>
+`
+
+func TestParseLoTWReport(t *testing.T) {
+	confirmations, err := ParseLoTWReport(strings.NewReader(lotwReport))
+	if err != nil {
+		t.Fatalf("ParseLoTWReport: %v", err)
+	}
+	if len(confirmations) != 2 {
+		t.Fatalf("got %d confirmations, want 2 (unconfirmed QSOs skipped)", len(confirmations))
+	}
+	c := confirmations[0]
+	if c.Call != "W1AW" || c.Band != "20m" || c.ModeGroup != "DATA" || c.GridSquare != "FN31" || c.MyGridSquare != "LL75rm" {
+		t.Errorf("unexpected confirmation %+v", c)
+	}
+	if c.QSLDate.Format("20060102") != "20250305" || len(c.Credit) != 2 {
+		t.Errorf("unexpected QSL date or credit %+v", c)
+	}
+	if confirmations[1].ModeGroup != "CW" {
+		t.Errorf("mode group not derived from mode: %q", confirmations[1].ModeGroup)
+	}
+}
+
+func TestLoTWStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lotw.json")
+	store, err := NewLoTWStore(path)
+	if err != nil {
+		t.Fatalf("NewLoTWStore: %v", err)
+	}
+	confirmations, _ := ParseLoTWReport(strings.NewReader(lotwReport))
+	if added, err := store.Import(confirmations); err != nil || added != 2 {
+		t.Fatalf("Import = %d, %v; want 2", added, err)
+	}
+	if added, _ := store.Import(confirmations); added != 0 {
+		t.Errorf("reimport added %d, want 0", added)
+	}
+
+	at := func(day, hour, min int) time.Time { return time.Date(2025, 3, day, hour, min, 0, 0, time.UTC) }
+	qsos := []QSO{
+		// Logged two minutes earlier than the other station did
+		{Call: "W1AW", Band: "20m", Mode: "MFSK", Timestamp: at(1, 12, 0), StationCall: "A66H", MyGridSquare: "LL75"},
+		// Same callsign but made from home, not the portable location
+		{Call: "DL1ABC", Band: "40m", Mode: "CW", Timestamp: at(2, 18, 0), StationCall: "A66H"},
+	}
+	merged := store.Apply(qsos)
+	if merged[0].LotwRcvd != QslYes || merged[0].GridSquare != "FN31" || merged[0].DXCC != "291" {
+		t.Errorf("W1AW not confirmed: %+v", merged[0])
+	}
+	if merged[1].LotwRcvd == QslYes {
+		t.Error("DL1ABC confirmed from a different station location")
+	}
+	if qsos[0].LotwRcvd == QslYes {
+		t.Error("Apply modified its input")
+	}
+
+	unmatched := store.Unmatched(qsos)
+	if len(unmatched) != 1 || unmatched[0].Call != "DL1ABC" {
+		t.Errorf("unexpected unmatched %+v", unmatched)
+	}
+
+	reloaded, err := NewLoTWStore(path)
+	if err != nil || reloaded.Count() != 2 {
+		t.Errorf("reloaded store has %d confirmations, %v", reloaded.Count(), err)
+	}
+}
@@ -0,0 +1,161 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// Attachment is a file attached to an email
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// Mailer sends email through an SMTP server
+type Mailer struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+}
+
+// NewMailer creates a mailer for the given SMTP server. Authentication is only
+// used when a username is given.
+func NewMailer(host, port, username, password, from string) *Mailer {
+	return &Mailer{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+// ValidateEmailAddress checks that s is a single plain email address (no
+// display name) with a plausible domain
+func ValidateEmailAddress(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s || addr.Name != "" {
+		return "", fmt.Errorf("invalid email address")
+	}
+
+	at := strings.LastIndex(s, "@")
+	domain := s[at+1:]
+	if !strings.Contains(domain, ".") || strings.HasPrefix(domain, ".") || strings.HasSuffix(domain, ".") {
+		return "", fmt.Errorf("invalid email domain")
+	}
+
+	return s, nil
+}
+
+// Send sends a plain text email with optional attachments
+func (m *Mailer) Send(to, subject, body string, attachments ...Attachment) error {
+	return m.SendWithReplyTo(to, "", subject, body, attachments...)
+}
+
+// SendWithReplyTo sends a plain text email with a Reply-To address, so replies
+// go to someone other than the sender
+func (m *Mailer) SendWithReplyTo(to, replyTo, subject, body string, attachments ...Attachment) error {
+	msg, err := m.buildMessage(to, replyTo, subject, body, attachments)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+
+	if err := smtp.SendMail(net.JoinHostPort(m.host, m.port), auth, m.from, []string{to}, msg); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// buildMessage encodes the email as a MIME message. Addresses are written
+// into headers as they are, so line breaks in them are refused.
+func (m *Mailer) buildMessage(to, replyTo, subject, body string, attachments []Attachment) ([]byte, error) {
+	if strings.ContainsAny(to+replyTo, "\r\n") {
+		return nil, fmt.Errorf("email address contains a line break")
+	}
+
+	var buf bytes.Buffer
+
+	headers := []string{
+		"From: " + m.from,
+		"To: " + to,
+		"Subject: " + mime.QEncoding.Encode("utf-8", subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+	}
+	if replyTo != "" {
+		headers = append(headers, "Reply-To: "+replyTo)
+	}
+
+	if len(attachments) == 0 {
+		headers = append(headers,
+			"Content-Type: text/plain; charset=utf-8",
+			"Content-Transfer-Encoding: base64")
+		buf.WriteString(strings.Join(headers, "\r\n") + "\r\n\r\n")
+		writeBase64Lines(&buf, []byte(body))
+		return buf.Bytes(), nil
+	}
+
+	writer := multipart.NewWriter(&buf)
+	headers = append(headers, "Content-Type: multipart/mixed; boundary="+writer.Boundary())
+	buf.WriteString(strings.Join(headers, "\r\n") + "\r\n\r\n")
+
+	part, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create email body: %w", err)
+	}
+	writeBase64Lines(part, []byte(body))
+
+	for _, a := range attachments {
+		part, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {a.ContentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Name})},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to attach %s: %w", a.Name, err)
+		}
+		writeBase64Lines(part, a.Data)
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish email: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeBase64Lines writes base64 data wrapped at 76 characters per line
+func writeBase64Lines(w io.Writer, data []byte) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		w.Write([]byte(encoded[:76] + "\r\n"))
+		encoded = encoded[76:]
+	}
+	w.Write([]byte(encoded + "\r\n"))
+}
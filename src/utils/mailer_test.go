@@ -0,0 +1,149 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+func TestValidateEmailAddress(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		ok    bool
+	}{
+		{"ali@example.com", "ali@example.com", true},
+		{"  ali@example.com ", "ali@example.com", true},
+		{"Ali <ali@example.com>", "", false},
+		{"<ali@example.com>", "", false},
+		{"ali@example.com\r\nBcc: victim@example.com", "", false},
+		{"ali@example.com\nBcc: victim@example.com", "", false},
+		{"ali@localhost", "", false},
+		{"ali@.example.com", "", false},
+		{"ali@example.", "", false},
+		{"ali@example.com, bob@example.com", "", false},
+		{"not an address", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, err := ValidateEmailAddress(tt.input)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ValidateEmailAddress(%q) = %q, %v, want %q (ok %v)", tt.input, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestMailerBuildMessage(t *testing.T) {
+	m := NewMailer("smtp.example.com", "587", "", "", "qsl@huma.id")
+	card := []byte("\x89PNG fake card")
+	msg, err := m.buildMessage("dl1abc@example.com", "", "QSO with DL1ABC\r\nBcc: victim@example.com", "73 de A66H", []Attachment{
+		{Name: "map.png", ContentType: "image/png", Data: card},
+	})
+	if err != nil {
+		t.Fatalf("buildMessage: %v", err)
+	}
+
+	parsed, err := mail.ReadMessage(bytes.NewReader(msg))
+	if err != nil {
+		t.Fatalf("Failed to parse message: %v", err)
+	}
+	if got := parsed.Header.Get("From"); got != "qsl@huma.id" {
+		t.Errorf("Expected From qsl@huma.id, got %q", got)
+	}
+	if got := parsed.Header.Get("To"); got != "dl1abc@example.com" {
+		t.Errorf("Expected To dl1abc@example.com, got %q", got)
+	}
+	if got := parsed.Header.Get("Bcc"); got != "" {
+		t.Errorf("Expected the subject not to inject a Bcc header, got %q", got)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil || subject != "QSO with DL1ABC\r\nBcc: victim@example.com" {
+		t.Errorf("Expected the subject to round trip encoded, got %q (%v)", subject, err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		t.Fatalf("Expected multipart/mixed, got %q (%v)", mediaType, err)
+	}
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+
+	var parts [][]byte
+	var types, names []string
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to read part: %v", err)
+		}
+		if got := part.Header.Get("Content-Transfer-Encoding"); got != "base64" {
+			t.Errorf("Expected base64 parts, got %q", got)
+		}
+		data, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, part))
+		if err != nil {
+			t.Fatalf("Failed to decode part: %v", err)
+		}
+		parts = append(parts, data)
+		types = append(types, part.Header.Get("Content-Type"))
+		names = append(names, part.FileName())
+	}
+
+	if len(parts) != 2 {
+		t.Fatalf("Expected a body and an attachment, got %d parts", len(parts))
+	}
+	if types[0] != "text/plain; charset=utf-8" || string(parts[0]) != "73 de A66H" {
+		t.Errorf("Expected the plain text body first, got %s %q", types[0], parts[0])
+	}
+	if types[1] != "image/png" || names[1] != "map.png" || !bytes.Equal(parts[1], card) {
+		t.Errorf("Expected the map.png attachment, got %s %q", types[1], names[1])
+	}
+}
+
+func TestMailerBuildMessagePlain(t *testing.T) {
+	m := NewMailer("smtp.example.com", "587", "", "", "qsl@huma.id")
+	msg, err := m.buildMessage("qsl@huma.id", "dl1abc@example.com", "Contact", strings.Repeat("73 ", 40), nil)
+	if err != nil {
+		t.Fatalf("buildMessage: %v", err)
+	}
+
+	parsed, err := mail.ReadMessage(bytes.NewReader(msg))
+	if err != nil {
+		t.Fatalf("Failed to parse message: %v", err)
+	}
+	if got := parsed.Header.Get("Reply-To"); got != "dl1abc@example.com" {
+		t.Errorf("Expected Reply-To dl1abc@example.com, got %q", got)
+	}
+	if got := parsed.Header.Get("Content-Type"); got != "text/plain; charset=utf-8" {
+		t.Errorf("Expected a plain text message, got %q", got)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(msg[bytes.Index(msg, []byte("\r\n\r\n"))+4:])), "\r\n") {
+		if len(line) > 76 {
+			t.Errorf("Expected body lines of at most 76 characters, got %d", len(line))
+		}
+	}
+	body, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, parsed.Body))
+	if err != nil || string(body) != strings.Repeat("73 ", 40) {
+		t.Errorf("Expected the body to round trip, got %q (%v)", body, err)
+	}
+}
+
+func TestMailerBuildMessageRejectsLineBreaks(t *testing.T) {
+	m := NewMailer("smtp.example.com", "587", "", "", "qsl@huma.id")
+	if _, err := m.buildMessage("dl1abc@example.com\r\nBcc: victim@example.com", "", "QSO", "73", nil); err == nil {
+		t.Error("Expected a line break in the recipient to be rejected")
+	}
+	if _, err := m.buildMessage("qsl@huma.id", "dl1abc@example.com\nBcc: victim@example.com", "QSO", "73", nil); err == nil {
+		t.Error("Expected a line break in the Reply-To address to be rejected")
+	}
+}
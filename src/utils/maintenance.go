@@ -0,0 +1,75 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// Maintenance is the state of maintenance mode
+type Maintenance struct {
+	Enabled bool      `json:"enabled"`
+	Message string    `json:"message,omitempty"` // Shown to visitors
+	Since   time.Time `json:"since,omitempty"`
+}
+
+// MaintenanceMode keeps the maintenance mode switch in a JSON file, so it
+// survives restarts during a migration
+type MaintenanceMode struct {
+	path  string
+	mutex sync.RWMutex
+	state Maintenance
+}
+
+// NewMaintenanceMode loads the maintenance state stored at path, starting
+// disabled if the file doesn't exist yet
+func NewMaintenanceMode(path string) (*MaintenanceMode, error) {
+	m := &MaintenanceMode{path: path}
+	if err := loadJSONFile(path, &m.state); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// State returns the current maintenance state
+func (m *MaintenanceMode) State() Maintenance {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.state
+}
+
+// Enabled reports whether maintenance mode is on
+func (m *MaintenanceMode) Enabled() bool {
+	return m.State().Enabled
+}
+
+// Enable turns maintenance mode on with a message for visitors, keeping the
+// original start time if it is already on
+func (m *MaintenanceMode) Enable(message string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if !m.state.Enabled {
+		m.state.Since = time.Now().UTC()
+	}
+	m.state.Enabled = true
+	m.state.Message = message
+	return m.save()
+}
+
+// Disable turns maintenance mode off
+func (m *MaintenanceMode) Disable() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.state = Maintenance{}
+	return m.save()
+}
+
+// save writes the state to disk. The caller must hold the lock.
+func (m *MaintenanceMode) save() error {
+	return saveJSONFile(m.path, m.state, 0644)
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestMaintenanceMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "maintenance.json")
+	m, err := NewMaintenanceMode(path)
+	if err != nil {
+		t.Fatalf("Failed to load maintenance mode: %v", err)
+	}
+	if m.Enabled() {
+		t.Error("Expected maintenance mode to start disabled")
+	}
+
+	if err := m.Enable("Migrating the log"); err != nil {
+		t.Fatalf("Failed to enable maintenance mode: %v", err)
+	}
+	since := m.State().Since
+	if err := m.Enable("Nearly done"); err != nil {
+		t.Fatalf("Failed to update maintenance mode: %v", err)
+	}
+	if state := m.State(); !state.Enabled || state.Message != "Nearly done" || !state.Since.Equal(since) {
+		t.Errorf("Expected the message to change and the start time to stay, got %+v", state)
+	}
+
+	reloaded, err := NewMaintenanceMode(path)
+	if err != nil {
+		t.Fatalf("Failed to reload maintenance mode: %v", err)
+	}
+	if !reloaded.Enabled() || reloaded.State().Message != "Nearly done" {
+		t.Errorf("Expected the state to survive a restart, got %+v", reloaded.State())
+	}
+
+	if err := reloaded.Disable(); err != nil {
+		t.Fatalf("Failed to disable maintenance mode: %v", err)
+	}
+	if reloaded.Enabled() {
+		t.Error("Expected maintenance mode to be disabled")
+	}
+}
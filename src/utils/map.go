@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"bytes"
 	"fmt"
 	"image"
 	"image/color"
@@ -18,6 +19,7 @@ type MapConfig struct {
 	Height     int
 	Zoom       int
 	OutputPath string
+	Satellite  *SatellitePosition // Satellite the QSO went through, if known
 }
 
 func DefaultMapConfig() MapConfig {
@@ -30,17 +32,34 @@ func DefaultMapConfig() MapConfig {
 }
 
 func CreateGridMap(myGrid, theirGrid string, config MapConfig) error {
+	img, err := RenderGridMap(myGrid, theirGrid, config)
+	if err != nil {
+		return err
+	}
+	return saveImage(img, config.OutputPath)
+}
+
+// RenderGridMap draws the map between two grid locations, ignoring the
+// output path in config
+func RenderGridMap(myGrid, theirGrid string, config MapConfig) (image.Image, error) {
+	if err := ValidateGridSquare(myGrid); err != nil {
+		return nil, err
+	}
+	if err := ValidateGridSquare(theirGrid); err != nil {
+		return nil, err
+	}
+
 	ctx := sm.NewContext()
 	ctx.SetSize(config.Width, config.Height)
 
 	myPoint, err := maidenhead.ParseLocator(myGrid)
 	if err != nil {
-		return fmt.Errorf("failed to parse my grid locator %s: %w", myGrid, err)
+		return nil, fmt.Errorf("failed to parse my grid locator %s: %w", myGrid, err)
 	}
 
 	theirPoint, err := maidenhead.ParseLocator(theirGrid)
 	if err != nil {
-		return fmt.Errorf("failed to parse their grid locator %s: %w", theirGrid, err)
+		return nil, fmt.Errorf("failed to parse their grid locator %s: %w", theirGrid, err)
 	}
 
 	myPos := s2.LatLngFromDegrees(myPoint.Latitude, myPoint.Longitude)
@@ -52,6 +71,14 @@ func CreateGridMap(myGrid, theirGrid string, config MapConfig) error {
 	minLon := math.Min(myPoint.Longitude, theirPoint.Longitude)
 	maxLon := math.Max(myPoint.Longitude, theirPoint.Longitude)
 
+	// Keep the sub-satellite point in view too
+	if sat := config.Satellite; sat != nil {
+		minLat = math.Min(minLat, sat.Lat)
+		maxLat = math.Max(maxLat, sat.Lat)
+		minLon = math.Min(minLon, sat.Lng)
+		maxLon = math.Max(maxLon, sat.Lng)
+	}
+
 	// Add padding (10% of the range)
 	latRange := maxLat - minLat
 	lonRange := maxLon - minLon
@@ -81,28 +108,40 @@ func CreateGridMap(myGrid, theirGrid string, config MapConfig) error {
 	ctx.SetZoom(zoom)
 
 	// Set center point
-	centerLat := (myPoint.Latitude + theirPoint.Latitude) / 2
-	centerLon := (myPoint.Longitude + theirPoint.Longitude) / 2
+	centerLat := (minLat + maxLat) / 2
+	centerLon := (minLon + maxLon) / 2
 	ctx.SetCenter(s2.LatLngFromDegrees(centerLat, centerLon))
 
+	// Add the satellite footprint under the stations, with the signal path
+	// going up to the satellite and back down
+	route := []s2.LatLng{myPos, theirPos}
+	if sat := config.Satellite; sat != nil {
+		satPos := s2.LatLngFromDegrees(sat.Lat, sat.Lng)
+		ctx.AddObject(sm.NewCircle(satPos, color.RGBA{255, 165, 0, 200}, color.RGBA{255, 165, 0, 48}, sat.FootprintRadiusKm()*1000, 2))
+		ctx.AddObject(sm.NewMarker(satPos, color.RGBA{255, 165, 0, 255}, 12.0))
+		route = []s2.LatLng{myPos, satPos, theirPos}
+	}
+
 	// Add markers and path
 	ctx.AddObject(sm.NewMarker(myPos, color.RGBA{255, 0, 0, 255}, 16.0))
 	ctx.AddObject(sm.NewMarker(theirPos, color.RGBA{0, 0, 255, 255}, 16.0))
 
-	path := sm.NewPath([]s2.LatLng{myPos, theirPos}, color.RGBA{0, 255, 0, 255}, 2)
+	path := sm.NewPath(route, color.RGBA{0, 255, 0, 255}, 2)
 	ctx.AddObject(path)
 
 	// Get original attribution and create custom attribution
 	originalAttribution := ctx.Attribution()
 	customAttribution := fmt.Sprintf("QSL Map: %s <-> %s\n%s", myGrid, theirGrid, originalAttribution)
+	if sat := config.Satellite; sat != nil {
+		customAttribution = fmt.Sprintf("QSL Map: %s <-> %s via %s\n%s", myGrid, theirGrid, sat.Name, originalAttribution)
+	}
 	ctx.OverrideAttribution(customAttribution)
 
 	img, err := ctx.Render()
 	if err != nil {
-		return fmt.Errorf("failed to render map: %w", err)
+		return nil, fmt.Errorf("failed to render map: %w", err)
 	}
-
-	return saveImage(img, config.OutputPath)
+	return img, nil
 }
 
 // calculateZoomLevel calculates appropriate zoom level to fit bounding box
@@ -135,21 +174,11 @@ func calculateZoomLevel(minLat, maxLat, minLon, maxLon float64, width, height in
 }
 
 func CreateGridMapWithDistance(myGrid, theirGrid string, config MapConfig) (float64, error) {
-	myPoint, err := maidenhead.ParseLocator(myGrid)
+	distance, err := Distance(myGrid, theirGrid)
 	if err != nil {
-		return 0, fmt.Errorf("failed to parse my grid locator %s: %w", myGrid, err)
+		return 0, err
 	}
 
-	theirPoint, err := maidenhead.ParseLocator(theirGrid)
-	if err != nil {
-		return 0, fmt.Errorf("failed to parse their grid locator %s: %w", theirGrid, err)
-	}
-
-	myPos := s2.LatLngFromDegrees(myPoint.Latitude, myPoint.Longitude)
-	theirPos := s2.LatLngFromDegrees(theirPoint.Latitude, theirPoint.Longitude)
-
-	distance := myPos.Distance(theirPos).Degrees() * 111.32
-
 	// Use CreateGridMap which now includes custom attribution
 	err = CreateGridMap(myGrid, theirGrid, config)
 	if err != nil {
@@ -159,6 +188,15 @@ func CreateGridMapWithDistance(myGrid, theirGrid string, config MapConfig) (floa
 	return distance, nil
 }
 
+// EncodePNG encodes an image as PNG
+func EncodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode PNG: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
 func saveImage(img image.Image, filename string) error {
 	file, err := os.Create(filename)
 	if err != nil {
@@ -0,0 +1,36 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"strings"
+	"time"
+)
+
+// MatchQSO finds QSOs with a call sign logged in exactly the given minute,
+// for visitors who must prove the QSO rather than approximate it. A non-empty
+// band or mode must also match the logged one, ignoring case.
+func (p *ADIFParser) MatchQSO(callSign string, at time.Time, band, mode string) []QSO {
+	callSign = strings.ToUpper(strings.TrimSpace(callSign))
+	at = at.Truncate(time.Minute)
+
+	matches := []QSO{}
+	for _, qso := range p.QSOs {
+		if qso.Call != callSign || qso.Timestamp.IsZero() {
+			continue
+		}
+		if !qso.Timestamp.Truncate(time.Minute).Equal(at) {
+			continue
+		}
+		if band != "" && !strings.EqualFold(qso.Band, strings.TrimSpace(band)) {
+			continue
+		}
+		if mode != "" && !strings.EqualFold(qso.Mode, strings.TrimSpace(mode)) {
+			continue
+		}
+		matches = append(matches, qso)
+	}
+	return matches
+}
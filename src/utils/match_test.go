@@ -0,0 +1,39 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMatchQSO(t *testing.T) {
+	at := time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC)
+	p := &ADIFParser{QSOs: []QSO{
+		{Call: "A61AA", Band: "20m", Mode: "SSB", Timestamp: at.Add(42 * time.Second)},
+		{Call: "A61AA", Band: "40m", Mode: "CW", Timestamp: at.Add(2 * time.Minute)},
+		{Call: "A61BB", Band: "20m", Mode: "SSB", Timestamp: at},
+	}}
+
+	tests := []struct {
+		name       string
+		call       string
+		at         time.Time
+		band, mode string
+		want       int
+	}{
+		{"same minute", "a61aa", at, "", "", 1},
+		{"band and mode", "A61AA", at, "20M", "ssb", 1},
+		{"wrong band", "A61AA", at, "40m", "", 0},
+		{"wrong mode", "A61AA", at, "", "CW", 0},
+		{"minute off", "A61AA", at.Add(time.Minute), "", "", 0},
+		{"other call", "A61CC", at, "", "", 0},
+	}
+	for _, tt := range tests {
+		if got := p.MatchQSO(tt.call, tt.at, tt.band, tt.mode); len(got) != tt.want {
+			t.Errorf("%s: got %d QSOs, want %d", tt.name, len(got), tt.want)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"sort"
+	"strings"
+)
+
+// ModeFamily groups related ADIF modes
+type ModeFamily int
+
+const (
+	ModeFamilyCW ModeFamily = iota
+	ModeFamilySSB
+	ModeFamilyFT      // FT8 and FT4, including MFSK with an FT4 submode
+	ModeFamilyDigital // Every other data mode
+	ModeFamilyOther   // AM, FM and unknown modes
+	modeFamilyCount
+)
+
+// ModeFamilies lists the mode families in display order
+var ModeFamilies = []ModeFamily{ModeFamilyCW, ModeFamilySSB, ModeFamilyFT, ModeFamilyDigital, ModeFamilyOther}
+
+// Key returns the family's identifier, used for locale keys
+func (f ModeFamily) Key() string {
+	switch f {
+	case ModeFamilyCW:
+		return "cw"
+	case ModeFamilySSB:
+		return "ssb"
+	case ModeFamilyFT:
+		return "ft"
+	case ModeFamilyDigital:
+		return "digital"
+	}
+	return "other"
+}
+
+// otherModes are the modes outside the digital family that aren't CW or SSB
+var otherModes = map[string]bool{
+	"":             true,
+	"AM":           true,
+	"FM":           true,
+	"ATV":          true,
+	"SSTV":         true,
+	"FAX":          true,
+	"DIGITALVOICE": true,
+}
+
+// ModeFamilyOf returns the family of an ADIF mode and submode
+func ModeFamilyOf(mode, submode string) ModeFamily {
+	mode = strings.ToUpper(strings.TrimSpace(mode))
+	submode = strings.ToUpper(strings.TrimSpace(submode))
+
+	switch {
+	case mode == "CW":
+		return ModeFamilyCW
+	case mode == "SSB" || mode == "USB" || mode == "LSB":
+		return ModeFamilySSB
+	case mode == "FT8" || mode == "FT4":
+		return ModeFamilyFT
+	case mode == "MFSK" && (submode == "FT4" || submode == "FT8"):
+		return ModeFamilyFT
+	case otherModes[mode]:
+		return ModeFamilyOther
+	}
+	return ModeFamilyDigital
+}
+
+// ModeFamilyCount is the number of QSOs in a mode family
+type ModeFamilyCount struct {
+	Family ModeFamily
+	QSOs   int
+}
+
+// ModeYear is the number of QSOs made in each mode family in one year, in
+// the order of ModeFamilies
+type ModeYear struct {
+	Year  int
+	QSOs  []int
+	Total int
+}
+
+// ModeBreakdown is the log's QSOs grouped by mode family, overall and by year
+type ModeBreakdown struct {
+	Families []ModeFamilyCount // Families with QSOs, busiest first
+	Years    []ModeYear        // Years with QSOs, most recent first
+}
+
+// ComputeModeBreakdown groups QSOs into mode families. QSOs without a date
+// only count towards the overall totals.
+func ComputeModeBreakdown(qsos []QSO) ModeBreakdown {
+	var totals [modeFamilyCount]int
+	years := make(map[int]*ModeYear)
+
+	for _, qso := range qsos {
+		family := ModeFamilyOf(qso.Mode, qso.Submode)
+		totals[family]++
+
+		if qso.Timestamp.IsZero() {
+			continue
+		}
+		year := qso.Timestamp.UTC().Year()
+		y := years[year]
+		if y == nil {
+			y = &ModeYear{Year: year, QSOs: make([]int, len(ModeFamilies))}
+			years[year] = y
+		}
+		y.QSOs[family]++
+		y.Total++
+	}
+
+	var b ModeBreakdown
+	for _, family := range ModeFamilies {
+		if totals[family] > 0 {
+			b.Families = append(b.Families, ModeFamilyCount{Family: family, QSOs: totals[family]})
+		}
+	}
+	sort.SliceStable(b.Families, func(i, j int) bool {
+		return b.Families[i].QSOs > b.Families[j].QSOs
+	})
+
+	for _, y := range years {
+		b.Years = append(b.Years, *y)
+	}
+	sort.Slice(b.Years, func(i, j int) bool {
+		return b.Years[i].Year > b.Years[j].Year
+	})
+	return b
+}
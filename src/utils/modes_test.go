@@ -0,0 +1,55 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestModeFamilyOf(t *testing.T) {
+	tests := []struct {
+		mode, submode string
+		want          ModeFamily
+	}{
+		{"CW", "", ModeFamilyCW},
+		{"ssb", "USB", ModeFamilySSB},
+		{"FT8", "", ModeFamilyFT},
+		{"MFSK", "FT4", ModeFamilyFT},
+		{"MFSK", "JS8", ModeFamilyDigital},
+		{"RTTY", "", ModeFamilyDigital},
+		{"FM", "", ModeFamilyOther},
+		{"", "", ModeFamilyOther},
+	}
+	for _, tt := range tests {
+		if got := ModeFamilyOf(tt.mode, tt.submode); got != tt.want {
+			t.Errorf("ModeFamilyOf(%q, %q) = %v, want %v", tt.mode, tt.submode, got, tt.want)
+		}
+	}
+}
+
+func TestComputeModeBreakdown(t *testing.T) {
+	at := func(year int) time.Time { return time.Date(year, 6, 1, 12, 0, 0, 0, time.UTC) }
+	b := ComputeModeBreakdown([]QSO{
+		{Mode: "FT8", Timestamp: at(2024)},
+		{Mode: "MFSK", Submode: "FT4", Timestamp: at(2025)},
+		{Mode: "FT8", Timestamp: at(2025)},
+		{Mode: "CW", Timestamp: at(2025)},
+		{Mode: "SSB"},
+	})
+
+	if len(b.Families) != 3 || b.Families[0].Family != ModeFamilyFT || b.Families[0].QSOs != 3 {
+		t.Fatalf("unexpected families %+v", b.Families)
+	}
+	if len(b.Years) != 2 || b.Years[0].Year != 2025 || b.Years[0].Total != 3 {
+		t.Fatalf("unexpected years %+v", b.Years)
+	}
+	if got := b.Years[0].QSOs[ModeFamilyFT]; got != 2 {
+		t.Errorf("2025 FT QSOs = %d, want 2", got)
+	}
+	if got := b.Years[1].QSOs[ModeFamilyCW]; got != 0 {
+		t.Errorf("2024 CW QSOs = %d, want 0", got)
+	}
+}
@@ -0,0 +1,217 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MQTTPublisher publishes events to an MQTT broker. It implements just enough
+// of MQTT 3.1.1 to publish QoS 0 messages, reconnecting on demand.
+type MQTTPublisher struct {
+	addr     string
+	clientID string
+	username string
+	password string
+	prefix   string
+
+	mutex sync.Mutex
+	conn  net.Conn
+}
+
+// NewMQTTPublisher creates a publisher for the broker at addr (host:port),
+// publishing under the given topic prefix
+func NewMQTTPublisher(addr, clientID, username, password, prefix string) *MQTTPublisher {
+	return &MQTTPublisher{
+		addr:     addr,
+		clientID: clientID,
+		username: username,
+		password: password,
+		prefix:   strings.TrimSuffix(prefix, "/"),
+	}
+}
+
+// Handle publishes an event as JSON to <prefix>/<event type>. It is meant to
+// be subscribed to an EventBus.
+func (m *MQTTPublisher) Handle(e Event) {
+	payload, err := json.Marshal(eventPayload(e))
+	if err != nil {
+		log.Printf("Failed to encode MQTT event: %v", err)
+		return
+	}
+
+	topic := fmt.Sprintf("%s/%s", m.prefix, e.Type)
+	if err := m.Publish(topic, payload, false); err != nil {
+		log.Printf("Failed to publish MQTT event: %v", err)
+	}
+}
+
+// Publish sends a QoS 0 message, connecting first if needed and retrying once
+// on a fresh connection if the existing one has gone away
+func (m *MQTTPublisher) Publish(topic string, payload []byte, retain bool) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	packet := mqttPublishPacket(topic, payload, retain)
+
+	for attempt := 0; attempt < 2; attempt++ {
+		if m.conn == nil {
+			conn, err := m.connect()
+			if err != nil {
+				return err
+			}
+			m.conn = conn
+		}
+
+		_ = m.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+		if _, err := m.conn.Write(packet); err == nil {
+			return nil
+		}
+
+		m.conn.Close()
+		m.conn = nil
+	}
+
+	return fmt.Errorf("failed to publish to %s", topic)
+}
+
+// connect opens a connection to the broker and performs the MQTT handshake
+func (m *MQTTPublisher) connect() (net.Conn, error) {
+	conn, err := net.DialTimeout("tcp", m.addr, 10*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MQTT broker: %w", err)
+	}
+
+	_ = conn.SetDeadline(time.Now().Add(10 * time.Second))
+	if _, err := conn.Write(mqttConnectPacket(m.clientID, m.username, m.password)); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to send MQTT connect: %w", err)
+	}
+
+	ack := make([]byte, 4)
+	if _, err := io.ReadFull(conn, ack); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read MQTT connack: %w", err)
+	}
+	if ack[0] != 0x20 || ack[3] != 0 {
+		conn.Close()
+		return nil, fmt.Errorf("MQTT broker refused connection (code %d)", ack[3])
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	return conn, nil
+}
+
+// mqttConnectPacket builds a CONNECT packet with a clean session and keep
+// alive disabled, since we only ever write to the connection
+func mqttConnectPacket(clientID, username, password string) []byte {
+	var body bytes.Buffer
+	writeMQTTString(&body, "MQTT")
+	body.WriteByte(4) // Protocol level 3.1.1
+
+	flags := byte(0x02) // Clean session
+	if username != "" {
+		flags |= 0x80
+		if password != "" {
+			flags |= 0x40
+		}
+	}
+	body.WriteByte(flags)
+	body.Write([]byte{0, 0}) // Keep alive disabled
+
+	writeMQTTString(&body, clientID)
+	if username != "" {
+		writeMQTTString(&body, username)
+		if password != "" {
+			writeMQTTString(&body, password)
+		}
+	}
+
+	return mqttPacket(0x10, body.Bytes())
+}
+
+// mqttPublishPacket builds a QoS 0 PUBLISH packet
+func mqttPublishPacket(topic string, payload []byte, retain bool) []byte {
+	var body bytes.Buffer
+	writeMQTTString(&body, topic)
+	body.Write(payload)
+
+	header := byte(0x30)
+	if retain {
+		header |= 0x01
+	}
+	return mqttPacket(header, body.Bytes())
+}
+
+// mqttPacket prefixes a packet body with its fixed header
+func mqttPacket(header byte, body []byte) []byte {
+	packet := []byte{header}
+
+	// Remaining length is a variable length integer, 7 bits per byte
+	length := len(body)
+	for {
+		b := byte(length % 128)
+		length /= 128
+		if length > 0 {
+			b |= 0x80
+		}
+		packet = append(packet, b)
+		if length == 0 {
+			break
+		}
+	}
+
+	return append(packet, body...)
+}
+
+func writeMQTTString(buf *bytes.Buffer, s string) {
+	buf.WriteByte(byte(len(s) >> 8))
+	buf.WriteByte(byte(len(s)))
+	buf.WriteString(s)
+}
+
+// eventPayload converts an event into its JSON representation
+func eventPayload(e Event) map[string]interface{} {
+	payload := map[string]interface{}{
+		"type": e.Type,
+		"time": e.Time.UTC().Format(time.RFC3339),
+	}
+
+	switch e.Type {
+	case EventReload:
+		payload["count"] = e.Count
+	case EventLookup:
+		payload["callsign"] = e.Callsign
+		payload["search_time"] = e.SearchTime.UTC().Format(time.RFC3339)
+		payload["found"] = e.Found
+	case EventQSLRequest:
+		payload["callsign"] = e.Callsign
+		payload["route"] = e.QSLRoute
+	}
+
+	if len(e.QSOs) > 0 {
+		qsos := make([]map[string]string, 0, len(e.QSOs))
+		for _, qso := range e.QSOs {
+			qsos = append(qsos, map[string]string{
+				"call": qso.Call,
+				"band": qso.Band,
+				"mode": qso.Mode,
+				"freq": qso.Freq,
+				"time": qso.Timestamp.UTC().Format(time.RFC3339),
+			})
+		}
+		payload["qsos"] = qsos
+	}
+
+	return payload
+}
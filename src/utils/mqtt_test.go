@@ -0,0 +1,26 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMQTTPublishPacket(t *testing.T) {
+	packet := mqttPublishPacket("a/b", []byte("hi"), false)
+	expected := []byte{0x30, 7, 0, 3, 'a', '/', 'b', 'h', 'i'}
+	if !bytes.Equal(packet, expected) {
+		t.Fatalf("Expected %v, got %v", expected, packet)
+	}
+}
+
+func TestMQTTRemainingLength(t *testing.T) {
+	packet := mqttPacket(0x30, make([]byte, 321))
+	// 321 = 0x41 + 2*128, encoded as 0xC1 0x02
+	if packet[1] != 0xC1 || packet[2] != 0x02 || len(packet) != 324 {
+		t.Fatalf("Unexpected remaining length encoding: % x", packet[:3])
+	}
+}
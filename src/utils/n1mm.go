@@ -0,0 +1,197 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// N1MMAction is the kind of contact broadcast sent by N1MM Logger+
+type N1MMAction string
+
+const (
+	N1MMContactAdd     N1MMAction = "contactinfo"
+	N1MMContactReplace N1MMAction = "contactreplace"
+	N1MMContactDelete  N1MMAction = "contactdelete"
+)
+
+// ErrN1MMUnsupported is returned for N1MM broadcasts that are not contacts,
+// such as radio and spot information sent to the same port
+var ErrN1MMUnsupported = errors.New("unsupported N1MM message")
+
+// N1MMContact is a contact broadcast received from N1MM Logger+
+type N1MMContact struct {
+	Action N1MMAction
+	ID     string // N1MM's unique contact ID, stable across replacements
+	QSO    QSO
+}
+
+type n1mmContactXML struct {
+	XMLName     xml.Name
+	ContestName string `xml:"contestname"`
+	Timestamp   string `xml:"timestamp"`
+	MyCall      string `xml:"mycall"`
+	Band        string `xml:"band"`
+	RxFreq      string `xml:"rxfreq"`
+	TxFreq      string `xml:"txfreq"`
+	Mode        string `xml:"mode"`
+	Call        string `xml:"call"`
+	Snt         string `xml:"snt"`
+	Rcv         string `xml:"rcv"`
+	GridSquare  string `xml:"gridsquare"`
+	Comment     string `xml:"comment"`
+	QTH         string `xml:"qth"`
+	Name        string `xml:"name"`
+	Power       string `xml:"power"`
+	SentNr      string `xml:"sntnr"`
+	RcvNr       string `xml:"rcvnr"`
+	Exchange    string `xml:"exchange1"`
+	ID          string `xml:"ID"`
+}
+
+// ParseN1MMContact parses an N1MM Logger+ XML contact broadcast
+func ParseN1MMContact(data []byte) (N1MMContact, error) {
+	var msg n1mmContactXML
+	if err := xml.Unmarshal(data, &msg); err != nil {
+		return N1MMContact{}, fmt.Errorf("failed to decode N1MM broadcast: %w", err)
+	}
+
+	action := N1MMAction(strings.ToLower(msg.XMLName.Local))
+	switch action {
+	case N1MMContactAdd, N1MMContactReplace, N1MMContactDelete:
+	default:
+		return N1MMContact{}, fmt.Errorf("%w %q", ErrN1MMUnsupported, msg.XMLName.Local)
+	}
+
+	contact := N1MMContact{
+		Action: action,
+		ID:     strings.TrimSpace(msg.ID),
+	}
+	if action == N1MMContactDelete {
+		return contact, nil
+	}
+
+	timestamp, err := time.Parse("2006-01-02 15:04:05", strings.TrimSpace(msg.Timestamp))
+	if err != nil {
+		return N1MMContact{}, fmt.Errorf("invalid N1MM timestamp %q: %w", msg.Timestamp, err)
+	}
+
+	qso := QSO{
+		Call:        strings.ToUpper(strings.TrimSpace(msg.Call)),
+		QSODate:     timestamp.Format("20060102"),
+		TimeOn:      timestamp.Format("150405"),
+		Mode:        strings.TrimSpace(msg.Mode),
+		RSTSent:     strings.TrimSpace(msg.Snt),
+		RSTRcvd:     strings.TrimSpace(msg.Rcv),
+		QTH:         strings.TrimSpace(msg.QTH),
+		Name:        strings.TrimSpace(msg.Name),
+		Comment:     strings.TrimSpace(msg.Comment),
+		GridSquare:  strings.TrimSpace(msg.GridSquare),
+		StationCall: strings.ToUpper(strings.TrimSpace(msg.MyCall)),
+		TxPwr:       strings.TrimSpace(msg.Power),
+		Timestamp:   timestamp.UTC(),
+	}
+
+	// DXLOG is N1MM's general logging mode rather than a contest, and serial
+	// numbers are 0 when the contest doesn't use them
+	if contest := strings.ToUpper(strings.TrimSpace(msg.ContestName)); contest != "DXLOG" {
+		qso.ContestID = contest
+		qso.SRXString = strings.TrimSpace(msg.Exchange)
+		if nr := strings.TrimSpace(msg.RcvNr); nr != "0" {
+			qso.SRX = nr
+		}
+		if nr := strings.TrimSpace(msg.SentNr); nr != "0" {
+			qso.STX = nr
+		}
+	}
+
+	// N1MM sends frequencies in units of 10 Hz
+	freq := msg.TxFreq
+	if freq == "" {
+		freq = msg.RxFreq
+	}
+	if tensOfHz, err := strconv.ParseFloat(strings.TrimSpace(freq), 64); err == nil && tensOfHz > 0 {
+		mhz := tensOfHz / 1e5
+		qso.Freq = strconv.FormatFloat(mhz, 'f', -1, 64)
+		qso.Band = BandFromFrequency(mhz)
+	}
+
+	if qso.Call == "" {
+		return N1MMContact{}, fmt.Errorf("N1MM contact is missing a callsign")
+	}
+
+	contact.QSO = qso
+	return contact, nil
+}
+
+// DefaultN1MMSources are the networks N1MM broadcasts are accepted from by
+// default: loopback, private and link-local addresses. The broadcasts aren't
+// authenticated, so anything further away could log contacts.
+var DefaultN1MMSources = []string{
+	"127.0.0.0/8",
+	"10.0.0.0/8",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"169.254.0.0/16",
+	"::1/128",
+	"fc00::/7",
+	"fe80::/10",
+}
+
+// n1mmSourceAllowed reports whether a broadcast from addr falls within one
+// of the allowed networks
+func n1mmSourceAllowed(addr net.Addr, allowed []netip.Prefix) bool {
+	udp, ok := addr.(*net.UDPAddr)
+	if !ok {
+		return false
+	}
+	ip := udp.AddrPort().Addr().Unmap()
+	for _, prefix := range allowed {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ListenN1MM listens for N1MM Logger+ UDP contact broadcasts on the given
+// address and calls handle for every contact received from one of the
+// allowed networks. It blocks until the listener fails.
+func ListenN1MM(addr string, allowed []netip.Prefix, handle func(N1MMContact)) error {
+	conn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen for N1MM broadcasts: %w", err)
+	}
+	defer conn.Close()
+
+	buf := make([]byte, 65535)
+	for {
+		n, from, err := conn.ReadFrom(buf)
+		if err != nil {
+			return fmt.Errorf("failed to read N1MM broadcast: %w", err)
+		}
+		if !n1mmSourceAllowed(from, allowed) {
+			continue
+		}
+
+		contact, err := ParseN1MMContact(buf[:n])
+		if err != nil {
+			if !errors.Is(err, ErrN1MMUnsupported) {
+				log.Printf("Ignoring N1MM broadcast: %v", err)
+			}
+			continue
+		}
+
+		handle(contact)
+	}
+}
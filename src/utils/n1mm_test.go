@@ -0,0 +1,94 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"errors"
+	"net"
+	"net/netip"
+	"testing"
+)
+
+func TestParseN1MMContact(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="utf-8"?>
+<contactinfo>
+	<app>N1MM</app>
+	<contestname>CQWWSSB</contestname>
+	<timestamp>2024-10-26 13:05:42</timestamp>
+	<mycall>A66H</mycall>
+	<band>14</band>
+	<rxfreq>1421550</rxfreq>
+	<txfreq>1421550</txfreq>
+	<mode>USB</mode>
+	<call>dl1abc</call>
+	<snt>59</snt>
+	<rcv>59</rcv>
+	<sntnr>0</sntnr>
+	<rcvnr>0</rcvnr>
+	<exchange1>14</exchange1>
+	<ID>f9ffac4fcd3e479ca86e137df1338531</ID>
+</contactinfo>`)
+
+	contact, err := ParseN1MMContact(data)
+	if err != nil {
+		t.Fatalf("ParseN1MMContact failed: %v", err)
+	}
+
+	if contact.Action != N1MMContactAdd {
+		t.Errorf("Expected action %s, got %s", N1MMContactAdd, contact.Action)
+	}
+	if contact.QSO.Call != "DL1ABC" {
+		t.Errorf("Expected call DL1ABC, got %s", contact.QSO.Call)
+	}
+	if contact.QSO.Freq != "14.2155" || contact.QSO.Band != "20m" {
+		t.Errorf("Expected 14.2155 MHz on 20m, got %s MHz on %s", contact.QSO.Freq, contact.QSO.Band)
+	}
+	if contact.QSO.QSODate != "20241026" || contact.QSO.TimeOn != "130542" {
+		t.Errorf("Unexpected date/time %s %s", contact.QSO.QSODate, contact.QSO.TimeOn)
+	}
+	if contact.QSO.Timestamp.Unix() != 1729947942 {
+		t.Errorf("Unexpected timestamp %v", contact.QSO.Timestamp)
+	}
+	if contact.QSO.ContestID != "CQWWSSB" || contact.QSO.SRXString != "14" || contact.QSO.SRX != "" {
+		t.Errorf("Unexpected contest fields %q %q %q", contact.QSO.ContestID, contact.QSO.SRXString, contact.QSO.SRX)
+	}
+}
+
+func TestParseN1MMContactIgnoresOtherMessages(t *testing.T) {
+	_, err := ParseN1MMContact([]byte(`<RadioInfo><Freq>1421550</Freq></RadioInfo>`))
+	if !errors.Is(err, ErrN1MMUnsupported) {
+		t.Fatalf("Expected ErrN1MMUnsupported, got %v", err)
+	}
+}
+
+func TestN1MMSourceAllowed(t *testing.T) {
+	var allowed []netip.Prefix
+	for _, spec := range DefaultN1MMSources {
+		allowed = append(allowed, netip.MustParsePrefix(spec))
+	}
+
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"127.0.0.1", true},
+		{"192.168.1.20", true},
+		{"10.1.2.3", true},
+		{"::1", true},
+		{"::ffff:192.168.1.20", true},
+		{"203.0.113.7", false},
+		{"2001:db8::1", false},
+	}
+	for _, tt := range tests {
+		addr := &net.UDPAddr{IP: net.ParseIP(tt.ip), Port: 12060}
+		if got := n1mmSourceAllowed(addr, allowed); got != tt.want {
+			t.Errorf("n1mmSourceAllowed(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+
+	if n1mmSourceAllowed(&net.UDPAddr{IP: net.ParseIP("127.0.0.1")}, nil) {
+		t.Error("Expected no sources to be allowed with an empty allow list")
+	}
+}
@@ -0,0 +1,276 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	earthRadiusKm = 6378.137
+	earthMu       = 398600.4418 // km³/s²
+	earthJ2       = 1.08262668e-3
+
+	// tleMaxAge is how far from its epoch a low orbit TLE is trusted. The
+	// propagation below ignores drag, so it drifts off within weeks.
+	tleMaxAge = 14 * 24 * time.Hour
+)
+
+// TLE is the orbit of a satellite from a NORAD two-line element set
+type TLE struct {
+	Name         string
+	Epoch        time.Time
+	Inclination  float64 // Degrees
+	RAAN         float64 // Right ascension of the ascending node, degrees
+	Eccentricity float64
+	ArgPerigee   float64 // Degrees
+	MeanAnomaly  float64 // Degrees
+	MeanMotion   float64 // Revolutions per day
+}
+
+// SatellitePosition is where a satellite was at a moment in time
+type SatellitePosition struct {
+	Name       string
+	Lat        float64
+	Lng        float64
+	AltitudeKm float64
+}
+
+// FootprintRadiusKm returns the ground distance from the sub-satellite point
+// to the edge of the area that can see the satellite above the horizon
+func (p SatellitePosition) FootprintRadiusKm() float64 {
+	return earthRadiusKm * math.Acos(earthRadiusKm/(earthRadiusKm+p.AltitudeKm))
+}
+
+// IsGeostationary reports whether the satellite keeps pace with the Earth's
+// rotation, as QO-100 does
+func (t TLE) IsGeostationary() bool {
+	return math.Abs(t.MeanMotion-1.0027) < 0.01 && t.Inclination < 5
+}
+
+// ParseTLE parses a three-line element set: a name line followed by the two
+// element lines
+func ParseTLE(name, line1, line2 string) (TLE, error) {
+	if len(line1) < 32 || !strings.HasPrefix(line1, "1 ") {
+		return TLE{}, fmt.Errorf("invalid TLE line 1 for %s", name)
+	}
+	if len(line2) < 63 || !strings.HasPrefix(line2, "2 ") {
+		return TLE{}, fmt.Errorf("invalid TLE line 2 for %s", name)
+	}
+
+	field := func(line string, from, to int) (float64, error) {
+		return strconv.ParseFloat(strings.TrimSpace(line[from:to]), 64)
+	}
+
+	// Epoch is YYDDD.DDDDDDDD, with the day of year counted from 1
+	epochYear, err := strconv.Atoi(strings.TrimSpace(line1[18:20]))
+	if err != nil {
+		return TLE{}, fmt.Errorf("invalid TLE epoch for %s: %w", name, err)
+	}
+	epochDay, err := field(line1, 20, 32)
+	if err != nil {
+		return TLE{}, fmt.Errorf("invalid TLE epoch for %s: %w", name, err)
+	}
+	if epochYear < 57 {
+		epochYear += 2000
+	} else {
+		epochYear += 1900
+	}
+	epoch := time.Date(epochYear, 1, 1, 0, 0, 0, 0, time.UTC).
+		Add(time.Duration((epochDay - 1) * 24 * float64(time.Hour)))
+
+	tle := TLE{Name: strings.TrimSpace(name), Epoch: epoch}
+	for _, f := range []struct {
+		dst      *float64
+		from, to int
+	}{
+		{&tle.Inclination, 8, 16},
+		{&tle.RAAN, 17, 25},
+		{&tle.ArgPerigee, 34, 42},
+		{&tle.MeanAnomaly, 43, 51},
+		{&tle.MeanMotion, 52, 63},
+	} {
+		if *f.dst, err = field(line2, f.from, f.to); err != nil {
+			return TLE{}, fmt.Errorf("invalid TLE elements for %s: %w", name, err)
+		}
+	}
+
+	// Eccentricity has an implied leading decimal point
+	if tle.Eccentricity, err = strconv.ParseFloat("0."+strings.TrimSpace(line2[26:33]), 64); err != nil {
+		return TLE{}, fmt.Errorf("invalid TLE eccentricity for %s: %w", name, err)
+	}
+	if tle.MeanMotion <= 0 {
+		return TLE{}, fmt.Errorf("invalid TLE mean motion for %s", name)
+	}
+
+	return tle, nil
+}
+
+// Position propagates the orbit to the given time, reporting false if the
+// time is too far from the epoch for the result to mean anything. Only the
+// secular effect of the Earth's oblateness is modelled, which is close enough
+// to draw a footprint on a map.
+func (t TLE) Position(at time.Time) (SatellitePosition, bool) {
+	if !t.IsGeostationary() && absDuration(at.Sub(t.Epoch)) > tleMaxAge {
+		return SatellitePosition{}, false
+	}
+
+	const deg = math.Pi / 180
+	incl := t.Inclination * deg
+	ecc := t.Eccentricity
+
+	n := t.MeanMotion * 2 * math.Pi / 86400 // rad/s
+	a := math.Cbrt(earthMu / (n * n))
+	p := a * (1 - ecc*ecc)
+
+	// Secular J2 drift of the node, perigee and mean anomaly
+	k := 1.5 * earthJ2 * (earthRadiusKm / p) * (earthRadiusKm / p) * n
+	sinI := math.Sin(incl)
+	raanRate := -k * math.Cos(incl)
+	argpRate := k * (2 - 2.5*sinI*sinI)
+	meanRate := n + k*math.Sqrt(1-ecc*ecc)*(1-1.5*sinI*sinI)
+
+	dt := at.Sub(t.Epoch).Seconds()
+	raan := t.RAAN*deg + raanRate*dt
+	argp := t.ArgPerigee*deg + argpRate*dt
+	mean := math.Mod(t.MeanAnomaly*deg+meanRate*dt, 2*math.Pi)
+
+	// Kepler's equation by Newton's method
+	e := mean
+	for i := 0; i < 10; i++ {
+		e -= (e - ecc*math.Sin(e) - mean) / (1 - ecc*math.Cos(e))
+	}
+	nu := 2 * math.Atan2(math.Sqrt(1+ecc)*math.Sin(e/2), math.Sqrt(1-ecc)*math.Cos(e/2))
+	r := a * (1 - ecc*math.Cos(e))
+
+	// Orbital plane to Earth-centred inertial coordinates
+	u := argp + nu
+	x := r * (math.Cos(raan)*math.Cos(u) - math.Sin(raan)*math.Sin(u)*math.Cos(incl))
+	y := r * (math.Sin(raan)*math.Cos(u) + math.Cos(raan)*math.Sin(u)*math.Cos(incl))
+	z := r * math.Sin(u) * sinI
+
+	lng := math.Atan2(y, x)/deg - greenwichSiderealTime(at)
+	lng = math.Mod(lng+540, 360) - 180
+
+	return SatellitePosition{
+		Name:       t.Name,
+		Lat:        math.Atan2(z, math.Hypot(x, y)) / deg,
+		Lng:        lng,
+		AltitudeKm: r - earthRadiusKm,
+	}, true
+}
+
+// greenwichSiderealTime returns the Greenwich mean sidereal time in degrees
+func greenwichSiderealTime(at time.Time) float64 {
+	days := float64(at.UnixNano())/float64(24*time.Hour) + 2440587.5 - 2451545.0
+	return math.Mod(280.46061837+360.98564736629*days, 360)
+}
+
+// TLESet is a file of satellite orbits, looked up by the SAT_NAME logged
+type TLESet struct {
+	path string
+
+	mutex sync.RWMutex
+	tles  map[string]TLE
+}
+
+// NewTLESet loads the three-line element sets in path, such as AMSAT's
+// nasabare.txt
+func NewTLESet(path string) (*TLESet, error) {
+	s := &TLESet{path: path}
+	if err := s.Load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Load rereads the TLE file
+func (s *TLESet) Load() error {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to open TLE file: %w", err)
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimRight(scanner.Text(), " \r"); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read TLE file: %w", err)
+	}
+
+	tles := make(map[string]TLE)
+	for i := 0; i+2 < len(lines); {
+		tle, err := ParseTLE(lines[i], lines[i+1], lines[i+2])
+		if err != nil {
+			// Resynchronise on the next name line
+			i++
+			continue
+		}
+		for _, name := range tleNames(tle.Name) {
+			tles[name] = tle
+		}
+		i += 3
+	}
+
+	s.mutex.Lock()
+	s.tles = tles
+	s.mutex.Unlock()
+	return nil
+}
+
+// Position returns where the named satellite was at the given time
+func (s *TLESet) Position(satName string, at time.Time) (SatellitePosition, bool) {
+	if s == nil || satName == "" {
+		return SatellitePosition{}, false
+	}
+
+	s.mutex.RLock()
+	tle, ok := s.tles[normalizeSatName(satName)]
+	s.mutex.RUnlock()
+	if !ok {
+		return SatellitePosition{}, false
+	}
+
+	pos, ok := tle.Position(at)
+	pos.Name = strings.ToUpper(satName)
+	return pos, ok
+}
+
+// tleNames returns the names a TLE can be looked up by: the full name and
+// any designation in parentheses, e.g. "SAUDISAT 1C (SO-50)" is also SO-50
+func tleNames(name string) []string {
+	names := []string{normalizeSatName(name)}
+	if open := strings.Index(name, "("); open >= 0 {
+		if end := strings.Index(name[open:], ")"); end > 0 {
+			names = append(names, normalizeSatName(name[open+1:open+end]))
+			names = append(names, normalizeSatName(name[:open]))
+		}
+	}
+	return names
+}
+
+// normalizeSatName upper-cases a satellite name and drops separators, so
+// QO-100, QO 100 and qo100 are the same
+func normalizeSatName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', ' ', '_':
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(name)))
+}
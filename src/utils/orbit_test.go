@@ -0,0 +1,94 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testTLEs = `ISS (ZARYA)
+1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927
+2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537
+QO-100
+1 43700U 18090A   24001.50000000  .00000134  00000-0  00000-0 0  9990
+2 43700   0.0140 268.5813 0001905 215.0270 167.1530  1.00271000 18860
+`
+
+func TestParseTLE(t *testing.T) {
+	tle, err := ParseTLE("ISS (ZARYA)",
+		"1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927",
+		"2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537")
+	if err != nil {
+		t.Fatalf("ParseTLE failed: %v", err)
+	}
+
+	wantEpoch := time.Date(2008, 9, 20, 12, 25, 40, 104_192_000, time.UTC)
+	if d := absDuration(tle.Epoch.Sub(wantEpoch)); d > time.Millisecond {
+		t.Errorf("Expected epoch %v, got %v", wantEpoch, tle.Epoch)
+	}
+	if tle.Inclination != 51.6416 || tle.Eccentricity != 0.0006703 || tle.MeanMotion != 15.72125391 {
+		t.Errorf("Unexpected elements: %+v", tle)
+	}
+
+	if _, err := ParseTLE("BAD", "1 25544U", "2 25544"); err == nil {
+		t.Error("Expected truncated element lines to fail")
+	}
+}
+
+func TestTLESetPosition(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nasabare.txt")
+	if err := os.WriteFile(path, []byte(testTLEs), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tles, err := NewTLESet(path)
+	if err != nil {
+		t.Fatalf("NewTLESet failed: %v", err)
+	}
+
+	// The ISS stays within its inclination at a few hundred km up
+	epoch := time.Date(2008, 9, 20, 12, 25, 40, 0, time.UTC)
+	for _, name := range []string{"ISS", "iss (zarya)"} {
+		for h := 0; h < 24; h += 3 {
+			pos, ok := tles.Position(name, epoch.Add(time.Duration(h)*time.Hour))
+			if !ok {
+				t.Fatalf("Expected a position for %s", name)
+			}
+			if math.Abs(pos.Lat) > 51.7 || pos.AltitudeKm < 300 || pos.AltitudeKm > 450 {
+				t.Errorf("Unexpected ISS position %+v", pos)
+			}
+			if r := pos.FootprintRadiusKm(); r < 1800 || r > 2400 {
+				t.Errorf("Expected an ISS footprint around 2000 km, got %.0f km", r)
+			}
+		}
+	}
+	if _, ok := tles.Position("ISS", epoch.Add(60*24*time.Hour)); ok {
+		t.Error("Expected a stale low orbit TLE not to be propagated")
+	}
+
+	// QO-100 stays over the same point, whatever the TLE age
+	first, ok := tles.Position("QO100", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	if !ok {
+		t.Fatal("Expected a position for QO-100")
+	}
+	later, _ := tles.Position("QO-100", time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC))
+	if math.Abs(first.Lat) > 0.5 || math.Abs(first.Lng-later.Lng) > 0.5 {
+		t.Errorf("Expected QO-100 to stay put, got %+v then %+v", first, later)
+	}
+	if first.AltitudeKm < 35500 || first.AltitudeKm > 36000 {
+		t.Errorf("Expected geostationary altitude, got %.0f km", first.AltitudeKm)
+	}
+
+	if _, ok := tles.Position("AO-91", epoch); ok {
+		t.Error("Expected an unknown satellite to have no position")
+	}
+	var none *TLESet
+	if _, ok := none.Position("ISS", epoch); ok {
+		t.Error("Expected a nil TLE set to have no positions")
+	}
+}
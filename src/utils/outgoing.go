@@ -0,0 +1,120 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OutgoingQSL is a paper QSL card I owe another station, because the QSO is
+// marked as requested or queued in the log or the card was requested on
+// this site
+type OutgoingQSL struct {
+	QSO     QSO
+	Request *QSLRequest // The request made on this site, if any
+}
+
+// OutgoingQueue returns the paper cards still to be sent, oldest QSO first.
+// Requests made on this site for QSOs the log already marks as sent are
+// left out.
+func OutgoingQueue(qsos []QSO, requests []QSLRequest) []OutgoingQSL {
+	requested := make(map[string]*QSLRequest)
+	for i := range requests {
+		if !requests[i].IsSent() {
+			requested[CorrectionKey(requests[i].Call, requests[i].QSOTime)] = &requests[i]
+		}
+	}
+
+	var queue []OutgoingQSL
+	for _, qso := range qsos {
+		if qso.QslSent.Confirmed() {
+			continue
+		}
+		req := requested[CorrectionKey(qso.Call, qso.Timestamp)]
+		if req != nil || qso.QslSent == QslRequested || qso.QslSent == QslQueued {
+			queue = append(queue, OutgoingQSL{QSO: qso, Request: req})
+		}
+	}
+
+	sort.SliceStable(queue, func(i, j int) bool {
+		return queue[i].QSO.Timestamp.Before(queue[j].QSO.Timestamp)
+	})
+	return queue
+}
+
+// OutgoingQSLStore keeps the paper cards marked as sent in the admin area in
+// a JSON file, so they show as sent before the log is updated
+type OutgoingQSLStore struct {
+	path  string
+	mutex sync.RWMutex
+	sent  map[string]time.Time // Date sent by CorrectionKey
+}
+
+// NewOutgoingQSLStore loads the sent cards stored at path, starting empty if
+// the file doesn't exist yet
+func NewOutgoingQSLStore(path string) (*OutgoingQSLStore, error) {
+	s := &OutgoingQSLStore{path: path, sent: make(map[string]time.Time)}
+	if err := loadJSONFile(path, &s.sent); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// MarkSent records that the card for a QSO was sent at the given time
+func (s *OutgoingQSLStore) MarkSent(call string, qsoTime, sent time.Time) error {
+	key := CorrectionKey(call, qsoTime)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	previous, existed := s.sent[key]
+	s.sent[key] = sent.UTC()
+	if err := saveJSONFile(s.path, s.sent, 0644); err != nil {
+		if existed {
+			s.sent[key] = previous
+		} else {
+			delete(s.sent, key)
+		}
+		return err
+	}
+	return nil
+}
+
+// Count returns how many cards have been marked as sent
+func (s *OutgoingQSLStore) Count() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return len(s.sent)
+}
+
+// Apply returns the QSOs with the cards marked as sent in the admin area
+// recorded as sent, unless the log already records it. The input slice is
+// not modified.
+func (s *OutgoingQSLStore) Apply(qsos []QSO) []QSO {
+	if s == nil {
+		return qsos
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if len(s.sent) == 0 {
+		return qsos
+	}
+
+	marked := make([]QSO, len(qsos))
+	for i, qso := range qsos {
+		if sent, ok := s.sent[CorrectionKey(qso.Call, qso.Timestamp)]; ok && !qso.QslSent.Confirmed() {
+			qso.QslSent = QslYes
+			if strings.TrimSpace(qso.QslSentDate) == "" {
+				qso.QslSentDate = sent.Format("20060102")
+			}
+		}
+		marked[i] = qso
+	}
+	return marked
+}
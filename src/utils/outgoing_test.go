@@ -0,0 +1,80 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOutgoingQueue(t *testing.T) {
+	day := time.Date(2025, 3, 1, 18, 30, 0, 0, time.UTC)
+	qsos := []QSO{
+		{Call: "W1AW", Timestamp: day.Add(2 * time.Hour), QslSent: QslQueued},
+		{Call: "A61XX", Timestamp: day, QslSent: QslRequested},
+		{Call: "JA1ABC", Timestamp: day.Add(time.Hour)},
+		{Call: "G4ABC", Timestamp: day.Add(3 * time.Hour), QslSent: QslYes},
+		{Call: "DL1ABC", Timestamp: day.Add(4 * time.Hour)},
+	}
+	requests := []QSLRequest{
+		{ID: "1", Call: "ja1abc", QSOTime: day.Add(time.Hour), Route: QSLRouteBureau},
+		{ID: "2", Call: "G4ABC", QSOTime: day.Add(3 * time.Hour)},
+		{ID: "3", Call: "DL1ABC", QSOTime: day.Add(4 * time.Hour), Sent: day},
+	}
+
+	queue := OutgoingQueue(qsos, requests)
+	if len(queue) != 3 {
+		t.Fatalf("Expected 3 cards to send, got %d", len(queue))
+	}
+	for i, call := range []string{"A61XX", "JA1ABC", "W1AW"} {
+		if queue[i].QSO.Call != call {
+			t.Errorf("Expected %s at %d, got %s", call, i, queue[i].QSO.Call)
+		}
+	}
+	if queue[1].Request == nil || queue[1].Request.ID != "1" || queue[0].Request != nil {
+		t.Error("Expected the site request attached to its QSO only")
+	}
+}
+
+func TestOutgoingQSLStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outgoing.json")
+	store, err := NewOutgoingQSLStore(path)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	qsoTime := time.Date(2025, 3, 1, 18, 30, 0, 0, time.UTC)
+	qsos := []QSO{
+		{Call: "A61XX", Timestamp: qsoTime, QslSent: QslRequested},
+		{Call: "W1AW", Timestamp: qsoTime, QslSent: QslRequested},
+	}
+
+	sent := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	if err := store.MarkSent("a61xx", qsoTime, sent); err != nil {
+		t.Fatalf("Failed to mark sent: %v", err)
+	}
+
+	applied := store.Apply(qsos)
+	if applied[0].QslSent != QslYes || applied[0].QslSentDate != "20250310" {
+		t.Errorf("Expected card recorded as sent, got %+v", applied[0])
+	}
+	if applied[1].QslSent != QslRequested || qsos[0].QslSent != QslRequested {
+		t.Error("Expected other QSOs and the input slice to be untouched")
+	}
+
+	reloaded, err := NewOutgoingQSLStore(path)
+	if err != nil {
+		t.Fatalf("Failed to reload store: %v", err)
+	}
+	if reloaded.Count() != 1 {
+		t.Error("Expected sent card to persist")
+	}
+
+	var none *OutgoingQSLStore
+	if got := none.Apply(qsos); got[0].QslSent != QslRequested {
+		t.Error("Expected nil store to change nothing")
+	}
+}
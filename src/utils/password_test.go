@@ -0,0 +1,39 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckPassword(t *testing.T) {
+	hash, err := HashPassword("correct horse")
+	if err != nil {
+		t.Fatalf("Failed to hash password: %v", err)
+	}
+	if !strings.HasPrefix(hash, "$2a$12$") {
+		t.Errorf("Expected a bcrypt hash with cost 12, got %q", hash)
+	}
+
+	if !CheckPassword(hash, "correct horse") {
+		t.Error("Expected password to match its hash")
+	}
+	if CheckPassword(hash, "battery staple") {
+		t.Error("Expected wrong password not to match")
+	}
+
+	for _, bad := range []string{"", "correct horse", "$2a$12$short", "pbkdf2-sha256$600000$c2FsdA$a2V5"} {
+		if CheckPassword(bad, "correct horse") {
+			t.Errorf("Expected malformed hash %q not to match", bad)
+		}
+	}
+}
+
+func TestHashPasswordTooLong(t *testing.T) {
+	if _, err := HashPassword(strings.Repeat("a", 73)); err == nil {
+		t.Error("Expected a password over 72 bytes to be refused")
+	}
+}
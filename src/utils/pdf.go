@@ -0,0 +1,136 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"strings"
+)
+
+// pdfJPEGQuality is the quality images are compressed at when embedded in a
+// PDF
+const pdfJPEGQuality = 90
+
+// WriteImagePDF writes a single page PDF showing img over the whole page.
+// Page sizes are in points, 1/72 of an inch. The image is embedded as a JPEG,
+// which PDF readers decode natively, so no PDF library is needed.
+func WriteImagePDF(w io.Writer, img image.Image, title string, width, height float64) error {
+	var jpg bytes.Buffer
+	if err := jpeg.Encode(&jpg, img, &jpeg.Options{Quality: pdfJPEGQuality}); err != nil {
+		return fmt.Errorf("failed to encode JPEG: %w", err)
+	}
+	bounds := img.Bounds()
+	content := fmt.Sprintf("q %.2f 0 0 %.2f 0 0 cm /Im0 Do Q", width, height)
+
+	var pdf pdfDocument
+	pdf.object("<< /Type /Catalog /Pages 2 0 R >>")
+	pdf.object("<< /Type /Pages /Kids [3 0 R] /Count 1 >>")
+	pdf.object("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /XObject << /Im0 5 0 R >> >> /Contents 4 0 R >>", width, height)
+	pdf.object("<< /Length %d >>\nstream\n%s\nendstream", len(content), content)
+	pdf.object("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode /Length %d >>\nstream\n%s\nendstream",
+		bounds.Dx(), bounds.Dy(), jpg.Len(), jpg.Bytes())
+	pdf.object("<< /Title (%s) /Producer (humaid-qsl) >>", pdfString(title))
+	return pdf.writeTo(w)
+}
+
+// Layout of text PDFs, on A4 pages in points
+const (
+	pdfPageWidth  = 595.28
+	pdfPageHeight = 841.89
+	pdfMargin     = 50
+	pdfFontSize   = 10
+	pdfLeading    = 13
+	pdfPageLines  = 57 // Lines fitting between the top and bottom margins
+)
+
+// WriteTextPDF writes lines of text as an A4 PDF in the built in Courier
+// font, so columns line up, starting new pages as needed. Characters
+// outside ASCII are dropped.
+func WriteTextPDF(w io.Writer, lines []string, title string) error {
+	var pages [][]string
+	for len(lines) > pdfPageLines {
+		pages = append(pages, lines[:pdfPageLines])
+		lines = lines[pdfPageLines:]
+	}
+	pages = append(pages, lines)
+
+	// Each page is followed by its content stream, then come the font and
+	// the document info
+	font := 3 + 2*len(pages)
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 3+2*i)
+	}
+
+	var pdf pdfDocument
+	pdf.object("<< /Type /Catalog /Pages 2 0 R >>")
+	pdf.object("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages))
+	for i, page := range pages {
+		var content strings.Builder
+		fmt.Fprintf(&content, "BT /F1 %d Tf %d TL %d %.2f Td", pdfFontSize, pdfLeading, pdfMargin, pdfPageHeight-pdfMargin-pdfFontSize)
+		for _, line := range page {
+			fmt.Fprintf(&content, " (%s) Tj T*", pdfString(line))
+		}
+		content.WriteString(" ET")
+
+		pdf.object("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 %d 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, font, 4+2*i)
+		pdf.object("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String())
+	}
+	pdf.object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	pdf.object("<< /Title (%s) /Producer (humaid-qsl) >>", pdfString(title))
+	return pdf.writeTo(w)
+}
+
+// pdfDocument builds a PDF from numbered objects. The catalog must be the
+// first object and the document info the last.
+type pdfDocument struct {
+	buf     bytes.Buffer
+	offsets []int
+}
+
+// object adds the next numbered object
+func (d *pdfDocument) object(format string, args ...interface{}) {
+	if d.buf.Len() == 0 {
+		d.buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	}
+	d.offsets = append(d.offsets, d.buf.Len())
+	fmt.Fprintf(&d.buf, "%d 0 obj\n", len(d.offsets))
+	fmt.Fprintf(&d.buf, format, args...)
+	d.buf.WriteString("\nendobj\n")
+}
+
+// writeTo adds the cross reference table and trailer and writes the PDF
+func (d *pdfDocument) writeTo(w io.Writer) error {
+	xref := d.buf.Len()
+	fmt.Fprintf(&d.buf, "xref\n0 %d\n0000000000 65535 f \n", len(d.offsets)+1)
+	for _, offset := range d.offsets {
+		fmt.Fprintf(&d.buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&d.buf, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(d.offsets)+1, len(d.offsets), xref)
+
+	_, err := d.buf.WriteTo(w)
+	return err
+}
+
+// pdfString escapes text for a PDF literal string, dropping characters
+// outside ASCII since the document info uses the standard encoding
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
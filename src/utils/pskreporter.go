@@ -0,0 +1,170 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const pskReporterURL = "https://retrieve.pskreporter.info/query"
+
+// PSKReception is a single reception report of my signal from PSK Reporter
+type PSKReception struct {
+	ReceiverCall    string
+	ReceiverLocator string
+	ReceiverCountry string
+	Frequency       int64 // Hz
+	Mode            string
+	SNR             int
+	Time            time.Time
+}
+
+type pskReceptionReports struct {
+	Reports []struct {
+		ReceiverCallsign string `xml:"receiverCallsign,attr"`
+		ReceiverLocator  string `xml:"receiverLocator,attr"`
+		ReceiverDXCC     string `xml:"receiverDXCC,attr"`
+		Frequency        int64  `xml:"frequency,attr"`
+		FlowStartSeconds int64  `xml:"flowStartSeconds,attr"`
+		Mode             string `xml:"mode,attr"`
+		SNR              int    `xml:"sNR,attr"`
+	} `xml:"receptionReport"`
+}
+
+// PSKReporter periodically fetches and caches reception reports of a callsign
+type PSKReporter struct {
+	apiURL   string
+	callsign string
+	window   time.Duration
+	client   *http.Client
+
+	mutex   sync.RWMutex
+	reports []PSKReception
+}
+
+// NewPSKReporter creates a PSK Reporter client for the given callsign, looking
+// back over the given window for reception reports
+func NewPSKReporter(callsign string, window time.Duration) *PSKReporter {
+	return &PSKReporter{
+		apiURL:   pskReporterURL,
+		callsign: strings.ToUpper(strings.TrimSpace(callsign)),
+		window:   window,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Fetch queries PSK Reporter and replaces the cached reports
+func (p *PSKReporter) Fetch(ctx context.Context) error {
+	params := url.Values{}
+	params.Set("senderCallsign", p.callsign)
+	params.Set("flowStartSeconds", strconv.Itoa(-int(p.window.Seconds())))
+	params.Set("rronly", "1")
+	params.Set("noactive", "1")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.apiURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create PSK Reporter request: %w", err)
+	}
+	req.Header.Set("User-Agent", "humaid-qsl")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to query PSK Reporter: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("PSK Reporter returned status %d", resp.StatusCode)
+	}
+
+	var result pskReceptionReports
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode PSK Reporter response: %w", err)
+	}
+
+	reports := make([]PSKReception, 0, len(result.Reports))
+	for _, r := range result.Reports {
+		reports = append(reports, PSKReception{
+			ReceiverCall:    strings.ToUpper(r.ReceiverCallsign),
+			ReceiverLocator: r.ReceiverLocator,
+			ReceiverCountry: r.ReceiverDXCC,
+			Frequency:       r.Frequency,
+			Mode:            r.Mode,
+			SNR:             r.SNR,
+			Time:            time.Unix(r.FlowStartSeconds, 0).UTC(),
+		})
+	}
+
+	// Newest first
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].Time.After(reports[j].Time)
+	})
+
+	p.mutex.Lock()
+	p.reports = reports
+	p.mutex.Unlock()
+
+	return nil
+}
+
+// StartFetching starts the periodic fetch goroutine. PSK Reporter asks clients
+// not to query more often than every five minutes, so shorter intervals are
+// raised to that.
+func (p *PSKReporter) StartFetching(interval time.Duration) {
+	if interval < 5*time.Minute {
+		interval = 5 * time.Minute
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			if err := p.Fetch(ctx); err != nil {
+				log.Printf("Failed to fetch PSK Reporter spots: %v", err)
+			}
+			cancel()
+
+			<-ticker.C
+		}
+	}()
+}
+
+// Recent returns up to limit of the most recent cached reception reports,
+// keeping only the latest report per receiver
+func (p *PSKReporter) Recent(limit int) []PSKReception {
+	if p == nil {
+		return nil
+	}
+
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	seen := make(map[string]bool)
+	var result []PSKReception
+	for _, r := range p.reports {
+		if seen[r.ReceiverCall] {
+			continue
+		}
+		seen[r.ReceiverCall] = true
+		result = append(result, r)
+		if len(result) >= limit {
+			break
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+const pskReporterResponse = `<?xml version="1.0" encoding="UTF-8"?>
+<receptionReports>
+	<activeReceiver callsign="DL1ABC" locator="JO62qm" />
+	<receptionReport receiverCallsign="dl1abc" receiverLocator="JO62qm" receiverDXCC="Fed. Rep. of Germany" senderCallsign="A66H" frequency="14075123" flowStartSeconds="1740835800" mode="FT8" sNR="-12" />
+	<receptionReport receiverCallsign="K1ABC" receiverLocator="FN42" receiverDXCC="United States" senderCallsign="A66H" frequency="14075500" flowStartSeconds="1740836100" mode="FT8" sNR="-20" />
+	<receptionReport receiverCallsign="DL1ABC" receiverLocator="JO62qm" receiverDXCC="Fed. Rep. of Germany" senderCallsign="A66H" frequency="7074200" flowStartSeconds="1740836400" mode="FT8" sNR="-5" />
+</receptionReports>`
+
+func TestPSKReporterFetch(t *testing.T) {
+	var query url.Values
+	body := pskReporterResponse
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	p := NewPSKReporter(" a66h ", time.Hour)
+	p.apiURL = srv.URL
+
+	if err := p.Fetch(context.Background()); err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if query.Get("senderCallsign") != "A66H" || query.Get("flowStartSeconds") != "-3600" {
+		t.Errorf("Unexpected query %v", query)
+	}
+
+	recent := p.Recent(10)
+	if len(recent) != 2 {
+		t.Fatalf("Expected the latest report from each of 2 receivers, got %+v", recent)
+	}
+	want := PSKReception{
+		ReceiverCall:    "DL1ABC",
+		ReceiverLocator: "JO62qm",
+		ReceiverCountry: "Fed. Rep. of Germany",
+		Frequency:       7074200,
+		Mode:            "FT8",
+		SNR:             -5,
+		Time:            time.Unix(1740836400, 0).UTC(),
+	}
+	if recent[0] != want {
+		t.Errorf("Expected the newest report first, got %+v", recent[0])
+	}
+	if recent[1].ReceiverCall != "K1ABC" {
+		t.Errorf("Expected K1ABC second, got %s", recent[1].ReceiverCall)
+	}
+	if got := p.Recent(1); len(got) != 1 || got[0].ReceiverCall != "DL1ABC" {
+		t.Errorf("Expected the limit to apply, got %+v", got)
+	}
+
+	// A failed fetch keeps the cached reports
+	status = http.StatusServiceUnavailable
+	if err := p.Fetch(context.Background()); err == nil {
+		t.Error("Expected an error for a failed fetch")
+	}
+	if len(p.Recent(10)) != 2 {
+		t.Error("Expected the cached reports to be kept after a failed fetch")
+	}
+
+	// A successful fetch replaces them, so reports that fell out of the
+	// window are dropped
+	status = http.StatusOK
+	body = `<receptionReports></receptionReports>`
+	if err := p.Fetch(context.Background()); err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if got := p.Recent(10); len(got) != 0 {
+		t.Errorf("Expected the cache to be replaced, got %+v", got)
+	}
+
+	var disabled *PSKReporter
+	if disabled.Recent(10) != nil {
+		t.Error("Expected a nil PSKReporter to have no reports")
+	}
+}
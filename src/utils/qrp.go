@@ -0,0 +1,96 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// QRPMaxWatts is the highest transmit power counted as QRP
+const QRPMaxWatts = 5.0
+
+// ParsePower parses an ADIF TX_PWR value in watts, allowing a trailing W as
+// some loggers write it
+func ParsePower(pwr string) (float64, bool) {
+	pwr = strings.TrimSpace(pwr)
+	pwr = strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(pwr, "W"), "w"))
+	if pwr == "" {
+		return 0, false
+	}
+	watts, err := strconv.ParseFloat(pwr, 64)
+	if err != nil || watts <= 0 {
+		return 0, false
+	}
+	return watts, true
+}
+
+// Power returns the QSO's transmit power in watts, reporting false when it
+// wasn't logged or can't be parsed
+func (qso QSO) Power() (float64, bool) {
+	return ParsePower(qso.TxPwr)
+}
+
+// IsQRP reports whether a QSO was made with at most QRPMaxWatts
+func (qso QSO) IsQRP() bool {
+	watts, ok := qso.Power()
+	return ok && watts <= QRPMaxWatts
+}
+
+// QRPContact is a QRP QSO with a known distance
+type QRPContact struct {
+	QSO   QSO
+	Watts float64
+	Km    float64
+}
+
+// KmPerWatt returns the distance covered per watt of transmit power
+func (c QRPContact) KmPerWatt() float64 {
+	return c.Km / c.Watts
+}
+
+// QRPStats summarises the QSOs made at QRP power
+type QRPStats struct {
+	QSOs        int
+	Entities    []string     // DXCC entities worked QRP, by name
+	Leaderboard []QRPContact // Best distance per watt first
+}
+
+// ComputeQRPStats summarises the QRP QSOs in a log, keeping at most limit
+// contacts on the distance per watt leaderboard. Only QSOs with both grids
+// logged have a distance and can be placed on it.
+func ComputeQRPStats(qsos []QSO, limit int) QRPStats {
+	var stats QRPStats
+	entities := make(map[string]bool)
+
+	for _, qso := range qsos {
+		watts, ok := qso.Power()
+		if !ok || watts > QRPMaxWatts {
+			continue
+		}
+		stats.QSOs++
+
+		if entity := qso.Entity(); entity != "" {
+			entities[entity] = true
+		}
+		if km, err := Distance(qso.MyGridSquare, qso.GridSquare); err == nil && km > 0 {
+			stats.Leaderboard = append(stats.Leaderboard, QRPContact{QSO: qso, Watts: watts, Km: km})
+		}
+	}
+
+	for entity := range entities {
+		stats.Entities = append(stats.Entities, entity)
+	}
+	sort.Strings(stats.Entities)
+
+	sort.Slice(stats.Leaderboard, func(i, j int) bool {
+		return stats.Leaderboard[i].KmPerWatt() > stats.Leaderboard[j].KmPerWatt()
+	})
+	if len(stats.Leaderboard) > limit {
+		stats.Leaderboard = stats.Leaderboard[:limit]
+	}
+	return stats
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import "testing"
+
+func TestParsePower(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+		ok   bool
+	}{
+		{"5", 5, true},
+		{"0.5", 0.5, true},
+		{" 100W ", 100, true},
+		{"", 0, false},
+		{"QRP", 0, false},
+		{"-1", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParsePower(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParsePower(%q) = %v, %v; want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestComputeQRPStats(t *testing.T) {
+	stats := ComputeQRPStats([]QSO{
+		{Call: "EA4ABC", TxPwr: "5", MyGridSquare: "LL75", GridSquare: "IN80", DXCC: "281"},
+		{Call: "A61AA", TxPwr: "1", MyGridSquare: "LL75", GridSquare: "LL65", DXCC: "391"},
+		{Call: "4X1AB", TxPwr: "0.5", DXCC: "336"},
+		{Call: "W1AW", TxPwr: "100", MyGridSquare: "LL75", GridSquare: "FN31", DXCC: "291"},
+		{Call: "DL1ABC", MyGridSquare: "LL75", GridSquare: "JO62"},
+	}, 1)
+
+	if stats.QSOs != 3 {
+		t.Errorf("QSOs = %d, want 3", stats.QSOs)
+	}
+	if len(stats.Entities) != 3 {
+		t.Errorf("unexpected entities %v", stats.Entities)
+	}
+	if len(stats.Leaderboard) != 1 || stats.Leaderboard[0].QSO.Call != "EA4ABC" {
+		t.Fatalf("unexpected leaderboard %+v", stats.Leaderboard)
+	}
+	if stats.Leaderboard[0].KmPerWatt() <= 0 {
+		t.Errorf("KmPerWatt = %v, want positive", stats.Leaderboard[0].KmPerWatt())
+	}
+}
@@ -0,0 +1,190 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// QSLRoute is how a paper QSL card should be delivered
+type QSLRoute string
+
+const (
+	QSLRouteDirect QSLRoute = "direct" // Posted directly to the station's address
+	QSLRouteBureau QSLRoute = "bureau" // Sent through the QSL bureau
+)
+
+// ErrQSLRequestNotFound is returned when a QSL request ID is unknown
+var ErrQSLRequestNotFound = errors.New("QSL request not found")
+
+// qslRequestAddressData is authenticated along with each encrypted address,
+// followed by the request ID so an address can't be moved to another request
+var qslRequestAddressData = "humaid-qsl QSL request address "
+
+// QSLRequest is a request from another station for a paper QSL card
+type QSLRequest struct {
+	ID      string    `json:"id"`
+	Call    string    `json:"call"`
+	QSOTime time.Time `json:"qso_time"`
+	Band    string    `json:"band"`
+	Mode    string    `json:"mode"`
+	Route   QSLRoute  `json:"route"`
+	Name    string    `json:"name,omitempty"`
+	Address string    `json:"address,omitempty"` // Only kept for direct cards
+	Email   string    `json:"email,omitempty"`
+	Note    string    `json:"note,omitempty"`
+	Created time.Time `json:"created"`
+	Sent    time.Time `json:"sent,omitempty"`
+}
+
+// IsSent reports whether the card for this request has been sent
+func (r QSLRequest) IsSent() bool {
+	return !r.Sent.IsZero()
+}
+
+// qslRequestRecord is the on-disk form of a request, with the postal address
+// encrypted. Address is only set in files written before addresses were
+// encrypted, and is encrypted on the next save.
+type qslRequestRecord struct {
+	QSLRequest
+	SealedAddress []byte `json:"sealed_address,omitempty"` // Nonce followed by the ciphertext
+}
+
+// QSLRequestStore keeps paper QSL requests in a JSON file. Postal addresses
+// are encrypted with the address book key, so backups and copies of the data
+// directory don't expose them without it.
+type QSLRequestStore struct {
+	path     string
+	aead     cipher.AEAD
+	mutex    sync.RWMutex
+	requests []QSLRequest
+}
+
+// NewQSLRequestStore loads the requests stored at path, decrypting addresses
+// with a 32 byte address book key. It starts empty if the file doesn't exist
+// yet.
+func NewQSLRequestStore(path string, key []byte) (*QSLRequestStore, error) {
+	aead, err := newAddressBookAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	s := &QSLRequestStore{path: path, aead: aead}
+
+	var records []qslRequestRecord
+	if err := loadJSONFile(path, &records); err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		req := record.QSLRequest
+		if record.SealedAddress != nil {
+			nonceSize := aead.NonceSize()
+			if len(record.SealedAddress) < nonceSize {
+				return nil, fmt.Errorf("failed to decrypt address of QSL request %s: too short", req.ID)
+			}
+			nonce, sealed := record.SealedAddress[:nonceSize], record.SealedAddress[nonceSize:]
+			address, err := aead.Open(nil, nonce, sealed, []byte(qslRequestAddressData+req.ID))
+			if err != nil {
+				return nil, fmt.Errorf("failed to decrypt address of QSL request %s, is the key right? %w", req.ID, err)
+			}
+			req.Address = string(address)
+		}
+		s.requests = append(s.requests, req)
+	}
+	return s, nil
+}
+
+// Add stores a new request, assigning its ID and creation time
+func (s *QSLRequestStore) Add(req QSLRequest) (QSLRequest, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return req, fmt.Errorf("failed to generate request ID: %w", err)
+	}
+	req.ID = hex.EncodeToString(id)
+	req.Created = time.Now().UTC()
+	if req.Route != QSLRouteDirect {
+		req.Address = ""
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.requests = append(s.requests, req)
+	if err := s.save(); err != nil {
+		s.requests = s.requests[:len(s.requests)-1]
+		return req, err
+	}
+	return req, nil
+}
+
+// Pending returns whether there is an unsent request for the given QSO
+func (s *QSLRequestStore) Pending(call string, qsoTime time.Time) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, r := range s.requests {
+		if !r.IsSent() && strings.EqualFold(r.Call, call) && r.QSOTime.Equal(qsoTime) {
+			return true
+		}
+	}
+	return false
+}
+
+// List returns all requests, unsent first and oldest first within each group
+func (s *QSLRequestStore) List() []QSLRequest {
+	s.mutex.RLock()
+	requests := make([]QSLRequest, len(s.requests))
+	copy(requests, s.requests)
+	s.mutex.RUnlock()
+
+	sort.SliceStable(requests, func(i, j int) bool {
+		if requests[i].IsSent() != requests[j].IsSent() {
+			return !requests[i].IsSent()
+		}
+		return requests[i].Created.Before(requests[j].Created)
+	})
+	return requests
+}
+
+// MarkSent records that the card for a request has been sent, returning the
+// updated request
+func (s *QSLRequestStore) MarkSent(id string) (QSLRequest, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i := range s.requests {
+		if s.requests[i].ID == id {
+			s.requests[i].Sent = time.Now().UTC()
+			return s.requests[i], s.save()
+		}
+	}
+	return QSLRequest{}, ErrQSLRequestNotFound
+}
+
+// save encrypts the addresses with fresh nonces and writes the requests to
+// disk. The caller must hold the lock.
+func (s *QSLRequestStore) save() error {
+	records := make([]qslRequestRecord, len(s.requests))
+	for i, req := range s.requests {
+		records[i].QSLRequest = req
+		if req.Address == "" {
+			continue
+		}
+		nonce := make([]byte, s.aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return fmt.Errorf("failed to generate nonce: %w", err)
+		}
+		records[i].Address = ""
+		records[i].SealedAddress = s.aead.Seal(nonce, nonce, []byte(req.Address), []byte(qslRequestAddressData+req.ID))
+	}
+	return saveJSONFile(s.path, records, 0600)
+}
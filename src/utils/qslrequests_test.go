@@ -0,0 +1,115 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQSLRequestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "qsl-requests.json")
+	key := bytes.Repeat([]byte{7}, 32)
+	store, err := NewQSLRequestStore(path, key)
+	if err != nil {
+		t.Fatalf("Failed to create QSL request store: %v", err)
+	}
+
+	qsoTime := time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC)
+	direct, err := store.Add(QSLRequest{Call: "A61XX", QSOTime: qsoTime, Route: QSLRouteDirect, Name: "Ali", Address: "PO Box 1\nAbu Dhabi"})
+	if err != nil {
+		t.Fatalf("Failed to add request: %v", err)
+	}
+	if direct.ID == "" || direct.Created.IsZero() {
+		t.Errorf("Expected an ID and creation time, got %+v", direct)
+	}
+
+	bureau, err := store.Add(QSLRequest{Call: "DL1ABC", QSOTime: qsoTime, Route: QSLRouteBureau, Address: "Somewhere"})
+	if err != nil {
+		t.Fatalf("Failed to add request: %v", err)
+	}
+	if bureau.Address != "" {
+		t.Errorf("Expected the address of a bureau request to be dropped, got %q", bureau.Address)
+	}
+
+	if !store.Pending("a61xx", qsoTime) {
+		t.Error("Expected the direct request to be pending")
+	}
+	if store.Pending("A61XX", qsoTime.Add(time.Minute)) {
+		t.Error("Expected no pending request for another QSO")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read QSL requests: %v", err)
+	}
+	if strings.Contains(string(data), "Abu Dhabi") {
+		t.Error("Expected the postal address to be encrypted")
+	}
+
+	reloaded, err := NewQSLRequestStore(path, key)
+	if err != nil {
+		t.Fatalf("Failed to reopen QSL request store: %v", err)
+	}
+	requests := reloaded.List()
+	if len(requests) != 2 || requests[0].Address != "PO Box 1\nAbu Dhabi" {
+		t.Fatalf("Expected both requests with the address decrypted, got %+v", requests)
+	}
+
+	if _, err := NewQSLRequestStore(path, bytes.Repeat([]byte{8}, 32)); err == nil {
+		t.Error("Expected opening with the wrong key to fail")
+	}
+
+	sent, err := reloaded.MarkSent(direct.ID)
+	if err != nil {
+		t.Fatalf("Failed to mark request sent: %v", err)
+	}
+	if !sent.IsSent() {
+		t.Error("Expected the request to be marked sent")
+	}
+	if reloaded.Pending("A61XX", qsoTime) {
+		t.Error("Expected a sent request to no longer be pending")
+	}
+	if requests := reloaded.List(); requests[0].ID != bureau.ID {
+		t.Errorf("Expected unsent requests first, got %s", requests[0].Call)
+	}
+
+	if _, err := reloaded.MarkSent("unknown"); !errors.Is(err, ErrQSLRequestNotFound) {
+		t.Errorf("Expected ErrQSLRequestNotFound, got %v", err)
+	}
+}
+
+func TestQSLRequestStoreEncryptsPlainAddresses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "qsl-requests.json")
+	plain := `[{"id":"abc","call":"A61XX","route":"direct","address":"PO Box 1\nAbu Dhabi"}]`
+	if err := os.WriteFile(path, []byte(plain), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	key := bytes.Repeat([]byte{7}, 32)
+	store, err := NewQSLRequestStore(path, key)
+	if err != nil {
+		t.Fatalf("Failed to open QSL request store: %v", err)
+	}
+	if requests := store.List(); len(requests) != 1 || requests[0].Address != "PO Box 1\nAbu Dhabi" {
+		t.Fatalf("Expected the plain address to be read, got %+v", requests)
+	}
+
+	if _, err := store.MarkSent("abc"); err != nil {
+		t.Fatalf("Failed to mark request sent: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read QSL requests: %v", err)
+	}
+	if strings.Contains(string(data), "Abu Dhabi") {
+		t.Error("Expected the address to be encrypted when saved")
+	}
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter allows up to limit events per key within a sliding window
+type RateLimiter struct {
+	limit  int
+	window time.Duration
+
+	mutex     sync.Mutex
+	events    map[string][]time.Time
+	lastPrune time.Time
+}
+
+// NewRateLimiter creates a rate limiter allowing limit events per window
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		limit:  limit,
+		window: window,
+		events: make(map[string][]time.Time),
+	}
+}
+
+// Allow records an event for key and reports whether it is within the limit.
+// Rejected events are not recorded.
+func (rl *RateLimiter) Allow(key string) bool {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	now := time.Now()
+	rl.prune(now)
+
+	recent := rl.recent(key, now)
+	if len(recent) >= rl.limit {
+		rl.events[key] = recent
+		return false
+	}
+
+	rl.events[key] = append(recent, now)
+	return true
+}
+
+// Count returns the number of events recorded for key within the window
+func (rl *RateLimiter) Count(key string) int {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+	return len(rl.recent(key, time.Now()))
+}
+
+// recent returns the events for key that are still within the window
+func (rl *RateLimiter) recent(key string, now time.Time) []time.Time {
+	events := rl.events[key]
+	i := 0
+	for i < len(events) && now.Sub(events[i]) >= rl.window {
+		i++
+	}
+	return events[i:]
+}
+
+// prune drops keys with no recent events, at most once per window
+func (rl *RateLimiter) prune(now time.Time) {
+	if now.Sub(rl.lastPrune) < rl.window {
+		return
+	}
+	rl.lastPrune = now
+
+	for key := range rl.events {
+		if len(rl.recent(key, now)) == 0 {
+			delete(rl.events, key)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RBNTelnetAddr is the Reverse Beacon Network telnet feed carrying CW and
+// RTTY skimmer spots
+const RBNTelnetAddr = "telnet.reversebeacon.net:7000"
+
+const (
+	rbnMaxSpots     = 100
+	rbnSpotLifetime = time.Hour
+)
+
+// RBNSpot is a Reverse Beacon Network skimmer spot of my signal
+type RBNSpot struct {
+	Skimmer   string
+	Spotted   string
+	Frequency float64 // kHz
+	Mode      string
+	SNR       int
+	Speed     int    // WPM for CW, baud for RTTY
+	SpeedUnit string // WPM or BPS
+	Time      time.Time
+}
+
+// FormatSpeed formats the keying speed with its unit (e.g. 22 WPM)
+func (s RBNSpot) FormatSpeed() string {
+	return strconv.Itoa(s.Speed) + " " + s.SpeedUnit
+}
+
+// Example: "DX de KM3T-#:     14025.0  A66H           CW    24 dB  22 WPM  CQ      1234Z"
+var rbnSpotRegex = regexp.MustCompile(`^DX de ([A-Za-z0-9/]+)-#:?\s+([0-9.]+)\s+([A-Za-z0-9/]+)\s+(CW|RTTY)\s+(-?\d+)\s+dB\s+(\d+)\s+(WPM|BPS)\b.*?(\d{4})Z`)
+
+// ParseRBNSpot parses a CW or RTTY spot line from the RBN telnet feed
+func ParseRBNSpot(line string) (RBNSpot, bool) {
+	match := rbnSpotRegex.FindStringSubmatch(strings.TrimSpace(line))
+	if match == nil {
+		return RBNSpot{}, false
+	}
+
+	freq, err := strconv.ParseFloat(match[2], 64)
+	if err != nil {
+		return RBNSpot{}, false
+	}
+	snr, _ := strconv.Atoi(match[5])
+	speed, _ := strconv.Atoi(match[6])
+
+	return RBNSpot{
+		Skimmer:   strings.ToUpper(match[1]),
+		Spotted:   strings.ToUpper(match[3]),
+		Frequency: freq,
+		Mode:      match[4],
+		SNR:       snr,
+		Speed:     speed,
+		SpeedUnit: match[7],
+		Time:      time.Now().UTC(),
+	}, true
+}
+
+// RBN watches the Reverse Beacon Network feed for skimmer spots of my callsign
+type RBN struct {
+	addr     string
+	login    string
+	callsign string
+
+	mutex sync.RWMutex
+	spots []RBNSpot
+}
+
+// NewRBN creates an RBN client that logs in to addr and records skimmer spots
+// of the given callsign
+func NewRBN(addr, callsign string) *RBN {
+	callsign = strings.ToUpper(strings.TrimSpace(callsign))
+	return &RBN{
+		addr:     addr,
+		login:    callsign,
+		callsign: callsign,
+	}
+}
+
+// Start connects to the RBN feed in the background, reconnecting with
+// exponential backoff when the connection drops
+func (r *RBN) Start() {
+	startTelnetFeed("RBN", r.addr, r.login, r.handleLine)
+}
+
+// handleLine records a spot line if it spots my callsign
+func (r *RBN) handleLine(line string) {
+	// The feed carries every skimmer spot worldwide, so cheaply skip lines
+	// that cannot be about me before running the regex
+	if !strings.Contains(strings.ToUpper(line), r.callsign) {
+		return
+	}
+
+	spot, ok := ParseRBNSpot(line)
+	if !ok || !MatchesCallsign(spot.Spotted, r.callsign) {
+		return
+	}
+	r.addSpot(spot)
+}
+
+// addSpot records a spot, replacing any earlier spot from the same skimmer on
+// the same band so each skimmer shows its latest reception
+func (r *RBN) addSpot(spot RBNSpot) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	// Newest first, dropping expired spots and keeping the cache bounded
+	spots := []RBNSpot{spot}
+	for _, existing := range r.spots {
+		if len(spots) >= rbnMaxSpots {
+			break
+		}
+		if existing.Skimmer == spot.Skimmer && spotBand(existing.Frequency) == spotBand(spot.Frequency) {
+			continue
+		}
+		if spot.Time.Sub(existing.Time) > rbnSpotLifetime {
+			continue
+		}
+		spots = append(spots, existing)
+	}
+	r.spots = spots
+}
+
+// Recent returns up to limit of the most recent unexpired spots
+func (r *RBN) Recent(limit int) []RBNSpot {
+	if r == nil {
+		return nil
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var result []RBNSpot
+	for _, spot := range r.spots {
+		if time.Since(spot.Time) > rbnSpotLifetime {
+			continue
+		}
+		result = append(result, spot)
+		if len(result) >= limit {
+			break
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRBNSpot(t *testing.T) {
+	spot, ok := ParseRBNSpot("DX de KM3T-#:     14025.0  A66H/P         CW    24 dB  22 WPM  CQ      1234Z")
+	if !ok {
+		t.Fatal("Expected CW spot line to parse")
+	}
+	if spot.Skimmer != "KM3T" || spot.Mode != "CW" || spot.SNR != 24 {
+		t.Errorf("Expected KM3T CW 24 dB, got %s %s %d dB", spot.Skimmer, spot.Mode, spot.SNR)
+	}
+	if spot.FormatSpeed() != "22 WPM" || spot.Frequency != 14025.0 {
+		t.Errorf("Expected 22 WPM on 14025.0 kHz, got %s on %v", spot.FormatSpeed(), spot.Frequency)
+	}
+	if !MatchesCallsign(spot.Spotted, "A66H") {
+		t.Errorf("Expected %s to match A66H", spot.Spotted)
+	}
+
+	spot, ok = ParseRBNSpot("DX de W3OA-#:     14083.0  A66H           RTTY  -3 dB  45 BPS  CQ      0915Z")
+	if !ok {
+		t.Fatal("Expected RTTY spot line to parse")
+	}
+	if spot.Mode != "RTTY" || spot.SNR != -3 || spot.FormatSpeed() != "45 BPS" {
+		t.Errorf("Expected RTTY -3 dB 45 BPS, got %s %d dB %s", spot.Mode, spot.SNR, spot.FormatSpeed())
+	}
+
+	if _, ok := ParseRBNSpot("DX de W3LPL:   14025.0  A66H  UP 2           1234Z"); ok {
+		t.Error("Expected a human cluster spot not to parse as an RBN spot")
+	}
+}
+
+func TestRBNKeepsLatestSpotPerSkimmerAndBand(t *testing.T) {
+	rbn := NewRBN(RBNTelnetAddr, "a66h")
+
+	rbn.handleLine("DX de KM3T-#:     14025.0  A66H  CW    12 dB  22 WPM  CQ      1200Z")
+	rbn.handleLine("DX de KM3T-#:     14025.1  A66H  CW    18 dB  22 WPM  CQ      1210Z")
+	rbn.handleLine("DX de KM3T-#:      7025.0  A66H  CW     9 dB  22 WPM  CQ      1215Z")
+	rbn.handleLine("DX de DL9GTB-#:   14025.0  A66H  CW    30 dB  22 WPM  CQ      1220Z")
+	rbn.handleLine("DX de DL9GTB-#:   14030.0  A66HA CW    30 dB  22 WPM  CQ      1220Z")
+
+	spots := rbn.Recent(10)
+	if len(spots) != 3 {
+		t.Fatalf("Expected 3 spots, got %d", len(spots))
+	}
+	if spots[0].Skimmer != "DL9GTB" {
+		t.Errorf("Expected newest spot first, got %s", spots[0].Skimmer)
+	}
+	if spots[2].SNR != 18 {
+		t.Errorf("Expected the later 20m KM3T spot to replace the earlier one, got %d dB", spots[2].SNR)
+	}
+
+	// Expired spots are hidden
+	rbn.mutex.Lock()
+	for i := range rbn.spots {
+		rbn.spots[i].Time = time.Now().Add(-2 * rbnSpotLifetime)
+	}
+	rbn.mutex.Unlock()
+	if len(rbn.Recent(10)) != 0 {
+		t.Error("Expected expired spots to be hidden")
+	}
+
+	var disabled *RBN
+	if disabled.Recent(10) != nil {
+		t.Error("Expected a nil RBN to report no spots")
+	}
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// RedactMode is how a redacted field is shown on public pages
+type RedactMode int
+
+const (
+	RedactNone    RedactMode = iota
+	RedactHide               // Field is left out entirely
+	RedactPartial            // Field is shortened, e.g. "John Smith" to "John S."
+)
+
+// redactFields are the QSO fields that may be redacted
+var redactFields = []string{"name", "qth", "comment"}
+
+// Redactor hides or shortens personal QSO fields on public pages. Exports and
+// the admin area use the QSOs directly and keep the full values. A nil
+// Redactor redacts nothing.
+type Redactor struct {
+	modes map[string]RedactMode
+}
+
+// ParseRedactor parses redaction rules of the form "field" or "field:mode",
+// where field is name, qth or comment and mode is hide (the default) or
+// partial
+func ParseRedactor(rules []string) (*Redactor, error) {
+	r := &Redactor{modes: make(map[string]RedactMode)}
+	for _, rule := range rules {
+		field, mode, _ := strings.Cut(strings.ToLower(strings.TrimSpace(rule)), ":")
+		if !slices.Contains(redactFields, field) {
+			return nil, fmt.Errorf("unknown redaction field %q, expected one of %s", field, strings.Join(redactFields, ", "))
+		}
+
+		switch mode {
+		case "", "hide":
+			r.modes[field] = RedactHide
+		case "partial":
+			r.modes[field] = RedactPartial
+		default:
+			return nil, fmt.Errorf("unknown redaction mode %q for %s, expected hide or partial", mode, field)
+		}
+	}
+	return r, nil
+}
+
+// Mode returns how field is redacted
+func (r *Redactor) Mode(field string) RedactMode {
+	if r == nil {
+		return RedactNone
+	}
+	return r.modes[field]
+}
+
+// Redact returns value as it may be shown publicly for field
+func (r *Redactor) Redact(field, value string) string {
+	switch r.Mode(field) {
+	case RedactHide:
+		return ""
+	case RedactPartial:
+		return redactPartial(field, value)
+	}
+	return value
+}
+
+// redactPartial shortens a value. Names keep the first name and initials of
+// the rest, QTHs keep only their last, broadest part, e.g. the country in
+// "Springfield, IL, USA", and comments are hidden since they can't be
+// meaningfully shortened.
+func redactPartial(field, value string) string {
+	switch field {
+	case "name":
+		words := strings.Fields(value)
+		for i := 1; i < len(words); i++ {
+			words[i] = string([]rune(words[i])[:1]) + "."
+		}
+		return strings.Join(words, " ")
+	case "qth":
+		parts := strings.Split(value, ",")
+		return strings.TrimSpace(parts[len(parts)-1])
+	}
+	return ""
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import "testing"
+
+func TestRedactor(t *testing.T) {
+	r, err := ParseRedactor([]string{"name:partial", "QTH:partial", "comment"})
+	if err != nil {
+		t.Fatalf("ParseRedactor: %v", err)
+	}
+
+	tests := []struct {
+		field, value, want string
+	}{
+		{"name", "John Smith", "John S."},
+		{"name", "Ahmed bin Zayed", "Ahmed b. Z."},
+		{"name", "Jo", "Jo"},
+		{"qth", "Springfield, IL, USA", "USA"},
+		{"qth", "Dubai", "Dubai"},
+		{"comment", "Home 555-0100", ""},
+	}
+	for _, tt := range tests {
+		if got := r.Redact(tt.field, tt.value); got != tt.want {
+			t.Errorf("Redact(%q, %q) = %q, want %q", tt.field, tt.value, got, tt.want)
+		}
+	}
+
+	hide, err := ParseRedactor([]string{"name"})
+	if err != nil {
+		t.Fatalf("ParseRedactor: %v", err)
+	}
+	if got := hide.Redact("name", "John Smith"); got != "" {
+		t.Errorf("hidden name = %q, want empty", got)
+	}
+	if got := hide.Redact("qth", "Dubai"); got != "Dubai" {
+		t.Errorf("unredacted QTH = %q, want Dubai", got)
+	}
+
+	var none *Redactor
+	if got := none.Redact("name", "John Smith"); got != "John Smith" {
+		t.Errorf("nil Redactor changed name to %q", got)
+	}
+}
+
+func TestParseRedactorErrors(t *testing.T) {
+	for _, rule := range []string{"email", "name:blur", ""} {
+		if _, err := ParseRedactor([]string{rule}); err == nil {
+			t.Errorf("ParseRedactor(%q) succeeded", rule)
+		}
+	}
+}
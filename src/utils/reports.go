@@ -0,0 +1,132 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ReportFormat is the kind of signal report a mode uses
+type ReportFormat int
+
+const (
+	ReportAny ReportFormat = iota // Unknown mode, any plausible report
+	ReportRS                      // Phone: readability and strength, e.g. 59
+	ReportRST                     // CW: readability, strength and tone, e.g. 599
+	ReportDB                      // Weak signal data: signal to noise in dB, e.g. -10
+)
+
+// dbModes are the modes and submodes reporting signal to noise ratio in dB
+var dbModes = map[string]bool{
+	"FT8":    true,
+	"FT4":    true,
+	"JT4":    true,
+	"JT9":    true,
+	"JT65":   true,
+	"JS8":    true,
+	"Q65":    true,
+	"FST4":   true,
+	"MSK144": true,
+	"ISCAT":  true,
+}
+
+// phoneModes are the modes reporting readability and strength only
+var phoneModes = map[string]bool{
+	"SSB":          true,
+	"USB":          true,
+	"LSB":          true,
+	"AM":           true,
+	"FM":           true,
+	"DIGITALVOICE": true,
+}
+
+// ReportFormatOf returns the report format of an ADIF mode and submode.
+// Data modes other than the weak signal ones use RST in some logs and dB in
+// others, so any plausible report is accepted for them.
+func ReportFormatOf(mode, submode string) ReportFormat {
+	mode = strings.ToUpper(strings.TrimSpace(mode))
+	submode = strings.ToUpper(strings.TrimSpace(submode))
+
+	switch {
+	case dbModes[mode] || dbModes[submode]:
+		return ReportDB
+	case mode == "CW":
+		return ReportRST
+	case phoneModes[mode]:
+		return ReportRS
+	}
+	return ReportAny
+}
+
+// parseDBReport parses a signal to noise report in dB. Some loggers leave
+// out the sign of positive reports.
+func parseDBReport(s string) (int, bool) {
+	db, err := strconv.Atoi(strings.TrimSpace(s))
+	return db, err == nil && db >= -50 && db <= 50
+}
+
+// CheckReport returns why a signal report doesn't suit the report format of
+// its mode, or "" if it does
+func CheckReport(report string, format ReportFormat) string {
+	report = strings.TrimSpace(report)
+	if format == ReportDB {
+		if _, ok := parseDBReport(report); ok {
+			return ""
+		}
+		if ValidRST(report) {
+			return "isn't a dB report"
+		}
+		return "is out of range"
+	}
+
+	if !ValidRST(report) {
+		return "is out of range"
+	}
+	switch {
+	case format == ReportAny:
+		return ""
+	case report[0] == '-' || report[0] == '+':
+		return "is a dB report"
+	case format == ReportRS && len(report) != 2:
+		return "has a tone digit"
+	case format == ReportRST && len(report) != 3:
+		return "is missing the tone digit"
+	}
+	return ""
+}
+
+// ReportFormat returns the report format of the QSO's mode
+func (qso QSO) ReportFormat() ReportFormat {
+	return ReportFormatOf(qso.Mode, qso.Submode)
+}
+
+// ReportsInDB reports whether the QSO's mode reports signal to noise in dB
+func (qso QSO) ReportsInDB() bool {
+	return qso.ReportFormat() == ReportDB
+}
+
+// FormatReport formats a signal report for display, writing dB reports as
+// WSJT-X does with a sign and two digits, e.g. -05 dB
+func FormatReport(report string, format ReportFormat) string {
+	report = strings.TrimSpace(report)
+	if format == ReportDB {
+		if db, ok := parseDBReport(report); ok {
+			return fmt.Sprintf("%+03d dB", db)
+		}
+	}
+	return report
+}
+
+// FormatRSTSent formats the sent signal report for display
+func (qso QSO) FormatRSTSent() string {
+	return FormatReport(qso.RSTSent, qso.ReportFormat())
+}
+
+// FormatRSTRcvd formats the received signal report for display
+func (qso QSO) FormatRSTRcvd() string {
+	return FormatReport(qso.RSTRcvd, qso.ReportFormat())
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import "testing"
+
+func TestReportFormatOf(t *testing.T) {
+	tests := []struct {
+		mode, submode string
+		want          ReportFormat
+	}{
+		{"FT8", "", ReportDB},
+		{"mfsk", "ft4", ReportDB},
+		{"MFSK", "JS8", ReportDB},
+		{"CW", "", ReportRST},
+		{"SSB", "USB", ReportRS},
+		{"FM", "", ReportRS},
+		{"RTTY", "", ReportAny},
+		{"", "", ReportAny},
+	}
+	for _, tt := range tests {
+		if got := ReportFormatOf(tt.mode, tt.submode); got != tt.want {
+			t.Errorf("ReportFormatOf(%q, %q) = %v, want %v", tt.mode, tt.submode, got, tt.want)
+		}
+	}
+}
+
+func TestCheckReport(t *testing.T) {
+	tests := []struct {
+		report string
+		format ReportFormat
+		want   string
+	}{
+		{"-10", ReportDB, ""},
+		{"+05", ReportDB, ""},
+		{"12", ReportDB, ""},
+		{"599", ReportDB, "isn't a dB report"},
+		{"-60", ReportDB, "is out of range"},
+		{"59", ReportRS, ""},
+		{"599", ReportRS, "has a tone digit"},
+		{"-10", ReportRS, "is a dB report"},
+		{"599", ReportRST, ""},
+		{"59", ReportRST, "is missing the tone digit"},
+		{"699", ReportRST, "is out of range"},
+		{"-10", ReportAny, ""},
+		{"599", ReportAny, ""},
+		{"S9", ReportAny, "is out of range"},
+	}
+	for _, tt := range tests {
+		if got := CheckReport(tt.report, tt.format); got != tt.want {
+			t.Errorf("CheckReport(%q, %v) = %q, want %q", tt.report, tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestFormatReport(t *testing.T) {
+	tests := []struct {
+		qso  QSO
+		want string
+	}{
+		{QSO{Mode: "FT8", RSTRcvd: "-5"}, "-05 dB"},
+		{QSO{Mode: "FT4", RSTRcvd: "12"}, "+12 dB"},
+		{QSO{Mode: "FT8", RSTRcvd: "599"}, "599"},
+		{QSO{Mode: "SSB", RSTRcvd: "59"}, "59"},
+		{QSO{Mode: "CW", RSTRcvd: " 599 "}, "599"},
+		{QSO{Mode: "FT8"}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.qso.FormatRSTRcvd(); got != tt.want {
+			t.Errorf("FormatRSTRcvd() for %s %q = %q, want %q", tt.qso.Mode, tt.qso.RSTRcvd, got, tt.want)
+		}
+	}
+	if got := (QSO{Mode: "FT8", RSTSent: "+0"}).FormatRSTSent(); got != "+00 dB" {
+		t.Errorf("FormatRSTSent() = %q, want +00 dB", got)
+	}
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// futureSlack allows for clock differences between the logging computer and
+// the server before a QSO is considered to be in the future
+const futureSlack = time.Hour
+
+// SanityIssue is a QSO that parsed but looks wrong
+type SanityIssue struct {
+	Call      string
+	Timestamp time.Time
+	Problem   string
+}
+
+// CheckLog looks for suspicious QSOs: times in the future, frequencies
+// outside the logged band, out of range signal reports or ones that don't
+// suit the mode, and exact duplicates
+func CheckLog(qsos []QSO, now time.Time) []SanityIssue {
+	var issues []SanityIssue
+	add := func(qso QSO, format string, args ...interface{}) {
+		issues = append(issues, SanityIssue{
+			Call:      qso.Call,
+			Timestamp: qso.Timestamp,
+			Problem:   fmt.Sprintf(format, args...),
+		})
+	}
+
+	type recordKey struct {
+		call, band, mode, freq, rstSent, rstRcvd string
+		unix                                     int64
+	}
+	seen := make(map[recordKey]bool, len(qsos))
+	for _, qso := range qsos {
+		if qso.Timestamp.After(now.Add(futureSlack)) {
+			add(qso, "logged in the future")
+		}
+
+		if mhz, ok := ParseFrequency(qso.Freq); ok {
+			if qso.Band != "" {
+				if b, known := bandByName(qso.Band); known && (mhz < b.Lower || mhz > b.Upper) {
+					add(qso, "frequency %s MHz is outside the %s band", qso.Freq, b.Name)
+				}
+			} else if BandFromFrequency(mhz) == "" {
+				add(qso, "frequency %s MHz is outside all amateur bands", qso.Freq)
+			}
+		} else if qso.Freq != "" {
+			add(qso, "frequency %q is not a number", qso.Freq)
+		}
+
+		format := qso.ReportFormat()
+		for _, r := range []struct{ dir, report string }{{"sent", qso.RSTSent}, {"received", qso.RSTRcvd}} {
+			if r.report == "" {
+				continue
+			}
+			if problem := CheckReport(r.report, format); problem != "" {
+				if format != ReportAny {
+					problem += " for " + strings.ToUpper(qso.Mode)
+				}
+				add(qso, "%s report %q %s", r.dir, r.report, problem)
+			}
+		}
+
+		key := recordKey{
+			call:    strings.ToUpper(qso.Call),
+			band:    strings.ToUpper(qso.Band),
+			mode:    strings.ToUpper(qso.Mode),
+			freq:    qso.Freq,
+			rstSent: qso.RSTSent,
+			rstRcvd: qso.RSTRcvd,
+			unix:    qso.Timestamp.Unix(),
+		}
+		if seen[key] {
+			add(qso, "duplicate of an earlier record")
+		}
+		seen[key] = true
+	}
+
+	return issues
+}
+
+// ValidRST reports whether s is a plausible signal report: RS for phone,
+// RST for CW and data modes, or a signed dB report as used by FT8 and similar
+func ValidRST(s string) bool {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return false
+	}
+
+	if s[0] == '-' || s[0] == '+' {
+		db, err := strconv.Atoi(s)
+		return err == nil && db >= -50 && db <= 50
+	}
+
+	if len(s) != 2 && len(s) != 3 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '1' || s[i] > '9' {
+			return false
+		}
+	}
+	// Readability only goes up to 5
+	return s[0] <= '5'
+}
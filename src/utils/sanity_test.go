@@ -0,0 +1,63 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckLog(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	ok := QSO{Call: "A61XX", Band: "20m", Freq: "14.074", RSTSent: "-05", RSTRcvd: "+02", Timestamp: now.Add(-time.Hour)}
+
+	qsos := []QSO{
+		ok,
+		{Call: "W1AW", Band: "20m", Freq: "14.200", RSTSent: "59", Timestamp: now.Add(48 * time.Hour)},
+		{Call: "K1ABC", Band: "40M", Freq: "14.200", RSTSent: "599", Timestamp: now.Add(-2 * time.Hour)},
+		{Call: "G4XYZ", Freq: "13.500", Timestamp: now.Add(-3 * time.Hour)},
+		{Call: "JA1ZZZ", Band: "20m", Freq: "14.010", RSTSent: "699", RSTRcvd: "50", Timestamp: now.Add(-4 * time.Hour)},
+		{Call: "VK2AB", Band: "20m", Freq: "14.074", Mode: "FT8", RSTSent: "599", RSTRcvd: "-12", Timestamp: now.Add(-5 * time.Hour)},
+		{Call: "ZL1AB", Band: "20m", Freq: "14.250", Mode: "SSB", RSTSent: "-10", RSTRcvd: "59", Timestamp: now.Add(-6 * time.Hour)},
+		ok,
+	}
+
+	issues := CheckLog(qsos, now)
+	want := []struct{ call, problem string }{
+		{"W1AW", "future"},
+		{"K1ABC", "outside the 40m band"},
+		{"G4XYZ", "outside all amateur bands"},
+		{"JA1ZZZ", "sent report \"699\""},
+		{"JA1ZZZ", "received report \"50\""},
+		{"VK2AB", "sent report \"599\" isn't a dB report for FT8"},
+		{"ZL1AB", "sent report \"-10\" is a dB report for SSB"},
+		{"A61XX", "duplicate"},
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("Expected %d issues, got %d: %+v", len(want), len(issues), issues)
+	}
+	for i, w := range want {
+		if issues[i].Call != w.call || !strings.Contains(issues[i].Problem, w.problem) {
+			t.Errorf("Issue %d = %s: %q, want %s: ...%s...", i, issues[i].Call, issues[i].Problem, w.call, w.problem)
+		}
+	}
+}
+
+func TestValidRST(t *testing.T) {
+	valid := []string{"59", "599", "11", "339", "-10", "+05", "-24"}
+	for _, s := range valid {
+		if !ValidRST(s) {
+			t.Errorf("Expected %q to be valid", s)
+		}
+	}
+
+	invalid := []string{"", "0", "5", "69", "590", "5999", "5NN", "-60", "+", "S9"}
+	for _, s := range invalid {
+		if ValidRST(s) {
+			t.Errorf("Expected %q to be invalid", s)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"sort"
+	"strings"
+)
+
+// SatelliteStats is the activity through a single satellite
+type SatelliteStats struct {
+	Name     string
+	Modes    []string // Satellite modes used, e.g. U/V
+	QSOs     int
+	Grids    int // Distinct 4-character grids worked
+	Entities int // Distinct DXCC entities worked
+}
+
+// IsSatellite reports whether a QSO was made through a satellite
+func (qso QSO) IsSatellite() bool {
+	return qso.SatName != "" || qso.PropMode == "SAT"
+}
+
+// ComputeSatelliteStats breaks down satellite QSOs per satellite, busiest
+// first. QSOs with PROP_MODE SAT but no SAT_NAME are grouped under an empty
+// name.
+func ComputeSatelliteStats(qsos []QSO) []SatelliteStats {
+	type tally struct {
+		stats    SatelliteStats
+		modes    map[string]bool
+		grids    map[string]bool
+		entities map[string]bool
+	}
+	birds := make(map[string]*tally)
+
+	for _, qso := range qsos {
+		if !qso.IsSatellite() {
+			continue
+		}
+
+		t := birds[qso.SatName]
+		if t == nil {
+			t = &tally{
+				stats:    SatelliteStats{Name: qso.SatName},
+				modes:    make(map[string]bool),
+				grids:    make(map[string]bool),
+				entities: make(map[string]bool),
+			}
+			birds[qso.SatName] = t
+		}
+
+		t.stats.QSOs++
+		if qso.SatMode != "" {
+			t.modes[qso.SatMode] = true
+		}
+		for _, grid := range qsoGrids(qso) {
+			t.grids[grid] = true
+		}
+		if entity := qso.Entity(); entity != "" {
+			t.entities[strings.ToUpper(entity)] = true
+		}
+	}
+
+	stats := make([]SatelliteStats, 0, len(birds))
+	for _, t := range birds {
+		for mode := range t.modes {
+			t.stats.Modes = append(t.stats.Modes, mode)
+		}
+		sort.Strings(t.stats.Modes)
+		t.stats.Grids = len(t.grids)
+		t.stats.Entities = len(t.entities)
+		stats = append(stats, t.stats)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].QSOs != stats[j].QSOs {
+			return stats[i].QSOs > stats[j].QSOs
+		}
+		return stats[i].Name < stats[j].Name
+	})
+	return stats
+}
@@ -0,0 +1,32 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import "testing"
+
+func TestComputeSatelliteStats(t *testing.T) {
+	stats := ComputeSatelliteStats([]QSO{
+		{Call: "A61AA", SatName: "SO-50", SatMode: "V/U", GridSquare: "LL75", DXCC: "391"},
+		{Call: "A61BB", SatName: "SO-50", SatMode: "V/U", GridSquare: "LL65ab", DXCC: "391"},
+		{Call: "4X1AB", SatName: "SO-50", GridSquare: "KM72", DXCC: "336"},
+		{Call: "EA4ABC", SatName: "QO-100", SatMode: "S/X", GridSquare: "IN80", DXCC: "281"},
+		{Call: "W1AW", PropMode: "SAT"},
+		{Call: "DL1ABC", Band: "20m"},
+	})
+
+	if len(stats) != 3 {
+		t.Fatalf("got %d satellites, want 3: %+v", len(stats), stats)
+	}
+	so50 := stats[0]
+	if so50.Name != "SO-50" || so50.QSOs != 3 || so50.Grids != 3 || so50.Entities != 2 {
+		t.Errorf("unexpected SO-50 stats %+v", so50)
+	}
+	if len(so50.Modes) != 1 || so50.Modes[0] != "V/U" {
+		t.Errorf("unexpected SO-50 modes %v", so50.Modes)
+	}
+	if stats[1].Name != "" || stats[2].Name != "QO-100" {
+		t.Errorf("unexpected order %q, %q", stats[1].Name, stats[2].Name)
+	}
+}
@@ -0,0 +1,185 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"sort"
+	"strconv"
+	"time"
+)
+
+// spotFeedLimit caps how many spots each feed contributes to a merge
+const spotFeedLimit = 50
+
+// SpotSource identifies the spotting network a spot came from
+type SpotSource string
+
+const (
+	SpotSourceDXCluster   SpotSource = "dxcluster"
+	SpotSourceRBN         SpotSource = "rbn"
+	SpotSourcePSKReporter SpotSource = "pskreporter"
+)
+
+// Name returns the display name of the spotting network
+func (s SpotSource) Name() string {
+	switch s {
+	case SpotSourceDXCluster:
+		return "DX Cluster"
+	case SpotSourceRBN:
+		return "RBN"
+	case SpotSourcePSKReporter:
+		return "PSK Reporter"
+	}
+	return string(s)
+}
+
+// Spot is a normalized report of my signal from any spotting network
+type Spot struct {
+	Source    SpotSource
+	Spotter   string
+	Locator   string  // spotter grid, when the network reports one
+	Frequency float64 // kHz
+	Mode      string
+	SNR       int
+	HasSNR    bool
+	Comment   string
+	Time      time.Time
+}
+
+// FormatFrequency formats the spot frequency in kHz
+func (s Spot) FormatFrequency() string {
+	if s.Frequency == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(s.Frequency, 'f', 1, 64)
+}
+
+// FormatTime formats the spot time for display (HH:MM UTC)
+func (s Spot) FormatTime() string {
+	return s.Time.UTC().Format("15:04 UTC")
+}
+
+// Band returns the band the spot frequency is in
+func (s Spot) Band() string {
+	return spotBand(s.Frequency)
+}
+
+// spotBand returns the band of a spot frequency in kHz
+func spotBand(kHz float64) string {
+	return BandFromFrequency(kHz / 1000)
+}
+
+// Report returns the signal report and comment for display
+func (s Spot) Report() string {
+	if !s.HasSNR {
+		return s.Comment
+	}
+	report := strconv.Itoa(s.SNR) + " dB"
+	if s.Comment != "" {
+		report += ", " + s.Comment
+	}
+	return report
+}
+
+// SpotFeed is a spotting network client that can report recent spots of me
+type SpotFeed interface {
+	Spots() []Spot
+}
+
+// Spots returns the cached reception reports as normalized spots
+func (p *PSKReporter) Spots() []Spot {
+	var spots []Spot
+	for _, r := range p.Recent(spotFeedLimit) {
+		spots = append(spots, Spot{
+			Source:    SpotSourcePSKReporter,
+			Spotter:   r.ReceiverCall,
+			Locator:   r.ReceiverLocator,
+			Frequency: float64(r.Frequency) / 1000,
+			Mode:      r.Mode,
+			SNR:       r.SNR,
+			HasSNR:    true,
+			Time:      r.Time,
+		})
+	}
+	return spots
+}
+
+// Spots returns the cached skimmer spots as normalized spots
+func (r *RBN) Spots() []Spot {
+	var spots []Spot
+	for _, s := range r.Recent(spotFeedLimit) {
+		spots = append(spots, Spot{
+			Source:    SpotSourceRBN,
+			Spotter:   s.Skimmer,
+			Frequency: s.Frequency,
+			Mode:      s.Mode,
+			SNR:       s.SNR,
+			HasSNR:    true,
+			Comment:   s.FormatSpeed(),
+			Time:      s.Time,
+		})
+	}
+	return spots
+}
+
+// Spots returns the cached cluster spots as normalized spots
+func (d *DXCluster) Spots() []Spot {
+	var spots []Spot
+	for _, s := range d.Recent(spotFeedLimit) {
+		spots = append(spots, Spot{
+			Source:    SpotSourceDXCluster,
+			Spotter:   s.Spotter,
+			Frequency: s.Frequency,
+			Comment:   s.Comment,
+			Time:      s.Time,
+		})
+	}
+	return spots
+}
+
+// RecentSpots merges the spots of every enabled spotting network
+type RecentSpots struct {
+	feeds []SpotFeed
+}
+
+// NewRecentSpots creates a merged view over the given feeds
+func NewRecentSpots(feeds ...SpotFeed) *RecentSpots {
+	return &RecentSpots{feeds: feeds}
+}
+
+// all returns the spots of every feed, newest first
+func (r *RecentSpots) all() []Spot {
+	if r == nil {
+		return nil
+	}
+
+	var spots []Spot
+	for _, feed := range r.feeds {
+		spots = append(spots, feed.Spots()...)
+	}
+	sort.SliceStable(spots, func(i, j int) bool {
+		return spots[i].Time.After(spots[j].Time)
+	})
+	return spots
+}
+
+// Recent returns up to limit of the most recent spots across all feeds
+func (r *RecentSpots) Recent(limit int) []Spot {
+	spots := r.all()
+	if len(spots) > limit {
+		spots = spots[:limit]
+	}
+	return spots
+}
+
+// SpotterCount returns the number of distinct stations spotting me across all
+// feeds
+func (r *RecentSpots) SpotterCount() int {
+	seen := make(map[string]bool)
+	for _, spot := range r.all() {
+		seen[spot.Spotter] = true
+	}
+	return len(seen)
+}
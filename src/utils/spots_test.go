@@ -0,0 +1,69 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecentSpotsMergesFeeds(t *testing.T) {
+	now := time.Now().UTC()
+
+	psk := NewPSKReporter("A66H", time.Hour)
+	psk.reports = []PSKReception{
+		{ReceiverCall: "DL1ABC", ReceiverLocator: "JO31", Frequency: 14074500, Mode: "FT8", SNR: -12, Time: now.Add(-5 * time.Minute)},
+	}
+
+	rbn := NewRBN(RBNTelnetAddr, "A66H")
+	rbn.spots = []RBNSpot{
+		{Skimmer: "KM3T", Spotted: "A66H", Frequency: 14025.0, Mode: "CW", SNR: 24, Speed: 22, SpeedUnit: "WPM", Time: now.Add(-time.Minute)},
+	}
+
+	dx := NewDXCluster("localhost:7300", "A66H", []string{"A66H"})
+	dx.spots = []DXSpot{
+		{Spotter: "DL1ABC", Spotted: "A66H", Frequency: 7010.0, Comment: "TNX QSO", Time: now.Add(-10 * time.Minute)},
+	}
+
+	var disabled *RBN
+	spots := NewRecentSpots(psk, rbn, dx, disabled)
+
+	recent := spots.Recent(10)
+	if len(recent) != 3 {
+		t.Fatalf("Expected 3 merged spots, got %d", len(recent))
+	}
+
+	wantSources := []SpotSource{SpotSourceRBN, SpotSourcePSKReporter, SpotSourceDXCluster}
+	for i, want := range wantSources {
+		if recent[i].Source != want {
+			t.Errorf("Expected spot %d from %s, got %s", i, want, recent[i].Source)
+		}
+	}
+
+	if got := recent[0].Report(); got != "24 dB, 22 WPM" {
+		t.Errorf("Expected RBN report \"24 dB, 22 WPM\", got %q", got)
+	}
+	if got := recent[1].FormatFrequency(); got != "14074.5" {
+		t.Errorf("Expected PSK Reporter frequency in kHz, got %s", got)
+	}
+	if got := recent[1].Band(); got != "20m" {
+		t.Errorf("Expected PSK Reporter spot on 20m, got %s", got)
+	}
+	if got := recent[2].Report(); got != "TNX QSO" {
+		t.Errorf("Expected DX cluster comment as the report, got %q", got)
+	}
+
+	if got := spots.Recent(1); len(got) != 1 || got[0].Source != SpotSourceRBN {
+		t.Errorf("Expected limit to keep the newest spot, got %v", got)
+	}
+	if got := spots.SpotterCount(); got != 2 {
+		t.Errorf("Expected 2 distinct spotters, got %d", got)
+	}
+
+	var none *RecentSpots
+	if none.Recent(10) != nil || none.SpotterCount() != 0 {
+		t.Error("Expected nil RecentSpots to report nothing")
+	}
+}
@@ -0,0 +1,239 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3" // Registers the sqlite3 driver
+)
+
+// sqliteSchema keeps each QSO as JSON beside the columns lookups filter on.
+// Times are Unix seconds, NULL for QSOs without a timestamp.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS qsos (
+	seq       INTEGER PRIMARY KEY,
+	call      TEXT NOT NULL,
+	qso_time  INTEGER,
+	entity    TEXT NOT NULL,
+	paper_qsl INTEGER NOT NULL,
+	record    TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS qsos_call_time ON qsos (call, qso_time);
+CREATE INDEX IF NOT EXISTS qsos_time ON qsos (qso_time);
+`
+
+// SQLiteStore is a QSOStore kept in an SQLite database. It mirrors the
+// published log through Replace, narrows each lookup with an indexed query
+// and leaves the matching rules to ADIFParser, so both stores answer alike.
+type SQLiteStore struct {
+	db *sql.DB
+
+	// Reloader rereads the log the store mirrors, if set
+	Reloader func() error
+}
+
+var _ QSOStore = (*SQLiteStore)(nil)
+
+// OpenSQLiteStore opens or creates the SQLite database at path
+func OpenSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite3", "file:"+path+"?_busy_timeout=5000&_journal_mode=WAL")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create SQLite schema: %w", err)
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+// Close closes the database
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// Replace swaps the stored QSOs for qsos in one transaction, so lookups see
+// either the old log or the new one
+func (s *SQLiteStore) Replace(qsos []QSO) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to replace QSOs: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM qsos"); err != nil {
+		return fmt.Errorf("failed to replace QSOs: %w", err)
+	}
+	stmt, err := tx.Prepare("INSERT INTO qsos (seq, call, qso_time, entity, paper_qsl, record) VALUES (?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return fmt.Errorf("failed to replace QSOs: %w", err)
+	}
+	defer stmt.Close()
+
+	for i, qso := range qsos {
+		record, err := json.Marshal(qso)
+		if err != nil {
+			return fmt.Errorf("failed to encode QSO with %s: %w", qso.Call, err)
+		}
+		var at sql.NullInt64
+		if !qso.Timestamp.IsZero() {
+			at = sql.NullInt64{Int64: qso.Timestamp.Unix(), Valid: true}
+		}
+		if _, err := stmt.Exec(i, qso.Call, at, qso.Entity(), qso.QslRcvd.Confirmed(), record); err != nil {
+			return fmt.Errorf("failed to store QSO with %s: %w", qso.Call, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to replace QSOs: %w", err)
+	}
+	return nil
+}
+
+// query returns the QSOs a query selects from the record column, in log
+// order unless the query orders them. Lookups can't return errors, so a
+// failed query is logged and finds nothing.
+func (s *SQLiteStore) query(query string, args ...any) []QSO {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		log.Printf("SQLite lookup failed: %v", err)
+		return nil
+	}
+	defer rows.Close()
+
+	var qsos []QSO
+	for rows.Next() {
+		var record []byte
+		var qso QSO
+		if err := rows.Scan(&record); err != nil {
+			log.Printf("SQLite lookup failed: %v", err)
+			return nil
+		}
+		if err := json.Unmarshal(record, &qso); err != nil {
+			log.Printf("Failed to decode stored QSO: %v", err)
+			return nil
+		}
+		qsos = append(qsos, qso)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("SQLite lookup failed: %v", err)
+		return nil
+	}
+	return qsos
+}
+
+// count returns the number a query selects
+func (s *SQLiteStore) count(query string) int {
+	var n int
+	if err := s.db.QueryRow(query).Scan(&n); err != nil {
+		log.Printf("SQLite lookup failed: %v", err)
+		return 0
+	}
+	return n
+}
+
+// narrowed wraps the QSOs found by a narrowing query, to apply the full
+// lookup to them
+func narrowed(qsos []QSO) *ADIFParser {
+	return &ADIFParser{QSOs: qsos}
+}
+
+// normalizeCall matches ADIFParser's handling of searched call signs
+func normalizeCall(call string) string {
+	return strings.ToUpper(strings.TrimSpace(call))
+}
+
+// SearchQSO returns the QSO with a callsign closest to a time, within
+// toleranceMinutes
+func (s *SQLiteStore) SearchQSO(call string, at time.Time, toleranceMinutes int) []QSO {
+	tolerance := int64(toleranceMinutes) * 60
+	qsos := s.query("SELECT record FROM qsos WHERE call = ? AND qso_time BETWEEN ? AND ? ORDER BY seq",
+		normalizeCall(call), at.Unix()-tolerance-1, at.Unix()+tolerance+1)
+	return narrowed(qsos).SearchQSO(call, at, toleranceMinutes)
+}
+
+// MatchQSO returns the QSOs with a callsign logged in the minute of a time
+func (s *SQLiteStore) MatchQSO(call string, at time.Time, band, mode string) []QSO {
+	minute := at.Truncate(time.Minute).Unix()
+	qsos := s.query("SELECT record FROM qsos WHERE call = ? AND qso_time BETWEEN ? AND ? ORDER BY seq",
+		normalizeCall(call), minute, minute+59)
+	return narrowed(qsos).MatchQSO(call, at, band, mode)
+}
+
+// SuggestQSOs returns near misses for a search that found nothing. Only call
+// signs within the suggestion edit distance in length can qualify.
+func (s *SQLiteStore) SuggestQSOs(call string, at time.Time, toleranceMinutes, limit int) []QSO {
+	n := len(normalizeCall(call))
+	qsos := s.query("SELECT record FROM qsos WHERE qso_time IS NOT NULL AND length(call) BETWEEN ? AND ? ORDER BY seq",
+		n-maxSuggestDistance, n+maxSuggestDistance)
+	return narrowed(qsos).SuggestQSOs(call, at, toleranceMinutes, limit)
+}
+
+// GetQSOsByCallsign returns all QSOs with a callsign
+func (s *SQLiteStore) GetQSOsByCallsign(call string) []QSO {
+	return s.query("SELECT record FROM qsos WHERE call = ? ORDER BY seq", normalizeCall(call))
+}
+
+// GetCallsignTimeline returns the QSOs with a callsign as a timeline
+func (s *SQLiteStore) GetCallsignTimeline(call string) []TimelineEntry {
+	return narrowed(s.GetQSOsByCallsign(call)).GetCallsignTimeline(call)
+}
+
+// GetQSOs returns every QSO in log order
+func (s *SQLiteStore) GetQSOs() []QSO {
+	return s.query("SELECT record FROM qsos ORDER BY seq")
+}
+
+// GetLatestQSOs returns the most recent QSOs, newest first, with QSOs
+// logged at the same time in log order and those without a time last
+func (s *SQLiteStore) GetLatestQSOs(limit int) []QSO {
+	if limit <= 0 {
+		return []QSO{}
+	}
+	qsos := s.query("SELECT record FROM qsos ORDER BY qso_time IS NULL, qso_time DESC, seq LIMIT ?", limit)
+	if qsos == nil {
+		return []QSO{}
+	}
+	return qsos
+}
+
+// GetLatestQSO returns the most recent QSO, or nil if none has a time
+func (s *SQLiteStore) GetLatestQSO() *QSO {
+	qsos := s.query("SELECT record FROM qsos WHERE qso_time IS NOT NULL ORDER BY qso_time DESC, seq LIMIT 1")
+	if len(qsos) == 0 {
+		return nil
+	}
+	return &qsos[0]
+}
+
+// GetTotalQSOCount returns the number of QSOs
+func (s *SQLiteStore) GetTotalQSOCount() int {
+	return s.count("SELECT COUNT(*) FROM qsos")
+}
+
+// GetUniqueCountriesCount returns the number of DXCC entities worked
+func (s *SQLiteStore) GetUniqueCountriesCount() int {
+	return s.count("SELECT COUNT(DISTINCT entity) FROM qsos WHERE entity != ''")
+}
+
+// GetPaperQSLHallOfFame returns the QSOs confirmed with paper cards
+func (s *SQLiteStore) GetPaperQSLHallOfFame() []QSO {
+	return narrowed(s.query("SELECT record FROM qsos WHERE paper_qsl ORDER BY seq")).GetPaperQSLHallOfFame()
+}
+
+// Reload rereads the log through the Reloader, which republishes it here.
+// Without one there is nothing to reread.
+func (s *SQLiteStore) Reload() error {
+	if s.Reloader == nil {
+		return nil
+	}
+	return s.Reloader()
+}
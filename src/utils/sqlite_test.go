@@ -0,0 +1,105 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func openTestSQLiteStore(t *testing.T) *SQLiteStore {
+	t.Helper()
+	store, err := OpenSQLiteStore(filepath.Join(t.TempDir(), "qsos.db"))
+	if err != nil {
+		t.Fatalf("OpenSQLiteStore failed: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+// TestSQLiteStoreMatchesParser checks that the SQLite store answers every
+// lookup the way the in-memory parser does for the same log
+func TestSQLiteStoreMatchesParser(t *testing.T) {
+	at := func(h, m int) time.Time { return time.Date(2025, 3, 1, h, m, 0, 0, time.UTC) }
+	parser := &ADIFParser{QSOs: []QSO{
+		{Call: "A61XX", Timestamp: at(10, 0), Band: "20m", Mode: "SSB", Country: "United Arab Emirates", DXCC: "391", QslRcvd: QslYes, Name: "Ali"},
+		{Call: "A61XX", Timestamp: at(10, 20), Band: "40m", Mode: "CW", Country: "United Arab Emirates", QslRcvd: QslYes},
+		{Call: "A61XY", Timestamp: at(10, 5), Band: "20m", Mode: "FT8", Country: "United Arab Emirates"},
+		{Call: "W1AW", Timestamp: at(12, 0), Band: "20m", Mode: "SSB", Country: "United States", DXCC: "291", QslRcvd: QslVerified},
+		{Call: "W1AW", Timestamp: at(12, 0), Band: "15m", Mode: "SSB", Country: "United States"},
+		{Call: "JA1XX", Country: "Japan"},
+		{Call: "DL1XX", Timestamp: at(9, 0), Band: "10m", Mode: "FT8", Country: "Fed. Rep. of Germany", QslRcvd: QslQueued},
+	}}
+	store := openTestSQLiteStore(t)
+	if err := store.Replace(parser.QSOs); err != nil {
+		t.Fatalf("Replace failed: %v", err)
+	}
+
+	check := func(name string, got, want any) {
+		t.Helper()
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s = %+v, want %+v", name, got, want)
+		}
+	}
+	for _, call := range []string{"A61XX", " a61xx ", "W1AW", "JA1XX", "N0CALL"} {
+		check("SearchQSO("+call+")", store.SearchQSO(call, at(10, 12), 15), parser.SearchQSO(call, at(10, 12), 15))
+		check("MatchQSO("+call+")", store.MatchQSO(call, at(12, 0).Add(30*time.Second), "20M", ""), parser.MatchQSO(call, at(12, 0).Add(30*time.Second), "20M", ""))
+		check("SuggestQSOs("+call+")", store.SuggestQSOs(call, at(10, 3), 15, 5), parser.SuggestQSOs(call, at(10, 3), 15, 5))
+		check("GetQSOsByCallsign("+call+")", store.GetQSOsByCallsign(call), parser.GetQSOsByCallsign(call))
+		check("GetCallsignTimeline("+call+")", store.GetCallsignTimeline(call), parser.GetCallsignTimeline(call))
+	}
+	check("GetQSOs", store.GetQSOs(), parser.GetQSOs())
+	for _, limit := range []int{0, 1, 3, 10} {
+		check("GetLatestQSOs", store.GetLatestQSOs(limit), parser.GetLatestQSOs(limit))
+	}
+	check("GetLatestQSO", store.GetLatestQSO(), parser.GetLatestQSO())
+	check("GetTotalQSOCount", store.GetTotalQSOCount(), parser.GetTotalQSOCount())
+	check("GetUniqueCountriesCount", store.GetUniqueCountriesCount(), parser.GetUniqueCountriesCount())
+	check("GetPaperQSLHallOfFame", store.GetPaperQSLHallOfFame(), parser.GetPaperQSLHallOfFame())
+}
+
+func TestSQLiteStoreReplace(t *testing.T) {
+	store := openTestSQLiteStore(t)
+	at := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	if err := store.Replace([]QSO{{Call: "A61XX", Timestamp: at}, {Call: "W1AW", Timestamp: at}}); err != nil {
+		t.Fatalf("Replace failed: %v", err)
+	}
+	if err := store.Replace([]QSO{{Call: "W1AW", Timestamp: at}}); err != nil {
+		t.Fatalf("Replace failed: %v", err)
+	}
+
+	if got := store.GetTotalQSOCount(); got != 1 {
+		t.Errorf("Expected the second log to replace the first, got %d QSOs", got)
+	}
+	if got := store.GetQSOsByCallsign("A61XX"); len(got) != 0 {
+		t.Errorf("Expected QSOs missing from the new log to be gone, got %v", got)
+	}
+
+	if err := store.Replace(nil); err != nil {
+		t.Fatalf("Replace failed: %v", err)
+	}
+	if got := store.GetLatestQSO(); got != nil {
+		t.Errorf("Expected no latest QSO in an empty store, got %v", got)
+	}
+	if got := store.GetLatestQSOs(5); got == nil || len(got) != 0 {
+		t.Errorf("Expected an empty list from an empty store, got %#v", got)
+	}
+}
+
+func TestSQLiteStoreReload(t *testing.T) {
+	store := openTestSQLiteStore(t)
+	if err := store.Reload(); err != nil {
+		t.Fatalf("Reload() without a reloader = %v, want nil", err)
+	}
+
+	failed := errors.New("log missing")
+	store.Reloader = func() error { return failed }
+	if err := store.Reload(); !errors.Is(err, failed) {
+		t.Errorf("Reload() = %v, want %v", err, failed)
+	}
+}
@@ -0,0 +1,41 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+// StationDefaults are my station details used for QSOs logged without them,
+// such as older log entries. A nil StationDefaults fills in nothing.
+type StationDefaults struct {
+	MyGridSquare string
+	MyRig        string
+	MyAntenna    string
+	TxPwr        string // Watts
+}
+
+// Apply returns the QSOs with missing station details filled in from the
+// defaults. The home grid isn't used for portable and mobile QSOs, which
+// were made from somewhere else. The input slice is not modified.
+func (d *StationDefaults) Apply(qsos []QSO) []QSO {
+	if d == nil {
+		return qsos
+	}
+
+	filled := make([]QSO, len(qsos))
+	for i, qso := range qsos {
+		if qso.MyGridSquare == "" && !IsPortable(qso.StationCall) {
+			qso.MyGridSquare = d.MyGridSquare
+		}
+		if qso.MyRig == "" {
+			qso.MyRig = d.MyRig
+		}
+		if qso.MyAntenna == "" {
+			qso.MyAntenna = d.MyAntenna
+		}
+		if qso.TxPwr == "" {
+			qso.TxPwr = d.TxPwr
+		}
+		filled[i] = qso
+	}
+	return filled
+}
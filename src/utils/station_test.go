@@ -0,0 +1,35 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import "testing"
+
+func TestStationDefaults(t *testing.T) {
+	d := &StationDefaults{MyGridSquare: "LL75rb", MyRig: "IC-7300", MyAntenna: "Hexbeam", TxPwr: "100"}
+	qsos := []QSO{
+		{Call: "W1AW"},
+		{Call: "K1ABC", MyGridSquare: "LL65", MyRig: "FT-817", TxPwr: "5"},
+		{Call: "DL1ABC", StationCall: "A66H/P"},
+	}
+
+	filled := d.Apply(qsos)
+	if got := filled[0]; got.MyGridSquare != "LL75rb" || got.MyRig != "IC-7300" || got.MyAntenna != "Hexbeam" || got.TxPwr != "100" {
+		t.Errorf("Expected the defaults to be filled in, got %+v", got)
+	}
+	if got := filled[1]; got.MyGridSquare != "LL65" || got.MyRig != "FT-817" || got.MyAntenna != "Hexbeam" || got.TxPwr != "5" {
+		t.Errorf("Expected logged details to be kept, got %+v", got)
+	}
+	if got := filled[2]; got.MyGridSquare != "" || got.MyRig != "IC-7300" {
+		t.Errorf("Expected a portable QSO to get no home grid, got %+v", got)
+	}
+	if qsos[0].MyRig != "" {
+		t.Error("Apply modified its input")
+	}
+
+	var none *StationDefaults
+	if got := none.Apply(qsos); got[0].MyRig != "" {
+		t.Error("Expected nil defaults to fill in nothing")
+	}
+}
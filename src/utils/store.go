@@ -0,0 +1,43 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import "time"
+
+// QSOStore is where the handlers look up logged QSOs. The in-memory
+// ADIFParser is one, and SQLiteStore can be served in its place.
+type QSOStore interface {
+	// SearchQSO returns the QSOs with a callsign within toleranceMinutes of
+	// a time
+	SearchQSO(call string, at time.Time, toleranceMinutes int) []QSO
+	// MatchQSO returns the QSOs with a callsign on a band and mode around a
+	// time, as reported by another station's log
+	MatchQSO(call string, at time.Time, band, mode string) []QSO
+	// SuggestQSOs returns QSOs close to a search that found nothing
+	SuggestQSOs(call string, at time.Time, toleranceMinutes, limit int) []QSO
+	// GetQSOsByCallsign returns all QSOs with a callsign
+	GetQSOsByCallsign(call string) []QSO
+	// GetCallsignTimeline returns the QSOs with a callsign, oldest first,
+	// with firsts and QSL events marked
+	GetCallsignTimeline(call string) []TimelineEntry
+
+	// GetQSOs returns every QSO, for aggregates such as awards and stats
+	GetQSOs() []QSO
+	// GetLatestQSOs returns the most recent QSOs, newest first
+	GetLatestQSOs(limit int) []QSO
+	// GetLatestQSO returns the most recent QSO, or nil if there are none
+	GetLatestQSO() *QSO
+	// GetTotalQSOCount returns the number of QSOs
+	GetTotalQSOCount() int
+	// GetUniqueCountriesCount returns the number of countries worked
+	GetUniqueCountriesCount() int
+	// GetPaperQSLHallOfFame returns the QSOs confirmed with paper cards
+	GetPaperQSLHallOfFame() []QSO
+
+	// Reload rereads the QSOs from the backing log
+	Reload() error
+}
+
+var _ QSOStore = (*ADIFParser)(nil)
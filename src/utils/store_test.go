@@ -0,0 +1,30 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestADIFParserReload(t *testing.T) {
+	var store QSOStore = NewADIFParser()
+	if err := store.Reload(); err != nil {
+		t.Fatalf("Reload() without a reloader = %v, want nil", err)
+	}
+
+	calls := 0
+	failed := errors.New("log missing")
+	store = &ADIFParser{Reloader: func() error {
+		calls++
+		return failed
+	}}
+	if err := store.Reload(); !errors.Is(err, failed) {
+		t.Errorf("Reload() = %v, want %v", err, failed)
+	}
+	if calls != 1 {
+		t.Errorf("reloader called %d times, want 1", calls)
+	}
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxSuggestDistance is the largest edit distance between call signs for a
+// QSO to be suggested
+const maxSuggestDistance = 2
+
+// SuggestQSOs returns up to limit QSOs that are near misses for a search that
+// found nothing: similar call signs around the searched time first, then the
+// same call sign at other times, then similar call signs at other times
+func (p *ADIFParser) SuggestQSOs(callSign string, searchTime time.Time, toleranceMinutes int, limit int) []QSO {
+	callSign = strings.ToUpper(strings.TrimSpace(callSign))
+	if callSign == "" || limit <= 0 {
+		return nil
+	}
+	tolerance := time.Duration(toleranceMinutes) * time.Minute
+
+	type candidate struct {
+		qso      QSO
+		tier     int
+		distance int
+		timeDiff time.Duration
+	}
+	var candidates []candidate
+	for _, qso := range p.QSOs {
+		if qso.Timestamp.IsZero() {
+			continue
+		}
+		distance := editDistance(qso.Call, callSign, maxSuggestDistance)
+		if distance > maxSuggestDistance {
+			continue
+		}
+		timeDiff := qso.Timestamp.Sub(searchTime)
+		if timeDiff < 0 {
+			timeDiff = -timeDiff
+		}
+
+		var tier int
+		switch {
+		case distance == 0 && timeDiff <= tolerance:
+			// An exact match would have been found by SearchQSO
+			continue
+		case timeDiff <= tolerance:
+			tier = 0
+		case distance == 0:
+			tier = 1
+		default:
+			tier = 2
+		}
+		candidates = append(candidates, candidate{qso, tier, distance, timeDiff})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.tier != b.tier {
+			return a.tier < b.tier
+		}
+		if a.timeDiff != b.timeDiff {
+			return a.timeDiff < b.timeDiff
+		}
+		return a.distance < b.distance
+	})
+
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	suggestions := make([]QSO, len(candidates))
+	for i, c := range candidates {
+		suggestions[i] = c.qso
+	}
+	return suggestions
+}
+
+// editDistance returns the Levenshtein distance between a and b, or max+1
+// once the distance is known to be larger than max
+func editDistance(a, b string, max int) int {
+	if diff := len(a) - len(b); diff > max || -diff > max {
+		return max + 1
+	}
+
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > max {
+			return max + 1
+		}
+		prev, curr = curr, prev
+	}
+	if prev[len(b)] > max {
+		return max + 1
+	}
+	return prev[len(b)]
+}
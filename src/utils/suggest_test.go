@@ -0,0 +1,66 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"A66H", "A66H", 0},
+		{"A66H", "A66G", 1},
+		{"A66H", "A6H", 1},
+		{"A66H", "A66HH", 1},
+		{"A62A", "A26A", 2},
+		{"A66H", "W1AW", 3},
+		{"A66H", "VERYLONGCALL", 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b, 2); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSuggestQSOs(t *testing.T) {
+	at := time.Date(2024, time.May, 1, 13, 5, 0, 0, time.UTC)
+	p := &ADIFParser{QSOs: []QSO{
+		{Call: "A62A", Timestamp: at.Add(-30 * 24 * time.Hour)},
+		{Call: "A62B", Timestamp: at.Add(2 * time.Minute)},
+		{Call: "A62A", Timestamp: at.Add(3 * time.Hour)},
+		{Call: "A26B", Timestamp: at.Add(time.Hour)},
+		{Call: "W1AW", Timestamp: at},
+	}}
+
+	got := p.SuggestQSOs("a62a", at, 10, 10)
+	want := []struct {
+		call string
+		at   time.Time
+	}{
+		{"A62B", at.Add(2 * time.Minute)},
+		{"A62A", at.Add(3 * time.Hour)},
+		{"A62A", at.Add(-30 * 24 * time.Hour)},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("SuggestQSOs returned %d suggestions, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if got[i].Call != w.call || !got[i].Timestamp.Equal(w.at) {
+			t.Errorf("suggestion %d = %s %v, want %s %v", i, got[i].Call, got[i].Timestamp, w.call, w.at)
+		}
+	}
+
+	if got := p.SuggestQSOs("A62A", at, 10, 1); len(got) != 1 || got[0].Call != "A62B" {
+		t.Errorf("SuggestQSOs with limit 1 = %+v, want only A62B", got)
+	}
+	if got := p.SuggestQSOs("ZZ9ZZZ", at, 10, 5); len(got) != 0 {
+		t.Errorf("SuggestQSOs for an unknown call = %+v, want none", got)
+	}
+}
@@ -0,0 +1,25 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+// ParseComplete parses the complete records in data, a part of an ADIF file
+// after its header such as the bytes appended since it was last read. It
+// returns how many bytes were consumed; a last record still being written,
+// without its <eor>, is left for the next call.
+func (p *ADIFParser) ParseComplete(data []byte) (int, error) {
+	content := string(data)
+	end := 0
+	for {
+		_, tagEnd := findEndTag(content[end:], "eor")
+		if tagEnd == -1 {
+			break
+		}
+		end += tagEnd
+	}
+	if end == 0 {
+		return 0, nil
+	}
+	return end, p.parseContent(content[:end])
+}
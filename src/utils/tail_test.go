@@ -0,0 +1,31 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import "testing"
+
+func TestParseComplete(t *testing.T) {
+	data := []byte("<CALL:4>W1AW<QSO_DATE:8>20250301<TIME_ON:4>1200<EOR>\n<call:6>DL1ABC<qso_date:8>20250301<time_on:4>1205<eor>\n<CALL:5>A61AA<QSO_DA")
+
+	p := NewADIFParser()
+	n, err := p.ParseComplete(data)
+	if err != nil {
+		t.Fatalf("ParseComplete: %v", err)
+	}
+	if want := len(data) - len("\n<CALL:5>A61AA<QSO_DA"); n != want {
+		t.Errorf("consumed %d bytes, want %d", n, want)
+	}
+	if len(p.QSOs) != 2 || p.QSOs[1].Call != "DL1ABC" {
+		t.Errorf("unexpected QSOs %+v", p.QSOs)
+	}
+
+	if n, _ := NewADIFParser().ParseComplete([]byte("<CALL:4>W1AW")); n != 0 {
+		t.Errorf("consumed %d bytes of an incomplete record", n)
+	}
+	// A comment still being written that mentions <eor> doesn't end the record
+	if n, _ := NewADIFParser().ParseComplete([]byte("<CALL:4>W1AW<COMMENT:20>Bye <eor>")); n != 0 {
+		t.Errorf("consumed %d bytes of a record with an incomplete comment", n)
+	}
+}
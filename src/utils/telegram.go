@@ -0,0 +1,128 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const telegramAPIURL = "https://api.telegram.org"
+
+// TelegramNotifier sends event notifications to a Telegram chat via a bot
+type TelegramNotifier struct {
+	apiURL string
+	token  string
+	chatID string
+	events map[EventType]bool
+	client *http.Client
+}
+
+// NewTelegramNotifier creates a notifier for the given bot token and chat,
+// sending only the listed event types
+func NewTelegramNotifier(token, chatID string, events []string) *TelegramNotifier {
+	filter := make(map[EventType]bool)
+	for _, e := range events {
+		filter[EventType(strings.TrimSpace(e))] = true
+	}
+
+	return &TelegramNotifier{
+		apiURL: telegramAPIURL,
+		token:  token,
+		chatID: chatID,
+		events: filter,
+		client: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Handle formats and sends a notification for an event, if it passes the
+// event filter. It is meant to be subscribed to an EventBus.
+func (t *TelegramNotifier) Handle(e Event) {
+	if !t.events[e.Type] {
+		return
+	}
+
+	text := FormatEventMessage(e)
+	if text == "" {
+		return
+	}
+
+	if err := t.Send(text); err != nil {
+		log.Printf("Failed to send Telegram notification: %v", err)
+	}
+}
+
+// Send sends a plain text message to the configured chat
+func (t *TelegramNotifier) Send(text string) error {
+	endpoint := fmt.Sprintf("%s/bot%s/sendMessage", t.apiURL, t.token)
+	resp, err := t.client.PostForm(endpoint, url.Values{
+		"chat_id":                  {t.chatID},
+		"text":                     {text},
+		"disable_web_page_preview": {"true"},
+	})
+	if err != nil {
+		// Avoid leaking the bot token, which is part of the URL
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to reach Telegram: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("telegram returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// FormatEventMessage renders an event as a short human-readable message
+func FormatEventMessage(e Event) string {
+	switch e.Type {
+	case EventLookup:
+		if e.Found && len(e.QSOs) > 0 {
+			qso := e.QSOs[0]
+			return fmt.Sprintf("QSO lookup: %s found (%s, %s %s)",
+				e.Callsign, qso.FormatQSOTime(), qso.Band, qso.Mode)
+		}
+		return fmt.Sprintf("QSO lookup: %s around %s UTC not found",
+			e.Callsign, e.SearchTime.UTC().Format("2006-01-02 15:04"))
+
+	case EventNewQSOs:
+		if len(e.QSOs) == 0 {
+			return ""
+		}
+		if len(e.QSOs) > 5 {
+			return fmt.Sprintf("%d new QSOs logged", len(e.QSOs))
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "%d new QSO", len(e.QSOs))
+		if len(e.QSOs) > 1 {
+			b.WriteString("s")
+		}
+		b.WriteString(" logged:")
+		for _, qso := range e.QSOs {
+			fmt.Fprintf(&b, "\n%s %s %s (%s)", qso.Call, qso.Band, qso.Mode, qso.FormatQSOTime())
+		}
+		return b.String()
+
+	case EventReload:
+		return fmt.Sprintf("Log reloaded with %d QSOs", e.Count)
+
+	case EventQSLRequest:
+		if len(e.QSOs) == 0 {
+			return fmt.Sprintf("Paper QSL requested by %s via %s", e.Callsign, e.QSLRoute)
+		}
+		qso := e.QSOs[0]
+		return fmt.Sprintf("Paper QSL requested by %s via %s (%s, %s %s)",
+			e.Callsign, e.QSLRoute, qso.FormatQSOTime(), qso.Band, qso.Mode)
+	}
+
+	return ""
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestTelegramNotifier(t *testing.T) {
+	var requests []*http.Request
+	var forms []url.Values
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm: %v", err)
+		}
+		requests = append(requests, r)
+		forms = append(forms, r.PostForm)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	n := NewTelegramNotifier("123:abc", "-1001", []string{"reload", " lookup "})
+	n.apiURL = srv.URL
+
+	if err := n.Send("73 de A66H"); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(requests) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(requests))
+	}
+	if r := requests[0]; r.Method != http.MethodPost || r.URL.Path != "/bot123:abc/sendMessage" {
+		t.Errorf("Expected POST /bot123:abc/sendMessage, got %s %s", r.Method, r.URL.Path)
+	}
+	if got := forms[0].Get("chat_id"); got != "-1001" {
+		t.Errorf("Expected chat_id -1001, got %q", got)
+	}
+	if got := forms[0].Get("text"); got != "73 de A66H" {
+		t.Errorf("Expected text %q, got %q", "73 de A66H", got)
+	}
+
+	// Only the listed events are sent
+	n.Handle(Event{Type: EventNewQSOs, QSOs: []QSO{{Call: "W1AW"}}})
+	n.Handle(Event{Type: EventReload, Count: 42})
+	if len(requests) != 2 {
+		t.Fatalf("Expected only the reload event to be sent, got %d requests", len(requests))
+	}
+	if got, want := forms[1].Get("text"), "Log reloaded with 42 QSOs"; got != want {
+		t.Errorf("Expected text %q, got %q", want, got)
+	}
+
+	status = http.StatusUnauthorized
+	if err := n.Send("73"); err == nil {
+		t.Error("Expected a non-2xx response to be an error")
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// QSL milestones in a callsign timeline
+const (
+	MilestoneCardSent     = "sent"
+	MilestoneCardReceived = "received"
+	MilestoneLotw         = "lotw"
+)
+
+// TimelineMilestone is a QSL event for a QSO
+type TimelineMilestone struct {
+	Kind string
+	Date time.Time // Zero when the log doesn't record the date
+}
+
+// TimelineEntry is a QSO in a callsign's timeline, marked with whether it
+// was the first with the station on its band or in its mode
+type TimelineEntry struct {
+	QSO
+	NewBand    bool
+	NewMode    bool
+	Milestones []TimelineMilestone
+}
+
+// GetCallsignTimeline returns every QSO with a callsign, oldest first, with
+// the first QSO on each band and in each mode marked and the QSL cards sent
+// and received and LoTW matches listed under each QSO
+func (p *ADIFParser) GetCallsignTimeline(callSign string) []TimelineEntry {
+	qsos := p.GetQSOsByCallsign(callSign)
+	sort.SliceStable(qsos, func(i, j int) bool {
+		return qsos[i].Timestamp.Before(qsos[j].Timestamp)
+	})
+
+	bands := make(map[string]bool)
+	modes := make(map[string]bool)
+	timeline := make([]TimelineEntry, len(qsos))
+	for i, qso := range qsos {
+		band := strings.ToLower(qso.Band)
+		mode := strings.ToUpper(qso.Mode)
+		timeline[i] = TimelineEntry{
+			QSO:        qso,
+			NewBand:    band != "" && !bands[band],
+			NewMode:    mode != "" && !modes[mode],
+			Milestones: qso.milestones(),
+		}
+		bands[band] = true
+		modes[mode] = true
+	}
+	return timeline
+}
+
+// milestones returns the QSL events recorded for the QSO
+func (qso QSO) milestones() []TimelineMilestone {
+	var milestones []TimelineMilestone
+	for _, m := range []struct {
+		kind   string
+		status QslStatus
+		date   string
+	}{
+		{MilestoneCardSent, qso.QslSent, qso.QslSentDate},
+		{MilestoneCardReceived, qso.QslRcvd, qso.QslRcvdDate},
+		{MilestoneLotw, qso.LotwRcvd, qso.LotwRcvdDate},
+	} {
+		if !m.status.Confirmed() {
+			continue
+		}
+		date, _ := time.Parse("20060102", m.date)
+		milestones = append(milestones, TimelineMilestone{Kind: m.kind, Date: date})
+	}
+	return milestones
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetCallsignTimeline(t *testing.T) {
+	at := func(day int) time.Time { return time.Date(2025, 3, day, 12, 0, 0, 0, time.UTC) }
+	p := NewADIFParser()
+	p.QSOs = []QSO{
+		{Call: "W1AW", Band: "20m", Mode: "SSB", Timestamp: at(3), LotwRcvd: QslYes, LotwRcvdDate: "20250310"},
+		{Call: "W1AW", Band: "20m", Mode: "CW", Timestamp: at(1), QslSent: QslYes, QslSentDate: "20250302", QslRcvd: QslYes},
+		{Call: "K1ABC", Band: "40m", Mode: "CW", Timestamp: at(2)},
+		{Call: "W1AW", Band: "40M", Mode: "cw", Timestamp: at(2), QslSent: QslRequested},
+	}
+
+	timeline := p.GetCallsignTimeline("w1aw")
+	if len(timeline) != 3 {
+		t.Fatalf("Expected 3 QSOs, got %d", len(timeline))
+	}
+	for i, want := range []struct {
+		day              int
+		newBand, newMode bool
+		milestones       []string
+	}{
+		{1, true, true, []string{MilestoneCardSent, MilestoneCardReceived}},
+		{2, true, false, nil},
+		{3, false, true, []string{MilestoneLotw}},
+	} {
+		e := timeline[i]
+		if !e.Timestamp.Equal(at(want.day)) || e.NewBand != want.newBand || e.NewMode != want.newMode {
+			t.Errorf("Entry %d = %v new band %v new mode %v, want day %d %v %v", i, e.Timestamp, e.NewBand, e.NewMode, want.day, want.newBand, want.newMode)
+		}
+		if len(e.Milestones) != len(want.milestones) {
+			t.Errorf("Entry %d has milestones %+v, want %v", i, e.Milestones, want.milestones)
+			continue
+		}
+		for j, kind := range want.milestones {
+			if e.Milestones[j].Kind != kind {
+				t.Errorf("Entry %d milestone %d = %s, want %s", i, j, e.Milestones[j].Kind, kind)
+			}
+		}
+	}
+
+	if sent := timeline[0].Milestones[0].Date; !sent.Equal(time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the card sent date, got %v", sent)
+	}
+	if rcvd := timeline[0].Milestones[1].Date; !rcvd.IsZero() {
+		t.Errorf("Expected no date for a card received without QSLRDATE, got %v", rcvd)
+	}
+	if lotw := timeline[2].Milestones[0].Date; !lotw.Equal(time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the LoTW date, got %v", lotw)
+	}
+}
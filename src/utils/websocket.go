@@ -0,0 +1,236 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// websocketGUID is appended to the client's key to prove the server speaks
+// the WebSocket protocol (RFC 6455 section 1.3)
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// maxWebSocketFrame limits frames read from clients, which only ever send
+// pings and close frames to a push-only socket
+const maxWebSocketFrame = 4096
+
+// WebSocket opcodes
+const (
+	wsText  = 0x1
+	wsClose = 0x8
+	wsPing  = 0x9
+	wsPong  = 0xa
+)
+
+// errWebSocketClosed is returned when writing to a closed WebSocket
+var errWebSocketClosed = errors.New("websocket closed")
+
+// WebSocket is a server side WebSocket connection that only pushes text
+// messages. Messages from the client are read and dropped, answering pings
+// and close frames.
+type WebSocket struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	mutex  sync.Mutex // Serializes writes
+	closed chan struct{}
+	once   sync.Once
+}
+
+// headerHasToken reports whether a comma separated header contains token,
+// ignoring case
+func headerHasToken(h http.Header, name, token string) bool {
+	for _, value := range h.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// UpgradeWebSocket completes the WebSocket handshake for a request and takes
+// over its connection. If the request isn't a valid WebSocket handshake an
+// error response is written and an error returned.
+func UpgradeWebSocket(w http.ResponseWriter, r *http.Request) (*WebSocket, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	switch {
+	case r.Method != http.MethodGet,
+		!headerHasToken(r.Header, "Connection", "upgrade"),
+		!headerHasToken(r.Header, "Upgrade", "websocket"),
+		key == "":
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return nil, errors.New("not a WebSocket handshake")
+	case r.Header.Get("Sec-WebSocket-Version") != "13":
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "Upgrade Required", http.StatusUpgradeRequired)
+		return nil, errors.New("unsupported WebSocket version")
+	}
+
+	conn, rw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return nil, fmt.Errorf("failed to take over connection: %w", err)
+	}
+	// Drop the server's read and write timeouts, which would otherwise end
+	// the socket
+	_ = conn.SetDeadline(time.Time{})
+
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n\r\n"
+	if _, err := conn.Write([]byte(response)); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to complete handshake: %w", err)
+	}
+
+	ws := &WebSocket{conn: conn, reader: rw.Reader, closed: make(chan struct{})}
+	go ws.readLoop()
+	return ws, nil
+}
+
+// Closed returns a channel closed once the connection is closed by either
+// side
+func (ws *WebSocket) Closed() <-chan struct{} {
+	return ws.closed
+}
+
+// WriteText sends a text message, giving up after timeout
+func (ws *WebSocket) WriteText(message []byte, timeout time.Duration) error {
+	return ws.writeFrame(wsText, message, timeout)
+}
+
+// Close sends a close frame and closes the connection
+func (ws *WebSocket) Close() error {
+	// Status 1001, going away
+	_ = ws.writeFrame(wsClose, []byte{0x03, 0xe9}, time.Second)
+	ws.shutdown()
+	return nil
+}
+
+// shutdown closes the connection once
+func (ws *WebSocket) shutdown() {
+	ws.once.Do(func() {
+		close(ws.closed)
+		ws.conn.Close()
+	})
+}
+
+// writeFrame writes a single unmasked frame, as servers must
+func (ws *WebSocket) writeFrame(opcode byte, payload []byte, timeout time.Duration) error {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	select {
+	case <-ws.closed:
+		return errWebSocketClosed
+	default:
+	}
+
+	header := []byte{0x80 | opcode}
+	switch n := len(payload); {
+	case n < 126:
+		header = append(header, byte(n))
+	case n <= 0xffff:
+		header = append(header, 126)
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header = append(header, 127)
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+
+	_ = ws.conn.SetWriteDeadline(time.Now().Add(timeout))
+	if _, err := ws.conn.Write(append(header, payload...)); err != nil {
+		ws.shutdown()
+		return err
+	}
+	return nil
+}
+
+// readLoop reads frames from the client until it closes the connection,
+// answering pings and close frames
+func (ws *WebSocket) readLoop() {
+	defer ws.shutdown()
+
+	for {
+		opcode, payload, err := ws.readFrame()
+		if err != nil {
+			return
+		}
+		switch opcode {
+		case wsPing:
+			if ws.writeFrame(wsPong, payload, 10*time.Second) != nil {
+				return
+			}
+		case wsClose:
+			// Echo the status code back as the closing handshake
+			if len(payload) > 2 {
+				payload = payload[:2]
+			}
+			_ = ws.writeFrame(wsClose, payload, time.Second)
+			return
+		}
+	}
+}
+
+// readFrame reads a single frame from the client, unmasking its payload.
+// Client frames must be masked, and fragmented or oversized frames aren't
+// expected from a push-only socket's clients.
+func (ws *WebSocket) readFrame() (byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(ws.reader, header[:]); err != nil {
+		return 0, nil, err
+	}
+	opcode := header[0] & 0x0f
+	if header[1]&0x80 == 0 {
+		return 0, nil, errors.New("unmasked client frame")
+	}
+
+	length := uint64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(ws.reader, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(ws.reader, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > maxWebSocketFrame {
+		return 0, nil, errors.New("client frame too large")
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(ws.reader, mask[:]); err != nil {
+		return 0, nil, err
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(ws.reader, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return opcode, payload, nil
+}
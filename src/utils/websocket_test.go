@@ -0,0 +1,90 @@
+/*
+ * Copyright 2025 Humaid Alqasimi
+ * SPDX-License-Identifier: Apache-2.0
+ */
+package utils
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebSocket(t *testing.T) {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := UpgradeWebSocket(w, r)
+		if err != nil {
+			return
+		}
+		if err := ws.WriteText([]byte(`{"type":"totals"}`), time.Second); err != nil {
+			t.Errorf("Failed to write message: %v", err)
+		}
+		<-ws.Closed()
+		close(done)
+	}))
+	defer srv.Close()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Example key and accept value from RFC 6455
+	io.WriteString(conn, "GET /ws HTTP/1.1\r\nHost: example\r\nUpgrade: websocket\r\nConnection: keep-alive, Upgrade\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n")
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatalf("Failed to read handshake: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("Unexpected handshake response %d %v", resp.StatusCode, resp.Header)
+	}
+
+	frame := make([]byte, 2+len(`{"type":"totals"}`))
+	if _, err := io.ReadFull(reader, frame); err != nil {
+		t.Fatalf("Failed to read message: %v", err)
+	}
+	if frame[0] != 0x81 || int(frame[1]) != len(frame)-2 || string(frame[2:]) != `{"type":"totals"}` {
+		t.Errorf("Unexpected frame %q", frame)
+	}
+
+	// Masked ping, then a masked close with status 1000
+	mask := []byte{1, 2, 3, 4}
+	conn.Write([]byte{0x89, 0x82, 1, 2, 3, 4, 'h' ^ mask[0], 'i' ^ mask[1]})
+	pong := make([]byte, 4)
+	if _, err := io.ReadFull(reader, pong); err != nil || string(pong) != "\x8a\x02hi" {
+		t.Errorf("Expected pong, got %q (%v)", pong, err)
+	}
+	conn.Write([]byte{0x88, 0x82, 1, 2, 3, 4, 0x03 ^ mask[0], 0xe8 ^ mask[1]})
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the socket to close after the close frame")
+	}
+}
+
+func TestUpgradeWebSocketRejects(t *testing.T) {
+	rec := httptest.NewRecorder()
+	if _, err := UpgradeWebSocket(rec, httptest.NewRequest(http.MethodGet, "/ws", nil)); err == nil || rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected plain request to be rejected, got %d", rec.Code)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/ws", nil)
+	r.Header.Set("Connection", "Upgrade")
+	r.Header.Set("Upgrade", "websocket")
+	r.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	r.Header.Set("Sec-WebSocket-Version", "8")
+	rec = httptest.NewRecorder()
+	if _, err := UpgradeWebSocket(rec, r); err == nil || rec.Code != http.StatusUpgradeRequired {
+		t.Errorf("Expected old version to be rejected, got %d", rec.Code)
+	}
+}